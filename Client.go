@@ -23,12 +23,83 @@ type Client struct {
 	TemplateService         services.TemplateService
 	VirtualMachineService   services.VirtualMachineService
 	NetworkInterfaceService services.NetworkInterfaceService
+	SecurityGroupService    services.SecurityGroupService
+	DiskService             services.DiskService
+	ACLService              services.ACLService
+	VMGroupService          services.VMGroupService
+	VirtualRouterService    services.VirtualRouterService
+	ZoneService             services.ZoneService
+	MarketplaceService      services.MarketplaceService
+	MarketplaceAppService   services.MarketplaceAppService
+	HookService             services.HookService
+}
+
+// ClientOption configures Client created by CreateClientWithOptions.
+type ClientOption func(*services.RPC, *clientOptions)
+
+// clientOptions contains options which are not part of services.RPC.
+type clientOptions struct {
+	httpClient *http.Client
+}
+
+// WithHTTPClient sets http client used to make the XML-RPC calls, http.DefaultClient is used by default.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(rpc *services.RPC, options *clientOptions) {
+		options.httpClient = client
+	}
+}
+
+// WithTokenSource sets source of the token used instead of the token string, the token string passed
+// to CreateClientWithOptions is not kept in the client.
+func WithTokenSource(source services.TokenSource) ClientOption {
+	return func(rpc *services.RPC, options *clientOptions) {
+		rpc.Token = ""
+		rpc.TokenSource = source
+	}
+}
+
+// WithRetryPolicy sets policy used to retry failed calls, failed calls are not retried by default.
+func WithRetryPolicy(policy services.RetryPolicy) ClientOption {
+	return func(rpc *services.RPC, options *clientOptions) {
+		rpc.Retry = &policy
+	}
+}
+
+// WithInterceptors appends interceptors every call of the client passes through, the first interceptor
+// is the outermost one.
+func WithInterceptors(interceptors ...services.Interceptor) ClientOption {
+	return func(rpc *services.RPC, options *clientOptions) {
+		rpc.Interceptors = append(rpc.Interceptors, interceptors...)
+	}
 }
 
 // CreateClient creates Client with endpoint, token and http client
 func CreateClient(endpoint, token string, client *http.Client) *Client {
-	rpc := &services.RPC{Client: xmlrpc.NewClient(endpoint, client), Token: token}
+	return createClient(&services.RPC{Client: xmlrpc.NewClient(endpoint, client), Token: token})
+}
+
+// CreateClientWithOptions creates Client with endpoint, token and options, e.g.
+// CreateClientWithOptions(endpoint, token, WithHTTPClient(client), WithInterceptors(logging)).
+func CreateClientWithOptions(endpoint, token string, options ...ClientOption) *Client {
+	rpc, _ := createRPC(endpoint, token, options)
+
+	return createClient(rpc)
+}
+
+// createRPC applies options to RPC connected to endpoint, http client used by the RPC is returned too.
+func createRPC(endpoint, token string, options []ClientOption) (*services.RPC, *http.Client) {
+	rpc := &services.RPC{Token: token}
+	opts := &clientOptions{httpClient: http.DefaultClient}
 
+	for _, option := range options {
+		option(rpc, opts)
+	}
+	rpc.Client = xmlrpc.NewClient(endpoint, opts.httpClient)
+
+	return rpc, opts.httpClient
+}
+
+func createClient(rpc *services.RPC) *Client {
 	return &Client{UserService: services.UserService{Service: services.Service{RPC: rpc}},
 		TokenService:            services.TokenService{Service: services.Service{RPC: rpc}},
 		GroupService:            services.GroupService{Service: services.Service{RPC: rpc}},
@@ -43,5 +114,20 @@ func CreateClient(endpoint, token string, client *http.Client) *Client {
 		TemplateService:         services.TemplateService{Service: services.Service{RPC: rpc}},
 		VirtualMachineService:   services.VirtualMachineService{Service: services.Service{RPC: rpc}},
 		NetworkInterfaceService: services.NetworkInterfaceService{Service: services.Service{RPC: rpc}},
+		SecurityGroupService:    services.SecurityGroupService{Service: services.Service{RPC: rpc}},
+		DiskService:             services.DiskService{Service: services.Service{RPC: rpc}},
+		ACLService:              services.ACLService{Service: services.Service{RPC: rpc}},
+		VMGroupService:          services.VMGroupService{Service: services.Service{RPC: rpc}},
+		VirtualRouterService:    services.VirtualRouterService{Service: services.Service{RPC: rpc}},
+		ZoneService:             services.ZoneService{Service: services.Service{RPC: rpc}},
+		MarketplaceService:      services.MarketplaceService{Service: services.Service{RPC: rpc}},
+		MarketplaceAppService:   services.MarketplaceAppService{Service: services.Service{RPC: rpc}},
+		HookService:             services.HookService{Service: services.Service{RPC: rpc}},
 	}
 }
+
+// DocumentService creates DocumentService managing documents of given type, the service uses
+// the same connection as the other services of the client.
+func (c *Client) DocumentService(documentType int) *services.DocumentService {
+	return &services.DocumentService{Service: c.UserService.Service, DocumentType: documentType}
+}
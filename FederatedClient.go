@@ -0,0 +1,140 @@
+package onego
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/xmlrpc"
+)
+
+// FederatedClient structure manages all zones of an OpenNebula federation. The embedded Client is
+// connected to the endpoint the federated client was created with (usually the master zone), clients
+// of the other zones are created from endpoints of the zones and reused.
+type FederatedClient struct {
+	*Client
+
+	endpoint   string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// CreateFederatedClient creates FederatedClient with endpoint of a zone of the federation, token and
+// http client. The token and http client are used for all zones, http.DefaultClient is used when the http
+// client is nil.
+func CreateFederatedClient(endpoint, token string, client *http.Client) *FederatedClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := CreateClient(endpoint, token, client)
+
+	return &FederatedClient{Client: c, endpoint: endpoint, httpClient: client,
+		clients: map[string]*Client{endpoint: c}}
+}
+
+// CreateFederatedClientWithOptions creates FederatedClient with endpoint of a zone of the federation, token
+// and options, e.g. CreateFederatedClientWithOptions(endpoint, token, WithHTTPClient(client),
+// WithRetryPolicy(policy)). The options are used for all zones.
+func CreateFederatedClientWithOptions(endpoint, token string, options ...ClientOption) *FederatedClient {
+	rpc, client := createRPC(endpoint, token, options)
+	c := createClient(rpc)
+
+	return &FederatedClient{Client: c, endpoint: endpoint, httpClient: client,
+		clients: map[string]*Client{endpoint: c}}
+}
+
+// Zones discovers all zones of the federation.
+func (fc *FederatedClient) Zones(ctx context.Context) ([]*resources.Zone, error) {
+	return fc.ZoneService.List(ctx)
+}
+
+// ZoneClient returns client connected to the endpoint of given zone. Information of the zone is retrieved
+// when the zone does not contain its endpoint (e.g. zone created by resources.CreateZoneWithID).
+func (fc *FederatedClient) ZoneClient(ctx context.Context, zone resources.Zone) (*Client, error) {
+	endpoint, err := zone.Endpoint()
+	if err != nil {
+		var zoneID int
+		zoneID, err = zone.ID()
+		if err != nil {
+			return nil, err
+		}
+
+		var info *resources.Zone
+		info, err = fc.ZoneService.RetrieveInfo(ctx, zoneID)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint, err = info.Endpoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	c, ok := fc.clients[endpoint]
+	if !ok {
+		// zone clients share retry policy and interceptors of the client of the federation
+		httpClient := fc.httpClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+
+		rpc := *fc.UserService.RPC
+		rpc.Client = xmlrpc.NewClient(endpoint, httpClient)
+		c = createClient(&rpc)
+		fc.clients[endpoint] = c
+	}
+
+	return c, nil
+}
+
+// Clients discovers all zones of the federation and returns map of clients with zone ID as a key.
+func (fc *FederatedClient) Clients(ctx context.Context) (map[int]*Client, error) {
+	zones, err := fc.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make(map[int]*Client, len(zones))
+	for _, zone := range zones {
+		zoneID, err := zone.ID()
+		if err != nil {
+			return nil, err
+		}
+
+		clients[zoneID], err = fc.ZoneClient(ctx, *zone)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return clients, nil
+}
+
+// ForEachZone calls fn with every zone of the federation and client of the zone, zones are visited
+// in the order of their IDs. Iteration stops on the first error returned by fn.
+func (fc *FederatedClient) ForEachZone(ctx context.Context, fn func(zone *resources.Zone, client *Client) error) error {
+	zones, err := fc.Zones(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, zone := range zones {
+		client, err := fc.ZoneClient(ctx, *zone)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(zone, client); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package blueprint
+
+import "encoding/json"
+
+// DocumentBlueprint to set document elements.
+type DocumentBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateDocumentBlueprint creates empty DocumentBlueprint.
+func CreateAllocateDocumentBlueprint() *DocumentBlueprint {
+	return &DocumentBlueprint{Blueprint: *CreateBlueprint("DOCUMENT")}
+}
+
+// CreateUpdateDocumentBlueprint creates empty DocumentBlueprint.
+func CreateUpdateDocumentBlueprint() *DocumentBlueprint {
+	return &DocumentBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetBody sets body of the given document.
+func (db *DocumentBlueprint) SetBody(body string) {
+	db.SetElement("BODY", body)
+}
+
+// MarshalBody sets body of the given document to JSON encoding of given object.
+func (db *DocumentBlueprint) MarshalBody(object interface{}) error {
+	body, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	db.SetBody(string(body))
+
+	return nil
+}
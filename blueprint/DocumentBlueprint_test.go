@@ -0,0 +1,69 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("DocumentBlueprint", func() {
+	var blueprint *DocumentBlueprint
+
+	ginkgo.Describe("CreateAllocateDocumentBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateDocumentBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with DOCUMENT element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("DOCUMENT"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateDocumentBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateDocumentBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetBody", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateDocumentBlueprint()
+		})
+
+		ginkgo.It("should set BODY tag to specified value", func() {
+			blueprint.SetBody("test-value")
+
+			gomega.Expect(blueprint.XMLData.FindElement("DOCUMENT/BODY").Text()).To(gomega.Equal("test-value"))
+		})
+	})
+
+	ginkgo.Describe("MarshalBody", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateDocumentBlueprint()
+		})
+
+		ginkgo.It("should set BODY tag to JSON encoding of specified value", func() {
+			err := blueprint.MarshalBody(struct {
+				Owner    string `json:"owner"`
+				Replicas int    `json:"replicas"`
+			}{Owner: "web-team", Replicas: 3})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(blueprint.XMLData.FindElement("DOCUMENT/BODY").Text()).To(
+				gomega.Equal(`{"owner":"web-team","replicas":3}`))
+		})
+
+		ginkgo.It("should return an error for value which cannot be encoded", func() {
+			err := blueprint.MarshalBody(make(chan int))
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(blueprint.XMLData.FindElement("DOCUMENT/BODY")).To(gomega.BeNil())
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package blueprint
+
+import "github.com/onego-project/onego/resources"
+
+// HookBlueprint to set hook elements.
+type HookBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateHookBlueprint creates empty HookBlueprint.
+func CreateAllocateHookBlueprint() *HookBlueprint {
+	return &HookBlueprint{Blueprint: *CreateBlueprint("HOOK")}
+}
+
+// CreateUpdateHookBlueprint creates empty HookBlueprint.
+func CreateUpdateHookBlueprint() *HookBlueprint {
+	return &HookBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetType sets type of the given hook.
+func (hb *HookBlueprint) SetType(hookType resources.HookType) {
+	hb.SetElement("TYPE", resources.HookTypeMap[hookType])
+}
+
+// SetCommand sets command run by the given hook, relative paths are relative to the hooks directory
+// of the frontend.
+func (hb *HookBlueprint) SetCommand(command string) {
+	hb.SetElement("COMMAND", command)
+}
+
+// SetArguments sets arguments of the command run by the given hook, e.g. "$API" or "$TEMPLATE".
+func (hb *HookBlueprint) SetArguments(arguments string) {
+	hb.SetElement("ARGUMENTS", arguments)
+}
+
+// SetRemote sets whether the command of the given hook is run on the host of the resource.
+func (hb *HookBlueprint) SetRemote(remote bool) {
+	hb.SetElement("REMOTE", boolToString(remote))
+}
+
+// SetAPIHook sets the given hook to be triggered by the API call.
+func (hb *HookBlueprint) SetAPIHook(hook resources.APIHook) {
+	hb.SetType(resources.HookTypeAPI)
+	hb.SetElement("CALL", hook.Call)
+}
+
+// SetStateHook sets the given hook to be triggered by the state change of the resource,
+// empty states are not set.
+func (hb *HookBlueprint) SetStateHook(hook resources.StateHook) {
+	hb.SetType(resources.HookTypeState)
+	hb.SetElement("RESOURCE", hook.Resource)
+
+	for _, element := range [][2]string{{"STATE", hook.State}, {"LCM_STATE", hook.LCMState}, {"ON", hook.On}} {
+		if element[1] != "" {
+			hb.SetElement(element[0], element[1])
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package blueprint
+
+import (
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("HookBlueprint", func() {
+	var blueprint *HookBlueprint
+
+	ginkgo.Describe("CreateAllocateHookBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with HOOK element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("HOOK"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateHookBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateHookBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetType", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should set TYPE tag to specified value", func() {
+			blueprint.SetType(resources.HookTypeState)
+
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/TYPE").Text()).To(gomega.Equal("state"))
+		})
+	})
+
+	ginkgo.Describe("SetCommand", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should set COMMAND tag to specified value", func() {
+			blueprint.SetCommand("notify.sh")
+
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/COMMAND").Text()).To(gomega.Equal("notify.sh"))
+		})
+	})
+
+	ginkgo.Describe("SetArguments", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should set ARGUMENTS tag to specified value", func() {
+			blueprint.SetArguments("$API")
+
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/ARGUMENTS").Text()).To(gomega.Equal("$API"))
+		})
+	})
+
+	ginkgo.Describe("SetRemote", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should set REMOTE tag to specified value", func() {
+			blueprint.SetRemote(true)
+
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/REMOTE").Text()).To(gomega.Equal("YES"))
+		})
+	})
+
+	ginkgo.Describe("SetAPIHook", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should set TYPE and CALL tags", func() {
+			blueprint.SetAPIHook(resources.APIHook{Call: "one.vm.deploy"})
+
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/TYPE").Text()).To(gomega.Equal("api"))
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/CALL").Text()).To(gomega.Equal("one.vm.deploy"))
+		})
+	})
+
+	ginkgo.Describe("SetStateHook", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateHookBlueprint()
+		})
+
+		ginkgo.It("should set TYPE, RESOURCE and given states", func() {
+			blueprint.SetStateHook(resources.StateHook{Resource: "VM", On: "RUNNING"})
+
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/TYPE").Text()).To(gomega.Equal("state"))
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/RESOURCE").Text()).To(gomega.Equal("VM"))
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/ON").Text()).To(gomega.Equal("RUNNING"))
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/STATE")).To(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.FindElement("HOOK/LCM_STATE")).To(gomega.BeNil())
+		})
+	})
+})
@@ -37,6 +37,11 @@ func (ds *ImageBlueprint) SetDriver(driver string) {
 	ds.SetElement("DRIVER", driver)
 }
 
+// SetPath sets path (or URL) the given image is registered from
+func (ds *ImageBlueprint) SetPath(path string) {
+	ds.SetElement("PATH", path)
+}
+
 // SetTarget sets target of the given image
 func (ds *ImageBlueprint) SetTarget(target string) {
 	ds.SetElement("TARGET", target)
@@ -94,6 +94,21 @@ var _ = ginkgo.Describe("ImageBlueprint", func() {
 		})
 	})
 
+	ginkgo.Describe("SetPath", func() {
+		var path string
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &ImageBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+			path = "/var/tmp/sunstone/upload/ubuntu.qcow2"
+		})
+
+		ginkgo.It("should set PATH tag to specified value", func() {
+			blueprint.SetPath(path)
+
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/PATH").Text()).To(gomega.Equal(path))
+		})
+	})
+
 	ginkgo.Describe("SetTarget", func() {
 		var target string
 
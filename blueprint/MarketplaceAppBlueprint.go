@@ -0,0 +1,47 @@
+package blueprint
+
+import (
+	"strconv"
+
+	"github.com/onego-project/onego/resources"
+)
+
+// MarketplaceAppBlueprint to set marketplace app elements.
+type MarketplaceAppBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateMarketplaceAppBlueprint creates empty MarketplaceAppBlueprint.
+func CreateAllocateMarketplaceAppBlueprint() *MarketplaceAppBlueprint {
+	return &MarketplaceAppBlueprint{Blueprint: *CreateBlueprint("MARKETPLACEAPP")}
+}
+
+// CreateUpdateMarketplaceAppBlueprint creates empty MarketplaceAppBlueprint.
+func CreateUpdateMarketplaceAppBlueprint() *MarketplaceAppBlueprint {
+	return &MarketplaceAppBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetOriginID sets ID of the resource (e.g. image) the given marketplace app is exported from.
+func (mab *MarketplaceAppBlueprint) SetOriginID(originID int) {
+	mab.SetElement("ORIGIN_ID", strconv.Itoa(originID))
+}
+
+// SetType sets type of the given marketplace app.
+func (mab *MarketplaceAppBlueprint) SetType(appType resources.MarketplaceAppType) {
+	mab.SetElement("TYPE", resources.MarketplaceAppTypeMap[appType])
+}
+
+// SetDescription sets description of the given marketplace app.
+func (mab *MarketplaceAppBlueprint) SetDescription(description string) {
+	mab.SetElement("DESCRIPTION", description)
+}
+
+// SetVersion sets version of the given marketplace app.
+func (mab *MarketplaceAppBlueprint) SetVersion(version string) {
+	mab.SetElement("VERSION", version)
+}
+
+// SetSize sets size of the given marketplace app in MB.
+func (mab *MarketplaceAppBlueprint) SetSize(size int) {
+	mab.SetElement("SIZE", strconv.Itoa(size))
+}
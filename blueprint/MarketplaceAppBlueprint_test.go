@@ -0,0 +1,96 @@
+package blueprint
+
+import (
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("MarketplaceAppBlueprint", func() {
+	var blueprint *MarketplaceAppBlueprint
+
+	ginkgo.Describe("CreateAllocateMarketplaceAppBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with MARKETPLACEAPP element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("MARKETPLACEAPP"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateMarketplaceAppBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetOriginID", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should set ORIGIN_ID tag to specified value", func() {
+			blueprint.SetOriginID(42)
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACEAPP/ORIGIN_ID").Text()).To(gomega.Equal("42"))
+		})
+	})
+
+	ginkgo.Describe("SetType", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should set TYPE tag to specified value", func() {
+			blueprint.SetType(resources.MarketplaceAppTypeImage)
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACEAPP/TYPE").Text()).To(gomega.Equal("IMAGE"))
+		})
+	})
+
+	ginkgo.Describe("SetDescription", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should set DESCRIPTION tag to specified value", func() {
+			blueprint.SetDescription("test-value")
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACEAPP/DESCRIPTION").Text()).To(
+				gomega.Equal("test-value"))
+		})
+	})
+
+	ginkgo.Describe("SetVersion", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should set VERSION tag to specified value", func() {
+			blueprint.SetVersion("1.2")
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACEAPP/VERSION").Text()).To(gomega.Equal("1.2"))
+		})
+	})
+
+	ginkgo.Describe("SetSize", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceAppBlueprint()
+		})
+
+		ginkgo.It("should set SIZE tag to specified value", func() {
+			blueprint.SetSize(2048)
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACEAPP/SIZE").Text()).To(gomega.Equal("2048"))
+		})
+	})
+})
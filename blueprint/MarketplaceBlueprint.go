@@ -0,0 +1,31 @@
+package blueprint
+
+// MarketplaceBlueprint to set marketplace elements.
+type MarketplaceBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateMarketplaceBlueprint creates empty MarketplaceBlueprint.
+func CreateAllocateMarketplaceBlueprint() *MarketplaceBlueprint {
+	return &MarketplaceBlueprint{Blueprint: *CreateBlueprint("MARKETPLACE")}
+}
+
+// CreateUpdateMarketplaceBlueprint creates empty MarketplaceBlueprint.
+func CreateUpdateMarketplaceBlueprint() *MarketplaceBlueprint {
+	return &MarketplaceBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetMarketMad sets MARKET_MAD (driver) of the given marketplace, e.g. http or s3.
+func (mb *MarketplaceBlueprint) SetMarketMad(marketMad string) {
+	mb.SetElement("MARKET_MAD", marketMad)
+}
+
+// SetBaseURL sets URL the apps of the given http marketplace are served from.
+func (mb *MarketplaceBlueprint) SetBaseURL(baseURL string) {
+	mb.SetElement("BASE_URL", baseURL)
+}
+
+// SetPublicDir sets directory the apps of the given http marketplace are stored in.
+func (mb *MarketplaceBlueprint) SetPublicDir(publicDir string) {
+	mb.SetElement("PUBLIC_DIR", publicDir)
+}
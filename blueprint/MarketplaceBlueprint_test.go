@@ -0,0 +1,72 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("MarketplaceBlueprint", func() {
+	var blueprint *MarketplaceBlueprint
+
+	ginkgo.Describe("CreateAllocateMarketplaceBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with MARKETPLACE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("MARKETPLACE"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateMarketplaceBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateMarketplaceBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetMarketMad", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceBlueprint()
+		})
+
+		ginkgo.It("should set MARKET_MAD tag to specified value", func() {
+			blueprint.SetMarketMad("http")
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACE/MARKET_MAD").Text()).To(gomega.Equal("http"))
+		})
+	})
+
+	ginkgo.Describe("SetBaseURL", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceBlueprint()
+		})
+
+		ginkgo.It("should set BASE_URL tag to specified value", func() {
+			blueprint.SetBaseURL("http://market.example.com/")
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACE/BASE_URL").Text()).To(
+				gomega.Equal("http://market.example.com/"))
+		})
+	})
+
+	ginkgo.Describe("SetPublicDir", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateMarketplaceBlueprint()
+		})
+
+		ginkgo.It("should set PUBLIC_DIR tag to specified value", func() {
+			blueprint.SetPublicDir("/var/www/market")
+
+			gomega.Expect(blueprint.XMLData.FindElement("MARKETPLACE/PUBLIC_DIR").Text()).To(
+				gomega.Equal("/var/www/market"))
+		})
+	})
+})
@@ -31,3 +31,13 @@ func (nb *NICBlueprint) SetNetworkID(value int) {
 func (nb *NICBlueprint) SetNetworkOwnerID(value int) {
 	nb.SetElement("NETWORK_UID", strconv.Itoa(value))
 }
+
+// SetFloatingIP sets FLOATING_IP of a given virtual router NIC, floating IP is leased by the virtual router itself.
+func (nb *NICBlueprint) SetFloatingIP(value bool) {
+	nb.SetElement("FLOATING_IP", boolToString(value))
+}
+
+// SetVirtualRouterManagement sets VROUTER_MANAGEMENT of a given virtual router NIC.
+func (nb *NICBlueprint) SetVirtualRouterManagement(value bool) {
+	nb.SetElement("VROUTER_MANAGEMENT", boolToString(value))
+}
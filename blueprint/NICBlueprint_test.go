@@ -85,4 +85,28 @@ var _ = ginkgo.Describe("NICBlueprint", func() {
 			gomega.Expect(i).To(gomega.Equal(value))
 		})
 	})
+
+	ginkgo.Describe("SetFloatingIP", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = &NICBlueprint{Blueprint: *CreateBlueprint("NIC")}
+		})
+
+		ginkgo.It("should set FLOATING_IP tag to specified value", func() {
+			blueprint.SetFloatingIP(true)
+
+			gomega.Expect(blueprint.XMLData.FindElement("NIC/FLOATING_IP").Text()).To(gomega.Equal("YES"))
+		})
+	})
+
+	ginkgo.Describe("SetVirtualRouterManagement", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = &NICBlueprint{Blueprint: *CreateBlueprint("NIC")}
+		})
+
+		ginkgo.It("should set VROUTER_MANAGEMENT tag to specified value", func() {
+			blueprint.SetVirtualRouterManagement(false)
+
+			gomega.Expect(blueprint.XMLData.FindElement("NIC/VROUTER_MANAGEMENT").Text()).To(gomega.Equal("NO"))
+		})
+	})
 })
@@ -0,0 +1,93 @@
+package blueprint
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+)
+
+// QuotaBlueprint to set quota limits of a user or group. Limits can be set to resources.QuotaDefault
+// to use the default quotas or to resources.QuotaUnlimited to remove the limit.
+type QuotaBlueprint struct {
+	Blueprint
+}
+
+// CreateQuotaBlueprint creates empty QuotaBlueprint.
+func CreateQuotaBlueprint() *QuotaBlueprint {
+	return &QuotaBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+func (qb *QuotaBlueprint) setVMElement(tag, value string) {
+	vm := qb.XMLData.Root().SelectElement("VM")
+	if vm == nil {
+		vm = qb.XMLData.Root().CreateElement("VM")
+	}
+
+	element := vm.SelectElement(tag)
+	if element == nil {
+		element = vm.CreateElement(tag)
+	}
+	element.SetText(value)
+}
+
+func (qb *QuotaBlueprint) addQuota(tag string, id int, limits map[string]int) {
+	quota := etree.NewDocument()
+	quota.CreateElement(tag).CreateElement("ID").SetText(strconv.Itoa(id))
+	for _, limit := range []string{"IMAGES", "SIZE", "LEASES", "RVMS"} {
+		if value, ok := limits[limit]; ok {
+			quota.Root().CreateElement(limit).SetText(strconv.Itoa(value))
+		}
+	}
+
+	qb.AddElement(*quota)
+}
+
+// SetVMs sets limit of number of virtual machines.
+func (qb *QuotaBlueprint) SetVMs(value int) {
+	qb.setVMElement("VMS", strconv.Itoa(value))
+}
+
+// SetCPU sets limit of CPU of all virtual machines.
+func (qb *QuotaBlueprint) SetCPU(value float64) {
+	qb.setVMElement("CPU", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// SetMemory sets limit of memory (in MB) of all virtual machines.
+func (qb *QuotaBlueprint) SetMemory(value int) {
+	qb.setVMElement("MEMORY", strconv.Itoa(value))
+}
+
+// SetRunningVMs sets limit of number of running virtual machines.
+func (qb *QuotaBlueprint) SetRunningVMs(value int) {
+	qb.setVMElement("RUNNING_VMS", strconv.Itoa(value))
+}
+
+// SetRunningCPU sets limit of CPU of running virtual machines.
+func (qb *QuotaBlueprint) SetRunningCPU(value float64) {
+	qb.setVMElement("RUNNING_CPU", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// SetRunningMemory sets limit of memory (in MB) of running virtual machines.
+func (qb *QuotaBlueprint) SetRunningMemory(value int) {
+	qb.setVMElement("RUNNING_MEMORY", strconv.Itoa(value))
+}
+
+// SetSystemDiskSize sets limit of size (in MB) of system disks of all virtual machines.
+func (qb *QuotaBlueprint) SetSystemDiskSize(value int) {
+	qb.setVMElement("SYSTEM_DISK_SIZE", strconv.Itoa(value))
+}
+
+// AddDatastoreQuota adds limits of number of images and their size (in MB) in the datastore with given ID.
+func (qb *QuotaBlueprint) AddDatastoreQuota(datastoreID, images, size int) {
+	qb.addQuota("DATASTORE", datastoreID, map[string]int{"IMAGES": images, "SIZE": size})
+}
+
+// AddNetworkQuota adds limit of number of leases in the virtual network with given ID.
+func (qb *QuotaBlueprint) AddNetworkQuota(networkID, leases int) {
+	qb.addQuota("NETWORK", networkID, map[string]int{"LEASES": leases})
+}
+
+// AddImageQuota adds limit of number of running virtual machines using the image with given ID.
+func (qb *QuotaBlueprint) AddImageQuota(imageID, runningVMs int) {
+	qb.addQuota("IMAGE", imageID, map[string]int{"RVMS": runningVMs})
+}
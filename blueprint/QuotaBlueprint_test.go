@@ -0,0 +1,77 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("QuotaBlueprint", func() {
+	var blueprint *QuotaBlueprint
+
+	ginkgo.BeforeEach(func() {
+		blueprint = CreateQuotaBlueprint()
+	})
+
+	ginkgo.Describe("CreateQuotaBlueprint", func() {
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("VM quota", func() {
+		ginkgo.It("should set all the VM limits in a single VM element", func() {
+			blueprint.SetVMs(5)
+			blueprint.SetCPU(2.5)
+			blueprint.SetMemory(4096)
+			blueprint.SetRunningVMs(-1)
+			blueprint.SetRunningCPU(-2)
+			blueprint.SetRunningMemory(2048)
+			blueprint.SetSystemDiskSize(10240)
+
+			gomega.Expect(blueprint.XMLData.FindElements("TEMPLATE/VM")).To(gomega.HaveLen(1))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/VMS").Text()).To(gomega.Equal("5"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/CPU").Text()).To(gomega.Equal("2.5"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/MEMORY").Text()).To(gomega.Equal("4096"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/RUNNING_VMS").Text()).To(gomega.Equal("-1"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/RUNNING_CPU").Text()).To(gomega.Equal("-2"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/RUNNING_MEMORY").Text()).To(
+				gomega.Equal("2048"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/SYSTEM_DISK_SIZE").Text()).To(
+				gomega.Equal("10240"))
+		})
+
+		ginkgo.It("should overwrite already set limit", func() {
+			blueprint.SetVMs(5)
+			blueprint.SetVMs(7)
+
+			gomega.Expect(blueprint.XMLData.FindElements("TEMPLATE/VM/VMS")).To(gomega.HaveLen(1))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VM/VMS").Text()).To(gomega.Equal("7"))
+		})
+	})
+
+	ginkgo.Describe("resource quotas", func() {
+		ginkgo.It("should add a quota element for each resource", func() {
+			blueprint.AddDatastoreQuota(1, 10, 20480)
+			blueprint.AddDatastoreQuota(2, -2, -1)
+			blueprint.AddNetworkQuota(3, 4)
+			blueprint.AddImageQuota(5, 6)
+
+			datastores := blueprint.XMLData.FindElements("TEMPLATE/DATASTORE")
+			gomega.Expect(datastores).To(gomega.HaveLen(2))
+			gomega.Expect(datastores[0].SelectElement("ID").Text()).To(gomega.Equal("1"))
+			gomega.Expect(datastores[0].SelectElement("IMAGES").Text()).To(gomega.Equal("10"))
+			gomega.Expect(datastores[0].SelectElement("SIZE").Text()).To(gomega.Equal("20480"))
+			gomega.Expect(datastores[1].SelectElement("ID").Text()).To(gomega.Equal("2"))
+			gomega.Expect(datastores[1].SelectElement("IMAGES").Text()).To(gomega.Equal("-2"))
+			gomega.Expect(datastores[1].SelectElement("SIZE").Text()).To(gomega.Equal("-1"))
+
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/NETWORK/ID").Text()).To(gomega.Equal("3"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/NETWORK/LEASES").Text()).To(gomega.Equal("4"))
+
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/IMAGE/ID").Text()).To(gomega.Equal("5"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/IMAGE/RVMS").Text()).To(gomega.Equal("6"))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package blueprint
+
+// SecurityGroupBlueprint to set SecurityGroup elements.
+type SecurityGroupBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateSecurityGroupBlueprint creates empty SecurityGroupBlueprint.
+func CreateAllocateSecurityGroupBlueprint() *SecurityGroupBlueprint {
+	return &SecurityGroupBlueprint{Blueprint: *CreateBlueprint("SECURITY_GROUP")}
+}
+
+// CreateUpdateSecurityGroupBlueprint creates empty SecurityGroupBlueprint.
+func CreateUpdateSecurityGroupBlueprint() *SecurityGroupBlueprint {
+	return &SecurityGroupBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetDescription sets description of the given Security Group.
+func (sgb *SecurityGroupBlueprint) SetDescription(description string) {
+	sgb.SetElement("DESCRIPTION", description)
+}
+
+// SetRule sets RULE of the given Security Group.
+func (sgb *SecurityGroupBlueprint) SetRule(blueprint SecurityGroupRuleBlueprint) {
+	sgb.AddElement(*blueprint.XMLData)
+}
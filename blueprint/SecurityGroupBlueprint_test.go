@@ -0,0 +1,64 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("SecurityGroupBlueprint", func() {
+	var blueprint *SecurityGroupBlueprint
+
+	ginkgo.Describe("CreateAllocateSecurityGroupBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateSecurityGroupBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with SECURITY_GROUP element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("SECURITY_GROUP"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateSecurityGroupBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateSecurityGroupBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetDescription", func() {
+		var value string
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupBlueprint{Blueprint: *CreateBlueprint("SECURITY_GROUP")}
+			value = "test-value"
+		})
+
+		ginkgo.It("should set DESCRIPTION tag to specified value", func() {
+			blueprint.SetDescription(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("SECURITY_GROUP/DESCRIPTION").Text()).To(gomega.Equal(value))
+		})
+	})
+
+	ginkgo.Describe("SetRule", func() {
+		var value *SecurityGroupRuleBlueprint
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupBlueprint{Blueprint: *CreateBlueprint("SECURITY_GROUP")}
+			value = CreateSecurityGroupRuleBlueprint()
+		})
+
+		ginkgo.It("should set RULE tag to specified value", func() {
+			blueprint.SetRule(*value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("SECURITY_GROUP/RULE").Text()).To(gomega.Equal(value.XMLData.Tag))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package blueprint
+
+import (
+	"strconv"
+
+	"github.com/onego-project/onego/resources"
+)
+
+// SecurityGroupRuleBlueprint to set rule elements of Security Group.
+type SecurityGroupRuleBlueprint struct {
+	Blueprint
+}
+
+// CreateSecurityGroupRuleBlueprint creates empty SecurityGroupRuleBlueprint.
+func CreateSecurityGroupRuleBlueprint() *SecurityGroupRuleBlueprint {
+	return &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+}
+
+// SetProtocol sets protocol of a rule.
+func (sgrb *SecurityGroupRuleBlueprint) SetProtocol(value resources.SecurityGroupProtocol) {
+	sgrb.SetElement("PROTOCOL", resources.SecurityGroupProtocolMap[value])
+}
+
+// SetRuleType sets type (inbound/outbound) of a rule.
+func (sgrb *SecurityGroupRuleBlueprint) SetRuleType(value resources.SecurityGroupRuleType) {
+	sgrb.SetElement("RULE_TYPE", resources.SecurityGroupRuleTypeMap[value])
+}
+
+// SetRange sets port range of a rule, e.g. "22,80:90".
+func (sgrb *SecurityGroupRuleBlueprint) SetRange(value string) {
+	sgrb.SetElement("RANGE", value)
+}
+
+// SetIP sets first IP address of the address range of a rule.
+func (sgrb *SecurityGroupRuleBlueprint) SetIP(value string) {
+	sgrb.SetElement("IP", value)
+}
+
+// SetSize sets number of addresses of the address range of a rule.
+func (sgrb *SecurityGroupRuleBlueprint) SetSize(value int) {
+	sgrb.SetElement("SIZE", strconv.Itoa(value))
+}
+
+// SetICMPType sets ICMP type of a rule.
+func (sgrb *SecurityGroupRuleBlueprint) SetICMPType(value int) {
+	sgrb.SetElement("ICMP_TYPE", strconv.Itoa(value))
+}
+
+// SetICMPv6Type sets ICMPv6 type of a rule.
+func (sgrb *SecurityGroupRuleBlueprint) SetICMPv6Type(value int) {
+	sgrb.SetElement("ICMPv6_TYPE", strconv.Itoa(value))
+}
+
+// SetNetworkID sets ID of the virtual network a rule applies to.
+func (sgrb *SecurityGroupRuleBlueprint) SetNetworkID(value int) {
+	sgrb.SetElement("NETWORK_ID", strconv.Itoa(value))
+}
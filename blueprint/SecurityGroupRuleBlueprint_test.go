@@ -0,0 +1,148 @@
+package blueprint
+
+import (
+	"strconv"
+
+	"github.com/onego-project/onego/resources"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("SecurityGroupRuleBlueprint", func() {
+	var blueprint *SecurityGroupRuleBlueprint
+
+	ginkgo.Describe("CreateSecurityGroupRuleBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateSecurityGroupRuleBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with RULE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("RULE"))
+		})
+	})
+
+	ginkgo.Describe("SetProtocol", func() {
+		var value resources.SecurityGroupProtocol
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = resources.SecurityGroupProtocolICMPv6
+		})
+
+		ginkgo.It("should set PROTOCOL tag to specified value", func() {
+			blueprint.SetProtocol(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/PROTOCOL").Text()).To(
+				gomega.Equal(resources.SecurityGroupProtocolMap[value]))
+		})
+	})
+
+	ginkgo.Describe("SetRuleType", func() {
+		var value resources.SecurityGroupRuleType
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = resources.SecurityGroupRuleTypeOutbound
+		})
+
+		ginkgo.It("should set RULE_TYPE tag to specified value", func() {
+			blueprint.SetRuleType(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/RULE_TYPE").Text()).To(
+				gomega.Equal(resources.SecurityGroupRuleTypeMap[value]))
+		})
+	})
+
+	ginkgo.Describe("SetRange", func() {
+		var value string
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = "22,80:90"
+		})
+
+		ginkgo.It("should set RANGE tag to specified value", func() {
+			blueprint.SetRange(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/RANGE").Text()).To(gomega.Equal(value))
+		})
+	})
+
+	ginkgo.Describe("SetIP", func() {
+		var value string
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = "10.0.0.1"
+		})
+
+		ginkgo.It("should set IP tag to specified value", func() {
+			blueprint.SetIP(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/IP").Text()).To(gomega.Equal(value))
+		})
+	})
+
+	ginkgo.Describe("SetSize", func() {
+		var value int
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = 256
+		})
+
+		ginkgo.It("should set SIZE tag to specified value", func() {
+			blueprint.SetSize(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/SIZE").Text()).To(gomega.Equal(strconv.Itoa(value)))
+		})
+	})
+
+	ginkgo.Describe("SetICMPType", func() {
+		var value int
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = 8
+		})
+
+		ginkgo.It("should set ICMP_TYPE tag to specified value", func() {
+			blueprint.SetICMPType(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/ICMP_TYPE").Text()).To(gomega.Equal(strconv.Itoa(value)))
+		})
+	})
+
+	ginkgo.Describe("SetICMPv6Type", func() {
+		var value int
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = 128
+		})
+
+		ginkgo.It("should set ICMPv6_TYPE tag to specified value", func() {
+			blueprint.SetICMPv6Type(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/ICMPv6_TYPE").Text()).To(gomega.Equal(strconv.Itoa(value)))
+		})
+	})
+
+	ginkgo.Describe("SetNetworkID", func() {
+		var value int
+
+		ginkgo.BeforeEach(func() {
+			blueprint = &SecurityGroupRuleBlueprint{Blueprint: *CreateBlueprint("RULE")}
+			value = 420
+		})
+
+		ginkgo.It("should set NETWORK_ID tag to specified value", func() {
+			blueprint.SetNetworkID(value)
+
+			gomega.Expect(blueprint.XMLData.FindElement("RULE/NETWORK_ID").Text()).To(gomega.Equal(strconv.Itoa(value)))
+		})
+	})
+})
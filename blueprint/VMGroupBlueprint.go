@@ -0,0 +1,38 @@
+package blueprint
+
+import "strings"
+
+// VMGroupBlueprint to set VM group elements.
+type VMGroupBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateVMGroupBlueprint creates empty VMGroupBlueprint.
+func CreateAllocateVMGroupBlueprint() *VMGroupBlueprint {
+	return &VMGroupBlueprint{Blueprint: *CreateBlueprint("VM_GROUP")}
+}
+
+// CreateUpdateVMGroupBlueprint creates empty VMGroupBlueprint.
+func CreateUpdateVMGroupBlueprint() *VMGroupBlueprint {
+	return &VMGroupBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetDescription sets description of the given VM group.
+func (vgb *VMGroupBlueprint) SetDescription(description string) {
+	vgb.SetElement("DESCRIPTION", description)
+}
+
+// AddRole adds ROLE to the given VM group.
+func (vgb *VMGroupBlueprint) AddRole(blueprint VMGroupRoleBlueprint) {
+	vgb.AddElement(*blueprint.XMLData)
+}
+
+// AddAffined adds rule placing virtual machines of given roles on the same host.
+func (vgb *VMGroupBlueprint) AddAffined(roles ...string) {
+	vgb.XMLData.Root().CreateElement("AFFINED").SetText(strings.Join(roles, ", "))
+}
+
+// AddAntiAffined adds rule placing virtual machines of given roles on different hosts.
+func (vgb *VMGroupBlueprint) AddAntiAffined(roles ...string) {
+	vgb.XMLData.Root().CreateElement("ANTI_AFFINED").SetText(strings.Join(roles, ", "))
+}
@@ -0,0 +1,88 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("VMGroupBlueprint", func() {
+	var blueprint *VMGroupBlueprint
+
+	ginkgo.Describe("CreateAllocateVMGroupBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVMGroupBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with VM_GROUP element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("VM_GROUP"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateVMGroupBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateVMGroupBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetDescription", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVMGroupBlueprint()
+		})
+
+		ginkgo.It("should set DESCRIPTION tag to specified value", func() {
+			blueprint.SetDescription("test-value")
+
+			gomega.Expect(blueprint.XMLData.FindElement("VM_GROUP/DESCRIPTION").Text()).To(
+				gomega.Equal("test-value"))
+		})
+	})
+
+	ginkgo.Describe("AddRole", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVMGroupBlueprint()
+		})
+
+		ginkgo.It("should add ROLE element for each role", func() {
+			replica := CreateVMGroupRoleBlueprint()
+			replica.SetName("replica")
+			proxy := CreateVMGroupRoleBlueprint()
+			proxy.SetName("proxy")
+
+			blueprint.AddRole(*replica)
+			blueprint.AddRole(*proxy)
+
+			roles := blueprint.XMLData.FindElements("VM_GROUP/ROLE")
+			gomega.Expect(roles).To(gomega.HaveLen(2))
+			gomega.Expect(roles[0].SelectElement("NAME").Text()).To(gomega.Equal("replica"))
+			gomega.Expect(roles[1].SelectElement("NAME").Text()).To(gomega.Equal("proxy"))
+		})
+	})
+
+	ginkgo.Describe("affinity rules", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVMGroupBlueprint()
+		})
+
+		ginkgo.It("should add AFFINED and ANTI_AFFINED element for each rule", func() {
+			blueprint.AddAffined("proxy", "backup")
+			blueprint.AddAntiAffined("replica", "backup")
+			blueprint.AddAntiAffined("replica", "proxy")
+
+			gomega.Expect(blueprint.XMLData.FindElement("VM_GROUP/AFFINED").Text()).To(
+				gomega.Equal("proxy, backup"))
+
+			rules := blueprint.XMLData.FindElements("VM_GROUP/ANTI_AFFINED")
+			gomega.Expect(rules).To(gomega.HaveLen(2))
+			gomega.Expect(rules[0].Text()).To(gomega.Equal("replica, backup"))
+			gomega.Expect(rules[1].Text()).To(gomega.Equal("replica, proxy"))
+		})
+	})
+})
@@ -0,0 +1,41 @@
+package blueprint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/onego-project/onego/resources"
+)
+
+// VMGroupRoleBlueprint to set role elements of VM group.
+type VMGroupRoleBlueprint struct {
+	Blueprint
+}
+
+// CreateVMGroupRoleBlueprint creates empty VMGroupRoleBlueprint.
+func CreateVMGroupRoleBlueprint() *VMGroupRoleBlueprint {
+	return &VMGroupRoleBlueprint{Blueprint: *CreateBlueprint("ROLE")}
+}
+
+// SetPolicy sets placement policy of virtual machines within a role.
+func (vgrb *VMGroupRoleBlueprint) SetPolicy(value resources.VMGroupPolicy) {
+	vgrb.SetElement("POLICY", resources.VMGroupPolicyMap[value])
+}
+
+// SetHostAffined sets IDs of hosts virtual machines of a role have to be placed on.
+func (vgrb *VMGroupRoleBlueprint) SetHostAffined(hostIDs []int) {
+	vgrb.SetElement("HOST_AFFINED", joinIDs(hostIDs))
+}
+
+// SetHostAntiAffined sets IDs of hosts virtual machines of a role must not be placed on.
+func (vgrb *VMGroupRoleBlueprint) SetHostAntiAffined(hostIDs []int) {
+	vgrb.SetElement("HOST_ANTI_AFFINED", joinIDs(hostIDs))
+}
+
+func joinIDs(ids []int) string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.Itoa(id)
+	}
+	return strings.Join(strs, ",")
+}
@@ -0,0 +1,41 @@
+package blueprint
+
+import (
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("VMGroupRoleBlueprint", func() {
+	var blueprint *VMGroupRoleBlueprint
+
+	ginkgo.BeforeEach(func() {
+		blueprint = CreateVMGroupRoleBlueprint()
+	})
+
+	ginkgo.Describe("CreateVMGroupRoleBlueprint", func() {
+		ginkgo.It("should create a blueprint with ROLE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("ROLE"))
+		})
+	})
+
+	ginkgo.Describe("SetPolicy", func() {
+		ginkgo.It("should set POLICY tag to specified value", func() {
+			blueprint.SetPolicy(resources.VMGroupPolicyAntiAffined)
+
+			gomega.Expect(blueprint.XMLData.FindElement("ROLE/POLICY").Text()).To(gomega.Equal("ANTI_AFFINED"))
+		})
+	})
+
+	ginkgo.Describe("hosts", func() {
+		ginkgo.It("should set HOST_AFFINED and HOST_ANTI_AFFINED tags to comma separated IDs", func() {
+			blueprint.SetHostAffined([]int{1, 2})
+			blueprint.SetHostAntiAffined([]int{3})
+
+			gomega.Expect(blueprint.XMLData.FindElement("ROLE/HOST_AFFINED").Text()).To(gomega.Equal("1,2"))
+			gomega.Expect(blueprint.XMLData.FindElement("ROLE/HOST_ANTI_AFFINED").Text()).To(gomega.Equal("3"))
+		})
+	})
+})
@@ -1,6 +1,10 @@
 package blueprint
 
-import "strconv"
+import (
+	"strconv"
+
+	"github.com/onego-project/onego/resources"
+)
 
 // VirtualMachineBlueprint to allocate and update OpenNebula virtual machine.
 type VirtualMachineBlueprint struct {
@@ -77,6 +81,24 @@ func (vmb *VirtualMachineBlueprint) SetTemplateID(id int) {
 	vmb.SetElement("TEMPLATE_ID", strconv.Itoa(id))
 }
 
+// SetVMGroup sets VM group and its role the given virtual machine belongs to.
+func (vmb *VirtualMachineBlueprint) SetVMGroup(vmGroup resources.VMGroup, role string) error {
+	groupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	if element := vmb.XMLData.Root().SelectElement("VMGROUP"); element != nil {
+		vmb.XMLData.Root().RemoveChild(element)
+	}
+
+	group := vmb.XMLData.Root().CreateElement("VMGROUP")
+	group.CreateElement("VMGROUP_ID").SetText(strconv.Itoa(groupID))
+	group.CreateElement("ROLE").SetText(role)
+
+	return nil
+}
+
 // SetVCPU sets VCPU of a given virtual machine.
 func (vmb *VirtualMachineBlueprint) SetVCPU(vcpu int) {
 	vmb.SetElement("VCPU", strconv.Itoa(vcpu))
@@ -3,6 +3,7 @@ package blueprint
 import (
 	"strconv"
 
+	"github.com/onego-project/onego/resources"
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
 )
@@ -219,6 +220,29 @@ var _ = ginkgo.Describe("VirtualMachineBlueprint", func() {
 		})
 	})
 
+	ginkgo.Describe("SetVMGroup", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = &VirtualMachineBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+		})
+
+		ginkgo.It("should set VMGROUP tag to specified group and role", func() {
+			err := blueprint.SetVMGroup(*resources.CreateVMGroupWithID(5), "replica")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			err = blueprint.SetVMGroup(*resources.CreateVMGroupWithID(7), "proxy")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(blueprint.XMLData.FindElements("TEMPLATE/VMGROUP")).To(gomega.HaveLen(1))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VMGROUP/VMGROUP_ID").Text()).To(gomega.Equal("7"))
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VMGROUP/ROLE").Text()).To(gomega.Equal("proxy"))
+		})
+
+		ginkgo.It("should return error when VM group has no ID", func() {
+			err := blueprint.SetVMGroup(resources.VMGroup{}, "replica")
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(blueprint.XMLData.FindElement("TEMPLATE/VMGROUP")).To(gomega.BeNil())
+		})
+	})
+
 	ginkgo.Describe("SetVCPU", func() {
 		var value int
 
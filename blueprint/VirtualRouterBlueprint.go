@@ -0,0 +1,38 @@
+package blueprint
+
+import "strconv"
+
+// VirtualRouterBlueprint to set virtual router elements.
+type VirtualRouterBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateVirtualRouterBlueprint creates empty VirtualRouterBlueprint.
+func CreateAllocateVirtualRouterBlueprint() *VirtualRouterBlueprint {
+	return &VirtualRouterBlueprint{Blueprint: *CreateBlueprint("VROUTER")}
+}
+
+// CreateUpdateVirtualRouterBlueprint creates empty VirtualRouterBlueprint.
+func CreateUpdateVirtualRouterBlueprint() *VirtualRouterBlueprint {
+	return &VirtualRouterBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetDescription sets description of the given virtual router.
+func (vrb *VirtualRouterBlueprint) SetDescription(description string) {
+	vrb.SetElement("DESCRIPTION", description)
+}
+
+// SetKeepAliveID sets KEEPALIVED_ID of the given virtual router.
+func (vrb *VirtualRouterBlueprint) SetKeepAliveID(id int) {
+	vrb.SetElement("KEEPALIVED_ID", strconv.Itoa(id))
+}
+
+// SetKeepAlivePassword sets KEEPALIVED_PASSWORD of the given virtual router.
+func (vrb *VirtualRouterBlueprint) SetKeepAlivePassword(password string) {
+	vrb.SetElement("KEEPALIVED_PASSWORD", password)
+}
+
+// SetNIC sets NIC of the given virtual router.
+func (vrb *VirtualRouterBlueprint) SetNIC(blueprint NICBlueprint) {
+	vrb.AddElement(*blueprint.XMLData)
+}
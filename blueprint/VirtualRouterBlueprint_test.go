@@ -0,0 +1,94 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("VirtualRouterBlueprint", func() {
+	var blueprint *VirtualRouterBlueprint
+
+	ginkgo.Describe("CreateAllocateVirtualRouterBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVirtualRouterBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with VROUTER element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("VROUTER"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateVirtualRouterBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateVirtualRouterBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetDescription", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVirtualRouterBlueprint()
+		})
+
+		ginkgo.It("should set DESCRIPTION tag to specified value", func() {
+			blueprint.SetDescription("test-value")
+
+			gomega.Expect(blueprint.XMLData.FindElement("VROUTER/DESCRIPTION").Text()).To(
+				gomega.Equal("test-value"))
+		})
+	})
+
+	ginkgo.Describe("SetKeepAliveID", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVirtualRouterBlueprint()
+		})
+
+		ginkgo.It("should set KEEPALIVED_ID tag to specified value", func() {
+			blueprint.SetKeepAliveID(12)
+
+			gomega.Expect(blueprint.XMLData.FindElement("VROUTER/KEEPALIVED_ID").Text()).To(gomega.Equal("12"))
+		})
+	})
+
+	ginkgo.Describe("SetKeepAlivePassword", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVirtualRouterBlueprint()
+		})
+
+		ginkgo.It("should set KEEPALIVED_PASSWORD tag to specified value", func() {
+			blueprint.SetKeepAlivePassword("secret")
+
+			gomega.Expect(blueprint.XMLData.FindElement("VROUTER/KEEPALIVED_PASSWORD").Text()).To(
+				gomega.Equal("secret"))
+		})
+	})
+
+	ginkgo.Describe("SetNIC", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateVirtualRouterBlueprint()
+		})
+
+		ginkgo.It("should add NIC element for each NIC", func() {
+			public := CreateNICBlueprint()
+			public.SetNetworkID(0)
+			public.SetFloatingIP(true)
+			private := CreateNICBlueprint()
+			private.SetNetworkID(1)
+
+			blueprint.SetNIC(*public)
+			blueprint.SetNIC(*private)
+
+			nics := blueprint.XMLData.FindElements("VROUTER/NIC")
+			gomega.Expect(nics).To(gomega.HaveLen(2))
+			gomega.Expect(nics[0].SelectElement("FLOATING_IP").Text()).To(gomega.Equal("YES"))
+			gomega.Expect(nics[1].SelectElement("NETWORK_ID").Text()).To(gomega.Equal("1"))
+		})
+	})
+})
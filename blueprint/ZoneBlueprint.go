@@ -0,0 +1,21 @@
+package blueprint
+
+// ZoneBlueprint to set zone elements.
+type ZoneBlueprint struct {
+	Blueprint
+}
+
+// CreateAllocateZoneBlueprint creates empty ZoneBlueprint.
+func CreateAllocateZoneBlueprint() *ZoneBlueprint {
+	return &ZoneBlueprint{Blueprint: *CreateBlueprint("ZONE")}
+}
+
+// CreateUpdateZoneBlueprint creates empty ZoneBlueprint.
+func CreateUpdateZoneBlueprint() *ZoneBlueprint {
+	return &ZoneBlueprint{Blueprint: *CreateBlueprint("TEMPLATE")}
+}
+
+// SetEndpoint sets XML-RPC endpoint of the given zone.
+func (zb *ZoneBlueprint) SetEndpoint(endpoint string) {
+	zb.SetElement("ENDPOINT", endpoint)
+}
@@ -0,0 +1,47 @@
+package blueprint
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("ZoneBlueprint", func() {
+	var blueprint *ZoneBlueprint
+
+	ginkgo.Describe("CreateAllocateZoneBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateZoneBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with ZONE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("ZONE"))
+		})
+	})
+
+	ginkgo.Describe("CreateUpdateZoneBlueprint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateUpdateZoneBlueprint()
+		})
+
+		ginkgo.It("should create a blueprint with TEMPLATE element", func() {
+			gomega.Expect(blueprint).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root()).ShouldNot(gomega.BeNil())
+			gomega.Expect(blueprint.XMLData.Root().Tag).To(gomega.Equal("TEMPLATE"))
+		})
+	})
+
+	ginkgo.Describe("SetEndpoint", func() {
+		ginkgo.BeforeEach(func() {
+			blueprint = CreateAllocateZoneBlueprint()
+		})
+
+		ginkgo.It("should set ENDPOINT tag to specified value", func() {
+			blueprint.SetEndpoint("http://localhost:2633/RPC2")
+
+			gomega.Expect(blueprint.XMLData.FindElement("ZONE/ENDPOINT").Text()).To(
+				gomega.Equal("http://localhost:2633/RPC2"))
+		})
+	})
+})
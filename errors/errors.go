@@ -12,11 +12,82 @@ type OpenNebulaError struct {
 	ObjectID int
 }
 
+// CallError structure represents errors which occurred while making the XML-RPC call
+// (e.g. transport errors), the original error is available through errors.Unwrap.
+type CallError struct {
+	Method string
+	Err    error
+}
+
 // XMLElementError structure represents errors in XML elements
 type XMLElementError struct {
 	Path string
 }
 
+// StateError structure represents error caused by a resource which reached a state
+// it can't leave without intervention (e.g. failure) while waiting for another state.
+type StateError struct {
+	Resource string
+	ID       int
+	State    string
+}
+
+// TransferError structure represents unsuccessful HTTP response received while transferring content
+// of an image. URL doesn't contain user credentials.
+type TransferError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+// OpenNebula error codes
+const (
+	CodeAuthentication = 0x0100
+	CodeAuthorization  = 0x0200
+	CodeNoExists       = 0x0400
+	CodeAction         = 0x0800
+	CodeXMLRPCAPI      = 0x1000
+	CodeInternal       = 0x2000
+	CodeAllocate       = 0x4000
+	CodeLocked         = 0x8000
+)
+
+// ErrAuthentication error category, the user could not be authenticated
+var ErrAuthentication = errors.New("authentication error")
+
+// ErrAuthorization error category, the user is not authorized to perform the action
+var ErrAuthorization = errors.New("authorization error")
+
+// ErrNotFound error category, the requested object doesn't exist
+var ErrNotFound = errors.New("object doesn't exist")
+
+// ErrAction error category, the action is not allowed (e.g. in the current state of the object)
+var ErrAction = errors.New("action not allowed")
+
+// ErrXMLRPCAPI error category, the XML-RPC call has wrong arguments
+var ErrXMLRPCAPI = errors.New("wrong XML-RPC API call")
+
+// ErrInternal error category, OpenNebula internal error
+var ErrInternal = errors.New("internal error")
+
+// ErrAllocate error category, the object could not be allocated
+var ErrAllocate = errors.New("allocation error")
+
+// ErrLocked error category, the object is locked
+var ErrLocked = errors.New("object is locked")
+
+// codeCategories maps OpenNebula error codes to error categories
+var codeCategories = map[int]error{
+	CodeAuthentication: ErrAuthentication,
+	CodeAuthorization:  ErrAuthorization,
+	CodeNoExists:       ErrNotFound,
+	CodeAction:         ErrAction,
+	CodeXMLRPCAPI:      ErrXMLRPCAPI,
+	CodeInternal:       ErrInternal,
+	CodeAllocate:       ErrAllocate,
+	CodeLocked:         ErrLocked,
+}
+
 // ErrNoClient error
 var ErrNoClient = errors.New("no client")
 
@@ -77,12 +148,69 @@ var ErrAddressRangeSetWrong = errors.New("unable to find address range, it was n
 // ErrAddressRangeNoID error
 var ErrAddressRangeNoID = errors.New("no address range id")
 
+// ErrACLRuleNoID error
+var ErrACLRuleNoID = errors.New("no ACL rule id")
+
+// ErrPageSize error
+var ErrPageSize = errors.New("page size has to be greater than one")
+
+// ErrHistoryNoVM error
+var ErrHistoryNoVM = errors.New("history record doesn't contain virtual machine, it wasn't retrieved by accounting")
+
 // ErrNoTemplate error
 var ErrNoTemplate = errors.New("no Template to finish test")
 
 // ErrNoTemplateBlueprint error
 var ErrNoTemplateBlueprint = errors.New("no Template blueprint to finish test")
 
+// ErrNoSecurityGroup error
+var ErrNoSecurityGroup = errors.New("no security group to finish test")
+
+// ErrNoSecurityGroupBlueprint error
+var ErrNoSecurityGroupBlueprint = errors.New("no security group blueprint to finish test")
+
+// ErrNoVMGroup error
+var ErrNoVMGroup = errors.New("no VM group to finish test")
+
+// ErrNoVMGroupBlueprint error
+var ErrNoVMGroupBlueprint = errors.New("no VM group blueprint to finish test")
+
+// ErrNoVirtualRouter error
+var ErrNoVirtualRouter = errors.New("no virtual router to finish test")
+
+// ErrNoVirtualRouterBlueprint error
+var ErrNoVirtualRouterBlueprint = errors.New("no virtual router blueprint to finish test")
+
+// ErrNoZone error
+var ErrNoZone = errors.New("no zone to finish test")
+
+// ErrNoZoneBlueprint error
+var ErrNoZoneBlueprint = errors.New("no zone blueprint to finish test")
+
+// ErrNoMarketplace error
+var ErrNoMarketplace = errors.New("no marketplace to finish test")
+
+// ErrNoMarketplaceBlueprint error
+var ErrNoMarketplaceBlueprint = errors.New("no marketplace blueprint to finish test")
+
+// ErrNoMarketplaceApp error
+var ErrNoMarketplaceApp = errors.New("no marketplace app to finish test")
+
+// ErrNoMarketplaceAppBlueprint error
+var ErrNoMarketplaceAppBlueprint = errors.New("no marketplace app blueprint to finish test")
+
+// ErrNoDocument error
+var ErrNoDocument = errors.New("no document to finish test")
+
+// ErrNoDocumentBlueprint error
+var ErrNoDocumentBlueprint = errors.New("no document blueprint to finish test")
+
+// ErrNoHook error
+var ErrNoHook = errors.New("no hook to finish test")
+
+// ErrNoHookBlueprint error
+var ErrNoHookBlueprint = errors.New("no hook blueprint to finish test")
+
 // NoObjectID to distinguish errors from OpenNebula with 3 or 4 arguments
 var NoObjectID = -1
 
@@ -94,6 +222,62 @@ func (one *OpenNebulaError) Error() string {
 	return fmt.Sprintf("%s, error code: %d", one.Message, one.Code)
 }
 
+// Is reports whether the error belongs to the target error category,
+// e.g. errors.Is(err, ErrNotFound).
+func (one *OpenNebulaError) Is(target error) bool {
+	category, ok := codeCategories[one.Code]
+	return ok && category == target
+}
+
+func (ce *CallError) Error() string {
+	return fmt.Sprintf("%s: %s", ce.Method, ce.Err)
+}
+
+// Unwrap returns the original error of the call.
+func (ce *CallError) Unwrap() error {
+	return ce.Err
+}
+
 func (xee *XMLElementError) Error() string {
 	return fmt.Sprintf("no element %s", xee.Path)
 }
+
+func (se *StateError) Error() string {
+	return fmt.Sprintf("%s %d reached state %s", se.Resource, se.ID, se.State)
+}
+
+func (te *TransferError) Error() string {
+	return fmt.Sprintf("transfer %s failed: %s", te.URL, te.Status)
+}
+
+// IsAuthentication reports whether the error is caused by failed authentication.
+func IsAuthentication(err error) bool {
+	return errors.Is(err, ErrAuthentication)
+}
+
+// IsAuthorization reports whether the error is caused by missing authorization.
+func IsAuthorization(err error) bool {
+	return errors.Is(err, ErrAuthorization)
+}
+
+// IsNotFound reports whether the error is caused by a non-existing object.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsActionNotAllowed reports whether the error is caused by an action which is not allowed.
+func IsActionNotAllowed(err error) bool {
+	return errors.Is(err, ErrAction)
+}
+
+// IsLocked reports whether the error is caused by a locked object.
+func IsLocked(err error) bool {
+	return errors.Is(err, ErrLocked)
+}
+
+// IsTransient reports whether the error may disappear when the call is made again, i.e. the XML-RPC call
+// failed (e.g. connection error or unsuccessful HTTP response) or OpenNebula reported an internal error.
+func IsTransient(err error) bool {
+	var callError *CallError
+	return errors.As(err, &callError) || errors.Is(err, ErrInternal)
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Errors", func() {
+	ginkgo.Describe("OpenNebula error categories", func() {
+		ginkgo.It("should match error category of the code", func() {
+			err := &OpenNebulaError{Code: CodeNoExists, Message: "[one.vm.info] Error getting virtual machine [1000].",
+				ObjectID: NoObjectID}
+
+			gomega.Expect(errors.Is(err, ErrNotFound)).To(gomega.BeTrue())
+			gomega.Expect(errors.Is(err, ErrAuthorization)).To(gomega.BeFalse())
+			gomega.Expect(IsNotFound(err)).To(gomega.BeTrue())
+			gomega.Expect(IsActionNotAllowed(err)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should match error category of the wrapped error", func() {
+			err := fmt.Errorf("deploy failed: %w", &OpenNebulaError{Code: CodeAction, ObjectID: NoObjectID})
+
+			gomega.Expect(IsActionNotAllowed(err)).To(gomega.BeTrue())
+			gomega.Expect(IsNotFound(err)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should match each category", func() {
+			gomega.Expect(IsAuthentication(&OpenNebulaError{Code: CodeAuthentication})).To(gomega.BeTrue())
+			gomega.Expect(IsAuthorization(&OpenNebulaError{Code: CodeAuthorization})).To(gomega.BeTrue())
+			gomega.Expect(IsLocked(&OpenNebulaError{Code: CodeLocked})).To(gomega.BeTrue())
+			gomega.Expect(errors.Is(&OpenNebulaError{Code: CodeXMLRPCAPI}, ErrXMLRPCAPI)).To(gomega.BeTrue())
+			gomega.Expect(errors.Is(&OpenNebulaError{Code: CodeInternal}, ErrInternal)).To(gomega.BeTrue())
+			gomega.Expect(errors.Is(&OpenNebulaError{Code: CodeAllocate}, ErrAllocate)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("shouldn't match any category for unknown code", func() {
+			err := &OpenNebulaError{Code: 1, ObjectID: NoObjectID}
+
+			gomega.Expect(errors.Is(err, ErrNotFound)).To(gomega.BeFalse())
+			gomega.Expect(errors.Is(err, ErrInternal)).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("call error", func() {
+		ginkgo.It("should contain method name and unwrap to the original error", func() {
+			original := errors.New("connection refused")
+			err := &CallError{Method: "one.vm.info", Err: original}
+
+			gomega.Expect(err.Error()).To(gomega.Equal("one.vm.info: connection refused"))
+			gomega.Expect(errors.Unwrap(err)).To(gomega.Equal(original))
+			gomega.Expect(errors.Is(err, original)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("transient errors", func() {
+		ginkgo.It("should report call errors and internal errors as transient", func() {
+			gomega.Expect(IsTransient(&CallError{Method: "one.vm.info", Err: errors.New("connection refused")})).
+				To(gomega.BeTrue())
+			gomega.Expect(IsTransient(fmt.Errorf("retrieve failed: %w", &OpenNebulaError{Code: CodeInternal}))).
+				To(gomega.BeTrue())
+			gomega.Expect(IsTransient(&OpenNebulaError{Code: CodeNoExists})).To(gomega.BeFalse())
+			gomega.Expect(IsTransient(errors.New("unknown"))).To(gomega.BeFalse())
+		})
+	})
+})
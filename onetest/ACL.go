@@ -0,0 +1,118 @@
+package onetest
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/resources"
+)
+
+// zoneID is the ID of the zone of the server.
+const zoneID = 0
+
+// bootstrapACL creates the ACL rules existing in a fresh OpenNebula installation.
+func (s *Server) bootstrapACL() {
+	all := resources.ACLScope{Type: resources.ACLScopeAll}
+	users := resources.ACLScope{Type: resources.ACLScopeGroup, ID: 1}
+
+	for _, rule := range []resources.ACLRule{
+		{User: users, Kinds: resources.VMKind | resources.NetworkKind | resources.ImageKind | resources.TemplateKind |
+			resources.DocumentKind | resources.SecurityGroupKind | resources.VirtualRouterKind | resources.VMGroupKind,
+			Resources: all, Rights: resources.ACLRightCreate, Zone: &all},
+		{User: all, Kinds: resources.ZoneKind, Resources: all, Rights: resources.ACLRightUse, Zone: &all},
+		{User: all, Kinds: resources.MarketplaceKind | resources.MarketplaceAppKind, Resources: all,
+			Rights: resources.ACLRightUse, Zone: &all},
+	} {
+		rule := rule
+		s.addACLRule(&rule)
+	}
+}
+
+func (s *Server) addACLRule(rule *resources.ACLRule) int {
+	rule.ID = s.nextACLID
+	s.nextACLID++
+	s.acls = append(s.acls, rule)
+
+	return rule.ID
+}
+
+func registerACLMethods(s *Server) {
+	s.methods["one.acl.addrule"] = aclAddRule
+	s.methods["one.acl.delrule"] = aclDelRule
+	s.methods["one.acl.info"] = aclInfo
+}
+
+func aclAddRule(s *Server, sess *session, args arguments) (interface{}, error) {
+	var values [3]string
+	for i := range values {
+		value, err := args.string(i)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	zone, err := args.optionalString(3, strconv.FormatUint(uint64(resources.ACLScopeIndividual)|zoneID, 16))
+	if err != nil {
+		return nil, err
+	}
+
+	request := "AclAddRule"
+
+	rule, err := resources.ParseACLRule(values[0], values[1], values[2], zone)
+	if err != nil {
+		return nil, errInternal(request, "Error parsing rule: %s", err)
+	}
+
+	if rule.Kinds == 0 {
+		return nil, errInternal(request, "Error creating rule %s. [Rule malformed] Resource type is missing.",
+			rule)
+	}
+
+	if rule.Rights == 0 {
+		return nil, errInternal(request, "Error creating rule %s. [Rule malformed] Rights are missing.", rule)
+	}
+
+	for _, existing := range s.acls {
+		if existing.String() == rule.String() {
+			return nil, errInternal(request, "Error creating rule %s. Rule already exists.", rule)
+		}
+	}
+
+	return s.addACLRule(rule), nil
+}
+
+func aclDelRule(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rule := range s.acls {
+		if rule.ID == id {
+			s.acls = append(s.acls[:i], s.acls[i+1:]...)
+			return id, nil
+		}
+	}
+
+	return nil, errInternal("AclDelRule", "Error deleting rule %d. Rule does not exist.", id)
+}
+
+func aclInfo(s *Server, sess *session, args arguments) (interface{}, error) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("ACL_POOL")
+
+	for _, rule := range s.acls {
+		user, resource, rights, zone := rule.Encode()
+
+		e := root.CreateElement("ACL")
+		e.CreateElement("ID").SetText(strconv.Itoa(rule.ID))
+		e.CreateElement("USER").SetText(user)
+		e.CreateElement("RESOURCE").SetText(resource)
+		e.CreateElement("RIGHTS").SetText(rights)
+		e.CreateElement("ZONE").SetText(zone)
+		e.CreateElement("STRING").SetText(rule.String())
+	}
+
+	return doc.WriteToString()
+}
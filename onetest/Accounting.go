@@ -0,0 +1,187 @@
+package onetest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+func registerAccountingMethods(s *Server) {
+	s.methods["one.vmpool.accounting"] = vmPoolAccounting
+	s.methods["one.vmpool.showback"] = vmPoolShowback
+}
+
+// ownedVMs returns the virtual machines matching the ownership filter, including the finished ones.
+func (s *Server) ownedVMs(sess *session, filterFlag int) []*object {
+	var userGroups []int
+	if u, ok := s.pool(kindUser).objects[sess.UserID]; ok {
+		userGroups = u.ids("GROUPS")
+	}
+
+	vms := make([]*object, 0)
+	for _, vm := range s.pool(kindVirtualMachine).sorted() {
+		if ownershipMatches(vm, sess, userGroups, filterFlag) {
+			vms = append(vms, vm)
+		}
+	}
+
+	return vms
+}
+
+// historyInterval returns the time interval (in UTC) in which the virtual machine was running, end of
+// running record is the current time.
+func (s *Server) historyInterval(record *etree.Element) (time.Time, time.Time) {
+	start, _ := strconv.ParseInt(childText(record, "RSTIME"), 10, 64)
+	end, _ := strconv.ParseInt(childText(record, "RETIME"), 10, 64)
+
+	if end == 0 {
+		return time.Unix(start, 0).UTC(), s.now().UTC()
+	}
+
+	return time.Unix(start, 0).UTC(), time.Unix(end, 0).UTC()
+}
+
+// vmPoolAccounting returns history records of the virtual machines with VM element, records are filtered
+// by their start and end time.
+func vmPoolAccounting(s *Server, sess *session, args arguments) (interface{}, error) {
+	filterFlag, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := args.int(2)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	root := doc.CreateElement("HISTORY_RECORDS")
+
+	for _, vm := range s.ownedVMs(sess, filterFlag) {
+		for _, record := range vm.element("HISTORY_RECORDS").SelectElements("HISTORY") {
+			stime, _ := strconv.Atoi(childText(record, "STIME"))
+			etime, _ := strconv.Atoi(childText(record, "ETIME"))
+
+			if (end != -1 && stime > end) || (start != -1 && etime != 0 && etime < start) {
+				continue
+			}
+
+			history := record.Copy()
+			info := history.CreateElement("VM")
+			for _, tag := range []string{"ID", "UID", "GID", "UNAME", "GNAME", "NAME"} {
+				info.CreateElement(tag).SetText(vm.text(tag))
+			}
+			info.AddChild(vm.element("TEMPLATE").Copy())
+
+			root.AddChild(history)
+		}
+	}
+
+	return doc.WriteToString()
+}
+
+// vmPoolShowback returns monthly costs of the virtual machines. Unlike OpenNebula, the costs are computed
+// from the history records on each request, so one.vmpool.calculateshowback is not needed.
+func vmPoolShowback(s *Server, sess *session, args arguments) (interface{}, error) {
+	filterFlag, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var bounds [4]int
+	for i := range bounds {
+		if bounds[i], err = args.int(i + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	// months are compared as year * 12 + month - 1
+	first, last := -1, -1
+	if bounds[0] != -1 && bounds[1] != -1 {
+		first = bounds[1]*12 + bounds[0] - 1
+	}
+	if bounds[2] != -1 && bounds[3] != -1 {
+		last = bounds[3]*12 + bounds[2] - 1
+	}
+
+	doc := etree.NewDocument()
+	root := doc.CreateElement("SHOWBACK_RECORDS")
+
+	for _, vm := range s.ownedVMs(sess, filterFlag) {
+		hours := make(map[int]float64)
+		months := make([]int, 0)
+
+		for _, record := range vm.element("HISTORY_RECORDS").SelectElements("HISTORY") {
+			start, end := s.historyInterval(record)
+
+			for month := start.Year()*12 + int(start.Month()) - 1; ; month++ {
+				monthStart := time.Date(month/12, time.Month(month%12+1), 1, 0, 0, 0, 0, time.UTC)
+				monthEnd := monthStart.AddDate(0, 1, 0)
+				if !monthStart.Before(end) {
+					break
+				}
+
+				from, to := start, end
+				if from.Before(monthStart) {
+					from = monthStart
+				}
+				if to.After(monthEnd) {
+					to = monthEnd
+				}
+
+				if _, ok := hours[month]; !ok {
+					months = append(months, month)
+				}
+				hours[month] += to.Sub(from).Hours()
+			}
+		}
+
+		for _, month := range months {
+			if (first != -1 && month < first) || (last != -1 && month > last) {
+				continue
+			}
+
+			root.AddChild(showbackRecord(vm, month, hours[month]))
+		}
+	}
+
+	return doc.WriteToString()
+}
+
+func showbackRecord(vm *object, month int, hours float64) *etree.Element {
+	value := func(path string) float64 {
+		v, _ := strconv.ParseFloat(vm.text(path), 64)
+		return v
+	}
+
+	diskSize := 0.0
+	for _, disk := range vm.element("TEMPLATE").SelectElements("DISK") {
+		size, _ := strconv.ParseFloat(childText(disk, "SIZE"), 64)
+		diskSize += size
+	}
+
+	cpuCost := value("TEMPLATE/CPU_COST") * value("TEMPLATE/CPU") * hours
+	memoryCost := value("TEMPLATE/MEMORY_COST") * value("TEMPLATE/MEMORY") * hours
+	diskCost := value("TEMPLATE/DISK_COST") * diskSize * hours
+
+	record := etree.NewElement("SHOWBACK")
+	for _, v := range [][2]string{{"VMID", itoa(vm.ID)}, {"VMNAME", vm.text("NAME")}, {"UID", vm.text("UID")},
+		{"GID", vm.text("GID")}, {"UNAME", vm.text("UNAME")}, {"GNAME", vm.text("GNAME")},
+		{"YEAR", itoa(month / 12)}, {"MONTH", itoa(month%12 + 1)}, {"CPU_COST", formatCost(cpuCost)},
+		{"MEMORY_COST", formatCost(memoryCost)}, {"DISK_COST", formatCost(diskCost)},
+		{"TOTAL_COST", formatCost(cpuCost + memoryCost + diskCost)}, {"HOURS", formatCost(hours)}} {
+		record.CreateElement(v[0]).SetText(v[1])
+	}
+
+	return record
+}
+
+func formatCost(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
@@ -0,0 +1,93 @@
+package onetest
+
+func registerDocumentMethods(s *Server) {
+	s.methods["one.document.allocate"] = documentAllocate
+	s.methods["one.document.clone"] = documentClone
+	s.methods["one.documentpool.info"] = documentPoolInfo
+}
+
+func documentAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	documentType, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "DocumentAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new document. No NAME in template.")
+	}
+
+	d := s.pool(kindDocument).create(name, sess)
+	d.setInt("TYPE", documentType)
+	replaceTemplate(d.element("TEMPLATE"), template)
+
+	return d.ID, nil
+}
+
+func documentClone(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "DocumentClone"
+
+	source, err := s.pool(kindDocument).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLock(request, kindDocument, source, operationUse); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new document. No NAME in template.")
+	}
+
+	d := s.pool(kindDocument).create(name, sess)
+	d.setInt("TYPE", source.intText("TYPE"))
+	replaceTemplate(d.element("TEMPLATE"), source.element("TEMPLATE"))
+	d.set("TEMPLATE/NAME", name)
+
+	return d.ID, nil
+}
+
+// documentPoolInfo lists documents of the type given by the fourth argument, the other arguments
+// are the same as for the other pools.
+func documentPoolInfo(s *Server, sess *session, args arguments) (interface{}, error) {
+	documentType, err := args.int(3)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*object
+	for _, o := range s.pool(kindDocument).sorted() {
+		if o.intText("TYPE") == documentType {
+			objects = append(objects, o)
+		}
+	}
+
+	if objects, err = s.filterPool(sess, objects, args); err != nil {
+		return nil, err
+	}
+
+	return renderPool(kindDocument, objects)
+}
@@ -0,0 +1,45 @@
+package onetest
+
+import (
+	"fmt"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// Error represents failed XML-RPC call.
+type Error struct {
+	Code     int
+	Message  string
+	ObjectID *int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether the error belongs to the target error category of the errors package,
+// e.g. errors.Is(err, errors.ErrNotFound).
+func (e *Error) Is(target error) bool {
+	return (&errors.OpenNebulaError{Code: e.Code}).Is(target)
+}
+
+func errNoExists(request string, k *kind, id int) *Error {
+	return &Error{Code: errors.CodeNoExists,
+		Message: fmt.Sprintf("[%s] Error getting %s [%d].", request, k.name, id)}
+}
+
+func errAction(request, format string, args ...interface{}) *Error {
+	return &Error{Code: errors.CodeAction, Message: fmt.Sprintf("[%s] %s", request, fmt.Sprintf(format, args...))}
+}
+
+func errAllocate(request, format string, args ...interface{}) *Error {
+	return &Error{Code: errors.CodeAllocate, Message: fmt.Sprintf("[%s] %s", request, fmt.Sprintf(format, args...))}
+}
+
+func errInternal(request, format string, args ...interface{}) *Error {
+	return &Error{Code: errors.CodeInternal, Message: fmt.Sprintf("[%s] %s", request, fmt.Sprintf(format, args...))}
+}
+
+func errAPI(request, format string, args ...interface{}) *Error {
+	return &Error{Code: errors.CodeXMLRPCAPI, Message: fmt.Sprintf("[%s] %s", request, fmt.Sprintf(format, args...))}
+}
@@ -0,0 +1,605 @@
+package onetest
+
+import "github.com/onego-project/onego/errors"
+
+// registerMethods registers all supported XML-RPC methods.
+func registerMethods(s *Server) {
+	for _, k := range kinds {
+		registerCommonMethods(s, k)
+	}
+
+	registerUserMethods(s)
+	registerGroupMethods(s)
+	registerInfrastructureMethods(s)
+	registerImageMethods(s)
+	registerVirtualMachineMethods(s)
+	registerVirtualNetworkMethods(s)
+	registerTemplateMethods(s)
+	registerSecurityGroupMethods(s)
+	registerACLMethods(s)
+	registerQuotaMethods(s)
+	registerAccountingMethods(s)
+	registerMonitoringMethods(s)
+	registerVMGroupMethods(s)
+	registerVirtualRouterMethods(s)
+	registerZoneMethods(s)
+	registerMarketplaceMethods(s)
+	registerDocumentMethods(s)
+	registerHookMethods(s)
+}
+
+// registerCommonMethods registers info, delete, rename, update and pool info methods,
+// chmod and chown for owned resources and lock and unlock for lockable resources.
+func registerCommonMethods(s *Server, k *kind) {
+	s.methods["one."+k.key+".info"] = commonInfo(k)
+	s.methods["one."+k.key+".delete"] = commonDelete(k)
+	s.methods["one."+k.key+".rename"] = commonRename(k)
+	s.methods["one."+k.key+".update"] = commonUpdate(k)
+	s.methods["one."+k.poolKey+".info"] = commonPoolInfo(k)
+
+	if k.owned {
+		s.methods["one."+k.key+".chmod"] = commonChmod(k)
+		s.methods["one."+k.key+".chown"] = commonChown(k)
+	}
+
+	if k.lockable {
+		s.methods["one."+k.key+".lock"] = commonLock(k)
+		s.methods["one."+k.key+".unlock"] = commonUnlock(k)
+	}
+}
+
+func commonInfo(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		// -1 stands for the connected user or his group
+		if id == -1 && k == kindUser {
+			id = sess.UserID
+		} else if id == -1 && k == kindGroup {
+			id = sess.GroupID
+		}
+
+		o, err := s.pool(k).get(requestName(k, "Info"), id)
+		if err != nil {
+			return nil, err
+		}
+
+		s.beforeRender(k, o)
+
+		return o.render()
+	}
+}
+
+// beforeRender updates the computed parts of the object (e.g. quota usage or host capacity) before
+// it is rendered.
+func (s *Server) beforeRender(k *kind, o *object) {
+	switch k {
+	case kindUser, kindGroup:
+		s.refreshQuotas(k, o)
+	case kindHost:
+		s.refreshHostShare(o)
+	case kindHook:
+		s.refreshHookLog(o)
+	}
+}
+
+func commonDelete(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "Delete")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkLock(request, k, o, operationManage); err != nil {
+			return nil, err
+		}
+
+		if err = s.checkDelete(request, k, o); err != nil {
+			return nil, err
+		}
+
+		s.pool(k).remove(id)
+
+		return id, nil
+	}
+}
+
+// checkDelete checks whether the resource may be deleted and releases resources used by it.
+func (s *Server) checkDelete(request string, k *kind, o *object) error {
+	switch k {
+	case kindUser:
+		if o.ID == 0 {
+			return errAction(request, "Cannot delete oneadmin user.")
+		}
+		for _, gid := range o.ids("GROUPS") {
+			if g, ok := s.pool(kindGroup).objects[gid]; ok {
+				g.removeID("USERS", o.ID)
+				g.removeID("ADMINS", o.ID)
+			}
+		}
+	case kindGroup:
+		if o.ID < kindGroup.firstID {
+			return errAction(request, "Cannot delete system group.")
+		}
+		if len(o.ids("USERS")) > 0 {
+			return errAction(request, "Cannot delete group, it contains users.")
+		}
+	case kindCluster:
+		if len(o.ids("HOSTS")) > 0 || len(o.ids("DATASTORES")) > 0 || len(o.ids("VNETS")) > 0 {
+			return errAction(request, "Cannot delete cluster. Cluster %d is not empty, it contains "+
+				"hosts, datastores or virtual networks.", o.ID)
+		}
+	case kindHost:
+		if len(o.ids("VMS")) > 0 {
+			return errAction(request, "Can not remove a host with running VMs")
+		}
+		s.removeFromClusters("HOSTS", o)
+	case kindDatastore:
+		if len(o.ids("IMAGES")) > 0 {
+			return errAction(request, "Cannot delete datastore. Datastore %d is not empty.", o.ID)
+		}
+		s.removeFromClusters("DATASTORES", o)
+	case kindImage:
+		if len(o.ids("VMS")) > 0 {
+			return errAction(request, "Cannot delete image %d. Image is in use.", o.ID)
+		}
+		if ds, ok := s.pool(kindDatastore).objects[o.intText("DATASTORE_ID")]; ok {
+			ds.removeID("IMAGES", o.ID)
+		}
+	case kindVirtualNetwork:
+		if o.intText("USED_LEASES") > 0 {
+			return errAction(request, "Can not remove a virtual network with leases in use")
+		}
+		s.removeFromClusters("VNETS", o)
+	case kindVirtualMachine:
+		return errAPI(request, "Method not supported, use one.vm.action.")
+	case kindVMGroup:
+		for _, role := range o.XML.FindElements("ROLES/ROLE") {
+			if childText(role, "VMS") != "" {
+				return errAction(request, "Cannot delete VM group, it contains virtual machines.")
+			}
+		}
+	case kindVirtualRouter:
+		s.releaseVRouter(o)
+	case kindMarketplace:
+		if len(o.ids("MARKETPLACEAPPS")) > 0 {
+			return errAction(request, "Cannot delete marketplace. Marketplace %d is not empty.", o.ID)
+		}
+	case kindMarketplaceApp:
+		s.removeFromMarketplace(o)
+	}
+
+	return nil
+}
+
+func (s *Server) removeFromClusters(path string, o *object) {
+	for _, c := range s.pool(kindCluster).objects {
+		c.removeID(path, o.ID)
+	}
+}
+
+func commonRename(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := args.string(1)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "Rename")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkLock(request, k, o, operationManage); err != nil {
+			return nil, err
+		}
+
+		if name == "" {
+			return nil, errAction(request, "Invalid name, it cannot be empty.")
+		}
+
+		if other := s.pool(k).findByName(name); other != nil && other.ID != id && k != kindVirtualMachine &&
+			k != kindDocument {
+			return nil, errAction(request, "Cannot rename %s. NAME is already taken by %s %d.", k.name,
+				k.name, other.ID)
+		}
+
+		o.set("NAME", name)
+		s.afterRename(k, o)
+
+		return id, nil
+	}
+}
+
+// afterRename propagates new name of the resource to the resources referencing it by name.
+func (s *Server) afterRename(k *kind, o *object) {
+	if k == kindMarketplace {
+		for _, appID := range o.ids("MARKETPLACEAPPS") {
+			if app, ok := s.pool(kindMarketplaceApp).objects[appID]; ok {
+				app.set("MARKETPLACE", o.text("NAME"))
+			}
+		}
+	}
+}
+
+func commonUpdate(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		text, err := args.string(1)
+		if err != nil {
+			return nil, err
+		}
+
+		updateType, err := args.optionalInt(2, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "UpdateTemplate")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkLock(request, k, o, operationManage); err != nil {
+			return nil, err
+		}
+
+		content, err := parseTemplate(request, text)
+		if err != nil {
+			return nil, err
+		}
+
+		if updateType == 1 {
+			mergeTemplate(o.element(k.templateTag), content)
+		} else {
+			replaceTemplate(o.element(k.templateTag), content)
+		}
+
+		s.afterUpdate(k, o)
+
+		return id, nil
+	}
+}
+
+// afterUpdate refreshes attributes derived from template of the resource.
+func (s *Server) afterUpdate(k *kind, o *object) {
+	switch k {
+	case kindSecurityGroup:
+		refreshSecurityGroupRules(o)
+	case kindMarketplaceApp:
+		refreshMarketAppInfo(o)
+	}
+}
+
+func commonChmod(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "Chmod")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkLock(request, k, o, operationManage); err != nil {
+			return nil, err
+		}
+
+		permissions := []string{"OWNER_U", "OWNER_M", "OWNER_A", "GROUP_U", "GROUP_M", "GROUP_A",
+			"OTHER_U", "OTHER_M", "OTHER_A"}
+
+		for i, permission := range permissions {
+			value, err := args.int(i + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			if value != -1 {
+				o.setInt("PERMISSIONS/"+permission, value)
+			}
+		}
+
+		return id, nil
+	}
+}
+
+func commonChown(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		userID, err := args.int(1)
+		if err != nil {
+			return nil, err
+		}
+
+		groupID, err := args.int(2)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "Chown")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkLock(request, k, o, operationAdmin); err != nil {
+			return nil, err
+		}
+
+		if userID != -1 {
+			user, err := s.pool(kindUser).get(request, userID)
+			if err != nil {
+				return nil, err
+			}
+			o.setInt("UID", userID)
+			o.set("UNAME", user.text("NAME"))
+		}
+
+		if groupID != -1 {
+			group, err := s.pool(kindGroup).get(request, groupID)
+			if err != nil {
+				return nil, err
+			}
+			o.setInt("GID", groupID)
+			o.set("GNAME", group.text("NAME"))
+		}
+
+		return id, nil
+	}
+}
+
+// operation levels used to check locks of the resources
+const (
+	operationUse = iota + 1
+	operationManage
+	operationAdmin
+)
+
+// lockLevelAll blocks all the operations
+const lockLevelAll = 4
+
+// checkLock checks whether the operation of given level is blocked by the lock of the resource.
+func checkLock(request string, k *kind, o *object, operation int) error {
+	level := o.intText("LOCK/LOCKED")
+	if level <= 0 || (level != lockLevelAll && operation < level) {
+		return nil
+	}
+
+	return &Error{Code: errors.CodeLocked, Message: sprintf("[%s] %s [%d] is locked.", request, k.name, o.ID)}
+}
+
+func commonLock(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		level, err := args.int(1)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "Lock")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if level < operationUse || level > lockLevelAll {
+			return nil, errAPI(request, "Wrong lock level %d.", level)
+		}
+
+		if o.XML.SelectElement("LOCK") != nil {
+			return nil, errAction(request, "Error trying to lock the resource.")
+		}
+
+		o.setInt("LOCK/LOCKED", level)
+		o.setInt("LOCK/OWNER", sess.UserID)
+		o.setTime("LOCK/TIME", s.now())
+		o.setInt("LOCK/REQ_ID", -1)
+
+		return id, nil
+	}
+}
+
+func commonUnlock(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		request := requestName(k, "Unlock")
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if lock := o.XML.SelectElement("LOCK"); lock != nil {
+			o.XML.RemoveChild(lock)
+		}
+
+		return id, nil
+	}
+}
+
+func commonPoolInfo(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		objects := s.pool(k).sorted()
+		for _, o := range objects {
+			s.beforeRender(k, o)
+		}
+
+		if k.owned {
+			var err error
+			if objects, err = s.filterPool(sess, objects, args); err != nil {
+				return nil, err
+			}
+		} else if args.len() >= 3 {
+			// pools of resources without owner (e.g. hooks) may take filter flag and range as well
+			start, err := args.int(1)
+			if err != nil {
+				return nil, err
+			}
+
+			end, err := args.int(2)
+			if err != nil {
+				return nil, err
+			}
+
+			objects = paginate(objects, start, end)
+		}
+
+		return renderPool(k, objects)
+	}
+}
+
+// filterPool applies ownership filter, pagination and (for virtual machines) state filter
+// to the objects of the pool.
+func (s *Server) filterPool(sess *session, objects []*object, args arguments) ([]*object, error) {
+	filterFlag, err := args.optionalInt(0, -2)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := args.optionalInt(1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := args.optionalInt(2, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := args.optionalInt(3, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	var userGroups []int
+	if u, ok := s.pool(kindUser).objects[sess.UserID]; ok {
+		userGroups = u.ids("GROUPS")
+	}
+
+	filtered := make([]*object, 0, len(objects))
+	for _, o := range objects {
+		if !ownershipMatches(o, sess, userGroups, filterFlag) {
+			continue
+		}
+
+		if o.XML.Tag == kindVirtualMachine.tag && !vmStateMatches(o, state) {
+			continue
+		}
+
+		filtered = append(filtered, o)
+	}
+
+	return paginate(filtered, start, end), nil
+}
+
+func ownershipMatches(o *object, sess *session, userGroups []int, filterFlag int) bool {
+	uid := o.intText("UID")
+	gid := o.intText("GID")
+
+	switch filterFlag {
+	case -4: // resources belonging to the user's primary group
+		return gid == sess.GroupID
+	case -3: // resources belonging to the user
+		return uid == sess.UserID
+	case -2: // all resources
+		return true
+	case -1: // resources belonging to the user and any of his groups
+		if uid == sess.UserID {
+			return true
+		}
+		for _, g := range userGroups {
+			if g == gid {
+				return true
+			}
+		}
+		return false
+	default: // resources belonging to the user with given ID
+		return uid == filterFlag
+	}
+}
+
+func vmStateMatches(o *object, state int) bool {
+	const stateDone = 6
+
+	vmState := o.intText("STATE")
+
+	switch state {
+	case -2:
+		return true
+	case -1:
+		return vmState != stateDone
+	default:
+		return vmState == state
+	}
+}
+
+// paginate applies OpenNebula pool range: start and end -1 stand for whole pool,
+// end lower than -1 stands for page of size -end starting at offset start,
+// otherwise IDs from start to end are returned.
+func paginate(objects []*object, start, end int) []*object {
+	switch {
+	case start == -1 && end == -1:
+		return objects
+	case end < -1:
+		offset, size := start, -end
+		if offset < 0 {
+			offset = 0
+		}
+		if offset >= len(objects) {
+			return []*object{}
+		}
+		if offset+size > len(objects) {
+			return objects[offset:]
+		}
+		return objects[offset : offset+size]
+	default:
+		ranged := make([]*object, 0, len(objects))
+		for _, o := range objects {
+			if o.ID >= start && (end == -1 || o.ID <= end) {
+				ranged = append(ranged, o)
+			}
+		}
+		return ranged
+	}
+}
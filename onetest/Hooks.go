@@ -0,0 +1,295 @@
+package onetest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/resources"
+)
+
+// hookLogSize is the number of the last execution records rendered in hook info.
+const hookLogSize = 20
+
+// HookRunner runs the command of a hook and returns its exit code and output. Arguments are the arguments
+// of the hook with $API and $TEMPLATE replaced by base64 encoded XML of the call and of the resource.
+// The runner is called while the server is handling a request, so it must not call the server.
+type HookRunner func(command, arguments string) (exitCode int, stdout, stderr string)
+
+// hookExecution is one execution of a hook stored in the hook log of the server.
+type hookExecution struct {
+	hookID      int
+	executionID int
+	timestamp   int64
+	exitCode    int
+	arguments   string
+	XML         *etree.Element
+}
+
+// lcmStateNames are names of the LCM states of virtual machines used by the server.
+var lcmStateNames = map[int]string{
+	lcmStateLcmInit: "LCM_INIT",
+	lcmStateRunning: "RUNNING",
+}
+
+func registerHookMethods(s *Server) {
+	s.methods["one.hook.allocate"] = hookAllocate
+	s.methods["one.hook.retry"] = hookRetry
+	s.methods["one.hooklog.info"] = hookLogInfo
+}
+
+// SetHookRunner sets the function the server uses to run the commands of hooks. By default no command is run
+// and every execution succeeds with empty output.
+func (s *Server) SetHookRunner(runner HookRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hookRunner = runner
+}
+
+func hookAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "HookAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new hook. No NAME in template.")
+	}
+
+	if childText(template, "COMMAND") == "" {
+		return nil, errAllocate(request, "Error allocating a new hook. No COMMAND in template.")
+	}
+
+	hookType := childText(template, "TYPE")
+	switch hookType {
+	case resources.HookTypeMap[resources.HookTypeAPI]:
+		if childText(template, "CALL") == "" {
+			return nil, errAllocate(request, "Error allocating a new hook. No CALL in template.")
+		}
+	case resources.HookTypeMap[resources.HookTypeState]:
+		if childText(template, "RESOURCE") == "" {
+			return nil, errAllocate(request, "Error allocating a new hook. No RESOURCE in template.")
+		}
+	default:
+		return nil, errAllocate(request, "Error allocating a new hook. Invalid hook TYPE: %s.", hookType)
+	}
+
+	if other := s.pool(kindHook).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new hook. NAME is already taken by HOOK %d.",
+			other.ID)
+	}
+
+	h := s.pool(kindHook).create(name, sess)
+	h.set("TYPE", hookType)
+	hookTemplate := h.element("TEMPLATE")
+	for _, e := range template.ChildElements() {
+		if e.Tag != "NAME" && e.Tag != "TYPE" {
+			hookTemplate.AddChild(e.Copy())
+		}
+	}
+
+	return h.ID, nil
+}
+
+func hookRetry(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	executionID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "HookRetry"
+
+	h, err := s.pool(kindHook).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, execution := range s.hookLog {
+		if execution.hookID == id && execution.executionID == executionID {
+			s.executeHook(h, execution.arguments, true)
+			return id, nil
+		}
+	}
+
+	return nil, errAction(request, "Error retrying hook %d. Execution %d not found.", id, executionID)
+}
+
+// hookLogInfo lists execution records filtered by minimal and maximal timestamp, hook ID and result
+// (1 for success, -1 for error, 0 for all), -1 stands for no filter.
+func hookLogInfo(s *Server, sess *session, args arguments) (interface{}, error) {
+	filter := make([]int, 4)
+	for i := range filter {
+		var err error
+		if filter[i], err = args.int(i); err != nil {
+			return nil, err
+		}
+	}
+
+	minTimestamp, maxTimestamp, hookID, result := int64(filter[0]), int64(filter[1]), filter[2], filter[3]
+
+	doc := etree.NewDocument()
+	root := doc.CreateElement("HOOKLOG")
+
+	for _, execution := range s.hookLog {
+		switch {
+		case minTimestamp != -1 && execution.timestamp < minTimestamp,
+			maxTimestamp != -1 && execution.timestamp > maxTimestamp,
+			hookID != -1 && execution.hookID != hookID,
+			result == 1 && execution.exitCode != 0,
+			result == -1 && execution.exitCode == 0:
+			continue
+		}
+		root.AddChild(execution.XML.Copy())
+	}
+
+	return doc.WriteToString()
+}
+
+// refreshHookLog renders the last execution records of the hook to its HOOKLOG element.
+func (s *Server) refreshHookLog(h *object) {
+	if old := h.XML.SelectElement("HOOKLOG"); old != nil {
+		h.XML.RemoveChild(old)
+	}
+
+	var executions []*hookExecution
+	for _, execution := range s.hookLog {
+		if execution.hookID == h.ID {
+			executions = append(executions, execution)
+		}
+	}
+
+	if len(executions) > hookLogSize {
+		executions = executions[len(executions)-hookLogSize:]
+	}
+
+	hookLog := h.element("HOOKLOG")
+	for _, execution := range executions {
+		hookLog.AddChild(execution.XML.Copy())
+	}
+}
+
+// executeHook runs the command of the hook and stores the execution record to the hook log.
+func (s *Server) executeHook(h *object, arguments string, retry bool) {
+	executionID := 0
+	for _, execution := range s.hookLog {
+		if execution.hookID == h.ID {
+			executionID = execution.executionID + 1
+		}
+	}
+
+	command := h.text("TEMPLATE/COMMAND")
+
+	exitCode, stdout, stderr := 0, "", ""
+	if s.hookRunner != nil {
+		exitCode, stdout, stderr = s.hookRunner(command, arguments)
+	}
+
+	execution := &hookExecution{hookID: h.ID, executionID: executionID, timestamp: s.now().Unix(),
+		exitCode: exitCode, arguments: arguments, XML: etree.NewElement("HOOK_EXECUTION_RECORD")}
+
+	record := execution.XML
+	record.CreateElement("HOOK_ID").SetText(itoa(h.ID))
+	record.CreateElement("EXECUTION_ID").SetText(itoa(executionID))
+	record.CreateElement("TIMESTAMP").SetText(sprintf("%d", execution.timestamp))
+	record.CreateElement("ARGUMENTS").SetText(arguments)
+	result := record.CreateElement("EXECUTION_RESULT")
+	result.CreateElement("COMMAND").SetText(command)
+	result.CreateElement("STDOUT").SetText(base64.StdEncoding.EncodeToString([]byte(stdout)))
+	result.CreateElement("STDERR").SetText(base64.StdEncoding.EncodeToString([]byte(stderr)))
+	result.CreateElement("CODE").SetText(itoa(exitCode))
+	if retry {
+		record.CreateElement("RETRY").SetText("yes")
+	}
+
+	s.hookLog = append(s.hookLog, execution)
+}
+
+// runAPIHooks runs the API hooks of the called method. The hooks are run for successful and failed
+// calls, the session string is not passed to the hooks.
+func (s *Server) runAPIHooks(methodName string, params []interface{}, success bool) {
+	for _, h := range s.pool(kindHook).sorted() {
+		if h.text("TYPE") != resources.HookTypeMap[resources.HookTypeAPI] ||
+			h.text("TEMPLATE/CALL") != methodName {
+			continue
+		}
+
+		doc := etree.NewDocument()
+		callInfo := doc.CreateElement("CALL_INFO")
+		callInfo.CreateElement("RESULT").SetText(boolToInt(success))
+		parameters := callInfo.CreateElement("PARAMETERS")
+		for i, param := range params {
+			parameter := parameters.CreateElement("PARAMETER")
+			parameter.CreateElement("POSITION").SetText(itoa(i + 2))
+			parameter.CreateElement("TYPE").SetText("IN")
+			parameter.CreateElement("VALUE").SetText(fmt.Sprint(param))
+		}
+
+		s.executeHook(h, hookArguments(h, "$API", doc), false)
+	}
+}
+
+// runStateHooks runs the state hooks matching the current state of the virtual machine.
+func (s *Server) runStateHooks(vm *object) {
+	state := resources.VirtualMachineStateMap[resources.VirtualMachineState(vm.intText("STATE"))]
+	lcmState := lcmStateNames[vm.intText("LCM_STATE")]
+
+	for _, h := range s.pool(kindHook).sorted() {
+		if h.text("TYPE") != resources.HookTypeMap[resources.HookTypeState] ||
+			h.text("TEMPLATE/RESOURCE") != "VM" || !stateHookMatches(h, state, lcmState) {
+			continue
+		}
+
+		doc := etree.NewDocument()
+		doc.SetRoot(vm.XML.Copy())
+
+		s.executeHook(h, hookArguments(h, "$TEMPLATE", doc), false)
+	}
+}
+
+// stateHookMatches checks STATE and LCM_STATE of the hook or ON when the states are not given,
+// ON=RUNNING stands for ACTIVE virtual machine in RUNNING LCM state.
+func stateHookMatches(h *object, state, lcmState string) bool {
+	if hookState := h.text("TEMPLATE/STATE"); hookState != "" {
+		hookLCMState := h.text("TEMPLATE/LCM_STATE")
+		return hookState == state && (hookLCMState == "" || hookLCMState == lcmState)
+	}
+
+	switch on := h.text("TEMPLATE/ON"); on {
+	case "":
+		return false
+	case "RUNNING":
+		return state == "ACTIVE" && lcmState == "RUNNING"
+	default:
+		return on == state
+	}
+}
+
+// hookArguments replaces the variable in the arguments of the hook by base64 encoded document.
+func hookArguments(h *object, variable string, doc *etree.Document) string {
+	arguments := h.text("TEMPLATE/ARGUMENTS")
+	if !strings.Contains(arguments, variable) {
+		return arguments
+	}
+
+	text, err := doc.WriteToString()
+	if err != nil {
+		return arguments
+	}
+
+	return strings.Replace(arguments, variable, base64.StdEncoding.EncodeToString([]byte(text)), -1)
+}
@@ -0,0 +1,334 @@
+package onetest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// image states
+const (
+	imageReady    = 1
+	imageUsed     = 2
+	imageDisabled = 3
+	imageUsedPers = 8
+)
+
+var imageTypes = map[string]int{"OS": 0, "CDROM": 1, "DATABLOCK": 2, "KERNEL": 3, "RAMDISK": 4, "CONTEXT": 5}
+
+func registerImageMethods(s *Server) {
+	s.methods["one.image.allocate"] = imageAllocate
+	s.methods["one.image.clone"] = imageClone
+	s.methods["one.image.enable"] = imageEnable
+	s.methods["one.image.persistent"] = imagePersistent
+	s.methods["one.image.chtype"] = imageChangeType
+	s.methods["one.image.snapshotdelete"] = imageSnapshot("ImageSnapshotDelete")
+	s.methods["one.image.snapshotrevert"] = imageSnapshot("ImageSnapshotRevert")
+	s.methods["one.image.snapshotflatten"] = imageSnapshot("ImageSnapshotFlatten")
+}
+
+func imageSource(datastoreID int, name string, id int) string {
+	sum := md5.Sum([]byte(sprintf("%s-%d", name, id)))
+	return sprintf("/var/lib/one//datastores/%d/%s", datastoreID, hex.EncodeToString(sum[:]))
+}
+
+// createImage creates a new image in the datastore.
+func (s *Server) createImage(request string, sess *session, name string, imageType, size int,
+	persistent bool, ds *object) *object {
+	img := s.pool(kindImage).create(name, sess)
+	img.setInt("TYPE", imageType)
+	img.setInt("DISK_TYPE", 0)
+	img.set("PERSISTENT", boolToInt(persistent))
+	img.setTime("REGTIME", s.now())
+	img.set("SOURCE", imageSource(ds.ID, name, img.ID))
+	img.set("PATH", "")
+	img.set("FSTYPE", "")
+	img.setInt("SIZE", size)
+	img.setInt("STATE", imageReady)
+	img.setInt("RUNNING_VMS", 0)
+	img.setInt("CLONING_OPS", 0)
+	img.setInt("CLONING_ID", -1)
+	img.setInt("TARGET_SNAPSHOT", -1)
+	img.setInt("DATASTORE_ID", ds.ID)
+	img.set("DATASTORE", ds.text("NAME"))
+	img.element("VMS")
+	img.element("CLONES")
+	img.element("APP_CLONES")
+	img.element("TEMPLATE")
+	img.element("SNAPSHOTS")
+
+	ds.addID("IMAGES", img.ID)
+
+	return img
+}
+
+func boolToInt(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func imageAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	datastoreID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ImageAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, err := s.pool(kindDatastore).get(request, datastoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ds.intText("TYPE") == 1 {
+		return nil, errAllocate(request, "New images cannot be allocated in a system datastore.")
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new image. No NAME in template.")
+	}
+
+	if other := s.findOwnedByName(kindImage, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new image. NAME is already taken by IMAGE %d.",
+			other.ID)
+	}
+
+	imageType := imageTypes["OS"]
+	if t := childText(template, "TYPE"); t != "" {
+		var ok bool
+		if imageType, ok = imageTypes[strings.ToUpper(t)]; !ok {
+			return nil, errAllocate(request, "Error allocating a new image. Unknown image type %s.", t)
+		}
+	}
+
+	size := 1
+	if sizeText := childText(template, "SIZE"); sizeText != "" {
+		if size, err = strconv.Atoi(sizeText); err != nil {
+			return nil, errAllocate(request, "Error allocating a new image. Wrong SIZE %s.", sizeText)
+		}
+	}
+
+	path := childText(template, "PATH")
+	if fromApp := childText(template, "FROM_APP"); fromApp != "" {
+		app, err := s.appImage(request, fromApp)
+		if err != nil {
+			return nil, err
+		}
+		size = app.intText("SIZE")
+		path = app.text("SOURCE")
+		template.CreateElement("FROM_APP_NAME").SetText(app.text("NAME"))
+		template.CreateElement("FROM_APP_MD5").SetText(app.text("MD5"))
+	}
+
+	img := s.createImage(request, sess, name, imageType, size, childText(template, "PERSISTENT") == "YES", ds)
+	img.set("PATH", path)
+	img.set("FSTYPE", childText(template, "FSTYPE"))
+	replaceTemplate(img.element("TEMPLATE"), template)
+
+	return img.ID, nil
+}
+
+// findOwnedByName finds resource of given kind with given name owned by given user.
+func (s *Server) findOwnedByName(k *kind, name string, userID int) *object {
+	for _, o := range s.pool(k).sorted() {
+		if o.text("NAME") == name && o.intText("UID") == userID {
+			return o
+		}
+	}
+	return nil
+}
+
+func imageClone(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	datastoreID, err := args.optionalInt(2, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ImageClone"
+
+	source, err := s.pool(kindImage).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if datastoreID == -1 {
+		datastoreID = source.intText("DATASTORE_ID")
+	}
+
+	ds, err := s.pool(kindDatastore).get(request, datastoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	if other := s.findOwnedByName(kindImage, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new image. NAME is already taken by IMAGE %d.",
+			other.ID)
+	}
+
+	img := s.createImage(request, sess, name, source.intText("TYPE"), source.intText("SIZE"), false, ds)
+	replaceTemplate(img.element("TEMPLATE"), source.element("TEMPLATE"))
+	source.addID("CLONES", img.ID)
+
+	return img.ID, nil
+}
+
+func imageEnable(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	enable, err := args.bool(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ImageEnable"
+
+	img, err := s.pool(kindImage).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	state := img.intText("STATE")
+	switch {
+	case enable && state == imageDisabled:
+		img.setInt("STATE", imageReady)
+	case !enable && state == imageReady:
+		img.setInt("STATE", imageDisabled)
+	case enable && state == imageReady, !enable && state == imageDisabled:
+	default:
+		return nil, errAction(request, "Could not enable image: Image cannot be enabled or disabled in "+
+			"its current state.")
+	}
+
+	return id, nil
+}
+
+func imagePersistent(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	persistent, err := args.bool(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ImagePersistent"
+
+	img, err := s.pool(kindImage).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if img.intText("RUNNING_VMS") > 0 {
+		return nil, errAction(request, "Cannot change persistent attribute, image is in use.")
+	}
+
+	img.set("PERSISTENT", boolToInt(persistent))
+
+	return id, nil
+}
+
+func imageChangeType(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	typeName, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ImageChangeType"
+
+	img, err := s.pool(kindImage).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	imageType, ok := imageTypes[strings.ToUpper(typeName)]
+	if !ok {
+		return nil, errAction(request, "Unknown type %s", typeName)
+	}
+
+	img.setInt("TYPE", imageType)
+
+	return id, nil
+}
+
+func imageSnapshot(request string) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotID, err := args.int(1)
+		if err != nil {
+			return nil, err
+		}
+
+		img, err := s.pool(kindImage).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots := img.element("SNAPSHOTS")
+		for _, snapshot := range snapshots.SelectElements("SNAPSHOT") {
+			if childText(snapshot, "ID") != itoa(snapshotID) {
+				continue
+			}
+
+			switch request {
+			case "ImageSnapshotDelete":
+				if childText(snapshot, "ACTIVE") == "YES" {
+					return nil, errAction(request, "Cannot delete the active snapshot")
+				}
+				snapshots.RemoveChild(snapshot)
+			case "ImageSnapshotRevert":
+				for _, other := range snapshots.SelectElements("SNAPSHOT") {
+					if active := other.SelectElement("ACTIVE"); active != nil {
+						other.RemoveChild(active)
+					}
+				}
+				snapshot.CreateElement("ACTIVE").SetText("YES")
+			case "ImageSnapshotFlatten":
+				for _, other := range snapshots.SelectElements("SNAPSHOT") {
+					snapshots.RemoveChild(other)
+				}
+			}
+
+			return id, nil
+		}
+
+		return nil, errAction(request, "Snapshot %d does not exist", snapshotID)
+	}
+}
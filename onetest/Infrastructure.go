@@ -0,0 +1,289 @@
+package onetest
+
+// host states
+const (
+	hostMonitored = 2
+	hostDisabled  = 4
+	hostOffline   = 8
+)
+
+func registerInfrastructureMethods(s *Server) {
+	s.methods["one.cluster.allocate"] = clusterAllocate
+	s.methods["one.cluster.addhost"] = clusterMember(kindHost, "HOSTS", "ClusterAddHost", true)
+	s.methods["one.cluster.delhost"] = clusterMember(kindHost, "HOSTS", "ClusterDelHost", false)
+	s.methods["one.cluster.adddatastore"] = clusterMember(kindDatastore, "DATASTORES", "ClusterAddDatastore", true)
+	s.methods["one.cluster.deldatastore"] = clusterMember(kindDatastore, "DATASTORES", "ClusterDelDatastore", false)
+	s.methods["one.cluster.addvnet"] = clusterMember(kindVirtualNetwork, "VNETS", "ClusterAddVNet", true)
+	s.methods["one.cluster.delvnet"] = clusterMember(kindVirtualNetwork, "VNETS", "ClusterDelVNet", false)
+
+	s.methods["one.host.allocate"] = hostAllocate
+	s.methods["one.host.status"] = hostStatus
+
+	s.methods["one.datastore.allocate"] = datastoreAllocate
+	s.methods["one.datastore.enable"] = datastoreEnable
+}
+
+func clusterAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	name, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ClusterAllocate"
+
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new cluster. Invalid NAME, it cannot be empty.")
+	}
+
+	if other := s.pool(kindCluster).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new cluster. NAME is already taken by CLUSTER %d.",
+			other.ID)
+	}
+
+	c := s.pool(kindCluster).create(name, sess)
+	c.element("HOSTS")
+	c.element("DATASTORES")
+	c.element("VNETS")
+	c.element("TEMPLATE")
+
+	return c.ID, nil
+}
+
+func clusterMember(k *kind, path, request string, add bool) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		clusterID, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		memberID, err := args.int(1)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := s.pool(kindCluster).get(request, clusterID)
+		if err != nil {
+			return nil, err
+		}
+
+		member, err := s.pool(k).get(request, memberID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !add {
+			c.removeID(path, memberID)
+			member.removeID("CLUSTERS", clusterID)
+			return clusterID, nil
+		}
+
+		if k == kindHost {
+			// host is in exactly one cluster
+			s.removeFromClusters(path, member)
+			member.setInt("CLUSTER_ID", clusterID)
+			member.set("CLUSTER", c.text("NAME"))
+		} else {
+			member.addID("CLUSTERS", clusterID)
+		}
+		c.addID(path, memberID)
+
+		return clusterID, nil
+	}
+}
+
+func hostAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	name, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	imMad, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	vmMad, err := args.string(2)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterID, err := args.optionalInt(3, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "HostAllocate"
+
+	if clusterID == -1 {
+		clusterID = 0
+	}
+
+	c, err := s.pool(kindCluster).get(request, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if other := s.pool(kindHost).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new host. NAME is already taken by HOST %d.",
+			other.ID)
+	}
+
+	h := s.pool(kindHost).create(name, sess)
+	h.setInt("STATE", hostMonitored)
+	h.set("IM_MAD", imMad)
+	h.set("VM_MAD", vmMad)
+	h.setTime("LAST_MON_TIME", s.now())
+	h.setInt("CLUSTER_ID", clusterID)
+	h.set("CLUSTER", c.text("NAME"))
+
+	share := map[string]int{"DISK_USAGE": 0, "MEM_USAGE": 0, "CPU_USAGE": 0, "TOTAL_MEM": 16777216,
+		"TOTAL_CPU": 800, "MAX_DISK": 102400, "MAX_MEM": 16777216, "MAX_CPU": 800, "FREE_DISK": 102400,
+		"FREE_MEM": 16777216, "FREE_CPU": 800, "USED_DISK": 0, "USED_MEM": 0, "USED_CPU": 0, "RUNNING_VMS": 0}
+	for _, tag := range []string{"DISK_USAGE", "MEM_USAGE", "CPU_USAGE", "TOTAL_MEM", "TOTAL_CPU", "MAX_DISK",
+		"MAX_MEM", "MAX_CPU", "FREE_DISK", "FREE_MEM", "FREE_CPU", "USED_DISK", "USED_MEM", "USED_CPU",
+		"RUNNING_VMS"} {
+		h.setInt("HOST_SHARE/"+tag, share[tag])
+	}
+	h.element("HOST_SHARE/DATASTORES")
+	h.element("HOST_SHARE/PCI_DEVICES")
+	h.element("VMS")
+	h.element("TEMPLATE")
+
+	c.addID("HOSTS", h.ID)
+
+	return h.ID, nil
+}
+
+func hostStatus(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := s.pool(kindHost).get("HostStatus", id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case 0:
+		h.setInt("STATE", hostMonitored)
+	case 1:
+		h.setInt("STATE", hostDisabled)
+	case 2:
+		h.setInt("STATE", hostOffline)
+	default:
+		return nil, errAction("HostStatus", "Wrong status code")
+	}
+
+	return id, nil
+}
+
+func (s *Server) newDatastore(id int, name string, dsType int, sess *session) *object {
+	ds := newObject(kindDatastore, id, name, sess)
+	ds.set("DS_MAD", "fs")
+	ds.set("TM_MAD", "ssh")
+	ds.set("BASE_PATH", sprintf("/var/lib/one//datastores/%d", id))
+	ds.setInt("TYPE", dsType)
+	ds.setInt("DISK_TYPE", 0)
+	ds.setInt("STATE", 0)
+	ds.element("CLUSTERS").CreateElement("ID").SetText("0")
+	ds.setInt("TOTAL_MB", 102400)
+	ds.setInt("FREE_MB", 102400)
+	ds.setInt("USED_MB", 0)
+	ds.element("IMAGES")
+	ds.element("TEMPLATE")
+
+	return ds
+}
+
+func datastoreAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterID, err := args.optionalInt(1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "DatastoreAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new datastore. No NAME in template.")
+	}
+
+	dsType := 0
+	switch childText(template, "TYPE") {
+	case "SYSTEM_DS":
+		dsType = 1
+	case "FILE_DS":
+		dsType = 2
+	}
+
+	if clusterID == -1 {
+		clusterID = 0
+	}
+
+	c, err := s.pool(kindCluster).get(request, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := s.pool(kindDatastore)
+	if p.nextID < kindDatastore.firstID {
+		p.nextID = kindDatastore.firstID
+	}
+
+	ds := s.newDatastore(p.nextID, name, dsType, sess)
+	ds.XML.SelectElement("CLUSTERS").SelectElement("ID").SetText(itoa(clusterID))
+	if dsMad := childText(template, "DS_MAD"); dsMad != "" {
+		ds.set("DS_MAD", dsMad)
+	}
+	if tmMad := childText(template, "TM_MAD"); tmMad != "" {
+		ds.set("TM_MAD", tmMad)
+	}
+	replaceTemplate(ds.element("TEMPLATE"), template)
+	p.add(ds)
+
+	c.addID("DATASTORES", ds.ID)
+
+	return ds.ID, nil
+}
+
+func datastoreEnable(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	enable, err := args.bool(1)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, err := s.pool(kindDatastore).get("DatastoreEnable", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if enable {
+		ds.setInt("STATE", 0)
+	} else {
+		ds.setInt("STATE", 1)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,329 @@
+package onetest
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// marketplace and marketplace app states
+const (
+	marketEnabled  = 0
+	marketDisabled = 1
+
+	marketAppReady    = 1
+	marketAppDisabled = 4
+)
+
+// marketplaceSize is the capacity of a new marketplace in MB.
+const marketplaceSize = 1048576
+
+var marketAppTypes = map[string]int{"UNKNOWN": 0, "IMAGE": 1, "VMTEMPLATE": 2, "SERVICE_TEMPLATE": 3}
+
+func registerMarketplaceMethods(s *Server) {
+	s.methods["one.market.allocate"] = marketAllocate
+	s.methods["one.market.enable"] = marketEnable
+	s.methods["one.marketapp.allocate"] = marketAppAllocate
+	s.methods["one.marketapp.enable"] = marketAppEnable
+}
+
+func marketAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "MarketPlaceAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new marketplace. No NAME in template.")
+	}
+
+	marketMad := childText(template, "MARKET_MAD")
+	if marketMad == "" {
+		return nil, errAllocate(request, "Error allocating a new marketplace. No MARKET_MAD in template.")
+	}
+
+	if other := s.pool(kindMarketplace).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new marketplace. NAME is already taken by "+
+			"MARKETPLACE %d.", other.ID)
+	}
+
+	m := s.pool(kindMarketplace).create(name, sess)
+	m.setInt("STATE", marketEnabled)
+	m.set("MARKET_MAD", marketMad)
+	m.setInt("ZONE_ID", zoneID)
+	m.setInt("TOTAL_MB", marketplaceSize)
+	m.setInt("FREE_MB", marketplaceSize)
+	m.setInt("USED_MB", 0)
+	m.element("MARKETPLACEAPPS")
+	marketTemplate := m.element("TEMPLATE")
+	for _, e := range template.ChildElements() {
+		if e.Tag != "NAME" {
+			marketTemplate.AddChild(e.Copy())
+		}
+	}
+
+	return m.ID, nil
+}
+
+func marketEnable(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	enable, err := args.bool(1)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.pool(kindMarketplace).get("MarketPlaceEnable", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if enable {
+		m.setInt("STATE", marketEnabled)
+	} else {
+		m.setInt("STATE", marketDisabled)
+	}
+
+	return id, nil
+}
+
+func marketAppAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	marketID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "MarketPlaceAppAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	market, err := s.pool(kindMarketplace).get(request, marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if market.intText("STATE") != marketEnabled {
+		return nil, errAllocate(request, "Error allocating a new marketplace app. Marketplace %d is disabled.",
+			marketID)
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new marketplace app. No NAME in template.")
+	}
+
+	for _, appID := range market.ids("MARKETPLACEAPPS") {
+		if other := s.pool(kindMarketplaceApp).objects[appID]; other != nil && other.text("NAME") == name {
+			return nil, errAllocate(request, "Error allocating a new marketplace app. NAME is already taken by "+
+				"MARKETPLACEAPP %d.", other.ID)
+		}
+	}
+
+	appType := marketAppTypes["IMAGE"]
+	if t := childText(template, "TYPE"); t != "" {
+		var ok bool
+		if appType, ok = marketAppTypes[strings.ToUpper(t)]; !ok {
+			return nil, errAllocate(request, "Error allocating a new marketplace app. Unknown TYPE %s.", t)
+		}
+	}
+
+	originID := -1
+	if origin := childText(template, "ORIGIN_ID"); origin != "" {
+		if originID, err = strconv.Atoi(origin); err != nil {
+			return nil, errAllocate(request, "Error allocating a new marketplace app. Wrong ORIGIN_ID %s.", origin)
+		}
+	}
+
+	size, _ := strconv.Atoi(childText(template, "SIZE"))
+	appTemplate := childText(template, "APPTEMPLATE64")
+	format := childText(template, "FORMAT")
+
+	// exported image is copied to the marketplace at once
+	var img *object
+	if appType == marketAppTypes["IMAGE"] && originID != -1 {
+		if img, err = s.pool(kindImage).get(request, originID); err != nil {
+			return nil, err
+		}
+
+		size = img.intText("SIZE")
+		if appTemplate == "" {
+			appTemplate = imageAppTemplate(img)
+		}
+		if format == "" {
+			format = "raw"
+		}
+	}
+
+	if size > market.intText("FREE_MB") {
+		return nil, errAllocate(request, "Error allocating a new marketplace app. Not enough space in "+
+			"marketplace %d.", marketID)
+	}
+
+	version := childText(template, "VERSION")
+	if version == "" {
+		version = "0.0"
+	}
+
+	app := s.pool(kindMarketplaceApp).create(name, sess)
+	app.setTime("REGTIME", s.now())
+	app.setInt("ZONE_ID", zoneID)
+	app.setInt("ORIGIN_ID", originID)
+	app.set("SOURCE", strings.TrimRight(market.text("TEMPLATE/BASE_URL"), "/")+"/"+itoa(app.ID))
+	sum := md5.Sum([]byte(sprintf("%s-%d", name, app.ID)))
+	app.set("MD5", hex.EncodeToString(sum[:]))
+	app.setInt("SIZE", size)
+	app.set("DESCRIPTION", childText(template, "DESCRIPTION"))
+	app.set("VERSION", version)
+	app.set("FORMAT", format)
+	app.set("APPTEMPLATE64", appTemplate)
+	app.setInt("MARKETPLACE_ID", marketID)
+	app.set("MARKETPLACE", market.text("NAME"))
+	app.setInt("STATE", marketAppReady)
+	app.setInt("TYPE", appType)
+	appTemplateElement := app.element("TEMPLATE")
+	for _, e := range template.ChildElements() {
+		switch e.Tag {
+		case "NAME", "ORIGIN_ID", "TYPE", "SIZE", "DESCRIPTION", "VERSION", "FORMAT", "APPTEMPLATE64":
+		default:
+			appTemplateElement.AddChild(e.Copy())
+		}
+	}
+
+	market.addID("MARKETPLACEAPPS", app.ID)
+	if img != nil {
+		img.addID("APP_CLONES", app.ID)
+	}
+	market.setInt("USED_MB", market.intText("USED_MB")+size)
+	market.setInt("FREE_MB", market.intText("FREE_MB")-size)
+
+	return app.ID, nil
+}
+
+// imageAppTemplate returns base64 encoded template of the image created when the marketplace app exported
+// from given image is imported.
+func imageAppTemplate(img *object) string {
+	var lines []string
+	for name, t := range imageTypes {
+		if t == img.intText("TYPE") {
+			lines = append(lines, sprintf("TYPE=\"%s\"", name))
+		}
+	}
+
+	for _, tag := range []string{"DEV_PREFIX", "DRIVER"} {
+		if value := img.text("TEMPLATE/" + tag); value != "" {
+			lines = append(lines, sprintf("%s=\"%s\"", tag, value))
+		}
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(strings.Join(lines, "\n")))
+}
+
+// removeFromMarketplace removes deleted marketplace app from its marketplace and from the image
+// it was exported from.
+func (s *Server) removeFromMarketplace(app *object) {
+	if app.intText("TYPE") == marketAppTypes["IMAGE"] {
+		if img, ok := s.pool(kindImage).objects[app.intText("ORIGIN_ID")]; ok {
+			img.removeID("APP_CLONES", app.ID)
+		}
+	}
+
+	market, ok := s.pool(kindMarketplace).objects[app.intText("MARKETPLACE_ID")]
+	if !ok {
+		return
+	}
+
+	market.removeID("MARKETPLACEAPPS", app.ID)
+	market.setInt("USED_MB", market.intText("USED_MB")-app.intText("SIZE"))
+	market.setInt("FREE_MB", market.intText("FREE_MB")+app.intText("SIZE"))
+}
+
+func marketAppEnable(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	enable, err := args.bool(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "MarketPlaceAppEnable"
+
+	app, err := s.pool(kindMarketplaceApp).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLock(request, kindMarketplaceApp, app, operationManage); err != nil {
+		return nil, err
+	}
+
+	state := app.intText("STATE")
+	switch {
+	case enable && state == marketAppDisabled:
+		app.setInt("STATE", marketAppReady)
+	case !enable && state == marketAppReady:
+		app.setInt("STATE", marketAppDisabled)
+	case enable && state == marketAppReady, !enable && state == marketAppDisabled:
+	default:
+		return nil, errAction(request, "Cannot enable or disable marketplace app in its current state.")
+	}
+
+	return id, nil
+}
+
+// refreshMarketAppInfo updates description and version of the marketplace app from its template.
+func refreshMarketAppInfo(app *object) {
+	for _, tag := range []string{"DESCRIPTION", "VERSION"} {
+		if e := app.XML.FindElement("TEMPLATE/" + tag); e != nil {
+			app.set(tag, e.Text())
+		}
+	}
+}
+
+// appImage returns the marketplace app the image is imported from, size and path of the image are taken
+// from the marketplace app.
+func (s *Server) appImage(request, fromApp string) (*object, error) {
+	appID, err := strconv.Atoi(fromApp)
+	if err != nil {
+		return nil, errAllocate(request, "Error allocating a new image. Wrong FROM_APP %s.", fromApp)
+	}
+
+	app, err := s.pool(kindMarketplaceApp).get(request, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.intText("TYPE") != marketAppTypes["IMAGE"] {
+		return nil, errAllocate(request, "Error allocating a new image. Marketplace app %d is not an image.",
+			appID)
+	}
+
+	if app.intText("STATE") != marketAppReady {
+		return nil, errAllocate(request, "Error allocating a new image. Marketplace app %d is not ready.", appID)
+	}
+
+	return app, nil
+}
@@ -0,0 +1,197 @@
+package onetest
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/resources"
+)
+
+func registerMonitoringMethods(s *Server) {
+	s.methods["one.vm.monitoring"] = vmMonitoring
+	s.methods["one.vmpool.monitoring"] = vmPoolMonitoring
+	s.methods["one.host.monitoring"] = hostMonitoring
+	s.methods["one.hostpool.monitoring"] = hostPoolMonitoring
+}
+
+// AddVirtualMachineMonitoring adds monitoring sample of the virtual machine as if it was reported
+// by the monitoring drivers. The sample becomes the current monitoring of the virtual machine, the current
+// time of the server is used when the timestamp of the sample is zero.
+func (s *Server) AddVirtualMachineMonitoring(sample resources.VirtualMachineMonitoring) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vm, err := s.pool(kindVirtualMachine).get("VirtualMachineMonitoring", sample.VMID)
+	if err != nil {
+		return err
+	}
+
+	if sample.Timestamp.IsZero() {
+		sample.Timestamp = s.now()
+	}
+
+	monitoring := etree.NewElement("MONITORING")
+	for _, v := range [][2]string{{"CPU", strconv.FormatFloat(sample.CPU, 'f', -1, 64)},
+		{"MEMORY", itoa(sample.Memory)}, {"NETRX", formatCounter(sample.NetRX)},
+		{"NETTX", formatCounter(sample.NetTX)}, {"DISKRDBYTES", formatCounter(sample.DiskReadBytes)},
+		{"DISKWRBYTES", formatCounter(sample.DiskWriteBytes)},
+		{"DISKRDIOPS", formatCounter(sample.DiskReadOperations)},
+		{"DISKWRIOPS", formatCounter(sample.DiskWriteOperations)}} {
+		monitoring.CreateElement(v[0]).SetText(v[1])
+	}
+
+	if old := vm.XML.SelectElement("MONITORING"); old != nil {
+		vm.XML.RemoveChild(old)
+	}
+	vm.XML.AddChild(monitoring)
+	vm.setTime("LAST_POLL", sample.Timestamp)
+
+	record := etree.NewElement("VM")
+	record.CreateElement("ID").SetText(itoa(vm.ID))
+	record.CreateElement("LAST_POLL").SetText(vm.text("LAST_POLL"))
+	record.AddChild(monitoring.Copy())
+
+	if s.vmMonitoring == nil {
+		s.vmMonitoring = make(map[int][]*etree.Element)
+	}
+	s.vmMonitoring[vm.ID] = append(s.vmMonitoring[vm.ID], record)
+
+	return nil
+}
+
+func formatCounter(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func (s *Server) renderMonitoring(vms []*object) (string, error) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("MONITORING_DATA")
+
+	for _, vm := range vms {
+		for _, record := range s.vmMonitoring[vm.ID] {
+			root.AddChild(record.Copy())
+		}
+	}
+
+	return doc.WriteToString()
+}
+
+func vmMonitoring(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := s.pool(kindVirtualMachine).get("VirtualMachineMonitoring", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderMonitoring([]*object{vm})
+}
+
+func vmPoolMonitoring(s *Server, sess *session, args arguments) (interface{}, error) {
+	filterFlag, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderMonitoring(s.ownedVMs(sess, filterFlag))
+}
+
+// AddHostMonitoring adds monitoring sample of the host as if it was reported by the monitoring drivers.
+// Only the values measured on the host (used and free CPU, memory and disk) are taken from the sample,
+// the current time of the server is used when the timestamp of the sample is zero.
+func (s *Server) AddHostMonitoring(sample resources.HostMonitoring) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host, err := s.pool(kindHost).get("HostMonitoring", sample.HostID)
+	if err != nil {
+		return err
+	}
+
+	if sample.Timestamp.IsZero() {
+		sample.Timestamp = s.now()
+	}
+
+	for tag, value := range map[string]int{"USED_CPU": sample.UsedCPU, "FREE_CPU": sample.FreeCPU,
+		"USED_MEM": sample.UsedMemory, "FREE_MEM": sample.FreeMemory, "USED_DISK": sample.UsedDisk,
+		"FREE_DISK": sample.FreeDisk} {
+		host.setInt("HOST_SHARE/"+tag, value)
+	}
+	host.setTime("LAST_MON_TIME", sample.Timestamp)
+	s.refreshHostShare(host)
+
+	record := etree.NewElement("HOST")
+	record.CreateElement("ID").SetText(itoa(host.ID))
+	record.CreateElement("LAST_MON_TIME").SetText(host.text("LAST_MON_TIME"))
+	record.AddChild(host.element("HOST_SHARE").Copy())
+
+	if s.hostMonitoring == nil {
+		s.hostMonitoring = make(map[int][]*etree.Element)
+	}
+	s.hostMonitoring[host.ID] = append(s.hostMonitoring[host.ID], record)
+
+	return nil
+}
+
+// refreshHostShare updates capacity of the host reduced by the reservations of its cluster and capacity
+// allocated by the virtual machines running on the host.
+func (s *Server) refreshHostShare(host *object) {
+	reservedCPU, reservedMemory := 0, 0
+	if c, ok := s.pool(kindCluster).objects[host.intText("CLUSTER_ID")]; ok {
+		reservedCPU, _ = strconv.Atoi(c.text("TEMPLATE/RESERVED_CPU"))
+		reservedMemory, _ = strconv.Atoi(c.text("TEMPLATE/RESERVED_MEM"))
+	}
+
+	host.setInt("HOST_SHARE/MAX_CPU", host.intText("HOST_SHARE/TOTAL_CPU")-reservedCPU)
+	host.setInt("HOST_SHARE/MAX_MEM", host.intText("HOST_SHARE/TOTAL_MEM")-reservedMemory)
+
+	cpu, memory := 0.0, 0
+	for _, id := range host.ids("VMS") {
+		if vm, ok := s.pool(kindVirtualMachine).objects[id]; ok {
+			vmCPU, _ := strconv.ParseFloat(vm.text("TEMPLATE/CPU"), 64)
+			cpu += vmCPU
+			memory += vm.intText("TEMPLATE/MEMORY")
+		}
+	}
+
+	// CPU is in percents of a core and memory in KB
+	host.setInt("HOST_SHARE/CPU_USAGE", int(cpu*100))
+	host.setInt("HOST_SHARE/MEM_USAGE", memory*1024)
+}
+
+func renderHostMonitoring(records []*etree.Element) (string, error) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement("MONITORING_DATA")
+
+	for _, record := range records {
+		root.AddChild(record.Copy())
+	}
+
+	return doc.WriteToString()
+}
+
+func hostMonitoring(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := s.pool(kindHost).get("HostMonitoring", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderHostMonitoring(s.hostMonitoring[host.ID])
+}
+
+func hostPoolMonitoring(s *Server, sess *session, args arguments) (interface{}, error) {
+	records := make([]*etree.Element, 0)
+	for _, host := range s.pool(kindHost).sorted() {
+		records = append(records, s.hostMonitoring[host.ID]...)
+	}
+
+	return renderHostMonitoring(records)
+}
@@ -0,0 +1,432 @@
+package onetest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+// kind describes one type of OpenNebula resource.
+type kind struct {
+	key     string // namespace of XML-RPC methods, e.g. "vm" for one.vm.info
+	poolKey string // namespace of pool methods, e.g. "vmpool" for one.vmpool.info
+	tag     string // XML tag of resource
+	poolTag string // XML tag of resource pool
+	name    string // human readable name used in error messages
+	request string // prefix of request name used in error messages
+	// owned resources have UID, GID and PERMISSIONS elements
+	owned bool
+	// templateTag is the element updated by one.<key>.update
+	templateTag string
+	// lockable resources can be locked by one.<key>.lock
+	lockable bool
+	// firstID is the ID of the first resource created by user
+	firstID int
+}
+
+var (
+	kindUser = &kind{key: "user", poolKey: "userpool", tag: "USER", poolTag: "USER_POOL", name: "user",
+		request: "User", templateTag: "TEMPLATE", firstID: 2}
+	kindGroup = &kind{key: "group", poolKey: "grouppool", tag: "GROUP", poolTag: "GROUP_POOL", name: "group",
+		request: "Group", templateTag: "TEMPLATE", firstID: 100}
+	kindCluster = &kind{key: "cluster", poolKey: "clusterpool", tag: "CLUSTER", poolTag: "CLUSTER_POOL",
+		name: "cluster", request: "Cluster", templateTag: "TEMPLATE", firstID: 100}
+	kindHost = &kind{key: "host", poolKey: "hostpool", tag: "HOST", poolTag: "HOST_POOL", name: "host",
+		request: "Host", templateTag: "TEMPLATE"}
+	kindDatastore = &kind{key: "datastore", poolKey: "datastorepool", tag: "DATASTORE",
+		poolTag: "DATASTORE_POOL", name: "datastore", request: "Datastore", owned: true, templateTag: "TEMPLATE",
+		firstID: 100}
+	kindImage = &kind{key: "image", poolKey: "imagepool", tag: "IMAGE", poolTag: "IMAGE_POOL", name: "image",
+		request: "Image", owned: true, templateTag: "TEMPLATE"}
+	kindVirtualMachine = &kind{key: "vm", poolKey: "vmpool", tag: "VM", poolTag: "VM_POOL",
+		name: "virtual machine", request: "VirtualMachine", owned: true, templateTag: "USER_TEMPLATE"}
+	kindVirtualNetwork = &kind{key: "vn", poolKey: "vnpool", tag: "VNET", poolTag: "VNET_POOL",
+		name: "virtual network", request: "VirtualNetwork", owned: true, templateTag: "TEMPLATE"}
+	kindTemplate = &kind{key: "template", poolKey: "templatepool", tag: "VMTEMPLATE", poolTag: "VMTEMPLATE_POOL",
+		name: "virtual machine template", request: "Template", owned: true, templateTag: "TEMPLATE"}
+	kindSecurityGroup = &kind{key: "secgroup", poolKey: "secgrouppool", tag: "SECURITY_GROUP",
+		poolTag: "SECURITY_GROUP_POOL", name: "security group", request: "SecurityGroup", owned: true,
+		templateTag: "TEMPLATE", firstID: 100}
+	kindVMGroup = &kind{key: "vmgroup", poolKey: "vmgrouppool", tag: "VM_GROUP", poolTag: "VM_GROUP_POOL",
+		name: "VM group", request: "VMGroup", owned: true, templateTag: "TEMPLATE", lockable: true}
+	kindVirtualRouter = &kind{key: "vrouter", poolKey: "vrouterpool", tag: "VROUTER", poolTag: "VROUTER_POOL",
+		name: "virtual router", request: "VirtualRouter", owned: true, templateTag: "TEMPLATE", lockable: true}
+	kindZone = &kind{key: "zone", poolKey: "zonepool", tag: "ZONE", poolTag: "ZONE_POOL", name: "zone",
+		request: "Zone", templateTag: "TEMPLATE", firstID: 100}
+	kindMarketplace = &kind{key: "market", poolKey: "marketpool", tag: "MARKETPLACE", poolTag: "MARKETPLACE_POOL",
+		name: "marketplace", request: "MarketPlace", owned: true, templateTag: "TEMPLATE", firstID: 100}
+	kindMarketplaceApp = &kind{key: "marketapp", poolKey: "marketapppool", tag: "MARKETPLACEAPP",
+		poolTag: "MARKETPLACEAPP_POOL", name: "marketplace app", request: "MarketPlaceApp", owned: true,
+		templateTag: "TEMPLATE", lockable: true}
+	kindDocument = &kind{key: "document", poolKey: "documentpool", tag: "DOCUMENT", poolTag: "DOCUMENT_POOL",
+		name: "document", request: "Document", owned: true, templateTag: "TEMPLATE", lockable: true}
+	kindHook = &kind{key: "hook", poolKey: "hookpool", tag: "HOOK", poolTag: "HOOK_POOL", name: "hook",
+		request: "Hook", templateTag: "TEMPLATE"}
+)
+
+var kinds = []*kind{kindUser, kindGroup, kindCluster, kindHost, kindDatastore, kindImage, kindVirtualMachine,
+	kindVirtualNetwork, kindTemplate, kindSecurityGroup, kindVMGroup, kindVirtualRouter, kindZone, kindMarketplace,
+	kindMarketplaceApp, kindDocument, kindHook}
+
+// object is one resource stored in the server.
+type object struct {
+	ID  int
+	XML *etree.Element
+}
+
+// pool stores resources of one kind.
+type pool struct {
+	kind    *kind
+	objects map[int]*object
+	nextID  int
+}
+
+func (p *pool) add(o *object) {
+	p.objects[o.ID] = o
+	if o.ID >= p.nextID {
+		p.nextID = o.ID + 1
+	}
+}
+
+// create creates new object with next free ID.
+func (p *pool) create(name string, sess *session) *object {
+	if p.nextID < p.kind.firstID {
+		p.nextID = p.kind.firstID
+	}
+
+	o := newObject(p.kind, p.nextID, name, sess)
+	p.add(o)
+
+	return o
+}
+
+func (p *pool) get(request string, id int) (*object, error) {
+	o, ok := p.objects[id]
+	if !ok {
+		return nil, errNoExists(request, p.kind, id)
+	}
+	return o, nil
+}
+
+func (p *pool) remove(id int) {
+	delete(p.objects, id)
+}
+
+func (p *pool) sorted() []*object {
+	ids := make([]int, 0, len(p.objects))
+	for id := range p.objects {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	objects := make([]*object, len(ids))
+	for i, id := range ids {
+		objects[i] = p.objects[id]
+	}
+	return objects
+}
+
+func (p *pool) findByName(name string) *object {
+	for _, o := range p.sorted() {
+		if o.text("NAME") == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// newObject creates object with elements common for all resources of given kind.
+func newObject(k *kind, id int, name string, sess *session) *object {
+	o := &object{ID: id, XML: etree.NewElement(k.tag)}
+	o.set("ID", strconv.Itoa(id))
+
+	if k.owned {
+		o.set("UID", strconv.Itoa(sess.UserID))
+		o.set("GID", strconv.Itoa(sess.GroupID))
+		o.set("UNAME", sess.UserName)
+		o.set("GNAME", sess.GroupName)
+		o.set("NAME", name)
+
+		for _, perm := range []string{"OWNER_U", "OWNER_M", "OWNER_A", "GROUP_U", "GROUP_M", "GROUP_A",
+			"OTHER_U", "OTHER_M", "OTHER_A"} {
+			o.set("PERMISSIONS/"+perm, "0")
+		}
+		o.set("PERMISSIONS/OWNER_U", "1")
+		o.set("PERMISSIONS/OWNER_M", "1")
+	} else {
+		o.set("NAME", name)
+	}
+
+	return o
+}
+
+// text returns text of element on given path or empty string.
+func (o *object) text(path string) string {
+	e := o.XML.FindElement(path)
+	if e == nil {
+		return ""
+	}
+	return e.Text()
+}
+
+// intText returns integer value of element on given path or -1.
+func (o *object) intText(path string) int {
+	i, err := strconv.Atoi(strings.TrimSpace(o.text(path)))
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+// element returns element on given path, missing elements on the path are created.
+func (o *object) element(path string) *etree.Element {
+	e := o.XML
+	for _, tag := range strings.Split(path, "/") {
+		child := e.SelectElement(tag)
+		if child == nil {
+			child = e.CreateElement(tag)
+		}
+		e = child
+	}
+	return e
+}
+
+// set sets text of element on given path, missing elements on the path are created.
+func (o *object) set(path, value string) {
+	o.element(path).SetText(value)
+}
+
+func (o *object) setInt(path string, value int) {
+	o.set(path, strconv.Itoa(value))
+}
+
+func (o *object) setTime(path string, t time.Time) {
+	o.set(path, strconv.FormatInt(t.Unix(), 10))
+}
+
+// addID adds ID to collection of IDs (e.g. VMS/ID).
+func (o *object) addID(path string, id int) {
+	collection := o.element(path)
+	for _, e := range collection.SelectElements("ID") {
+		if e.Text() == strconv.Itoa(id) {
+			return
+		}
+	}
+	collection.CreateElement("ID").SetText(strconv.Itoa(id))
+}
+
+// removeID removes ID from collection of IDs.
+func (o *object) removeID(path string, id int) {
+	collection := o.XML.FindElement(path)
+	if collection == nil {
+		return
+	}
+	for _, e := range collection.SelectElements("ID") {
+		if e.Text() == strconv.Itoa(id) {
+			collection.RemoveChild(e)
+		}
+	}
+}
+
+func (o *object) ids(path string) []int {
+	collection := o.XML.FindElement(path)
+	if collection == nil {
+		return nil
+	}
+
+	var ids []int
+	for _, e := range collection.SelectElements("ID") {
+		if id, err := strconv.Atoi(e.Text()); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (o *object) hasLoginToken(token string, now time.Time) bool {
+	for _, t := range o.XML.SelectElements("LOGIN_TOKEN") {
+		if t.SelectElement("TOKEN") == nil || t.SelectElement("TOKEN").Text() != token {
+			continue
+		}
+
+		expiration, err := strconv.ParseInt(t.SelectElement("EXPIRATION_TIME").Text(), 10, 64)
+		if err != nil {
+			return false
+		}
+		return expiration == -1 || expiration > now.Unix()
+	}
+	return false
+}
+
+// render renders object to XML string as returned by one.<key>.info.
+func (o *object) render() (string, error) {
+	doc := etree.NewDocument()
+	doc.SetRoot(o.XML.Copy())
+	return doc.WriteToString()
+}
+
+// renderPool renders objects to XML string as returned by one.<poolKey>.info.
+func renderPool(k *kind, objects []*object) (string, error) {
+	doc := etree.NewDocument()
+	root := doc.CreateElement(k.poolTag)
+	for _, o := range objects {
+		root.AddChild(o.XML.Copy())
+	}
+	return doc.WriteToString()
+}
+
+// parseTemplate parses template in XML format, the root element is returned.
+func parseTemplate(request, text string) (*etree.Element, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(text); err != nil || doc.Root() == nil {
+		return nil, errInternal(request, "Parse error: syntax error, unexpected $end, expecting VARIABLE "+
+			"at line 1, columns 1:1")
+	}
+	return doc.Root(), nil
+}
+
+// replaceTemplate replaces content of template element.
+func replaceTemplate(template, content *etree.Element) {
+	for _, child := range template.ChildElements() {
+		template.RemoveChild(child)
+	}
+	for _, child := range content.ChildElements() {
+		template.AddChild(child.Copy())
+	}
+}
+
+// mergeTemplate merges content to template element, attributes with the same name are replaced.
+func mergeTemplate(template, content *etree.Element) {
+	replaced := make(map[string]bool)
+	for _, child := range content.ChildElements() {
+		if !replaced[child.Tag] {
+			for _, old := range template.SelectElements(child.Tag) {
+				template.RemoveChild(old)
+			}
+			replaced[child.Tag] = true
+		}
+		template.AddChild(child.Copy())
+	}
+}
+
+// requestName returns the name of OpenNebula request used in error messages, e.g. VirtualMachineInfo.
+func requestName(k *kind, action string) string {
+	return k.request + action
+}
+
+func (s *Server) pool(k *kind) *pool {
+	return s.pools[k.key]
+}
+
+// arguments represents parameters of XML-RPC method without session string.
+type arguments struct {
+	method string
+	values []interface{}
+}
+
+func (a arguments) len() int {
+	return len(a.values)
+}
+
+func (a arguments) wrongType(i int, expected string) error {
+	return errAPI(a.method, "Parameter %d should be %s.", i+1, expected)
+}
+
+func (a arguments) int(i int) (int, error) {
+	if i >= len(a.values) {
+		return 0, errAPI(a.method, "Wrong number of parameters.")
+	}
+	v, ok := a.values[i].(int)
+	if !ok {
+		return 0, a.wrongType(i, "integer")
+	}
+	return v, nil
+}
+
+func (a arguments) string(i int) (string, error) {
+	if i >= len(a.values) {
+		return "", errAPI(a.method, "Wrong number of parameters.")
+	}
+	v, ok := a.values[i].(string)
+	if !ok {
+		return "", a.wrongType(i, "string")
+	}
+	return v, nil
+}
+
+func (a arguments) bool(i int) (bool, error) {
+	if i >= len(a.values) {
+		return false, errAPI(a.method, "Wrong number of parameters.")
+	}
+	v, ok := a.values[i].(bool)
+	if !ok {
+		return false, a.wrongType(i, "boolean")
+	}
+	return v, nil
+}
+
+func (a arguments) ints(i int) ([]int, error) {
+	if i >= len(a.values) {
+		return nil, errAPI(a.method, "Wrong number of parameters.")
+	}
+	values, ok := a.values[i].([]interface{})
+	if !ok {
+		return nil, a.wrongType(i, "array")
+	}
+	ints := make([]int, len(values))
+	for j, v := range values {
+		if ints[j], ok = v.(int); !ok {
+			return nil, a.wrongType(i, "array of integers")
+		}
+	}
+	return ints, nil
+}
+
+// optionalInt returns integer parameter or default value when the parameter is not present.
+func (a arguments) optionalInt(i, dflt int) (int, error) {
+	if i >= len(a.values) {
+		return dflt, nil
+	}
+	return a.int(i)
+}
+
+// optionalBool returns boolean parameter or default value when the parameter is not present.
+func (a arguments) optionalBool(i int, dflt bool) (bool, error) {
+	if i >= len(a.values) {
+		return dflt, nil
+	}
+	return a.bool(i)
+}
+
+// optionalString returns string parameter or default value when the parameter is not present.
+func (a arguments) optionalString(i int, dflt string) (string, error) {
+	if i >= len(a.values) {
+		return dflt, nil
+	}
+	return a.string(i)
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(format, args...)
+}
+
+// childText returns text of direct child element with given tag or empty string.
+func childText(e *etree.Element, tag string) string {
+	child := e.SelectElement(tag)
+	if child == nil {
+		return ""
+	}
+	return strings.TrimSpace(child.Text())
+}
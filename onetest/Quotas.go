@@ -0,0 +1,285 @@
+package onetest
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/beevik/etree"
+)
+
+// quotaTags are resource types with quotas in the order OpenNebula renders them.
+var quotaTags = []string{"DATASTORE", "NETWORK", "VM", "IMAGE"}
+
+// quotaLimits are limits of the quotas of given resource type.
+var quotaLimits = map[string][]string{
+	"DATASTORE": {"IMAGES", "SIZE"},
+	"NETWORK":   {"LEASES"},
+	"VM":        {"VMS", "CPU", "MEMORY", "RUNNING_VMS", "RUNNING_CPU", "RUNNING_MEMORY", "SYSTEM_DISK_SIZE"},
+	"IMAGE":     {"RVMS"},
+}
+
+// vmQuotaID stands for ID of VM quota which is not bound to any resource.
+const vmQuotaID = -1
+
+// quotaUsage contains usage of resources by type, resource ID and limit.
+type quotaUsage map[string]map[int]map[string]float64
+
+func (u quotaUsage) add(tag string, id int, limit string, value float64) {
+	if u[tag] == nil {
+		u[tag] = make(map[int]map[string]float64)
+	}
+	if u[tag][id] == nil {
+		u[tag][id] = make(map[string]float64)
+	}
+	u[tag][id][limit] += value
+}
+
+func registerQuotaMethods(s *Server) {
+	s.methods["one.user.quota"] = quotaSet(kindUser, "UserSetQuota")
+	s.methods["one.group.quota"] = quotaSet(kindGroup, "GroupSetQuota")
+	s.methods["one.userquota.info"] = defaultQuotaInfo(kindUser)
+	s.methods["one.groupquota.info"] = defaultQuotaInfo(kindGroup)
+	s.methods["one.userquota.update"] = defaultQuotaUpdate(kindUser, "UserQuotaUpdate")
+	s.methods["one.groupquota.update"] = defaultQuotaUpdate(kindGroup, "GroupQuotaUpdate")
+}
+
+// quotaSet sets quota limits of a user or group. Usage of the resources is tracked,
+// but the limits are not enforced by the server.
+func quotaSet(k *kind, request string) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		id, err := args.int(0)
+		if err != nil {
+			return nil, err
+		}
+
+		text, err := args.string(1)
+		if err != nil {
+			return nil, err
+		}
+
+		o, err := s.pool(k).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		template, err := parseTemplate(request, text)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = applyQuotaLimits(request, o.XML, template); err != nil {
+			return nil, err
+		}
+
+		return id, nil
+	}
+}
+
+func defaultQuotaInfo(k *kind) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		return renderElement(s.defaultQuotas(k))
+	}
+}
+
+func defaultQuotaUpdate(k *kind, request string) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		text, err := args.string(0)
+		if err != nil {
+			return nil, err
+		}
+
+		template, err := parseTemplate(request, text)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = applyQuotaLimits(request, s.defaultQuotas(k), template); err != nil {
+			return nil, err
+		}
+
+		return renderElement(s.defaultQuotas(k))
+	}
+}
+
+func renderElement(e *etree.Element) (string, error) {
+	doc := etree.NewDocument()
+	doc.SetRoot(e.Copy())
+	return doc.WriteToString()
+}
+
+// defaultQuotas returns element with default quotas of users or groups, e.g. DEFAULT_USER_QUOTAS.
+func (s *Server) defaultQuotas(k *kind) *etree.Element {
+	if s.defaultQuotaLimits == nil {
+		s.defaultQuotaLimits = make(map[*kind]*etree.Element)
+	}
+
+	quotas, ok := s.defaultQuotaLimits[k]
+	if !ok {
+		quotas = etree.NewElement("DEFAULT_" + k.tag + "_QUOTAS")
+		for _, tag := range quotaTags {
+			quotas.CreateElement(tag + "_QUOTA")
+		}
+		s.defaultQuotaLimits[k] = quotas
+	}
+
+	return quotas
+}
+
+// applyQuotaLimits sets limits from template to quotas element containing DATASTORE_QUOTA, NETWORK_QUOTA,
+// VM_QUOTA and IMAGE_QUOTA elements.
+func applyQuotaLimits(request string, quotas, template *etree.Element) error {
+	for _, quota := range template.ChildElements() {
+		limits, ok := quotaLimits[quota.Tag]
+		if !ok {
+			continue
+		}
+
+		id := vmQuotaID
+		if quota.Tag != "VM" {
+			var err error
+			if id, err = strconv.Atoi(childText(quota, "ID")); err != nil {
+				return errAction(request, "Error setting quota: %s quota has no ID.", quota.Tag)
+			}
+		}
+
+		entry := quotaEntry(quotas, quota.Tag, id)
+		for _, limit := range limits {
+			text := childText(quota, limit)
+			if text == "" {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil || value < -2 {
+				return errAction(request, "Error setting quota: wrong value %q of %s in %s quota.", text,
+					limit, quota.Tag)
+			}
+			entry.SelectElement(limit).SetText(formatQuotaValue(value))
+		}
+	}
+
+	return nil
+}
+
+// quotaEntry returns quota of resource with given ID, new quota with default limits is created when needed.
+func quotaEntry(quotas *etree.Element, tag string, id int) *etree.Element {
+	container := quotas.SelectElement(tag + "_QUOTA")
+	if container == nil {
+		container = quotas.CreateElement(tag + "_QUOTA")
+	}
+
+	for _, entry := range container.SelectElements(tag) {
+		if tag == "VM" || childText(entry, "ID") == itoa(id) {
+			return entry
+		}
+	}
+
+	entry := container.CreateElement(tag)
+	if tag != "VM" {
+		entry.CreateElement("ID").SetText(itoa(id))
+	}
+	for _, limit := range quotaLimits[tag] {
+		entry.CreateElement(limit).SetText("-1")
+		entry.CreateElement(limit + "_USED").SetText("0")
+	}
+
+	return entry
+}
+
+func formatQuotaValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// refreshQuotas updates usage in quotas of the user or group and its default quotas before rendering.
+func (s *Server) refreshQuotas(k *kind, o *object) {
+	usage := s.quotaUsage(k, o.ID)
+
+	for _, tag := range quotaTags {
+		o.element(tag + "_QUOTA")
+
+		ids := make([]int, 0, len(usage[tag]))
+		for id := range usage[tag] {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+
+		for _, id := range ids {
+			quotaEntry(o.XML, tag, id)
+		}
+
+		for _, entry := range o.XML.SelectElement(tag + "_QUOTA").SelectElements(tag) {
+			id := vmQuotaID
+			if tag != "VM" {
+				id, _ = strconv.Atoi(childText(entry, "ID"))
+			}
+
+			for _, limit := range quotaLimits[tag] {
+				entry.SelectElement(limit + "_USED").SetText(formatQuotaValue(usage[tag][id][limit]))
+			}
+		}
+	}
+
+	defaults := s.defaultQuotas(k).Copy()
+	if old := o.XML.SelectElement(defaults.Tag); old != nil {
+		o.XML.RemoveChild(old)
+	}
+	o.XML.AddChild(defaults)
+}
+
+// quotaUsage computes usage of resources owned by the user or group.
+func (s *Server) quotaUsage(k *kind, ownerID int) quotaUsage {
+	const stateDone = 6
+
+	owner := "UID"
+	if k == kindGroup {
+		owner = "GID"
+	}
+
+	usage := make(quotaUsage)
+
+	for _, vm := range s.pool(kindVirtualMachine).sorted() {
+		state := vm.intText("STATE")
+		if vm.intText(owner) != ownerID || state == stateDone {
+			continue
+		}
+
+		cpu, _ := strconv.ParseFloat(vm.text("TEMPLATE/CPU"), 64)
+		memory, _ := strconv.ParseFloat(vm.text("TEMPLATE/MEMORY"), 64)
+
+		usage.add("VM", vmQuotaID, "VMS", 1)
+		usage.add("VM", vmQuotaID, "CPU", cpu)
+		usage.add("VM", vmQuotaID, "MEMORY", memory)
+
+		// INIT, PENDING, HOLD and ACTIVE virtual machines count to running quotas
+		if state <= 3 {
+			usage.add("VM", vmQuotaID, "RUNNING_VMS", 1)
+			usage.add("VM", vmQuotaID, "RUNNING_CPU", cpu)
+			usage.add("VM", vmQuotaID, "RUNNING_MEMORY", memory)
+		}
+
+		for _, nic := range vm.XML.FindElements("TEMPLATE/NIC") {
+			if id, err := strconv.Atoi(childText(nic, "NETWORK_ID")); err == nil {
+				usage.add("NETWORK", id, "LEASES", 1)
+			}
+		}
+
+		for _, disk := range vm.XML.FindElements("TEMPLATE/DISK") {
+			if id, err := strconv.Atoi(childText(disk, "IMAGE_ID")); err == nil {
+				usage.add("IMAGE", id, "RVMS", 1)
+			}
+		}
+	}
+
+	for _, image := range s.pool(kindImage).sorted() {
+		if image.intText(owner) != ownerID {
+			continue
+		}
+
+		size, _ := strconv.ParseFloat(image.text("SIZE"), 64)
+
+		usage.add("DATASTORE", image.intText("DATASTORE_ID"), "IMAGES", 1)
+		usage.add("DATASTORE", image.intText("DATASTORE_ID"), "SIZE", size)
+	}
+
+	return usage
+}
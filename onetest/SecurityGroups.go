@@ -0,0 +1,146 @@
+package onetest
+
+import (
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+func registerSecurityGroupMethods(s *Server) {
+	s.methods["one.secgroup.allocate"] = securityGroupAllocate
+	s.methods["one.secgroup.clone"] = securityGroupClone
+	s.methods["one.secgroup.commit"] = securityGroupCommit
+}
+
+// refreshSecurityGroupRules marks virtual machines using the security group as outdated after its rules
+// were changed, they are updated when the changes are committed.
+func refreshSecurityGroupRules(sg *object) {
+	for _, vmID := range sg.ids("UPDATED_VMS") {
+		sg.removeID("UPDATED_VMS", vmID)
+		sg.addID("OUTDATED_VMS", vmID)
+	}
+}
+
+func newSecurityGroup(s *Server, name string, sess *session) *object {
+	sg := s.pool(kindSecurityGroup).create(name, sess)
+	sg.element("UPDATED_VMS")
+	sg.element("OUTDATED_VMS")
+	sg.element("UPDATING_VMS")
+	sg.element("ERROR_VMS")
+	sg.element("TEMPLATE")
+
+	return sg
+}
+
+func securityGroupAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "SecurityGroupAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new security group. No NAME in template.")
+	}
+
+	if other := s.findOwnedByName(kindSecurityGroup, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new security group. NAME is already taken by "+
+			"SECGROUP %d.", other.ID)
+	}
+
+	for _, rule := range template.SelectElements("RULE") {
+		if err = checkSecurityGroupRule(request, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	sg := newSecurityGroup(s, name, sess)
+	replaceTemplate(sg.element("TEMPLATE"), template)
+
+	return sg.ID, nil
+}
+
+func checkSecurityGroupRule(request string, rule *etree.Element) error {
+	protocol := childText(rule, "PROTOCOL")
+	if protocol == "" {
+		return errAllocate(request, "Error allocating a new security group. No PROTOCOL in RULE")
+	}
+
+	// OpenNebula compares protocols case-insensitively (e.g. ICMPv6)
+	switch strings.ToUpper(protocol) {
+	case "ALL", "TCP", "UDP", "ICMP", "ICMPV6", "IPSEC":
+		return nil
+	default:
+		return errAllocate(request, "Error allocating a new security group. Invalid PROTOCOL %s",
+			protocol)
+	}
+}
+
+func securityGroupClone(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "SecurityGroupClone"
+
+	source, err := s.pool(kindSecurityGroup).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if other := s.findOwnedByName(kindSecurityGroup, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new security group. NAME is already taken by "+
+			"SECGROUP %d.", other.ID)
+	}
+
+	sg := newSecurityGroup(s, name, sess)
+	replaceTemplate(sg.element("TEMPLATE"), source.element("TEMPLATE"))
+	sg.set("TEMPLATE/NAME", name)
+
+	return sg.ID, nil
+}
+
+func securityGroupCommit(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	recovery, err := args.optionalBool(1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sg, err := s.pool(kindSecurityGroup).get("SecurityGroupCommit", id)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := sg.ids("OUTDATED_VMS")
+	if recovery {
+		pending = append(pending, sg.ids("UPDATING_VMS")...)
+		pending = append(pending, sg.ids("ERROR_VMS")...)
+	}
+
+	for _, vmID := range pending {
+		sg.removeID("OUTDATED_VMS", vmID)
+		sg.removeID("UPDATING_VMS", vmID)
+		sg.removeID("ERROR_VMS", vmID)
+		sg.addID("UPDATED_VMS", vmID)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,195 @@
+// Package onetest provides an in-memory OpenNebula XML-RPC server for testing code built on onego.
+//
+// The server keeps the state of users, groups, images, virtual machines, virtual networks, templates
+// and other resources in memory and answers the one.* methods called by onego the way OpenNebula does,
+// so it can be used with onego.CreateClient without recordings or a running OpenNebula frontend.
+package onetest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+)
+
+// ServerName is sent in Server header of the responses, so recordings of the server can't be mistaken
+// for recordings of OpenNebula.
+const ServerName = "onego-onetest"
+
+// Server is an in-memory OpenNebula XML-RPC server.
+type Server struct {
+	// URL of the XML-RPC endpoint, e.g. http://127.0.0.1:36471/RPC2
+	URL string
+
+	httpServer *httptest.Server
+
+	mu      sync.Mutex
+	pools   map[string]*pool
+	methods map[string]method
+	now     func() time.Time
+
+	acls      []*resources.ACLRule
+	nextACLID int
+
+	defaultQuotaLimits map[*kind]*etree.Element
+	vmMonitoring       map[int][]*etree.Element
+	hostMonitoring     map[int][]*etree.Element
+
+	hookRunner HookRunner
+	hookLog    []*hookExecution
+}
+
+// method handles one XML-RPC method. It returns the value placed to the result index of the response.
+type method func(s *Server, session *session, args arguments) (interface{}, error)
+
+// session represents the caller of XML-RPC method.
+type session struct {
+	UserID    int
+	GroupID   int
+	UserName  string
+	GroupName string
+}
+
+// NewServer starts a new in-memory OpenNebula server.
+// The server is prepared with oneadmin user and group, default cluster, datastores and security group,
+// the same way as a fresh OpenNebula installation is. The caller should call Close when finished.
+func NewServer() *Server {
+	s := newServer()
+
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL + "/RPC2"
+	s.pool(kindZone).objects[zoneID].set("TEMPLATE/ENDPOINT", s.URL)
+
+	return s
+}
+
+func newServer() *Server {
+	s := &Server{pools: make(map[string]*pool), methods: make(map[string]method), now: time.Now}
+
+	for _, k := range kinds {
+		s.pools[k.key] = &pool{kind: k, objects: make(map[int]*object)}
+	}
+
+	registerMethods(s)
+	s.bootstrap()
+
+	return s
+}
+
+// SetClock sets the function the server uses to get the current time (e.g. for registration times,
+// history records and login tokens), time.Now is used by default.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// ServeHTTP handles XML-RPC request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	call, err := decodeMethodCall(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := encodeResponse(s.dispatch(call))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.Header().Set("Server", ServerName)
+	w.Write(response) //nolint
+}
+
+// dispatch calls the method and returns values of the XML-RPC response array.
+func (s *Server) dispatch(call *methodCall) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.methods[call.Name]
+	if !ok {
+		return failure(&Error{Code: errors.CodeXMLRPCAPI, Message: "[" + call.Name + "] Method not supported."})
+	}
+
+	if len(call.Params) == 0 {
+		return failure(&Error{Code: errors.CodeXMLRPCAPI, Message: "[" + call.Name + "] Missing session string."})
+	}
+
+	sess, err := s.authenticate(call.Name, call.Params[0])
+	if err != nil {
+		return failure(err)
+	}
+
+	result, err := m(s, sess, arguments{method: call.Name, values: call.Params[1:]})
+	s.runAPIHooks(call.Name, call.Params[1:], err == nil)
+	if err != nil {
+		return failure(err)
+	}
+
+	return []interface{}{true, result, 0}
+}
+
+func failure(err error) []interface{} {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Code: errors.CodeInternal, Message: err.Error()}
+	}
+
+	if e.ObjectID != nil {
+		return []interface{}{false, e.Message, e.Code, *e.ObjectID}
+	}
+	return []interface{}{false, e.Message, e.Code}
+}
+
+// authenticate checks the session string (username:password or username:token).
+func (s *Server) authenticate(methodName string, sessionValue interface{}) (*session, error) {
+	sessionString, _ := sessionValue.(string)
+	parts := strings.SplitN(sessionString, ":", 2)
+
+	authError := &Error{Code: errors.CodeAuthentication,
+		Message: "[" + methodName + "] User couldn't be authenticated, aborting call."}
+
+	if len(parts) != 2 {
+		return nil, authError
+	}
+
+	for _, u := range s.pools[kindUser.key].sorted() {
+		if u.text("NAME") != parts[0] {
+			continue
+		}
+
+		if u.text("PASSWORD") != parts[1] && !u.hasLoginToken(parts[1], s.now()) {
+			return nil, authError
+		}
+
+		return &session{UserID: u.ID, GroupID: u.intText("GID"), UserName: u.text("NAME"),
+			GroupName: u.text("GNAME")}, nil
+	}
+
+	return nil, authError
+}
@@ -0,0 +1,727 @@
+package onetest_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/onetest"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onego-project/xmlrpc"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Server", func() {
+	var (
+		server *onetest.Server
+		client *onego.Client
+		err    error
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = onetest.NewServer()
+		client = onego.CreateClient(server.URL, onetest.AdminToken, &http.Client{})
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.Describe("fresh installation", func() {
+		ginkgo.It("should contain oneadmin user", func() {
+			var user *resources.User
+			user, err = client.UserService.RetrieveConnectedUserInfo(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var name string
+			name, err = user.Name()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(name).To(gomega.Equal(onetest.AdminName))
+		})
+
+		ginkgo.It("should contain default datastores", func() {
+			var datastores []*resources.Datastore
+			datastores, err = client.DatastoreService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(datastores).To(gomega.HaveLen(3))
+		})
+
+		ginkgo.It("should contain default ACL rules", func() {
+			var rules []*resources.ACLRule
+			rules, err = client.ACLService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(rules).To(gomega.HaveLen(3))
+			gomega.Expect(rules[2].String()).To(gomega.Equal("* MARKETPLACE+MARKETPLACEAPP/* USE *"))
+		})
+	})
+
+	ginkgo.Describe("responses", func() {
+		ginkgo.It("should identify the server in Server header", func() {
+			request := "<?xml version=\"1.0\"?><methodCall><methodName>one.user.info</methodName><params>" +
+				"<param><value><string>" + onetest.AdminToken + "</string></value></param>" +
+				"<param><value><int>0</int></value></param></params></methodCall>"
+
+			var response *http.Response
+			response, err = http.Post(server.URL, "text/xml", strings.NewReader(request))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			defer response.Body.Close() //nolint
+
+			gomega.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+			gomega.Expect(response.Header.Get("Server")).To(gomega.Equal(onetest.ServerName))
+		})
+	})
+
+	ginkgo.Describe("authentication", func() {
+		ginkgo.It("should refuse wrong password", func() {
+			client = onego.CreateClient(server.URL, onetest.AdminName+":wrong", &http.Client{})
+
+			_, err = client.UserService.RetrieveConnectedUserInfo(context.TODO())
+			gomega.Expect(errors.IsAuthentication(err)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should accept new user", func() {
+			_, err = client.UserService.Allocate(context.TODO(), "alice", "secret", "core",
+				*resources.CreateGroupWithID(1), nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			client = onego.CreateClient(server.URL, "alice:secret", &http.Client{})
+
+			var user *resources.User
+			user, err = client.UserService.RetrieveConnectedUserInfo(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var name string
+			name, err = user.Name()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(name).To(gomega.Equal("alice"))
+		})
+	})
+
+	ginkgo.Describe("missing objects", func() {
+		ginkgo.It("should return that object doesn't exist", func() {
+			_, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 1000)
+			gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+
+			_, err = client.ImageService.RetrieveInfo(context.TODO(), 1000)
+			gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("virtual machine lifecycle", func() {
+		var (
+			host           *resources.Host
+			virtualMachine *resources.VirtualMachine
+		)
+
+		ginkgo.BeforeEach(func() {
+			host, err = client.HostService.Allocate(context.TODO(), "node1", "kvm", "kvm",
+				*resources.CreateClusterWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			vmBlueprint := blueprint.CreateAllocateVirtualMachineBlueprint()
+			vmBlueprint.SetName("web")
+			vmBlueprint.SetCPU(1)
+			vmBlueprint.SetMemory(1024)
+
+			virtualMachine, err = client.VirtualMachineService.Allocate(context.TODO(), vmBlueprint, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should keep state of the virtual machine", func() {
+			var state resources.VirtualMachineState
+			state, err = virtualMachine.State()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(state).To(gomega.Equal(resources.VirtualMachineStatePending))
+
+			err = client.VirtualMachineService.Deploy(context.TODO(), *virtualMachine, *host, false,
+				*resources.CreateDatastoreWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			virtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(context.TODO(),
+				*virtualMachine, resources.VirtualMachineStateActive, resources.VirtualMachineRunning,
+				services.Backoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = client.VirtualMachineService.PowerOff(context.TODO(), *virtualMachine, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var id int
+			id, err = virtualMachine.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			virtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), id)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			state, err = virtualMachine.State()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(state).To(gomega.Equal(resources.VirtualMachineStatePowerOff))
+		})
+
+		ginkgo.It("should report added monitoring samples", func() {
+			var vmID int
+			vmID, err = virtualMachine.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = server.AddVirtualMachineMonitoring(resources.VirtualMachineMonitoring{VMID: vmID, CPU: 30,
+				Memory: 1024, NetRX: 4096})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			virtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), vmID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var sample *resources.VirtualMachineMonitoring
+			sample, err = virtualMachine.Monitoring()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(sample.NetRX).To(gomega.Equal(int64(4096)))
+
+			var samples []*resources.VirtualMachineMonitoring
+			samples, err = client.VirtualMachineService.Monitoring(context.TODO(), *virtualMachine)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(samples).To(gomega.HaveLen(1))
+
+			err = server.AddVirtualMachineMonitoring(resources.VirtualMachineMonitoring{VMID: 1000})
+			gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should report host monitoring and capacity allocated by virtual machine", func() {
+			err = client.VirtualMachineService.Deploy(context.TODO(), *virtualMachine, *host, false,
+				*resources.CreateDatastoreWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var hostID int
+			hostID, err = host.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = server.AddHostMonitoring(resources.HostMonitoring{HostID: hostID, UsedCPU: 50, FreeCPU: 750})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var samples []*resources.HostMonitoring
+			samples, err = client.HostService.Monitoring(context.TODO(), *host)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(samples).To(gomega.HaveLen(1))
+			gomega.Expect(samples[0].UsedCPU).To(gomega.Equal(50))
+			gomega.Expect(samples[0].CPUUsage).To(gomega.Equal(100))
+			gomega.Expect(samples[0].MemoryUsage).To(gomega.Equal(1048576))
+			gomega.Expect(samples[0].RunningVMs).To(gomega.Equal(1))
+
+			var capacity *resources.ClusterCapacity
+			capacity, err = client.ClusterService.Capacity(context.TODO(), *resources.CreateClusterWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(capacity.AllocatedCPU).To(gomega.Equal(100))
+			gomega.Expect(capacity.UsedCPU).To(gomega.Equal(50))
+
+			err = server.AddHostMonitoring(resources.HostMonitoring{HostID: 1000})
+			gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("shouldn't allow action in wrong state", func() {
+			err = client.VirtualMachineService.Suspend(context.TODO(), *virtualMachine)
+			gomega.Expect(errors.IsActionNotAllowed(err)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("virtual network leases", func() {
+		ginkgo.It("should lease address to virtual machine NIC", func() {
+			vnBlueprint := blueprint.CreateAllocateVirtualNetworkBlueprint()
+			vnBlueprint.SetName("private")
+			vnBlueprint.SetBridge("br0")
+			vnBlueprint.SetVnMad("bridge")
+
+			var virtualNetwork *resources.VirtualNetwork
+			virtualNetwork, err = client.VirtualNetworkService.Allocate(context.TODO(), vnBlueprint,
+				*resources.CreateClusterWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			size := 10
+			_, err = client.AddressRangeService.Add(context.TODO(), *virtualNetwork,
+				resources.AddressRange{Type: "IP4", IP: net.ParseIP("10.0.0.1"), Size: &size})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var vnID int
+			vnID, err = virtualNetwork.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			nicBlueprint := blueprint.CreateNICBlueprint()
+			nicBlueprint.SetNetworkID(vnID)
+
+			vmBlueprint := blueprint.CreateAllocateVirtualMachineBlueprint()
+			vmBlueprint.SetName("db")
+			vmBlueprint.SetCPU(1)
+			vmBlueprint.SetMemory(1024)
+			vmBlueprint.SetNIC(*nicBlueprint)
+
+			var virtualMachine *resources.VirtualMachine
+			virtualMachine, err = client.VirtualMachineService.Allocate(context.TODO(), vmBlueprint, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var nics []*resources.NIC
+			nics, err = virtualMachine.NICs()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(nics).To(gomega.HaveLen(1))
+			gomega.Expect(nics[0].IP.String()).To(gomega.Equal("10.0.0.1"))
+
+			virtualNetwork, err = client.VirtualNetworkService.RetrieveInfo(context.TODO(), vnID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var usedLeases int
+			usedLeases, err = virtualNetwork.UsedLeases()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usedLeases).To(gomega.Equal(1))
+		})
+	})
+
+	ginkgo.Describe("templates", func() {
+		ginkgo.It("should instantiate virtual machine from template", func() {
+			templateBlueprint := blueprint.CreateAllocateTemplateBlueprint()
+			templateBlueprint.SetName("small")
+			templateBlueprint.SetCPU(1)
+			templateBlueprint.SetMemory(512)
+
+			var template *resources.Template
+			template, err = client.TemplateService.Allocate(context.TODO(), templateBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var virtualMachine *resources.VirtualMachine
+			virtualMachine, err = client.TemplateService.Instantiate(context.TODO(), *template, "small-1", false,
+				blueprint.CreateUpdateTemplateBlueprint(), false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var memory int
+			memory, err = virtualMachine.Memory()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(memory).To(gomega.Equal(512))
+		})
+	})
+
+	ginkgo.Describe("quotas", func() {
+		ginkgo.It("should track usage of virtual machine resources", func() {
+			templateBlueprint := blueprint.CreateAllocateTemplateBlueprint()
+			templateBlueprint.SetName("quota")
+			templateBlueprint.SetCPU(2)
+			templateBlueprint.SetMemory(256)
+
+			var template *resources.Template
+			template, err = client.TemplateService.Allocate(context.TODO(), templateBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = client.TemplateService.Instantiate(context.TODO(), *template, "quota-1", false,
+				blueprint.CreateUpdateTemplateBlueprint(), false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			quotaBlueprint := blueprint.CreateQuotaBlueprint()
+			quotaBlueprint.SetCPU(4)
+
+			var user *resources.User
+			user, err = client.UserService.SetQuota(context.TODO(), *resources.CreateUserWithID(0), quotaBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var quotas *resources.Quotas
+			quotas, err = user.Quotas()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(quotas.VM.CPU).To(gomega.Equal(float64(4)))
+			gomega.Expect(quotas.VM.CPUUsed).To(gomega.Equal(float64(2)))
+			gomega.Expect(quotas.VM.MemoryUsed).To(gomega.Equal(256))
+			gomega.Expect(quotas.VM.VMs).To(gomega.Equal(resources.QuotaDefault))
+		})
+	})
+
+	ginkgo.Describe("VM groups", func() {
+		ginkgo.It("should place virtual machine to role and honour the lock", func() {
+			roleBlueprint := blueprint.CreateVMGroupRoleBlueprint()
+			roleBlueprint.SetName("replica")
+			roleBlueprint.SetPolicy(resources.VMGroupPolicyAntiAffined)
+
+			vmGroupBlueprint := blueprint.CreateAllocateVMGroupBlueprint()
+			vmGroupBlueprint.SetName("replicas")
+			vmGroupBlueprint.AddRole(*roleBlueprint)
+
+			var vmGroup *resources.VMGroup
+			vmGroup, err = client.VMGroupService.Allocate(context.TODO(), vmGroupBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var vmGroupID int
+			vmGroupID, err = vmGroup.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			vmBlueprint := blueprint.CreateAllocateVirtualMachineBlueprint()
+			vmBlueprint.SetName("replica-1")
+			vmBlueprint.SetCPU(1)
+			vmBlueprint.SetMemory(512)
+			err = vmBlueprint.SetVMGroup(*vmGroup, "replica")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var virtualMachine *resources.VirtualMachine
+			virtualMachine, err = client.VirtualMachineService.Allocate(context.TODO(), vmBlueprint, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			vmGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), vmGroupID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var roles []*resources.VMGroupRole
+			roles, err = vmGroup.Roles()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(roles).To(gomega.HaveLen(1))
+
+			var vmID int
+			vmID, err = virtualMachine.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(roles[0].VirtualMachines).To(gomega.Equal([]int{vmID}))
+
+			err = client.VMGroupService.Lock(context.TODO(), *vmGroup, resources.LockManage)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = client.VMGroupService.Rename(context.TODO(), *vmGroup, "renamed")
+			gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("virtual routers", func() {
+		ginkgo.It("should release leases of virtual router and its virtual machines when deleted", func() {
+			vnBlueprint := blueprint.CreateAllocateVirtualNetworkBlueprint()
+			vnBlueprint.SetName("public")
+			vnBlueprint.SetBridge("br0")
+			vnBlueprint.SetVnMad("bridge")
+
+			var virtualNetwork *resources.VirtualNetwork
+			virtualNetwork, err = client.VirtualNetworkService.Allocate(context.TODO(), vnBlueprint,
+				*resources.CreateClusterWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			size := 10
+			_, err = client.AddressRangeService.Add(context.TODO(), *virtualNetwork,
+				resources.AddressRange{Type: "IP4", IP: net.ParseIP("192.168.0.1"), Size: &size})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var vnID int
+			vnID, err = virtualNetwork.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			nicBlueprint := blueprint.CreateNICBlueprint()
+			nicBlueprint.SetNetworkID(vnID)
+			nicBlueprint.SetFloatingIP(true)
+
+			vrBlueprint := blueprint.CreateAllocateVirtualRouterBlueprint()
+			vrBlueprint.SetName("gateway")
+			vrBlueprint.SetNIC(*nicBlueprint)
+
+			var virtualRouter *resources.VirtualRouter
+			virtualRouter, err = client.VirtualRouterService.Allocate(context.TODO(), vrBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			templateBlueprint := blueprint.CreateAllocateTemplateBlueprint()
+			templateBlueprint.SetName("router")
+			templateBlueprint.SetCPU(1)
+			templateBlueprint.SetMemory(256)
+
+			var template *resources.Template
+			template, err = client.TemplateService.Allocate(context.TODO(), templateBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			virtualRouter, err = client.VirtualRouterService.Instantiate(context.TODO(), *virtualRouter, 2, *template,
+				"", false, blueprint.CreateUpdateTemplateBlueprint())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			virtualNetwork, err = client.VirtualNetworkService.RetrieveInfo(context.TODO(), vnID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualNetwork.UsedLeases()).To(gomega.Equal(3))
+
+			err = client.VirtualRouterService.Delete(context.TODO(), *virtualRouter)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			virtualNetwork, err = client.VirtualNetworkService.RetrieveInfo(context.TODO(), vnID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualNetwork.UsedLeases()).To(gomega.Equal(0))
+			gomega.Expect(virtualNetwork.VirtualRouters()).To(gomega.HaveLen(0))
+		})
+	})
+
+	ginkgo.Describe("zones", func() {
+		ginkgo.It("should serve one client per zone of the federation", func() {
+			remote := onetest.NewServer()
+			defer remote.Close()
+
+			zoneBlueprint := blueprint.CreateAllocateZoneBlueprint()
+			zoneBlueprint.SetName("remote")
+			zoneBlueprint.SetEndpoint(remote.URL)
+
+			_, err = client.ZoneService.Allocate(context.TODO(), zoneBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			federation := onego.CreateFederatedClient(server.URL, onetest.AdminToken, &http.Client{})
+
+			var clients map[int]*onego.Client
+			clients, err = federation.Clients(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(clients).To(gomega.HaveLen(2))
+			gomega.Expect(clients[0]).To(gomega.BeIdenticalTo(federation.Client))
+
+			_, err = clients[100].GroupService.Allocate(context.TODO(), "remote-only")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			groups := make(map[int]int)
+			err = federation.ForEachZone(context.TODO(), func(zone *resources.Zone, zoneClient *onego.Client) error {
+				zoneID, err := zone.ID()
+				if err != nil {
+					return err
+				}
+
+				list, err := zoneClient.GroupService.List(context.TODO())
+				groups[zoneID] = len(list)
+				return err
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(groups).To(gomega.Equal(map[int]int{0: 2, 100: 3}))
+
+			var zoneClient *onego.Client
+			zoneClient, err = federation.ZoneClient(context.TODO(), *resources.CreateZoneWithID(100))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(zoneClient).To(gomega.BeIdenticalTo(clients[100]))
+		})
+
+		ginkgo.It("should use default http client when none is given", func() {
+			remote := onetest.NewServer()
+			defer remote.Close()
+
+			zoneBlueprint := blueprint.CreateAllocateZoneBlueprint()
+			zoneBlueprint.SetName("remote")
+			zoneBlueprint.SetEndpoint(remote.URL)
+
+			_, err = client.ZoneService.Allocate(context.TODO(), zoneBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			federation := onego.CreateFederatedClient(server.URL, onetest.AdminToken, nil)
+
+			var zoneClient *onego.Client
+			zoneClient, err = federation.ZoneClient(context.TODO(), *resources.CreateZoneWithID(100))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = zoneClient.GroupService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should use options of the federated client for all zones", func() {
+			remote := onetest.NewServer()
+			defer remote.Close()
+
+			zoneBlueprint := blueprint.CreateAllocateZoneBlueprint()
+			zoneBlueprint.SetName("remote")
+			zoneBlueprint.SetEndpoint(remote.URL)
+
+			_, err = client.ZoneService.Allocate(context.TODO(), zoneBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var methods []string
+			federation := onego.CreateFederatedClientWithOptions(server.URL, onetest.AdminToken,
+				onego.WithHTTPClient(&http.Client{}), onego.WithInterceptors(func(ctx context.Context,
+					methodName string, args []interface{}, invoker services.Invoker) ([]*xmlrpc.Result, error) {
+					methods = append(methods, methodName)
+					return invoker(ctx, methodName, args)
+				}))
+
+			var zoneClient *onego.Client
+			zoneClient, err = federation.ZoneClient(context.TODO(), *resources.CreateZoneWithID(100))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = zoneClient.GroupService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(methods).To(gomega.Equal([]string{"one.zone.info", "one.grouppool.info"}))
+		})
+	})
+
+	ginkgo.Describe("marketplaces", func() {
+		ginkgo.It("should export image to marketplace and import it back", func() {
+			marketplaceBlueprint := blueprint.CreateAllocateMarketplaceBlueprint()
+			marketplaceBlueprint.SetName("golden")
+			marketplaceBlueprint.SetMarketMad("http")
+			marketplaceBlueprint.SetBaseURL("http://market.example.com/")
+
+			var marketplace *resources.Marketplace
+			marketplace, err = client.MarketplaceService.Allocate(context.TODO(), marketplaceBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			imageBlueprint := blueprint.CreateAllocateImageBlueprint()
+			imageBlueprint.SetName("debian")
+			imageBlueprint.SetElement("SIZE", "2048")
+
+			var image *resources.Image
+			image, err = client.ImageService.Allocate(context.TODO(), imageBlueprint,
+				*resources.CreateDatastoreWithID(1))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var app *resources.MarketplaceApp
+			app, err = client.MarketplaceAppService.ExportImage(context.TODO(), *image, *marketplace, "debian")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(app.Size()).To(gomega.Equal(2048))
+
+			image, err = client.ImageService.RetrieveInfo(context.TODO(), 0)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(image.AppClones()).To(gomega.Equal([]int{0}))
+
+			marketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), 100)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(marketplace.UsedMB()).To(gomega.Equal(2048))
+
+			err = client.MarketplaceService.Rename(context.TODO(), *marketplace, "gold")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var imported *resources.Image
+			imported, err = client.MarketplaceAppService.Import(context.TODO(), *app,
+				*resources.CreateDatastoreWithID(1), "debian-imported")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(imported.Size()).To(gomega.Equal(2048))
+			gomega.Expect(imported.Path()).To(gomega.Equal("http://market.example.com/0"))
+
+			app, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(), 0)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(app.MarketplaceName()).To(gomega.Equal("gold"))
+
+			err = client.MarketplaceService.Delete(context.TODO(), *marketplace)
+			gomega.Expect(errors.IsActionNotAllowed(err)).To(gomega.BeTrue())
+
+			err = client.MarketplaceAppService.Delete(context.TODO(), *app)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			image, err = client.ImageService.RetrieveInfo(context.TODO(), 0)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(image.AppClones()).To(gomega.BeEmpty())
+
+			err = client.MarketplaceService.Delete(context.TODO(), *marketplace)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Describe("documents", func() {
+		ginkgo.It("should keep documents of different types apart", func() {
+			var document *resources.Document
+			document, err = client.DocumentService(1000).AllocateObject(context.TODO(), "web-app",
+				map[string]int{"replicas": 3})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(document.Type()).To(gomega.Equal(1000))
+
+			_, err = client.DocumentService(1001).AllocateObject(context.TODO(), "web-app",
+				map[string]int{"replicas": 1})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var clone *resources.Document
+			clone, err = client.DocumentService(1000).Clone(context.TODO(), *document, "web-app-copy")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var cloneID int
+			cloneID, err = clone.ID()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			replicas := map[string]int{}
+			_, err = client.DocumentService(1000).RetrieveObject(context.TODO(), cloneID, &replicas)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(replicas["replicas"]).To(gomega.Equal(3))
+
+			var documents []*resources.Document
+			documents, err = client.DocumentService(1000).ListAll(context.TODO(), services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(documents).To(gomega.HaveLen(2))
+
+			err = client.DocumentService(1000).Lock(context.TODO(), *document, resources.LockAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = client.DocumentService(1000).Delete(context.TODO(), *document)
+			gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("hooks", func() {
+		ginkgo.It("should run API and state hooks and keep their log", func() {
+			var commands []string
+			server.SetHookRunner(func(command, arguments string) (int, string, string) {
+				commands = append(commands, command)
+				if command == "host.sh" {
+					return 2, "", "host is not reachable"
+				}
+				return 0, "done", ""
+			})
+
+			hookBlueprint := blueprint.CreateAllocateHookBlueprint()
+			hookBlueprint.SetName("host-added")
+			hookBlueprint.SetCommand("host.sh")
+			hookBlueprint.SetArguments("$API")
+			hookBlueprint.SetAPIHook(resources.APIHook{Call: "one.host.allocate"})
+
+			var apiHook *resources.Hook
+			apiHook, err = client.HookService.Allocate(context.TODO(), hookBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			hookBlueprint = blueprint.CreateAllocateHookBlueprint()
+			hookBlueprint.SetName("vm-running")
+			hookBlueprint.SetCommand("running.sh")
+			hookBlueprint.SetStateHook(resources.StateHook{Resource: "VM", On: "RUNNING"})
+
+			_, err = client.HookService.Allocate(context.TODO(), hookBlueprint)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var host *resources.Host
+			host, err = client.HostService.Allocate(context.TODO(), "node1", "kvm", "kvm",
+				*resources.CreateClusterWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			vmBlueprint := blueprint.CreateAllocateVirtualMachineBlueprint()
+			vmBlueprint.SetName("web")
+			vmBlueprint.SetCPU(1)
+			vmBlueprint.SetMemory(1024)
+
+			var virtualMachine *resources.VirtualMachine
+			virtualMachine, err = client.VirtualMachineService.Allocate(context.TODO(), vmBlueprint, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = client.VirtualMachineService.Deploy(context.TODO(), *virtualMachine, *host, false,
+				*resources.CreateDatastoreWithID(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(commands).To(gomega.Equal([]string{"host.sh", "running.sh"}))
+
+			var records []*resources.HookExecutionRecord
+			records, err = client.HookService.LogForHook(context.TODO(), *apiHook, time.Time{}, time.Time{},
+				services.HookExecutionFilterError)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(records).To(gomega.HaveLen(1))
+			gomega.Expect(records[0].ExitCode).To(gomega.Equal(2))
+			gomega.Expect(records[0].Stderr).To(gomega.Equal("host is not reachable"))
+			gomega.Expect(records[0].Arguments).NotTo(gomega.Equal("$API"))
+
+			err = client.HookService.Retry(context.TODO(), *apiHook, records[0].ExecutionID)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			records, err = client.HookService.Log(context.TODO(), time.Time{}, time.Time{},
+				services.HookExecutionFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(records).To(gomega.HaveLen(3))
+			gomega.Expect(records[1].Stdout).To(gomega.Equal("done"))
+			gomega.Expect(records[2].Retry).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("clock", func() {
+		ginkgo.It("should use given clock for registration time", func() {
+			server.SetClock(func() time.Time { return time.Unix(1546300800, 0) })
+
+			imageBlueprint := blueprint.CreateAllocateImageBlueprint()
+			imageBlueprint.SetName("debian")
+			imageBlueprint.SetElement("SIZE", "1024")
+			imageBlueprint.SetElement("TYPE", "OS")
+
+			var image *resources.Image
+			image, err = client.ImageService.Allocate(context.TODO(), imageBlueprint,
+				*resources.CreateDatastoreWithID(1))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var registrationTime *time.Time
+			registrationTime, err = image.RegistrationTime()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(registrationTime.Unix()).To(gomega.Equal(int64(1546300800)))
+		})
+	})
+})
@@ -0,0 +1,144 @@
+package onetest
+
+import "strconv"
+
+func registerTemplateMethods(s *Server) {
+	s.methods["one.template.allocate"] = templateAllocate
+	s.methods["one.template.clone"] = templateClone
+	s.methods["one.template.instantiate"] = templateInstantiate
+}
+
+func templateAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "TemplateAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new virtual machine template. No NAME in template.")
+	}
+
+	if other := s.findOwnedByName(kindTemplate, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new virtual machine template. NAME is already "+
+			"taken by TEMPLATE %d.", other.ID)
+	}
+
+	t := s.pool(kindTemplate).create(name, sess)
+	t.setTime("REGTIME", s.now())
+	replaceTemplate(t.element("TEMPLATE"), template)
+
+	return t.ID, nil
+}
+
+func templateClone(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	recursive, err := args.optionalBool(2, false)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "TemplateClone"
+
+	source, err := s.pool(kindTemplate).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if other := s.findOwnedByName(kindTemplate, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new virtual machine template. NAME is already "+
+			"taken by TEMPLATE %d.", other.ID)
+	}
+
+	t := s.pool(kindTemplate).create(name, sess)
+	t.setTime("REGTIME", s.now())
+	replaceTemplate(t.element("TEMPLATE"), source.element("TEMPLATE"))
+	t.set("TEMPLATE/NAME", name)
+
+	if !recursive {
+		return t.ID, nil
+	}
+
+	// images used by the template are cloned too
+	for i, disk := range t.element("TEMPLATE").SelectElements("DISK") {
+		imageID := childText(disk, "IMAGE_ID")
+		if imageID == "" {
+			continue
+		}
+
+		cloneArgs := arguments{method: args.method, values: []interface{}{0, sprintf("%s-disk-%d", name, i)}}
+		if cloneArgs.values[0], err = strconv.Atoi(imageID); err != nil {
+			return nil, errInternal(request, "Wrong IMAGE_ID %s", imageID)
+		}
+
+		cloneID, err := imageClone(s, sess, cloneArgs)
+		if err != nil {
+			return nil, err
+		}
+		disk.SelectElement("IMAGE_ID").SetText(itoa(cloneID.(int)))
+	}
+
+	return t.ID, nil
+}
+
+func templateInstantiate(s *Server, sess *session, args arguments) (interface{}, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.optionalString(1, "")
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := args.optionalBool(2, false)
+	if err != nil {
+		return nil, err
+	}
+
+	extra, err := args.optionalString(3, "")
+	if err != nil {
+		return nil, err
+	}
+
+	request := "TemplateInstantiate"
+
+	t, err := s.pool(kindTemplate).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	template := t.element("TEMPLATE").Copy()
+	if extra != "" {
+		content, err := parseTemplate(request, extra)
+		if err != nil {
+			return nil, err
+		}
+		mergeTemplate(template, content)
+	}
+
+	if name == "" {
+		name = childText(template, "NAME")
+	}
+
+	template.CreateElement("TEMPLATE_ID").SetText(itoa(id))
+
+	return s.createVM(request, sess, template, hold, name)
+}
@@ -0,0 +1,411 @@
+package onetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// AdminName is the name of the administrator account of the server.
+const AdminName = "oneadmin"
+
+// AdminPassword is the password of the administrator account of the server.
+const AdminPassword = "opennebula"
+
+// AdminToken is the session string of the administrator account usable with onego.CreateClient.
+const AdminToken = AdminName + ":" + AdminPassword
+
+// bootstrap creates the resources existing in a fresh OpenNebula installation.
+func (s *Server) bootstrap() {
+	admin := &session{UserID: 0, GroupID: 0, UserName: AdminName, GroupName: AdminName}
+
+	for id, name := range []string{AdminName, "users"} {
+		g := newObject(kindGroup, id, name, admin)
+		g.element("TEMPLATE")
+		g.element("USERS")
+		g.element("ADMINS")
+		s.pool(kindGroup).add(g)
+	}
+
+	for id, name := range []string{AdminName, "serveradmin"} {
+		u := s.newUser(id, name, AdminPassword, "core", 0)
+		s.pool(kindUser).add(u)
+	}
+
+	c := newObject(kindCluster, 0, "default", admin)
+	c.element("HOSTS")
+	c.element("DATASTORES")
+	c.element("VNETS")
+	c.element("TEMPLATE")
+	s.pool(kindCluster).add(c)
+
+	for id, name := range []string{"system", "default", "files"} {
+		dsType := map[int]int{0: 1, 1: 0, 2: 2}[id]
+		s.pool(kindDatastore).add(s.newDatastore(id, name, dsType, admin))
+		c.addID("DATASTORES", id)
+	}
+
+	sg := newObject(kindSecurityGroup, 0, "default", admin)
+	sg.element("UPDATED_VMS")
+	sg.element("OUTDATED_VMS")
+	sg.element("UPDATING_VMS")
+	sg.element("ERROR_VMS")
+	sg.set("TEMPLATE/DESCRIPTION", "The default security group is added to every network. Use it to add "+
+		"default filter rules for your networks. You may remove this security group from any network by "+
+		"updating its properties.")
+	for _, ruleType := range []string{"outbound", "inbound"} {
+		rule := sg.element("TEMPLATE").CreateElement("RULE")
+		rule.CreateElement("PROTOCOL").SetText("ALL")
+		rule.CreateElement("RULE_TYPE").SetText(ruleType)
+	}
+	s.pool(kindSecurityGroup).add(sg)
+
+	z := newObject(kindZone, zoneID, "OpenNebula", admin)
+	z.set("TEMPLATE/ENDPOINT", defaultEndpoint)
+	z.element("SERVER_POOL")
+	s.pool(kindZone).add(z)
+
+	s.bootstrapACL()
+}
+
+func (s *Server) newUser(id int, name, password, authDriver string, groupID int) *object {
+	group := s.pool(kindGroup).objects[groupID]
+
+	u := newObject(kindUser, id, name, nil)
+	u.XML.RemoveChild(u.XML.SelectElement("NAME"))
+	u.setInt("GID", groupID)
+	u.addID("GROUPS", groupID)
+	u.set("GNAME", group.text("NAME"))
+	u.set("NAME", name)
+	u.set("PASSWORD", password)
+	u.set("AUTH_DRIVER", authDriver)
+	u.set("ENABLED", "1")
+	u.element("TEMPLATE")
+
+	group.addID("USERS", id)
+
+	return u
+}
+
+func registerUserMethods(s *Server) {
+	s.methods["one.user.allocate"] = userAllocate
+	s.methods["one.user.passwd"] = userPasswd
+	s.methods["one.user.chauth"] = userChauth
+	s.methods["one.user.chgrp"] = userChgrp
+	s.methods["one.user.addgroup"] = userAddGroup
+	s.methods["one.user.delgroup"] = userDelGroup
+	s.methods["one.user.login"] = userLogin
+}
+
+func registerGroupMethods(s *Server) {
+	s.methods["one.group.allocate"] = groupAllocate
+	s.methods["one.group.addadmin"] = groupAddAdmin
+	s.methods["one.group.deladmin"] = groupDelAdmin
+}
+
+func userAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	name, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	authDriver, err := args.optionalString(2, "core")
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []int{sess.GroupID}
+	if args.len() > 3 {
+		if groups, err = args.ints(3); err != nil {
+			return nil, err
+		}
+	}
+
+	request := "UserAllocate"
+
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new user. Invalid NAME, it cannot be empty.")
+	}
+
+	if other := s.pool(kindUser).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new user. NAME is already taken by USER %d.",
+			other.ID)
+	}
+
+	for _, g := range groups {
+		if _, err = s.pool(kindGroup).get(request, g); err != nil {
+			return nil, err
+		}
+	}
+
+	if authDriver == "" {
+		authDriver = "core"
+	}
+
+	p := s.pool(kindUser)
+	if p.nextID < kindUser.firstID {
+		p.nextID = kindUser.firstID
+	}
+
+	u := s.newUser(p.nextID, name, password, authDriver, groups[0])
+	for _, g := range groups[1:] {
+		u.addID("GROUPS", g)
+		s.pool(kindGroup).objects[g].addID("USERS", u.ID)
+	}
+	p.add(u)
+
+	return u.ID, nil
+}
+
+func userPasswd(s *Server, sess *session, args arguments) (interface{}, error) {
+	u, err := userArgument(s, args, "UserChangePassword")
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if password == "" {
+		return nil, errAction("UserChangePassword", "Invalid password, it can not be empty.")
+	}
+
+	u.set("PASSWORD", password)
+
+	return u.ID, nil
+}
+
+func userChauth(s *Server, sess *session, args arguments) (interface{}, error) {
+	u, err := userArgument(s, args, "UserChangeAuth")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := args.optionalString(2, "")
+	if err != nil {
+		return nil, err
+	}
+
+	u.set("AUTH_DRIVER", driver)
+	if password != "" {
+		u.set("PASSWORD", password)
+	}
+
+	return u.ID, nil
+}
+
+func userArgument(s *Server, args arguments, request string) (*object, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pool(kindUser).get(request, id)
+}
+
+func userGroupArguments(s *Server, args arguments, request string) (*object, *object, error) {
+	u, err := userArgument(s, args, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groupID, err := args.int(1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g, err := s.pool(kindGroup).get(request, groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return u, g, nil
+}
+
+func userChgrp(s *Server, sess *session, args arguments) (interface{}, error) {
+	u, g, err := userGroupArguments(s, args, "UserChown")
+	if err != nil {
+		return nil, err
+	}
+
+	if old, ok := s.pool(kindGroup).objects[u.intText("GID")]; ok {
+		old.removeID("USERS", u.ID)
+		u.removeID("GROUPS", old.ID)
+	}
+
+	u.setInt("GID", g.ID)
+	u.set("GNAME", g.text("NAME"))
+	u.addID("GROUPS", g.ID)
+	g.addID("USERS", u.ID)
+
+	return u.ID, nil
+}
+
+func userAddGroup(s *Server, sess *session, args arguments) (interface{}, error) {
+	u, g, err := userGroupArguments(s, args, "UserAddGroup")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range u.ids("GROUPS") {
+		if id == g.ID {
+			return nil, errAction("UserAddGroup", "User is already in this group")
+		}
+	}
+
+	u.addID("GROUPS", g.ID)
+	g.addID("USERS", u.ID)
+
+	return u.ID, nil
+}
+
+func userDelGroup(s *Server, sess *session, args arguments) (interface{}, error) {
+	u, g, err := userGroupArguments(s, args, "UserDelGroup")
+	if err != nil {
+		return nil, err
+	}
+
+	if u.intText("GID") == g.ID {
+		return nil, errAction("UserDelGroup", "Cannot remove user from the primary group")
+	}
+
+	u.removeID("GROUPS", g.ID)
+	g.removeID("USERS", u.ID)
+
+	return u.ID, nil
+}
+
+func userLogin(s *Server, sess *session, args arguments) (interface{}, error) {
+	name, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := args.optionalString(1, "")
+	if err != nil {
+		return nil, err
+	}
+
+	period, err := args.optionalInt(2, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	egid, err := args.optionalInt(3, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "UserLogin"
+
+	u := s.pool(kindUser).findByName(name)
+	if u == nil {
+		return nil, &Error{Code: errors.CodeNoExists,
+			Message: "[" + request + "] Error getting user " + name + "."}
+	}
+
+	if period == 0 {
+		// reset given token or all tokens
+		for _, t := range u.XML.SelectElements("LOGIN_TOKEN") {
+			if token == "" || t.SelectElement("TOKEN").Text() == token {
+				u.XML.RemoveChild(t)
+			}
+		}
+		return "", nil
+	}
+
+	if token == "" {
+		random := make([]byte, 20)
+		if _, err = rand.Read(random); err != nil {
+			return nil, errInternal(request, "%s", err.Error())
+		}
+		token = hex.EncodeToString(random)
+	}
+
+	expiration := int64(-1)
+	if period > 0 {
+		expiration = s.now().Unix() + int64(period)
+	}
+
+	t := u.XML.CreateElement("LOGIN_TOKEN")
+	t.CreateElement("TOKEN").SetText(token)
+	t.CreateElement("EXPIRATION_TIME").SetText(sprintf("%d", expiration))
+	t.CreateElement("EGID").SetText(itoa(egid))
+
+	return token, nil
+}
+
+func groupAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	name, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "GroupAllocate"
+
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new group. Invalid NAME, it cannot be empty.")
+	}
+
+	if other := s.pool(kindGroup).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new group. NAME is already taken by GROUP %d.",
+			other.ID)
+	}
+
+	g := s.pool(kindGroup).create(name, sess)
+	g.element("TEMPLATE")
+	g.element("USERS")
+	g.element("ADMINS")
+
+	return g.ID, nil
+}
+
+func groupAdmin(s *Server, args arguments, request string, add bool) (interface{}, error) {
+	groupID, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.pool(kindGroup).get(request, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.pool(kindUser).get(request, userID); err != nil {
+		return nil, err
+	}
+
+	if add {
+		g.addID("ADMINS", userID)
+	} else {
+		g.removeID("ADMINS", userID)
+	}
+
+	return groupID, nil
+}
+
+func groupAddAdmin(s *Server, sess *session, args arguments) (interface{}, error) {
+	return groupAdmin(s, args, "GroupAddAdmin", true)
+}
+
+func groupDelAdmin(s *Server, sess *session, args arguments) (interface{}, error) {
+	return groupAdmin(s, args, "GroupDelAdmin", false)
+}
@@ -0,0 +1,156 @@
+package onetest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+func registerVMGroupMethods(s *Server) {
+	s.methods["one.vmgroup.allocate"] = vmGroupAllocate
+}
+
+func vmGroupAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "VMGroupAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new VM group. No NAME in template.")
+	}
+
+	if other := s.findOwnedByName(kindVMGroup, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new VM group. NAME is already taken by "+
+			"VMGROUP %d.", other.ID)
+	}
+
+	roles := etree.NewElement("ROLES")
+	names := make(map[string]bool)
+	for i, r := range template.SelectElements("ROLE") {
+		role, err := createVMGroupRole(request, r, i, names)
+		if err != nil {
+			return nil, err
+		}
+		roles.AddChild(role)
+	}
+
+	for _, tag := range []string{"AFFINED", "ANTI_AFFINED"} {
+		for _, rule := range template.SelectElements(tag) {
+			for _, roleName := range splitList(rule.Text()) {
+				if !names[roleName] {
+					return nil, errAllocate(request, "Error allocating a new VM group. Some roles used in %s "+
+						"attribute, are not defined.", tag)
+				}
+			}
+		}
+	}
+
+	vg := s.pool(kindVMGroup).create(name, sess)
+	vg.XML.AddChild(roles)
+	for _, r := range template.SelectElements("ROLE") {
+		template.RemoveChild(r)
+	}
+	replaceTemplate(vg.element("TEMPLATE"), template)
+
+	return vg.ID, nil
+}
+
+// createVMGroupRole creates ROLE element of VM group from the role in the template.
+func createVMGroupRole(request string, r *etree.Element, id int, names map[string]bool) (*etree.Element, error) {
+	name := childText(r, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new VM group. ROLE must have a NAME.")
+	}
+
+	if names[name] {
+		return nil, errAllocate(request, "Error allocating a new VM group. Duplicated ROLE NAME: %s.", name)
+	}
+	names[name] = true
+
+	policy := childText(r, "POLICY")
+	switch policy {
+	case "":
+		policy = "NONE"
+	case "NONE", "AFFINED", "ANTI_AFFINED":
+	default:
+		return nil, errAllocate(request, "Error allocating a new VM group. Wrong POLICY %s in ROLE %s.", policy,
+			name)
+	}
+
+	role := etree.NewElement("ROLE")
+	role.CreateElement("ID").SetText(itoa(id))
+	role.CreateElement("NAME").SetText(name)
+	role.CreateElement("POLICY").SetText(policy)
+	for _, tag := range []string{"HOST_AFFINED", "HOST_ANTI_AFFINED"} {
+		if value := childText(r, tag); value != "" {
+			role.CreateElement(tag).SetText(value)
+		}
+	}
+	role.CreateElement("VMS")
+
+	return role, nil
+}
+
+// vmGroupRole finds the role of VM group referenced by VMGROUP element of virtual machine template.
+func (s *Server) vmGroupRole(request string, reference *etree.Element) (*etree.Element, error) {
+	groupID, err := strconv.Atoi(childText(reference, "VMGROUP_ID"))
+	if err != nil {
+		return nil, errAllocate(request, "VMGROUP_ID is missing or it is not a number.")
+	}
+
+	vg, ok := s.pool(kindVMGroup).objects[groupID]
+	if !ok {
+		return nil, errAllocate(request, "VM Group %d does not exist.", groupID)
+	}
+
+	roleName := childText(reference, "ROLE")
+	for _, role := range vg.XML.FindElements("ROLES/ROLE") {
+		if childText(role, "NAME") == roleName {
+			return role, nil
+		}
+	}
+
+	return nil, errAllocate(request, "Role %s does not exist in VM Group %d.", roleName, groupID)
+}
+
+// leaveVMGroup removes the virtual machine from the role of its VM group.
+func (s *Server) leaveVMGroup(vm *object) {
+	reference := vm.XML.FindElement("TEMPLATE/VMGROUP")
+	if reference == nil {
+		return
+	}
+
+	role, err := s.vmGroupRole("", reference)
+	if err != nil {
+		return
+	}
+
+	ids := make([]string, 0)
+	for _, id := range splitList(childText(role, "VMS")) {
+		if id != itoa(vm.ID) {
+			ids = append(ids, id)
+		}
+	}
+	role.SelectElement("VMS").SetText(strings.Join(ids, ","))
+}
+
+// splitList splits comma separated list, e.g. "db, web".
+func splitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
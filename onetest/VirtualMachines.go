@@ -0,0 +1,1227 @@
+package onetest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/resources"
+)
+
+// virtual machine states
+const (
+	vmStateInit       = 0
+	vmStatePending    = 1
+	vmStateHold       = 2
+	vmStateActive     = 3
+	vmStateStopped    = 4
+	vmStateSuspended  = 5
+	vmStateDone       = 6
+	vmStatePoweroff   = 8
+	vmStateUndeployed = 9
+)
+
+// virtual machine LCM states
+const (
+	lcmStateLcmInit = 0
+	lcmStateRunning = 3
+)
+
+func registerVirtualMachineMethods(s *Server) {
+	s.methods["one.vm.allocate"] = vmAllocate
+	s.methods["one.vm.deploy"] = vmDeploy
+	s.methods["one.vm.action"] = vmAction
+	s.methods["one.vm.migrate"] = vmMigrate
+	s.methods["one.vm.resize"] = vmResize
+	s.methods["one.vm.updateconf"] = vmUpdateConf
+	s.methods["one.vm.recover"] = vmRecover
+	s.methods["one.vm.snapshotcreate"] = vmSnapshotCreate
+	s.methods["one.vm.snapshotrevert"] = vmSnapshot("VirtualMachineSnapshotRevert", false)
+	s.methods["one.vm.snapshotdelete"] = vmSnapshot("VirtualMachineSnapshotDelete", true)
+	s.methods["one.vm.attach"] = vmAttach
+	s.methods["one.vm.detach"] = vmDetach
+	s.methods["one.vm.diskresize"] = vmDiskResize
+	s.methods["one.vm.disksaveas"] = vmDiskSaveAs
+	s.methods["one.vm.disksnapshotcreate"] = vmDiskSnapshotCreate
+	s.methods["one.vm.disksnapshotdelete"] = vmDiskSnapshot("VirtualMachineDiskSnapshotDelete")
+	s.methods["one.vm.disksnapshotrevert"] = vmDiskSnapshot("VirtualMachineDiskSnapshotRevert")
+	s.methods["one.vm.disksnapshotrename"] = vmDiskSnapshot("VirtualMachineDiskSnapshotRename")
+	s.methods["one.vm.attachnic"] = vmAttachNIC
+	s.methods["one.vm.detachnic"] = vmDetachNIC
+}
+
+// vmArgument returns the virtual machine given by the first parameter.
+func vmArgument(s *Server, args arguments, request string) (*object, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := s.pool(kindVirtualMachine).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if vm.intText("STATE") == vmStateDone {
+		return nil, errNoExists(request, kindVirtualMachine, id)
+	}
+
+	return vm, nil
+}
+
+func (s *Server) setVMState(vm *object, state, lcmState int) {
+	vm.setInt("PREV_STATE", vm.intText("STATE"))
+	vm.setInt("PREV_LCM_STATE", vm.intText("LCM_STATE"))
+	vm.setInt("STATE", state)
+	vm.setInt("LCM_STATE", lcmState)
+
+	if state == vmStateDone {
+		vm.setTime("ETIME", s.now())
+	}
+
+	s.runStateHooks(vm)
+}
+
+func vmAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := args.optionalBool(1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "VirtualMachineAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createVM(request, sess, template, hold, childText(template, "NAME"))
+}
+
+// createVM creates a new virtual machine from the template.
+func (s *Server) createVM(request string, sess *session, template *etree.Element, hold bool,
+	name string) (int, error) {
+	if childText(template, "MEMORY") == "" {
+		return -1, errAllocate(request, "No MEMORY in template.")
+	}
+
+	if childText(template, "CPU") == "" {
+		return -1, errAllocate(request, "No CPU in template.")
+	}
+
+	var role *etree.Element
+	if reference := template.SelectElement("VMGROUP"); reference != nil {
+		var err error
+		if role, err = s.vmGroupRole(request, reference); err != nil {
+			return -1, err
+		}
+	}
+
+	vm := s.pool(kindVirtualMachine).create(name, sess)
+	if name == "" {
+		vm.set("NAME", sprintf("one-%d", vm.ID))
+	}
+
+	state := vmStatePending
+	if hold {
+		state = vmStateHold
+	}
+
+	vm.setInt("LAST_POLL", 0)
+	vm.setInt("STATE", state)
+	vm.setInt("LCM_STATE", lcmStateLcmInit)
+	vm.setInt("PREV_STATE", state)
+	vm.setInt("PREV_LCM_STATE", lcmStateLcmInit)
+	vm.setInt("RESCHED", 0)
+	vm.setTime("STIME", s.now())
+	vm.setInt("ETIME", 0)
+	vm.set("DEPLOY_ID", "")
+	vm.element("MONITORING")
+
+	vmTemplate := vm.element("TEMPLATE")
+	userTemplate := vm.element("USER_TEMPLATE")
+
+	diskID, nicID := 0, 0
+	for _, e := range template.ChildElements() {
+		switch e.Tag {
+		case "NAME":
+		case "CPU", "VCPU", "MEMORY", "OS", "GRAPHICS", "FEATURES", "RAW", "CONTEXT", "TEMPLATE_ID", "CPU_COST",
+			"MEMORY_COST", "DISK_COST", "VMGROUP", "VROUTER_ID":
+			vmTemplate.AddChild(e.Copy())
+		case "DISK":
+			disk, err := s.createVMDisk(request, vm, e, diskID)
+			if err != nil {
+				s.pool(kindVirtualMachine).remove(vm.ID)
+				return -1, err
+			}
+			vmTemplate.AddChild(disk)
+			diskID++
+		case "NIC":
+			nic, err := s.createVMNIC(request, vm, e, nicID)
+			if err != nil {
+				s.pool(kindVirtualMachine).remove(vm.ID)
+				return -1, err
+			}
+			vmTemplate.AddChild(nic)
+			nicID++
+		default:
+			userTemplate.AddChild(e.Copy())
+		}
+	}
+
+	if role != nil {
+		vms := role.SelectElement("VMS")
+		vms.SetText(strings.Join(append(splitList(vms.Text()), itoa(vm.ID)), ","))
+	}
+
+	vmTemplate.CreateElement("CREATED_BY").SetText(itoa(sess.UserID))
+	vmTemplate.CreateElement("VMID").SetText(itoa(vm.ID))
+	vm.element("HISTORY_RECORDS")
+
+	return vm.ID, nil
+}
+
+// createVMDisk creates disk of the virtual machine from disk template.
+func (s *Server) createVMDisk(request string, vm *object, template *etree.Element, diskID int) (*etree.Element,
+	error) {
+	disk := etree.NewElement("DISK")
+
+	if imageID := childText(template, "IMAGE_ID"); imageID != "" {
+		id, err := strconv.Atoi(imageID)
+		if err != nil {
+			return nil, errAllocate(request, "Wrong IMAGE_ID %s.", imageID)
+		}
+
+		img, err := s.pool(kindImage).get(request, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if img.intText("STATE") == imageDisabled {
+			return nil, errAllocate(request, "Image %d is disabled.", id)
+		}
+
+		img.addID("VMS", vm.ID)
+		img.setInt("RUNNING_VMS", len(img.ids("VMS")))
+		if img.text("PERSISTENT") == "1" {
+			img.setInt("STATE", imageUsedPers)
+		} else {
+			img.setInt("STATE", imageUsed)
+		}
+
+		disk.CreateElement("CLONE").SetText(map[bool]string{true: "NO", false: "YES"}[img.text("PERSISTENT") == "1"])
+		disk.CreateElement("CLUSTER_ID").SetText("0")
+		disk.CreateElement("DATASTORE").SetText(img.text("DATASTORE"))
+		disk.CreateElement("DATASTORE_ID").SetText(img.text("DATASTORE_ID"))
+		disk.CreateElement("DEV_PREFIX").SetText("vd")
+		disk.CreateElement("DISK_TYPE").SetText("FILE")
+		disk.CreateElement("DRIVER").SetText("qcow2")
+		disk.CreateElement("IMAGE").SetText(img.text("NAME"))
+		disk.CreateElement("IMAGE_ID").SetText(itoa(id))
+		disk.CreateElement("IMAGE_STATE").SetText(img.text("STATE"))
+		disk.CreateElement("IMAGE_UNAME").SetText(img.text("UNAME"))
+		disk.CreateElement("READONLY").SetText("NO")
+		disk.CreateElement("SIZE").SetText(img.text("SIZE"))
+		disk.CreateElement("SOURCE").SetText(img.text("SOURCE"))
+		disk.CreateElement("TM_MAD").SetText("ssh")
+		disk.CreateElement("TYPE").SetText("FILE")
+	} else {
+		size := childText(template, "SIZE")
+		if size == "" {
+			return nil, errAllocate(request, "No IMAGE_ID or SIZE in DISK.")
+		}
+		disk.CreateElement("SIZE").SetText(size)
+		disk.CreateElement("TYPE").SetText(childText(template, "TYPE"))
+	}
+
+	disk.CreateElement("DISK_ID").SetText(itoa(diskID))
+	disk.CreateElement("TARGET").SetText("vd" + string(rune('a'+diskID)))
+
+	for _, e := range template.ChildElements() {
+		if disk.SelectElement(e.Tag) == nil {
+			disk.AddChild(e.Copy())
+		} else if e.Tag == "SIZE" {
+			disk.SelectElement("SIZE").SetText(e.Text())
+		}
+	}
+
+	return disk, nil
+}
+
+// createVMNIC creates network interface of the virtual machine from NIC template.
+func (s *Server) createVMNIC(request string, vm *object, template *etree.Element, nicID int) (*etree.Element,
+	error) {
+	networkID, err := strconv.Atoi(childText(template, "NETWORK_ID"))
+	if err != nil {
+		return nil, errAllocate(request, "No NETWORK_ID in NIC.")
+	}
+
+	vnet, err := s.pool(kindVirtualNetwork).get(request, networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := s.leaseAddress(request, vnet, "VM", vm.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	nic := etree.NewElement("NIC")
+	nic.CreateElement("AR_ID").SetText(itoa(lease.addressRangeID))
+	nic.CreateElement("BRIDGE").SetText(vnet.text("BRIDGE"))
+
+	clusterIDs := make([]string, 0)
+	for _, id := range vnet.ids("CLUSTERS") {
+		clusterIDs = append(clusterIDs, itoa(id))
+	}
+	nic.CreateElement("CLUSTER_ID").SetText(strings.Join(clusterIDs, ","))
+
+	nic.CreateElement("IP").SetText(lease.ip)
+	nic.CreateElement("MAC").SetText(lease.mac)
+	nic.CreateElement("NETWORK").SetText(vnet.text("NAME"))
+	nic.CreateElement("NETWORK_ID").SetText(itoa(networkID))
+	nic.CreateElement("NETWORK_UNAME").SetText(vnet.text("UNAME"))
+	nic.CreateElement("NIC_ID").SetText(itoa(nicID))
+	nic.CreateElement("SECURITY_GROUPS").SetText(vnet.text("TEMPLATE/SECURITY_GROUPS"))
+	nic.CreateElement("TARGET").SetText(sprintf("one-%d-%d", vm.ID, nicID))
+	nic.CreateElement("VN_MAD").SetText(vnet.text("VN_MAD"))
+
+	return nic, nil
+}
+
+// releaseVM releases images and leases used by the virtual machine.
+func (s *Server) releaseVM(vm *object) {
+	for _, disk := range vm.element("TEMPLATE").SelectElements("DISK") {
+		s.releaseDisk(vm, disk)
+	}
+
+	for _, nic := range vm.element("TEMPLATE").SelectElements("NIC") {
+		s.releaseNIC(vm, nic)
+	}
+
+	s.leaveHost(vm)
+	s.leaveVMGroup(vm)
+	s.leaveVirtualRouter(vm)
+}
+
+// leaveHost removes the virtual machine from the host of its last history record.
+func (s *Server) leaveHost(vm *object) {
+	if host, ok := s.pool(kindHost).objects[lastHistoryInt(vm, "HID")]; ok {
+		host.removeID("VMS", vm.ID)
+		host.setInt("HOST_SHARE/RUNNING_VMS", len(host.ids("VMS")))
+	}
+}
+
+func (s *Server) releaseDisk(vm *object, disk *etree.Element) {
+	imageID, err := strconv.Atoi(childText(disk, "IMAGE_ID"))
+	if err != nil {
+		return
+	}
+
+	img, ok := s.pool(kindImage).objects[imageID]
+	if !ok {
+		return
+	}
+
+	img.removeID("VMS", vm.ID)
+	img.setInt("RUNNING_VMS", len(img.ids("VMS")))
+	if len(img.ids("VMS")) == 0 {
+		img.setInt("STATE", imageReady)
+	}
+}
+
+func (s *Server) releaseNIC(vm *object, nic *etree.Element) {
+	networkID, err := strconv.Atoi(childText(nic, "NETWORK_ID"))
+	if err != nil {
+		return
+	}
+
+	if vnet, ok := s.pool(kindVirtualNetwork).objects[networkID]; ok {
+		releaseAddress(vnet, childText(nic, "IP"))
+	}
+}
+
+func vmDeploy(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineDeploy"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hostID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	datastoreID, err := args.optionalInt(3, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := s.pool(kindHost).get(request, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := vm.intText("STATE")
+	if state != vmStatePending && state != vmStateHold && state != vmStateStopped && state != vmStateUndeployed {
+		return nil, errAction(request, "Wrong state to perform action")
+	}
+
+	if datastoreID == -1 {
+		datastoreID = 0
+	}
+
+	s.addHistory(vm, host, datastoreID, resources.ActionNone)
+	s.setVMState(vm, vmStateActive, lcmStateRunning)
+	vm.set("DEPLOY_ID", sprintf("one-%d", vm.ID))
+	vm.setTime("LAST_POLL", s.now())
+
+	return vm.ID, nil
+}
+
+// lastHistoryInt returns integer value of the last history record of the virtual machine or -1.
+func lastHistoryInt(vm *object, tag string) int {
+	records := vm.element("HISTORY_RECORDS").SelectElements("HISTORY")
+	if len(records) == 0 {
+		return -1
+	}
+
+	value, err := strconv.Atoi(childText(records[len(records)-1], tag))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// addHistory adds history record of the virtual machine placed on the host, the previous record
+// is closed with given action.
+func (s *Server) addHistory(vm, host *object, datastoreID int, action resources.Action) {
+	records := vm.element("HISTORY_RECORDS")
+	sequence := len(records.SelectElements("HISTORY"))
+
+	if sequence > 0 {
+		s.closeHistory(vm, action)
+		s.leaveHost(vm)
+	}
+
+	now := sprintf("%d", s.now().Unix())
+
+	history := records.CreateElement("HISTORY")
+	for _, v := range [][2]string{{"OID", itoa(vm.ID)}, {"SEQ", itoa(sequence)}, {"HOSTNAME", host.text("NAME")},
+		{"HID", itoa(host.ID)}, {"CID", host.text("CLUSTER_ID")}, {"STIME", now}, {"ETIME", "0"},
+		{"VM_MAD", host.text("VM_MAD")}, {"TM_MAD", "ssh"}, {"DS_ID", itoa(datastoreID)}, {"PSTIME", now},
+		{"PETIME", now}, {"RSTIME", now}, {"RETIME", "0"}, {"ESTIME", "0"}, {"EETIME", "0"},
+		{"ACTION", itoa(int(resources.ActionNone))}, {"UID", "-1"}, {"GID", "-1"}, {"REQUEST_ID", "-1"}} {
+		history.CreateElement(v[0]).SetText(v[1])
+	}
+
+	host.addID("VMS", vm.ID)
+	host.setInt("HOST_SHARE/RUNNING_VMS", len(host.ids("VMS")))
+}
+
+// closeHistory ends the last open history record of the virtual machine with given action.
+func (s *Server) closeHistory(vm *object, action resources.Action) {
+	records := vm.element("HISTORY_RECORDS").SelectElements("HISTORY")
+	if len(records) == 0 {
+		return
+	}
+
+	last := records[len(records)-1]
+	if childText(last, "ETIME") != "0" {
+		return
+	}
+
+	now := sprintf("%d", s.now().Unix())
+	last.SelectElement("ETIME").SetText(now)
+	last.SelectElement("RETIME").SetText(now)
+	last.SelectElement("ACTION").SetText(itoa(int(action)))
+}
+
+// vmHistoryActions maps actions moving the virtual machine out of the host to the history actions.
+var vmHistoryActions = map[string]resources.Action{
+	"terminate":      resources.ActionTerminate,
+	"terminate-hard": resources.ActionTerminateHard,
+	"undeploy":       resources.ActionUndeploy,
+	"undeploy-hard":  resources.ActionUndeployHard,
+	"stop":           resources.ActionStop,
+}
+
+// vmTerminableStates contains the states in which the virtual machine can be terminated.
+var vmTerminableStates = []int{vmStatePending, vmStateHold, vmStateActive, vmStateStopped, vmStateSuspended,
+	vmStatePoweroff, vmStateUndeployed}
+
+// vmActions maps actions to the states in which they can be performed and the resulting state.
+var vmActions = map[string]struct {
+	from     []int
+	state    int
+	lcmState int
+}{
+	"terminate":      {vmTerminableStates, vmStateDone, lcmStateLcmInit},
+	"terminate-hard": {vmTerminableStates, vmStateDone, lcmStateLcmInit},
+	"undeploy":       {[]int{vmStateActive, vmStatePoweroff}, vmStateUndeployed, lcmStateLcmInit},
+	"undeploy-hard":  {[]int{vmStateActive, vmStatePoweroff}, vmStateUndeployed, lcmStateLcmInit},
+	"hold":           {[]int{vmStatePending}, vmStateHold, lcmStateLcmInit},
+	"release":        {[]int{vmStateHold}, vmStatePending, lcmStateLcmInit},
+	"stop":           {[]int{vmStateActive, vmStateSuspended}, vmStateStopped, lcmStateLcmInit},
+	"suspend":        {[]int{vmStateActive}, vmStateSuspended, lcmStateLcmInit},
+	"resume": {[]int{vmStateStopped, vmStateSuspended, vmStatePoweroff, vmStateUndeployed}, vmStateActive,
+		lcmStateRunning},
+	"reboot":        {[]int{vmStateActive}, vmStateActive, lcmStateRunning},
+	"reboot-hard":   {[]int{vmStateActive}, vmStateActive, lcmStateRunning},
+	"poweroff":      {[]int{vmStateActive}, vmStatePoweroff, lcmStateLcmInit},
+	"poweroff-hard": {[]int{vmStateActive}, vmStatePoweroff, lcmStateLcmInit},
+	"resched":       {[]int{vmStateActive}, vmStateActive, lcmStateRunning},
+	"unresched":     {[]int{vmStateActive}, vmStateActive, lcmStateRunning},
+}
+
+func vmAction(s *Server, sess *session, args arguments) (interface{}, error) {
+	action, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "VirtualMachineAction"
+
+	vm, err := vmArgument(s, arguments{method: args.method, values: []interface{}{id}}, request)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, ok := vmActions[action]
+	if !ok {
+		return nil, errAPI(request, "Virtual machine action \"%s\" is not supported", action)
+	}
+
+	state := vm.intText("STATE")
+	allowed := false
+	for _, from := range transition.from {
+		allowed = allowed || from == state
+	}
+
+	// resumed virtual machine must have been deployed on a host before
+	if action == "resume" && len(vm.element("HISTORY_RECORDS").SelectElements("HISTORY")) == 0 {
+		allowed = false
+	}
+
+	if !allowed {
+		return nil, errAction(request, "Error performing action \"%s\": This action is not available for "+
+			"state %s", action, vmStateNames[state])
+	}
+
+	switch action {
+	case "resched":
+		vm.setInt("RESCHED", 1)
+	case "unresched":
+		vm.setInt("RESCHED", 0)
+	}
+
+	if historyAction, ok := vmHistoryActions[action]; ok {
+		s.closeHistory(vm, historyAction)
+		s.leaveHost(vm)
+	}
+
+	if transition.state == vmStateDone {
+		s.releaseVM(vm)
+	}
+
+	s.setVMState(vm, transition.state, transition.lcmState)
+
+	return id, nil
+}
+
+var vmStateNames = map[int]string{vmStateInit: "INIT", vmStatePending: "PENDING", vmStateHold: "HOLD",
+	vmStateActive: "ACTIVE", vmStateStopped: "STOPPED", vmStateSuspended: "SUSPENDED", vmStateDone: "DONE",
+	vmStatePoweroff: "POWEROFF", vmStateUndeployed: "UNDEPLOYED"}
+
+func vmMigrate(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineMigrate"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hostID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := args.optionalBool(2, false)
+	if err != nil {
+		return nil, err
+	}
+
+	datastoreID, err := args.optionalInt(4, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := s.pool(kindHost).get(request, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := vm.intText("STATE")
+	if state != vmStateActive && state != vmStatePoweroff && state != vmStateSuspended {
+		return nil, errAction(request, "Migrate action is not available for state %s", vmStateNames[state])
+	}
+
+	if datastoreID == -1 {
+		datastoreID = lastHistoryInt(vm, "DS_ID")
+	}
+
+	action := resources.ActionMigrate
+	if live {
+		action = resources.ActionLiveMigrate
+	}
+
+	s.addHistory(vm, host, datastoreID, action)
+
+	return vm.ID, nil
+}
+
+func vmResize(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineResize"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	state := vm.intText("STATE")
+	if state != vmStatePending && state != vmStateHold && state != vmStatePoweroff && state != vmStateUndeployed {
+		return nil, errAction(request, "Resize action is not available for state %s", vmStateNames[state])
+	}
+
+	for _, tag := range []string{"CPU", "VCPU", "MEMORY"} {
+		if value := childText(template, tag); value != "" {
+			vm.set("TEMPLATE/"+tag, value)
+		}
+	}
+
+	return vm.ID, nil
+}
+
+func vmUpdateConf(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineUpdateConf"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	vmTemplate := vm.element("TEMPLATE")
+	for _, tag := range []string{"OS", "FEATURES", "INPUT", "GRAPHICS", "RAW", "CONTEXT"} {
+		e := template.SelectElement(tag)
+		if e == nil {
+			continue
+		}
+
+		if old := vmTemplate.SelectElement(tag); old != nil {
+			vmTemplate.RemoveChild(old)
+		}
+		vmTemplate.AddChild(e.Copy())
+	}
+
+	return vm.ID, nil
+}
+
+func vmRecover(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineRecover"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	operation, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case 0, 2: // failure, retry
+	case 1: // success
+		if vm.intText("STATE") == vmStateActive {
+			vm.setInt("LCM_STATE", lcmStateRunning)
+		}
+	case 3: // delete
+		s.releaseVM(vm)
+		s.setVMState(vm, vmStateDone, lcmStateLcmInit)
+	case 4: // delete-recreate
+		s.setVMState(vm, vmStatePending, lcmStateLcmInit)
+	default:
+		return nil, errAPI(request, "Wrong recover operation code")
+	}
+
+	return vm.ID, nil
+}
+
+func checkRunning(request string, vm *object) error {
+	if vm.intText("STATE") != vmStateActive || vm.intText("LCM_STATE") != lcmStateRunning {
+		return errAction(request, "Action is not available for state %s", vmStateNames[vm.intText("STATE")])
+	}
+	return nil
+}
+
+func vmSnapshotCreate(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineSnapshotCreate"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.optionalString(1, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkRunning(request, vm); err != nil {
+		return nil, err
+	}
+
+	template := vm.element("TEMPLATE")
+	snapshotID := 0
+	for _, snapshot := range template.SelectElements("SNAPSHOT") {
+		if id, err := strconv.Atoi(childText(snapshot, "SNAPSHOT_ID")); err == nil && id >= snapshotID {
+			snapshotID = id + 1
+		}
+	}
+
+	if name == "" {
+		name = sprintf("snapshot-%d", snapshotID)
+	}
+
+	snapshot := template.CreateElement("SNAPSHOT")
+	snapshot.CreateElement("HYPERVISOR_ID").SetText(sprintf("onesnap-%d", snapshotID))
+	snapshot.CreateElement("NAME").SetText(name)
+	snapshot.CreateElement("SNAPSHOT_ID").SetText(itoa(snapshotID))
+	snapshot.CreateElement("TIME").SetText(sprintf("%d", s.now().Unix()))
+
+	return snapshotID, nil
+}
+
+func vmSnapshot(request string, remove bool) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		vm, err := vmArgument(s, args, request)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotID, err := args.int(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkRunning(request, vm); err != nil {
+			return nil, err
+		}
+
+		template := vm.element("TEMPLATE")
+		for _, snapshot := range template.SelectElements("SNAPSHOT") {
+			if childText(snapshot, "SNAPSHOT_ID") != itoa(snapshotID) {
+				continue
+			}
+
+			if remove {
+				template.RemoveChild(snapshot)
+			}
+
+			return vm.ID, nil
+		}
+
+		return nil, errAction(request, "VM snapshot %d does not exist", snapshotID)
+	}
+}
+
+// findDisk returns disk of the virtual machine with given ID.
+func findDisk(vm *object, diskID int) *etree.Element {
+	for _, disk := range vm.element("TEMPLATE").SelectElements("DISK") {
+		if childText(disk, "DISK_ID") == itoa(diskID) {
+			return disk
+		}
+	}
+	return nil
+}
+
+// templateChild returns child element with given tag of template root or the root itself when it has the tag.
+func templateChild(template *etree.Element, tag string) *etree.Element {
+	if template.Tag == tag {
+		return template
+	}
+	return template.SelectElement(tag)
+}
+
+func checkHotplug(request string, vm *object) error {
+	state := vm.intText("STATE")
+	if (state == vmStateActive && vm.intText("LCM_STATE") == lcmStateRunning) || state == vmStatePoweroff ||
+		state == vmStatePending || state == vmStateHold || state == vmStateUndeployed {
+		return nil
+	}
+	return errAction(request, "Action is not available for state %s", vmStateNames[state])
+}
+
+func vmAttach(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineAttach"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	diskTemplate := templateChild(template, "DISK")
+	if diskTemplate == nil {
+		return nil, errAction(request, "Wrong DISK template")
+	}
+
+	if err = checkHotplug(request, vm); err != nil {
+		return nil, err
+	}
+
+	diskID := 0
+	for _, disk := range vm.element("TEMPLATE").SelectElements("DISK") {
+		if id, err := strconv.Atoi(childText(disk, "DISK_ID")); err == nil && id >= diskID {
+			diskID = id + 1
+		}
+	}
+
+	disk, err := s.createVMDisk(request, vm, diskTemplate, diskID)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.element("TEMPLATE").AddChild(disk)
+
+	return vm.ID, nil
+}
+
+func vmDiskArguments(s *Server, args arguments, request string) (*object, *etree.Element, error) {
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diskID, err := args.int(1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disk := findDisk(vm, diskID)
+	if disk == nil {
+		return nil, nil, errAction(request, "VM disk does not exist")
+	}
+
+	return vm, disk, nil
+}
+
+func vmDetach(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineDetach"
+
+	vm, disk, err := vmDiskArguments(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkHotplug(request, vm); err != nil {
+		return nil, err
+	}
+
+	s.releaseDisk(vm, disk)
+	vm.element("TEMPLATE").RemoveChild(disk)
+	s.removeDiskSnapshots(vm, childText(disk, "DISK_ID"))
+
+	return vm.ID, nil
+}
+
+func vmDiskResize(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineDiskResize"
+
+	vm, disk, err := vmDiskArguments(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeText, err := args.string(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkHotplug(request, vm); err != nil {
+		return nil, err
+	}
+
+	size, err := strconv.Atoi(sizeText)
+	if err != nil {
+		return nil, errAction(request, "Wrong size for the disk")
+	}
+
+	current, _ := strconv.Atoi(childText(disk, "SIZE"))
+	if size <= current {
+		return nil, errAction(request, "New disk size has to be greater than current one")
+	}
+
+	if disk.SelectElement("ORIGINAL_SIZE") == nil {
+		disk.CreateElement("ORIGINAL_SIZE").SetText(childText(disk, "SIZE"))
+	}
+	disk.SelectElement("SIZE").SetText(itoa(size))
+
+	return vm.ID, nil
+}
+
+func vmDiskSaveAs(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineDiskSaveas"
+
+	vm, disk, err := vmDiskArguments(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.string(2)
+	if err != nil {
+		return nil, err
+	}
+
+	imageTypeName, err := args.optionalString(3, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = args.optionalInt(4, -1); err != nil {
+		return nil, err
+	}
+
+	state := vm.intText("STATE")
+	if state != vmStatePoweroff && state != vmStateSuspended &&
+		!(state == vmStateActive && vm.intText("LCM_STATE") == lcmStateRunning) {
+		return nil, errAction(request, "Action is not available for state %s", vmStateNames[state])
+	}
+
+	imageID, err := strconv.Atoi(childText(disk, "IMAGE_ID"))
+	if err != nil {
+		return nil, errAction(request, "Cannot save_as a volatile disk")
+	}
+
+	source, err := s.pool(kindImage).get(request, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new image. Invalid NAME, it cannot be empty.")
+	}
+
+	if other := s.findOwnedByName(kindImage, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new image. NAME is already taken by IMAGE %d.",
+			other.ID)
+	}
+
+	imageType := source.intText("TYPE")
+	if imageTypeName != "" {
+		var ok bool
+		if imageType, ok = imageTypes[strings.ToUpper(imageTypeName)]; !ok {
+			return nil, errAllocate(request, "Unknown image type %s.", imageTypeName)
+		}
+	}
+
+	size, _ := strconv.Atoi(childText(disk, "SIZE"))
+	ds := s.pool(kindDatastore).objects[source.intText("DATASTORE_ID")]
+
+	img := s.createImage(request, sess, name, imageType, size, false, ds)
+	replaceTemplate(img.element("TEMPLATE"), source.element("TEMPLATE"))
+	img.set("TEMPLATE/SAVED_DISK_ID", childText(disk, "DISK_ID"))
+	img.set("TEMPLATE/SAVED_IMAGE_ID", itoa(imageID))
+	img.set("TEMPLATE/SAVED_VM_ID", itoa(vm.ID))
+
+	return img.ID, nil
+}
+
+// diskSnapshots returns SNAPSHOTS element of the disk, it is created when create is true.
+func diskSnapshots(vm *object, diskID string, create bool) *etree.Element {
+	for _, snapshots := range vm.XML.SelectElements("SNAPSHOTS") {
+		if childText(snapshots, "DISK_ID") == diskID {
+			return snapshots
+		}
+	}
+
+	if !create {
+		return nil
+	}
+
+	snapshots := vm.XML.CreateElement("SNAPSHOTS")
+	snapshots.CreateElement("ALLOW_ORPHANS").SetText("NO")
+	snapshots.CreateElement("CURRENT_BASE").SetText("-1")
+	snapshots.CreateElement("DISK_ID").SetText(diskID)
+	snapshots.CreateElement("NEXT_SNAPSHOT").SetText("0")
+
+	return snapshots
+}
+
+func (s *Server) removeDiskSnapshots(vm *object, diskID string) {
+	if snapshots := diskSnapshots(vm, diskID, false); snapshots != nil {
+		vm.XML.RemoveChild(snapshots)
+	}
+}
+
+func findDiskSnapshot(snapshots *etree.Element, snapshotID int) *etree.Element {
+	if snapshots == nil {
+		return nil
+	}
+	for _, snapshot := range snapshots.SelectElements("SNAPSHOT") {
+		if childText(snapshot, "ID") == itoa(snapshotID) {
+			return snapshot
+		}
+	}
+	return nil
+}
+
+func vmDiskSnapshotCreate(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineDiskSnapshotCreate"
+
+	vm, disk, err := vmDiskArguments(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.optionalString(2, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkHotplug(request, vm); err != nil {
+		return nil, err
+	}
+
+	diskID := childText(disk, "DISK_ID")
+	snapshots := diskSnapshots(vm, diskID, true)
+
+	snapshotID, _ := strconv.Atoi(childText(snapshots, "NEXT_SNAPSHOT"))
+	parent, _ := strconv.Atoi(childText(snapshots, "CURRENT_BASE"))
+
+	if name == "" {
+		name = sprintf("snapshot-%d", snapshotID)
+	}
+
+	// the new snapshot becomes the active one and a child of the previous active snapshot
+	for _, other := range snapshots.SelectElements("SNAPSHOT") {
+		if active := other.SelectElement("ACTIVE"); active != nil {
+			other.RemoveChild(active)
+		}
+	}
+
+	if parentSnapshot := findDiskSnapshot(snapshots, parent); parentSnapshot != nil {
+		children := parentSnapshot.SelectElement("CHILDREN")
+		if children == nil {
+			children = parentSnapshot.CreateElement("CHILDREN")
+			children.SetText(itoa(snapshotID))
+		} else {
+			children.SetText(children.Text() + "," + itoa(snapshotID))
+		}
+	}
+
+	snapshot := snapshots.CreateElement("SNAPSHOT")
+	snapshot.CreateElement("ACTIVE").SetText("YES")
+	snapshot.CreateElement("DATE").SetText(sprintf("%d", s.now().Unix()))
+	snapshot.CreateElement("ID").SetText(itoa(snapshotID))
+	snapshot.CreateElement("NAME").SetText(name)
+	snapshot.CreateElement("PARENT").SetText(itoa(parent))
+	snapshot.CreateElement("SIZE").SetText(childText(disk, "SIZE"))
+
+	snapshots.SelectElement("CURRENT_BASE").SetText(itoa(snapshotID))
+	snapshots.SelectElement("NEXT_SNAPSHOT").SetText(itoa(snapshotID + 1))
+
+	return snapshotID, nil
+}
+
+func vmDiskSnapshot(request string) method {
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		vm, disk, err := vmDiskArguments(s, args, request)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotID, err := args.int(2)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots := diskSnapshots(vm, childText(disk, "DISK_ID"), false)
+		snapshot := findDiskSnapshot(snapshots, snapshotID)
+		if snapshot == nil {
+			return nil, errAction(request, "Snapshot %d does not exist", snapshotID)
+		}
+
+		switch request {
+		case "VirtualMachineDiskSnapshotDelete":
+			if childText(snapshot, "ACTIVE") == "YES" {
+				return nil, errAction(request, "Cannot delete the active snapshot")
+			}
+			if childText(snapshot, "CHILDREN") != "" {
+				return nil, errAction(request, "Cannot delete snapshot with children")
+			}
+
+			parent, _ := strconv.Atoi(childText(snapshot, "PARENT"))
+			if parentSnapshot := findDiskSnapshot(snapshots, parent); parentSnapshot != nil {
+				removeChildID(parentSnapshot, snapshotID)
+			}
+			snapshots.RemoveChild(snapshot)
+		case "VirtualMachineDiskSnapshotRevert":
+			if err = checkHotplug(request, vm); err != nil {
+				return nil, err
+			}
+			for _, other := range snapshots.SelectElements("SNAPSHOT") {
+				if active := other.SelectElement("ACTIVE"); active != nil {
+					other.RemoveChild(active)
+				}
+			}
+			snapshot.InsertChild(snapshot.SelectElement("DATE"), etree.NewElement("ACTIVE"))
+			snapshot.SelectElement("ACTIVE").SetText("YES")
+			snapshots.SelectElement("CURRENT_BASE").SetText(itoa(snapshotID))
+		case "VirtualMachineDiskSnapshotRename":
+			name, err := args.string(3)
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				return nil, errAction(request, "Invalid name, it cannot be empty.")
+			}
+			snapshot.SelectElement("NAME").SetText(name)
+		}
+
+		return vm.ID, nil
+	}
+}
+
+func removeChildID(snapshot *etree.Element, id int) {
+	children := snapshot.SelectElement("CHILDREN")
+	if children == nil {
+		return
+	}
+
+	var remaining []string
+	for _, child := range strings.Split(children.Text(), ",") {
+		if child != itoa(id) {
+			remaining = append(remaining, child)
+		}
+	}
+
+	if len(remaining) == 0 {
+		snapshot.RemoveChild(children)
+		return
+	}
+	children.SetText(strings.Join(remaining, ","))
+}
+
+func vmAttachNIC(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineAttachNic"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	nicTemplate := templateChild(template, "NIC")
+	if nicTemplate == nil {
+		return nil, errAction(request, "Wrong NIC template")
+	}
+
+	if err = checkHotplug(request, vm); err != nil {
+		return nil, err
+	}
+
+	nicID := 0
+	for _, nic := range vm.element("TEMPLATE").SelectElements("NIC") {
+		if id, err := strconv.Atoi(childText(nic, "NIC_ID")); err == nil && id >= nicID {
+			nicID = id + 1
+		}
+	}
+
+	nic, err := s.createVMNIC(request, vm, nicTemplate, nicID)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.element("TEMPLATE").AddChild(nic)
+
+	return vm.ID, nil
+}
+
+func vmDetachNIC(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualMachineDetachNic"
+
+	vm, err := vmArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	nicID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkHotplug(request, vm); err != nil {
+		return nil, err
+	}
+
+	for _, nic := range vm.element("TEMPLATE").SelectElements("NIC") {
+		if childText(nic, "NIC_ID") == itoa(nicID) {
+			s.releaseNIC(vm, nic)
+			vm.element("TEMPLATE").RemoveChild(nic)
+			return vm.ID, nil
+		}
+	}
+
+	return nil, errAction(request, "NIC with NIC_ID %d does not exist", nicID)
+}
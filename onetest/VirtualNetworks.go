@@ -0,0 +1,568 @@
+package onetest
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+
+	"github.com/beevik/etree"
+)
+
+func registerVirtualNetworkMethods(s *Server) {
+	s.methods["one.vn.allocate"] = vnAllocate
+	s.methods["one.vn.add_ar"] = vnAddAddressRange
+	s.methods["one.vn.rm_ar"] = vnRemoveAddressRange
+	s.methods["one.vn.update_ar"] = vnUpdateAddressRange
+	s.methods["one.vn.free_ar"] = vnFreeAddressRange
+	s.methods["one.vn.reserve"] = vnReserve
+	s.methods["one.vn.hold"] = vnHold(true)
+	s.methods["one.vn.release"] = vnHold(false)
+}
+
+// lease is an address leased from virtual network.
+type lease struct {
+	addressRangeID int
+	ip             string
+	mac            string
+}
+
+func vnAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterID, err := args.optionalInt(1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "VirtualNetworkAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if clusterID == -1 {
+		clusterID = 0
+	}
+
+	c, err := s.pool(kindCluster).get(request, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new virtual network. No NAME in template.")
+	}
+
+	if other := s.findOwnedByName(kindVirtualNetwork, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new virtual network. NAME is already taken by "+
+			"NET %d.", other.ID)
+	}
+
+	vnMad := childText(template, "VN_MAD")
+	if vnMad == "" {
+		return nil, errAllocate(request, "Error allocating a new virtual network. No VN_MAD in template.")
+	}
+
+	vnet := s.createVirtualNetwork(name, sess, template, -1)
+	vnet.addID("CLUSTERS", clusterID)
+	c.addID("VNETS", vnet.ID)
+
+	for _, ar := range template.SelectElements("AR") {
+		if err = addAddressRange(request, vnet, ar); err != nil {
+			s.pool(kindVirtualNetwork).remove(vnet.ID)
+			c.removeID("VNETS", vnet.ID)
+			return nil, err
+		}
+	}
+
+	return vnet.ID, nil
+}
+
+func (s *Server) createVirtualNetwork(name string, sess *session, template *etree.Element,
+	parentID int) *object {
+	vnet := s.pool(kindVirtualNetwork).create(name, sess)
+	vnet.element("CLUSTERS")
+	vnet.set("BRIDGE", childText(template, "BRIDGE"))
+	if vnet.text("BRIDGE") == "" {
+		vnet.set("BRIDGE", sprintf("onebr%d", vnet.ID))
+	}
+	vnet.set("PARENT_NETWORK_ID", "")
+	if parentID != -1 {
+		vnet.setInt("PARENT_NETWORK_ID", parentID)
+	}
+	vnet.set("VN_MAD", childText(template, "VN_MAD"))
+	vnet.set("PHYDEV", childText(template, "PHYDEV"))
+	vnet.set("VLAN_ID", childText(template, "VLAN_ID"))
+	vnet.set("VLAN_ID_AUTOMATIC", "0")
+	vnet.setInt("USED_LEASES", 0)
+	vnet.element("VROUTERS")
+
+	vnetTemplate := vnet.element("TEMPLATE")
+	for _, e := range template.ChildElements() {
+		if e.Tag != "AR" {
+			vnetTemplate.AddChild(e.Copy())
+		}
+	}
+	if vnetTemplate.SelectElement("SECURITY_GROUPS") == nil {
+		vnetTemplate.CreateElement("SECURITY_GROUPS").SetText("0")
+	}
+
+	vnet.element("AR_POOL")
+
+	return vnet
+}
+
+// addAddressRange adds address range to the virtual network.
+func addAddressRange(request string, vnet *object, template *etree.Element) error {
+	arType := childText(template, "TYPE")
+	if arType != "IP4" && arType != "ETHER" && arType != "IP6" && arType != "IP4_6" {
+		return errAction(request, "Unknown or missing TYPE in address range")
+	}
+
+	size, err := strconv.Atoi(childText(template, "SIZE"))
+	if err != nil || size <= 0 {
+		return errAction(request, "Wrong SIZE for address range")
+	}
+
+	if (arType == "IP4" || arType == "IP4_6") && net.ParseIP(childText(template, "IP")).To4() == nil {
+		return errAction(request, "Wrong or empty IP for address range")
+	}
+
+	arPool := vnet.element("AR_POOL")
+
+	arID := 0
+	for _, ar := range arPool.SelectElements("AR") {
+		if id, err := strconv.Atoi(childText(ar, "AR_ID")); err == nil && id >= arID {
+			arID = id + 1
+		}
+	}
+
+	ar := arPool.CreateElement("AR")
+	ar.CreateElement("AR_ID").SetText(itoa(arID))
+	if ip := childText(template, "IP"); ip != "" {
+		ar.CreateElement("IP").SetText(ip)
+	}
+
+	mac := childText(template, "MAC")
+	if ip := net.ParseIP(childText(template, "IP")).To4(); mac == "" && ip != nil {
+		// OpenNebula derives the MAC address from the IP address
+		mac = sprintf("02:00:%02x:%02x:%02x:%02x", ip[0], ip[1], ip[2], ip[3])
+	} else if mac == "" {
+		mac = sprintf("02:00:%02x:%02x:%02x:%02x", vnet.ID>>8&0xff, vnet.ID&0xff, arID, 0)
+	}
+	ar.CreateElement("MAC").SetText(mac)
+	ar.CreateElement("SIZE").SetText(itoa(size))
+	ar.CreateElement("TYPE").SetText(arType)
+
+	ipEnd, macEnd := addressAt(ar, size-1)
+	ar.CreateElement("MAC_END").SetText(macEnd)
+	if ipEnd != "" {
+		ar.CreateElement("IP_END").SetText(ipEnd)
+	}
+
+	for _, e := range template.ChildElements() {
+		if ar.SelectElement(e.Tag) == nil {
+			ar.AddChild(e.Copy())
+		}
+	}
+
+	ar.CreateElement("USED_LEASES").SetText("0")
+	ar.CreateElement("LEASES")
+
+	return nil
+}
+
+func findAddressRange(vnet *object, arID int) *etree.Element {
+	for _, ar := range vnet.element("AR_POOL").SelectElements("AR") {
+		if childText(ar, "AR_ID") == itoa(arID) {
+			return ar
+		}
+	}
+	return nil
+}
+
+// addressAt returns IP and MAC address with given offset in the address range.
+func addressAt(ar *etree.Element, offset int) (string, string) {
+	ip := ""
+	if start := net.ParseIP(childText(ar, "IP")).To4(); start != nil {
+		next := make(net.IP, 4)
+		binary.BigEndian.PutUint32(next, binary.BigEndian.Uint32(start)+uint32(offset))
+		ip = next.String()
+	}
+
+	mac := ""
+	if hw, err := net.ParseMAC(childText(ar, "MAC")); err == nil && len(hw) == 6 {
+		low := binary.BigEndian.Uint32(hw[2:]) + uint32(offset)
+		next := make(net.HardwareAddr, 6)
+		copy(next, hw[:2])
+		binary.BigEndian.PutUint32(next[2:], low)
+		mac = next.String()
+	}
+
+	return ip, mac
+}
+
+// addLease adds lease to the address range, ownerTag is VM, VNET or VROUTER and ownerID its ID.
+func addLease(vnet *object, ar *etree.Element, ip, mac, ownerTag string, ownerID int) {
+	l := ar.SelectElement("LEASES").CreateElement("LEASE")
+	if ip != "" {
+		l.CreateElement("IP").SetText(ip)
+	}
+	l.CreateElement("MAC").SetText(mac)
+	l.CreateElement(ownerTag).SetText(itoa(ownerID))
+
+	used, _ := strconv.Atoi(childText(ar, "USED_LEASES"))
+	ar.SelectElement("USED_LEASES").SetText(itoa(used + 1))
+	vnet.setInt("USED_LEASES", vnet.intText("USED_LEASES")+1)
+}
+
+// leaseAddress leases first free address of the virtual network, ownerTag is VM or VROUTER and ownerID its ID.
+func (s *Server) leaseAddress(request string, vnet *object, ownerTag string, ownerID int) (*lease, error) {
+	for _, ar := range vnet.element("AR_POOL").SelectElements("AR") {
+		size, _ := strconv.Atoi(childText(ar, "SIZE"))
+		arID, _ := strconv.Atoi(childText(ar, "AR_ID"))
+
+		for offset := 0; offset < size; offset++ {
+			ip, mac := addressAt(ar, offset)
+			if isLeased(ar, mac) {
+				continue
+			}
+
+			addLease(vnet, ar, ip, mac, ownerTag, ownerID)
+
+			return &lease{addressRangeID: arID, ip: ip, mac: mac}, nil
+		}
+	}
+
+	return nil, errAllocate(request, "Cannot get IP/MAC lease from virtual network %d.", vnet.ID)
+}
+
+func isLeased(ar *etree.Element, mac string) bool {
+	for _, l := range ar.SelectElement("LEASES").SelectElements("LEASE") {
+		if childText(l, "MAC") == mac {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseAddress releases lease with given IP address.
+func releaseAddress(vnet *object, ip string) {
+	for _, ar := range vnet.element("AR_POOL").SelectElements("AR") {
+		leases := ar.SelectElement("LEASES")
+		for _, l := range leases.SelectElements("LEASE") {
+			if childText(l, "IP") != ip {
+				continue
+			}
+
+			leases.RemoveChild(l)
+
+			used, _ := strconv.Atoi(childText(ar, "USED_LEASES"))
+			ar.SelectElement("USED_LEASES").SetText(itoa(used - 1))
+			vnet.setInt("USED_LEASES", vnet.intText("USED_LEASES")-1)
+
+			return
+		}
+	}
+}
+
+func vnArgument(s *Server, args arguments, request string) (*object, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pool(kindVirtualNetwork).get(request, id)
+}
+
+func vnTemplateArgument(s *Server, args arguments, request string) (*object, *etree.Element, error) {
+	vnet, err := vnArgument(s, args, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text, err := args.string(1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vnet, template, nil
+}
+
+func vnAddAddressRange(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualNetworkAddAddressRange"
+
+	vnet, template, err := vnTemplateArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	ar := templateChild(template, "AR")
+	if ar == nil {
+		return nil, errAction(request, "Wrong AR template")
+	}
+
+	if err = addAddressRange(request, vnet, ar); err != nil {
+		return nil, err
+	}
+
+	return vnet.ID, nil
+}
+
+func vnRemoveAddressRange(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualNetworkRmAddressRange"
+
+	vnet, err := vnArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	arID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	ar := findAddressRange(vnet, arID)
+	if ar == nil {
+		return nil, errAction(request, "Address Range does not exist")
+	}
+
+	if childText(ar, "USED_LEASES") != "0" {
+		return nil, errAction(request, "Address Range has leases in use")
+	}
+
+	vnet.element("AR_POOL").RemoveChild(ar)
+
+	return vnet.ID, nil
+}
+
+func vnUpdateAddressRange(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualNetworkUpdateAddressRange"
+
+	vnet, template, err := vnTemplateArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	update := templateChild(template, "AR")
+	if update == nil {
+		return nil, errAction(request, "Wrong AR template")
+	}
+
+	arID, err := strconv.Atoi(childText(update, "AR_ID"))
+	if err != nil {
+		return nil, errAction(request, "Wrong AR_ID")
+	}
+
+	ar := findAddressRange(vnet, arID)
+	if ar == nil {
+		return nil, errAction(request, "Address Range does not exist")
+	}
+
+	for _, e := range update.ChildElements() {
+		switch e.Tag {
+		case "AR_ID", "TYPE", "IP", "MAC", "USED_LEASES", "LEASES":
+			continue
+		}
+
+		if old := ar.SelectElement(e.Tag); old != nil {
+			old.SetText(e.Text())
+		} else {
+			ar.InsertChild(ar.SelectElement("USED_LEASES"), e.Copy())
+		}
+	}
+
+	return vnet.ID, nil
+}
+
+func vnFreeAddressRange(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualNetworkFreeAddressRange"
+
+	vnet, err := vnArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	arID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if vnet.text("PARENT_NETWORK_ID") == "" {
+		return nil, errAction(request, "Virtual network is not a reservation")
+	}
+
+	ar := findAddressRange(vnet, arID)
+	if ar == nil {
+		return nil, errAction(request, "Address Range does not exist")
+	}
+
+	if parent, ok := s.pool(kindVirtualNetwork).objects[vnet.intText("PARENT_NETWORK_ID")]; ok {
+		for _, parentAR := range parent.element("AR_POOL").SelectElements("AR") {
+			leases := parentAR.SelectElement("LEASES")
+			for _, l := range leases.SelectElements("LEASE") {
+				if childText(l, "VNET") == itoa(vnet.ID) {
+					releaseAddress(parent, childText(l, "IP"))
+				}
+			}
+		}
+	}
+
+	vnet.element("AR_POOL").RemoveChild(ar)
+
+	return vnet.ID, nil
+}
+
+func vnReserve(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualNetworkReserve"
+
+	parent, template, err := vnTemplateArgument(s, args, request)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := strconv.Atoi(childText(template, "SIZE"))
+	if err != nil || size <= 0 {
+		return nil, errAction(request, "Reservation SIZE must be a greater than 0")
+	}
+
+	var vnet *object
+	if networkID := childText(template, "NETWORK_ID"); networkID != "" {
+		id, err := strconv.Atoi(networkID)
+		if err != nil {
+			return nil, errAction(request, "Wrong NETWORK_ID")
+		}
+		if vnet, err = s.pool(kindVirtualNetwork).get(request, id); err != nil {
+			return nil, err
+		}
+	} else {
+		name := childText(template, "NAME")
+		if name == "" {
+			return nil, errAction(request, "NAME for reservation has to be set")
+		}
+
+		reservationTemplate := etree.NewElement("TEMPLATE")
+		reservationTemplate.CreateElement("NAME").SetText(name)
+		reservationTemplate.CreateElement("VN_MAD").SetText(parent.text("VN_MAD"))
+		reservationTemplate.CreateElement("BRIDGE").SetText(parent.text("BRIDGE"))
+
+		vnet = s.createVirtualNetwork(name, sess, reservationTemplate, parent.ID)
+		for _, c := range parent.ids("CLUSTERS") {
+			vnet.addID("CLUSTERS", c)
+		}
+	}
+
+	arID := -1
+	if id := childText(template, "AR_ID"); id != "" {
+		if arID, err = strconv.Atoi(id); err != nil {
+			return nil, errAction(request, "Wrong AR_ID")
+		}
+	}
+
+	for _, ar := range parent.element("AR_POOL").SelectElements("AR") {
+		parentARID, _ := strconv.Atoi(childText(ar, "AR_ID"))
+		if arID != -1 && parentARID != arID {
+			continue
+		}
+
+		arSize, _ := strconv.Atoi(childText(ar, "SIZE"))
+
+		// find first block of free addresses of requested size
+		for offset := 0; offset+size <= arSize; offset++ {
+			free := true
+			for i := offset; i < offset+size && free; i++ {
+				_, mac := addressAt(ar, i)
+				free = !isLeased(ar, mac)
+			}
+			if !free {
+				continue
+			}
+
+			ip, mac := addressAt(ar, offset)
+			reservation := etree.NewElement("AR")
+			reservation.CreateElement("TYPE").SetText(childText(ar, "TYPE"))
+			if ip != "" {
+				reservation.CreateElement("IP").SetText(ip)
+			}
+			reservation.CreateElement("MAC").SetText(mac)
+			reservation.CreateElement("SIZE").SetText(itoa(size))
+			reservation.CreateElement("PARENT_NETWORK_AR_ID").SetText(itoa(parentARID))
+			if err = addAddressRange(request, vnet, reservation); err != nil {
+				return nil, err
+			}
+
+			for i := offset; i < offset+size; i++ {
+				leaseIP, leaseMAC := addressAt(ar, i)
+				addLease(parent, ar, leaseIP, leaseMAC, "VNET", vnet.ID)
+			}
+
+			return vnet.ID, nil
+		}
+	}
+
+	return nil, errAction(request, "Not enough free addresses in an address range")
+}
+
+func vnHold(hold bool) method {
+	request := "VirtualNetworkRelease"
+	if hold {
+		request = "VirtualNetworkHold"
+	}
+
+	return func(s *Server, sess *session, args arguments) (interface{}, error) {
+		vnet, template, err := vnTemplateArgument(s, args, request)
+		if err != nil {
+			return nil, err
+		}
+
+		leaseTemplate := templateChild(template, "LEASES")
+		if leaseTemplate == nil {
+			return nil, errAction(request, "Wrong LEASES template")
+		}
+
+		ip := childText(leaseTemplate, "IP")
+		arID := -1
+		if id := childText(leaseTemplate, "AR_ID"); id != "" {
+			arID, _ = strconv.Atoi(id)
+		}
+
+		for _, ar := range vnet.element("AR_POOL").SelectElements("AR") {
+			if arID != -1 && childText(ar, "AR_ID") != itoa(arID) {
+				continue
+			}
+
+			size, _ := strconv.Atoi(childText(ar, "SIZE"))
+			for offset := 0; offset < size; offset++ {
+				leaseIP, mac := addressAt(ar, offset)
+				if leaseIP != ip {
+					continue
+				}
+
+				if !hold {
+					releaseAddress(vnet, ip)
+					return vnet.ID, nil
+				}
+
+				if isLeased(ar, mac) {
+					return nil, errAction(request, "Error holding lease: IP %s already in use", ip)
+				}
+
+				addLease(vnet, ar, leaseIP, mac, "VM", -1)
+
+				return vnet.ID, nil
+			}
+		}
+
+		return nil, errAction(request, "Error holding lease: IP %s not in any address range", ip)
+	}
+}
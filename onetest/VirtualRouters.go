@@ -0,0 +1,365 @@
+package onetest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+func registerVirtualRouterMethods(s *Server) {
+	s.methods["one.vrouter.allocate"] = vrAllocate
+	s.methods["one.vrouter.instantiate"] = vrInstantiate
+	s.methods["one.vrouter.attachnic"] = vrAttachNIC
+	s.methods["one.vrouter.detachnic"] = vrDetachNIC
+}
+
+func vrAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "VirtualRouterAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new virtual router. No NAME in template.")
+	}
+
+	if other := s.findOwnedByName(kindVirtualRouter, name, sess.UserID); other != nil {
+		return nil, errAllocate(request, "Error allocating a new virtual router. NAME is already taken by "+
+			"VROUTER %d.", other.ID)
+	}
+
+	vr := s.pool(kindVirtualRouter).create(name, sess)
+	vr.element("VMS")
+	vrTemplate := vr.element("TEMPLATE")
+
+	nicID := 0
+	for _, e := range template.ChildElements() {
+		switch e.Tag {
+		case "NAME":
+		case "NIC":
+			nic, err := s.createVRouterNIC(request, vr, e, nicID)
+			if err != nil {
+				s.releaseVRouter(vr)
+				s.pool(kindVirtualRouter).remove(vr.ID)
+				return nil, err
+			}
+			vrTemplate.AddChild(nic)
+			nicID++
+		default:
+			vrTemplate.AddChild(e.Copy())
+		}
+	}
+
+	return vr.ID, nil
+}
+
+// createVRouterNIC creates network interface of the virtual router from NIC template. Floating IP is leased
+// by the virtual router itself.
+func (s *Server) createVRouterNIC(request string, vr *object, template *etree.Element, nicID int) (*etree.Element,
+	error) {
+	networkID, err := strconv.Atoi(childText(template, "NETWORK_ID"))
+	if err != nil {
+		return nil, errAllocate(request, "No NETWORK_ID in NIC.")
+	}
+
+	vnet, err := s.pool(kindVirtualNetwork).get(request, networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	nic := etree.NewElement("NIC")
+	for _, e := range template.ChildElements() {
+		switch e.Tag {
+		case "IP", "NETWORK", "NETWORK_ID", "NIC_ID":
+		default:
+			nic.AddChild(e.Copy())
+		}
+	}
+
+	if strings.EqualFold(childText(template, "FLOATING_IP"), "YES") {
+		lease, err := s.leaseAddress(request, vnet, "VROUTER", vr.ID)
+		if err != nil {
+			return nil, err
+		}
+		nic.CreateElement("IP").SetText(lease.ip)
+	}
+
+	nic.CreateElement("NETWORK").SetText(vnet.text("NAME"))
+	nic.CreateElement("NETWORK_ID").SetText(itoa(networkID))
+	nic.CreateElement("NIC_ID").SetText(itoa(nicID))
+
+	vnet.addID("VROUTERS", vr.ID)
+
+	return nic, nil
+}
+
+// releaseVRouterNIC releases floating IP of the virtual router NIC and removes the virtual router
+// from the virtual network when no other NIC is connected to it.
+func (s *Server) releaseVRouterNIC(vr *object, nic *etree.Element) {
+	networkID, err := strconv.Atoi(childText(nic, "NETWORK_ID"))
+	if err != nil {
+		return
+	}
+
+	vnet, ok := s.pool(kindVirtualNetwork).objects[networkID]
+	if !ok {
+		return
+	}
+
+	if ip := childText(nic, "IP"); ip != "" {
+		releaseAddress(vnet, ip)
+	}
+
+	for _, other := range vr.element("TEMPLATE").SelectElements("NIC") {
+		if other != nic && childText(other, "NETWORK_ID") == itoa(networkID) {
+			return
+		}
+	}
+	vnet.removeID("VROUTERS", vr.ID)
+}
+
+// releaseVRouter terminates virtual machines of the virtual router and releases its NICs.
+func (s *Server) releaseVRouter(vr *object) {
+	for _, id := range vr.ids("VMS") {
+		if vm, ok := s.pool(kindVirtualMachine).objects[id]; ok && vm.intText("STATE") != vmStateDone {
+			s.releaseVM(vm)
+			s.setVMState(vm, vmStateDone, lcmStateLcmInit)
+		}
+	}
+
+	for _, nic := range vr.element("TEMPLATE").SelectElements("NIC") {
+		s.releaseVRouterNIC(vr, nic)
+	}
+}
+
+// leaveVirtualRouter removes the virtual machine from its virtual router.
+func (s *Server) leaveVirtualRouter(vm *object) {
+	id, err := strconv.Atoi(vm.text("TEMPLATE/VROUTER_ID"))
+	if err != nil {
+		return
+	}
+
+	if vr, ok := s.pool(kindVirtualRouter).objects[id]; ok {
+		vr.removeID("VMS", vm.ID)
+	}
+}
+
+func vrArgument(s *Server, args arguments, request string, operation int) (*object, error) {
+	id, err := args.int(0)
+	if err != nil {
+		return nil, err
+	}
+
+	vr, err := s.pool(kindVirtualRouter).get(request, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLock(request, kindVirtualRouter, vr, operation); err != nil {
+		return nil, err
+	}
+
+	return vr, nil
+}
+
+func vrInstantiate(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualRouterInstantiate"
+
+	vr, err := vrArgument(s, args, request, operationManage)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	templateID, err := args.int(2)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := args.optionalString(3, "")
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := args.optionalBool(4, false)
+	if err != nil {
+		return nil, err
+	}
+
+	extra, err := args.optionalString(5, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if number < 1 {
+		return nil, errAction(request, "Number of virtual machines has to be greater than 0.")
+	}
+
+	t, err := s.pool(kindTemplate).get(request, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := t.element("TEMPLATE").Copy()
+	if extra != "" {
+		content, err := parseTemplate(request, extra)
+		if err != nil {
+			return nil, err
+		}
+		mergeTemplate(template, content)
+	}
+
+	// virtual machines are connected to the networks of the virtual router only
+	for _, nic := range template.SelectElements("NIC") {
+		template.RemoveChild(nic)
+	}
+	template.CreateElement("TEMPLATE_ID").SetText(itoa(templateID))
+	template.CreateElement("VROUTER_ID").SetText(itoa(vr.ID))
+
+	if name == "" {
+		name = "vr-" + vr.text("NAME") + "-%i"
+	}
+
+	for i := 0; i < number; i++ {
+		vmID, err := s.createVM(request, sess, template, hold, strings.Replace(name, "%i", itoa(i), -1))
+		if err != nil {
+			return nil, err
+		}
+
+		vm := s.pool(kindVirtualMachine).objects[vmID]
+		for _, vrNIC := range vr.element("TEMPLATE").SelectElements("NIC") {
+			if err = s.addVRouterVMNIC(request, vm, vrNIC); err != nil {
+				s.releaseVM(vm)
+				s.pool(kindVirtualMachine).remove(vmID)
+				return nil, err
+			}
+		}
+
+		vr.addID("VMS", vmID)
+	}
+
+	return vr.ID, nil
+}
+
+// addVRouterVMNIC connects the virtual machine of the virtual router to the network of the virtual router NIC,
+// the virtual machine NIC has the same NIC_ID.
+func (s *Server) addVRouterVMNIC(request string, vm *object, vrNIC *etree.Element) error {
+	nicID, _ := strconv.Atoi(childText(vrNIC, "NIC_ID"))
+
+	template := etree.NewElement("NIC")
+	template.CreateElement("NETWORK_ID").SetText(childText(vrNIC, "NETWORK_ID"))
+
+	nic, err := s.createVMNIC(request, vm, template, nicID)
+	if err != nil {
+		return err
+	}
+
+	if ip := childText(vrNIC, "IP"); ip != "" {
+		nic.CreateElement("VROUTER_IP").SetText(ip)
+	}
+	vm.element("TEMPLATE").AddChild(nic)
+
+	return nil
+}
+
+func vrAttachNIC(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualRouterAttachNic"
+
+	vr, err := vrArgument(s, args, request, operationManage)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := args.string(1)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	nicTemplate := templateChild(template, "NIC")
+	if nicTemplate == nil {
+		return nil, errAction(request, "Wrong NIC template")
+	}
+
+	nicID := 0
+	for _, nic := range vr.element("TEMPLATE").SelectElements("NIC") {
+		if id, err := strconv.Atoi(childText(nic, "NIC_ID")); err == nil && id >= nicID {
+			nicID = id + 1
+		}
+	}
+
+	nic, err := s.createVRouterNIC(request, vr, nicTemplate, nicID)
+	if err != nil {
+		return nil, err
+	}
+	vr.element("TEMPLATE").AddChild(nic)
+
+	for _, id := range vr.ids("VMS") {
+		if err = s.addVRouterVMNIC(request, s.pool(kindVirtualMachine).objects[id], nic); err != nil {
+			return nil, err
+		}
+	}
+
+	return vr.ID, nil
+}
+
+func vrDetachNIC(s *Server, sess *session, args arguments) (interface{}, error) {
+	request := "VirtualRouterDetachNic"
+
+	vr, err := vrArgument(s, args, request, operationManage)
+	if err != nil {
+		return nil, err
+	}
+
+	nicID, err := args.int(1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nic := range vr.element("TEMPLATE").SelectElements("NIC") {
+		if childText(nic, "NIC_ID") != itoa(nicID) {
+			continue
+		}
+
+		for _, id := range vr.ids("VMS") {
+			vm := s.pool(kindVirtualMachine).objects[id]
+			if vmNIC := findNIC(vm, nicID); vmNIC != nil {
+				s.releaseNIC(vm, vmNIC)
+				vm.element("TEMPLATE").RemoveChild(vmNIC)
+			}
+		}
+
+		s.releaseVRouterNIC(vr, nic)
+		vr.element("TEMPLATE").RemoveChild(nic)
+
+		return vr.ID, nil
+	}
+
+	return nil, errAction(request, "NIC with NIC_ID %d does not exist", nicID)
+}
+
+func findNIC(vm *object, nicID int) *etree.Element {
+	for _, nic := range vm.element("TEMPLATE").SelectElements("NIC") {
+		if childText(nic, "NIC_ID") == itoa(nicID) {
+			return nic
+		}
+	}
+	return nil
+}
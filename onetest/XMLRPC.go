@@ -0,0 +1,149 @@
+package onetest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// methodCall represents decoded XML-RPC request.
+type methodCall struct {
+	Name   string
+	Params []interface{}
+}
+
+// decodeMethodCall parses XML-RPC request body into method name and Go values.
+// Supported types are string, int (i4, int), boolean, double, base64, array and struct.
+func decodeMethodCall(data []byte) (*methodCall, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, err
+	}
+
+	nameElement := doc.FindElement("methodCall/methodName")
+	if nameElement == nil {
+		return nil, fmt.Errorf("no methodName in request")
+	}
+
+	valueElements := doc.FindElements("methodCall/params/param/value")
+	params := make([]interface{}, len(valueElements))
+
+	for i, e := range valueElements {
+		value, err := decodeValue(e)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = value
+	}
+
+	return &methodCall{Name: strings.TrimSpace(nameElement.Text()), Params: params}, nil
+}
+
+func decodeValue(e *etree.Element) (interface{}, error) {
+	children := e.ChildElements()
+	if len(children) == 0 {
+		// string is the default type
+		return e.Text(), nil
+	}
+
+	child := children[0]
+	switch child.Tag {
+	case "string":
+		return child.Text(), nil
+	case "int", "i4", "i8":
+		return strconv.Atoi(strings.TrimSpace(child.Text()))
+	case "boolean":
+		return strings.TrimSpace(child.Text()) == "1", nil
+	case "double":
+		return strconv.ParseFloat(strings.TrimSpace(child.Text()), 64)
+	case "base64":
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(child.Text()))
+	case "array":
+		values := child.FindElements("data/value")
+		array := make([]interface{}, len(values))
+		for i, v := range values {
+			value, err := decodeValue(v)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = value
+		}
+		return array, nil
+	case "struct":
+		members := child.SelectElements("member")
+		structure := make(map[string]interface{}, len(members))
+		for _, m := range members {
+			name := m.SelectElement("name")
+			v := m.SelectElement("value")
+			if name == nil || v == nil {
+				return nil, fmt.Errorf("malformed struct member")
+			}
+			value, err := decodeValue(v)
+			if err != nil {
+				return nil, err
+			}
+			structure[name.Text()] = value
+		}
+		return structure, nil
+	default:
+		return nil, fmt.Errorf("unsupported XML-RPC type %s", child.Tag)
+	}
+}
+
+// encodeResponse renders XML-RPC response in the same layout as OpenNebula (xmlrpc-c) does.
+func encodeResponse(values []interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\r\n<methodResponse>\r\n<params>\r\n" +
+		"<param><value><array><data>\r\n")
+
+	for _, v := range values {
+		if err := encodeValue(&buffer, v); err != nil {
+			return nil, err
+		}
+		buffer.WriteString("\r\n")
+	}
+
+	buffer.WriteString("</data></array></value></param>\r\n</params>\r\n</methodResponse>\r\n")
+
+	return buffer.Bytes(), nil
+}
+
+func encodeValue(buffer *bytes.Buffer, v interface{}) error {
+	buffer.WriteString("<value>")
+
+	switch value := v.(type) {
+	case string:
+		buffer.WriteString("<string>")
+		if err := escapeText(buffer, value); err != nil {
+			return err
+		}
+		buffer.WriteString("</string>")
+	case int:
+		buffer.WriteString("<i4>" + strconv.Itoa(value) + "</i4>")
+	case bool:
+		if value {
+			buffer.WriteString("<boolean>1</boolean>")
+		} else {
+			buffer.WriteString("<boolean>0</boolean>")
+		}
+	case float64:
+		buffer.WriteString("<double>" + strconv.FormatFloat(value, 'f', -1, 64) + "</double>")
+	default:
+		return fmt.Errorf("unsupported response type %T", v)
+	}
+
+	buffer.WriteString("</value>")
+
+	return nil
+}
+
+func escapeText(buffer *bytes.Buffer, s string) error {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	_, err := replacer.WriteString(buffer, s)
+	return err
+}
@@ -0,0 +1,47 @@
+package onetest
+
+// defaultEndpoint is the endpoint of the server zone until the HTTP listener of the server is started.
+const defaultEndpoint = "http://localhost:2633/RPC2"
+
+func registerZoneMethods(s *Server) {
+	s.methods["one.zone.allocate"] = zoneAllocate
+}
+
+func zoneAllocate(s *Server, sess *session, args arguments) (interface{}, error) {
+	text, err := args.string(0)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "ZoneAllocate"
+
+	template, err := parseTemplate(request, text)
+	if err != nil {
+		return nil, err
+	}
+
+	name := childText(template, "NAME")
+	if name == "" {
+		return nil, errAllocate(request, "Error allocating a new zone. No NAME in template.")
+	}
+
+	if childText(template, "ENDPOINT") == "" {
+		return nil, errAllocate(request, "Error allocating a new zone. No ENDPOINT in template.")
+	}
+
+	if other := s.pool(kindZone).findByName(name); other != nil {
+		return nil, errAllocate(request, "Error allocating a new zone. NAME is already taken by ZONE %d.",
+			other.ID)
+	}
+
+	z := s.pool(kindZone).create(name, sess)
+	zoneTemplate := z.element("TEMPLATE")
+	for _, e := range template.ChildElements() {
+		if e.Tag != "NAME" {
+			zoneTemplate.AddChild(e.Copy())
+		}
+	}
+	z.element("SERVER_POOL")
+
+	return z.ID, nil
+}
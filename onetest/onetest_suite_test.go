@@ -0,0 +1,13 @@
+package onetest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOnetest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Onetest Suite")
+}
@@ -0,0 +1,124 @@
+package requests
+
+import (
+	"github.com/onego-project/onego/resources"
+)
+
+// ACLRuleRequestBuilder structure to create ACL rule
+type ACLRuleRequestBuilder struct {
+	rule resources.ACLRule
+	err  error
+}
+
+var aclRights = map[PermissionType]resources.ACLRights{
+	Use:    resources.ACLRightUse,
+	Manage: resources.ACLRightManage,
+	Admin:  resources.ACLRightAdmin,
+}
+
+// ACLRuleBuilder constructs ACLRuleRequestBuilder. The rule applies to all users and all resources
+// of chosen kinds in the zone of the OpenNebula frontend by default, e.g.
+//
+//	ACLRuleBuilder().ForGroup(group).On(resources.VMKind).InCluster(cluster).Allow(Use, Manage).Build()
+func ACLRuleBuilder() *ACLRuleRequestBuilder {
+	return &ACLRuleRequestBuilder{rule: resources.ACLRule{ID: -1,
+		User:      resources.ACLScope{Type: resources.ACLScopeAll},
+		Resources: resources.ACLScope{Type: resources.ACLScopeAll}}}
+}
+
+func (arb *ACLRuleRequestBuilder) scope(scopeType resources.ACLScopeType,
+	object interface{ ID() (int, error) }) resources.ACLScope {
+	id, err := object.ID()
+	if err != nil && arb.err == nil {
+		arb.err = err
+	}
+
+	return resources.ACLScope{Type: scopeType, ID: id}
+}
+
+// ForUser to apply the rule to given user
+func (arb *ACLRuleRequestBuilder) ForUser(user resources.User) *ACLRuleRequestBuilder {
+	arb.rule.User = arb.scope(resources.ACLScopeIndividual, &user)
+	return arb
+}
+
+// ForGroup to apply the rule to users in given group
+func (arb *ACLRuleRequestBuilder) ForGroup(group resources.Group) *ACLRuleRequestBuilder {
+	arb.rule.User = arb.scope(resources.ACLScopeGroup, &group)
+	return arb
+}
+
+// ForAll to apply the rule to all users
+func (arb *ACLRuleRequestBuilder) ForAll() *ACLRuleRequestBuilder {
+	arb.rule.User = resources.ACLScope{Type: resources.ACLScopeAll}
+	return arb
+}
+
+// On to choose kinds of resources the rule applies to
+func (arb *ACLRuleRequestBuilder) On(kinds ...resources.Kind) *ACLRuleRequestBuilder {
+	for _, kind := range kinds {
+		arb.rule.Kinds |= kind
+	}
+
+	return arb
+}
+
+// WithID to apply the rule only to the resource with given ID
+func (arb *ACLRuleRequestBuilder) WithID(id int) *ACLRuleRequestBuilder {
+	arb.rule.Resources = resources.ACLScope{Type: resources.ACLScopeIndividual, ID: id}
+	return arb
+}
+
+// InGroup to apply the rule only to the resources belonging to given group
+func (arb *ACLRuleRequestBuilder) InGroup(group resources.Group) *ACLRuleRequestBuilder {
+	arb.rule.Resources = arb.scope(resources.ACLScopeGroup, &group)
+	return arb
+}
+
+// InCluster to apply the rule only to the resources in given cluster
+func (arb *ACLRuleRequestBuilder) InCluster(cluster resources.Cluster) *ACLRuleRequestBuilder {
+	arb.rule.Resources = arb.scope(resources.ACLScopeCluster, &cluster)
+	return arb
+}
+
+// InZone to apply the rule in the zone with given ID
+func (arb *ACLRuleRequestBuilder) InZone(zoneID int) *ACLRuleRequestBuilder {
+	arb.rule.Zone = &resources.ACLScope{Type: resources.ACLScopeIndividual, ID: zoneID}
+	return arb
+}
+
+// InAllZones to apply the rule in all zones of the federation
+func (arb *ACLRuleRequestBuilder) InAllZones() *ACLRuleRequestBuilder {
+	arb.rule.Zone = &resources.ACLScope{Type: resources.ACLScopeAll}
+	return arb
+}
+
+// Allow to allow given operations on the resources
+func (arb *ACLRuleRequestBuilder) Allow(permissionTypes ...PermissionType) *ACLRuleRequestBuilder {
+	for _, pt := range permissionTypes {
+		arb.rule.Rights |= aclRights[pt]
+	}
+
+	return arb
+}
+
+// AllowCreate to allow creation of the resources
+func (arb *ACLRuleRequestBuilder) AllowCreate() *ACLRuleRequestBuilder {
+	arb.rule.Rights |= resources.ACLRightCreate
+	return arb
+}
+
+// Build to create ACL rule
+func (arb *ACLRuleRequestBuilder) Build() (*resources.ACLRule, error) {
+	if arb.err != nil {
+		return nil, arb.err
+	}
+
+	rule := arb.rule
+	if rule.Zone != nil {
+		zone := *rule.Zone
+		rule.Zone = &zone
+	}
+
+	return &rule, nil
+}
@@ -0,0 +1,230 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind represents type of OpenNebula resource as encoded in ACL rules. Kinds can be combined
+// using bitwise or, e.g. VMKind | ImageKind.
+type Kind uint64
+
+// Kinds of OpenNebula resources
+const (
+	VMKind             Kind = 0x1000000000
+	HostKind           Kind = 0x2000000000
+	NetworkKind        Kind = 0x4000000000
+	ImageKind          Kind = 0x8000000000
+	UserKind           Kind = 0x10000000000
+	TemplateKind       Kind = 0x20000000000
+	GroupKind          Kind = 0x40000000000
+	DatastoreKind      Kind = 0x100000000000
+	ClusterKind        Kind = 0x200000000000
+	DocumentKind       Kind = 0x400000000000
+	ZoneKind           Kind = 0x800000000000
+	SecurityGroupKind  Kind = 0x1000000000000
+	VDCKind            Kind = 0x2000000000000
+	VirtualRouterKind  Kind = 0x4000000000000
+	MarketplaceKind    Kind = 0x8000000000000
+	MarketplaceAppKind Kind = 0x10000000000000
+	VMGroupKind        Kind = 0x20000000000000
+	VNTemplateKind     Kind = 0x40000000000000
+)
+
+var kinds = []Kind{VMKind, HostKind, NetworkKind, ImageKind, UserKind, TemplateKind, GroupKind, DatastoreKind,
+	ClusterKind, DocumentKind, ZoneKind, SecurityGroupKind, VDCKind, VirtualRouterKind, MarketplaceKind,
+	MarketplaceAppKind, VMGroupKind, VNTemplateKind}
+
+var kindNames = map[Kind]string{
+	VMKind:             "VM",
+	HostKind:           "HOST",
+	NetworkKind:        "NET",
+	ImageKind:          "IMAGE",
+	UserKind:           "USER",
+	TemplateKind:       "TEMPLATE",
+	GroupKind:          "GROUP",
+	DatastoreKind:      "DATASTORE",
+	ClusterKind:        "CLUSTER",
+	DocumentKind:       "DOCUMENT",
+	ZoneKind:           "ZONE",
+	SecurityGroupKind:  "SECGROUP",
+	VDCKind:            "VDC",
+	VirtualRouterKind:  "VROUTER",
+	MarketplaceKind:    "MARKETPLACE",
+	MarketplaceAppKind: "MARKETPLACEAPP",
+	VMGroupKind:        "VMGROUP",
+	VNTemplateKind:     "VNTEMPLATE",
+}
+
+// String returns names of the kinds joined by "+", e.g. "VM+IMAGE".
+func (k Kind) String() string {
+	names := make([]string, 0)
+	for _, kind := range kinds {
+		if k&kind != 0 {
+			names = append(names, kindNames[kind])
+		}
+	}
+
+	return strings.Join(names, "+")
+}
+
+// ACLScopeType says which objects (users, resources or zones) an ACL rule applies to.
+type ACLScopeType uint64
+
+const (
+	// ACLScopeIndividual - object with given ID, "#<id>"
+	ACLScopeIndividual ACLScopeType = 0x100000000
+	// ACLScopeGroup - objects belonging to group with given ID, "@<id>"
+	ACLScopeGroup ACLScopeType = 0x200000000
+	// ACLScopeAll - all objects, "*"
+	ACLScopeAll ACLScopeType = 0x400000000
+	// ACLScopeCluster - objects in cluster with given ID, "%<id>"
+	ACLScopeCluster ACLScopeType = 0x800000000
+)
+
+const base16 = 16
+
+const (
+	aclScopeMask = 0xF00000000
+	aclIDMask    = 0xFFFFFFFF
+	aclKindMask  = ^uint64(aclScopeMask | aclIDMask)
+)
+
+var aclScopePrefixes = map[ACLScopeType]string{
+	ACLScopeIndividual: "#",
+	ACLScopeGroup:      "@",
+	ACLScopeCluster:    "%",
+}
+
+// ACLScope structure represents users, resources or zones an ACL rule applies to.
+type ACLScope struct {
+	Type ACLScopeType
+	ID   int
+}
+
+func (s ACLScope) encode() uint64 {
+	if s.Type == ACLScopeAll {
+		return uint64(s.Type)
+	}
+
+	return uint64(s.Type) | uint64(uint32(s.ID))
+}
+
+// String returns scope in the notation of oneacl command, e.g. "@100".
+func (s ACLScope) String() string {
+	if s.Type == ACLScopeAll {
+		return "*"
+	}
+
+	return fmt.Sprintf("%s%d", aclScopePrefixes[s.Type], s.ID)
+}
+
+func decodeACLScope(value uint64) (ACLScope, error) {
+	scopeType := ACLScopeType(value & aclScopeMask)
+	if _, ok := aclScopePrefixes[scopeType]; !ok && scopeType != ACLScopeAll {
+		return ACLScope{}, fmt.Errorf("unable to find ACLScopeType of value: %x", uint64(scopeType))
+	}
+
+	return ACLScope{Type: scopeType, ID: int(value & aclIDMask)}, nil
+}
+
+// ACLRights represents operations allowed by an ACL rule. Rights can be combined using bitwise or.
+type ACLRights uint64
+
+// ACL rights
+const (
+	ACLRightUse    ACLRights = 0x1
+	ACLRightManage ACLRights = 0x2
+	ACLRightAdmin  ACLRights = 0x4
+	ACLRightCreate ACLRights = 0x8
+)
+
+// String returns names of the rights joined by "+", e.g. "USE+MANAGE".
+func (r ACLRights) String() string {
+	names := make([]string, 0)
+	for _, right := range []struct {
+		right ACLRights
+		name  string
+	}{{ACLRightUse, "USE"}, {ACLRightManage, "MANAGE"}, {ACLRightAdmin, "ADMIN"}, {ACLRightCreate, "CREATE"}} {
+		if r&right.right != 0 {
+			names = append(names, right.name)
+		}
+	}
+
+	return strings.Join(names, "+")
+}
+
+// ACLRule structure represents OpenNebula ACL rule: users (User) are allowed to perform operations (Rights)
+// on resources of given kinds (Kinds) selected by Resources in given zone (Zone).
+type ACLRule struct {
+	ID        int
+	User      ACLScope
+	Kinds     Kind
+	Resources ACLScope
+	Rights    ACLRights
+	// Zone is nil for rules applied to the zone of the OpenNebula frontend
+	Zone *ACLScope
+}
+
+// ParseACLRule parses ACL rule from hexadecimal encoded user, resource, rights and zone the way
+// OpenNebula stores them, e.g. "200000064", "1000400000000", "3", "100000000".
+// Zone can be empty for rules applied to the zone of the OpenNebula frontend.
+func ParseACLRule(user, resource, rights, zone string) (*ACLRule, error) {
+	var values [4]uint64
+	for i, text := range []string{user, resource, rights, zone} {
+		if i == 3 && text == "" {
+			break
+		}
+
+		value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(text), "0x"), base16, bitSize64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	rule := &ACLRule{ID: -1, Kinds: Kind(values[1] & aclKindMask), Rights: ACLRights(values[2])}
+
+	var err error
+	if rule.User, err = decodeACLScope(values[0]); err != nil {
+		return nil, err
+	}
+
+	if rule.Resources, err = decodeACLScope(values[1]); err != nil {
+		return nil, err
+	}
+
+	if zone != "" {
+		var zoneScope ACLScope
+		if zoneScope, err = decodeACLScope(values[3]); err != nil {
+			return nil, err
+		}
+		rule.Zone = &zoneScope
+	}
+
+	return rule, nil
+}
+
+// Encode renders user, resource, rights and zone of the rule in the hexadecimal encoding used by OpenNebula.
+// Zone is empty for rules without zone.
+func (r *ACLRule) Encode() (user, resource, rights, zone string) {
+	user = strconv.FormatUint(r.User.encode(), base16)
+	resource = strconv.FormatUint(uint64(r.Kinds)|r.Resources.encode(), base16)
+	rights = strconv.FormatUint(uint64(r.Rights), base16)
+	if r.Zone != nil {
+		zone = strconv.FormatUint(r.Zone.encode(), base16)
+	}
+
+	return user, resource, rights, zone
+}
+
+// String returns the rule in the notation of oneacl command, e.g. "@100 VM+NET/* USE+MANAGE #0".
+func (r *ACLRule) String() string {
+	rule := fmt.Sprintf("%s %s/%s %s", r.User, r.Kinds, r.Resources, r.Rights)
+	if r.Zone != nil {
+		rule += " " + r.Zone.String()
+	}
+
+	return rule
+}
@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("ACLRule", func() {
+	var (
+		rule *ACLRule
+		err  error
+	)
+
+	ginkgo.Context("when rule is valid", func() {
+		ginkgo.BeforeEach(func() {
+			rule, err = ParseACLRule("200000064", "5400000000", "3", "100000000")
+		})
+
+		ginkgo.It("should parse all the parts of the rule", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			gomega.Expect(rule.ID).To(gomega.Equal(-1))
+			gomega.Expect(rule.User).To(gomega.Equal(ACLScope{Type: ACLScopeGroup, ID: 100}))
+			gomega.Expect(rule.Kinds).To(gomega.Equal(VMKind | NetworkKind))
+			gomega.Expect(rule.Resources).To(gomega.Equal(ACLScope{Type: ACLScopeAll}))
+			gomega.Expect(rule.Rights).To(gomega.Equal(ACLRightUse | ACLRightManage))
+			gomega.Expect(rule.Zone).To(gomega.Equal(&ACLScope{Type: ACLScopeIndividual, ID: 0}))
+		})
+
+		ginkgo.It("should encode the rule back", func() {
+			user, resource, rights, zone := rule.Encode()
+			gomega.Expect(user).To(gomega.Equal("200000064"))
+			gomega.Expect(resource).To(gomega.Equal("5400000000"))
+			gomega.Expect(rights).To(gomega.Equal("3"))
+			gomega.Expect(zone).To(gomega.Equal("100000000"))
+		})
+
+		ginkgo.It("should render the rule in oneacl notation", func() {
+			gomega.Expect(rule.String()).To(gomega.Equal("@100 VM+NET/* USE+MANAGE #0"))
+		})
+	})
+
+	ginkgo.Context("when rule has no zone", func() {
+		ginkgo.It("should parse and encode rule with resources in cluster", func() {
+			rule, err = ParseACLRule("0x400000000", "0x20800000064", "0xc", "")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(rule.User).To(gomega.Equal(ACLScope{Type: ACLScopeAll}))
+			gomega.Expect(rule.Kinds).To(gomega.Equal(TemplateKind))
+			gomega.Expect(rule.Resources).To(gomega.Equal(ACLScope{Type: ACLScopeCluster, ID: 100}))
+			gomega.Expect(rule.Rights).To(gomega.Equal(ACLRightAdmin | ACLRightCreate))
+			gomega.Expect(rule.Zone).To(gomega.BeNil())
+			gomega.Expect(rule.String()).To(gomega.Equal("* TEMPLATE/%100 ADMIN+CREATE"))
+
+			_, _, _, zone := rule.Encode()
+			gomega.Expect(zone).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Context("when rule is malformed", func() {
+		ginkgo.It("should return that user isn't hexadecimal number", func() {
+			_, err = ParseACLRule("@100", "5400000000", "3", "")
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should return that user has no scope", func() {
+			_, err = ParseACLRule("64", "5400000000", "3", "")
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
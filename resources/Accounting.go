@@ -0,0 +1,182 @@
+package resources
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+)
+
+// HistoryVM structure represents the virtual machine the history record belongs to. It is a part
+// of the history records retrieved by accounting only.
+type HistoryVM struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	UID    int     `json:"uid"`
+	GID    int     `json:"gid"`
+	UName  string  `json:"uname"`
+	GName  string  `json:"gname"`
+	CPU    float64 `json:"cpu"`
+	Memory int     `json:"memory"`
+}
+
+// Showback structure represents monthly cost of a virtual machine.
+type Showback struct {
+	VMID       int     `json:"vm_id"`
+	VMName     string  `json:"vm_name"`
+	UID        int     `json:"uid"`
+	GID        int     `json:"gid"`
+	UName      string  `json:"uname"`
+	GName      string  `json:"gname"`
+	Year       int     `json:"year"`
+	Month      int     `json:"month"`
+	CPUCost    float64 `json:"cpu_cost"`
+	MemoryCost float64 `json:"memory_cost"`
+	DiskCost   float64 `json:"disk_cost"`
+	TotalCost  float64 `json:"total_cost"`
+	Hours      float64 `json:"hours"`
+}
+
+// Usage structure represents resources consumed by virtual machines of a user or group.
+// Memory hours are in MB-hours.
+type Usage struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	CPUHours    float64 `json:"cpu_hours"`
+	MemoryHours float64 `json:"memory_hours"`
+}
+
+// UsageGrouping type to choose whether usage is aggregated per user or per group.
+type UsageGrouping int
+
+const (
+	// UsageByUser - usage aggregated per owner of the virtual machines
+	UsageByUser UsageGrouping = iota
+	// UsageByGroup - usage aggregated per group of the virtual machines
+	UsageByGroup
+)
+
+// ParseHistory parses history record from HISTORY element, e.g. record retrieved by accounting.
+func ParseHistory(element *etree.Element) (*History, error) {
+	return createHistoryFromElement(element)
+}
+
+func createHistoryVMFromElement(element *etree.Element) (*HistoryVM, error) {
+	ints, err := parseIntsFromElement(element, []string{"ID", "UID", "GID"})
+	if err != nil {
+		return nil, err
+	}
+
+	strs := parseStringsFromElementWithoutError(element, []string{"NAME", "UNAME", "GNAME"})
+
+	vm := &HistoryVM{ID: ints[0], UID: ints[1], GID: ints[2], Name: strs[0], UName: strs[1], GName: strs[2]}
+
+	if cpu := element.FindElement("TEMPLATE/CPU"); cpu != nil {
+		if vm.CPU, err = strconv.ParseFloat(cpu.Text(), bitSize64); err != nil {
+			return nil, err
+		}
+	}
+
+	if memory := element.FindElement("TEMPLATE/MEMORY"); memory != nil {
+		if vm.Memory, err = strconv.Atoi(memory.Text()); err != nil {
+			return nil, err
+		}
+	}
+
+	return vm, nil
+}
+
+// ParseShowback parses showback record from SHOWBACK element.
+func ParseShowback(element *etree.Element) (*Showback, error) {
+	ints, err := parseIntsFromElement(element, []string{"VMID", "UID", "GID", "YEAR", "MONTH"})
+	if err != nil {
+		return nil, err
+	}
+
+	strs := parseStringsFromElementWithoutError(element, []string{"VMNAME", "UNAME", "GNAME"})
+
+	floats := make([]float64, 5)
+	for i, tag := range []string{"CPU_COST", "MEMORY_COST", "DISK_COST", "TOTAL_COST", "HOURS"} {
+		e := element.SelectElement(tag)
+		if e == nil {
+			if tag == "DISK_COST" {
+				continue
+			}
+			return nil, &errors.XMLElementError{Path: tag}
+		}
+
+		if floats[i], err = strconv.ParseFloat(e.Text(), bitSize64); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Showback{VMID: ints[0], UID: ints[1], GID: ints[2], Year: ints[3], Month: ints[4], VMName: strs[0],
+		UName: strs[1], GName: strs[2], CPUCost: floats[0], MemoryCost: floats[1], DiskCost: floats[2],
+		TotalCost: floats[3], Hours: floats[4]}, nil
+}
+
+// AggregateUsage sums CPU hours and memory hours of the history records per user or group. Only the running
+// part of the records (from RSTIME to RETIME) in the time range from start to end is counted. Zero start
+// means no lower limit, zero end means the current time is used for records which haven't ended yet.
+// The records have to be retrieved by accounting. Usage is sorted by user or group ID.
+func AggregateUsage(records []*History, grouping UsageGrouping, start, end time.Time) ([]*Usage, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	usage := make(map[int]*Usage)
+	for _, record := range records {
+		if record.VM == nil {
+			return nil, errors.ErrHistoryNoVM
+		}
+
+		// only the time the virtual machine was running is counted, the record ends at ETIME when the running
+		// period wasn't closed (e.g. the virtual machine failed)
+		from, to := record.RSTime, record.RETime
+		if from == nil {
+			continue
+		}
+		if to == nil {
+			to = record.ETime
+		}
+
+		recordStart := *from
+		if recordStart.Before(start) {
+			recordStart = start
+		}
+
+		recordEnd := end
+		if to != nil && to.Before(end) {
+			recordEnd = *to
+		}
+
+		if !recordEnd.After(recordStart) {
+			continue
+		}
+
+		id, name := record.VM.UID, record.VM.UName
+		if grouping == UsageByGroup {
+			id, name = record.VM.GID, record.VM.GName
+		}
+
+		u, ok := usage[id]
+		if !ok {
+			u = &Usage{ID: id, Name: name}
+			usage[id] = u
+		}
+
+		hours := recordEnd.Sub(recordStart).Hours()
+		u.CPUHours += hours * record.VM.CPU
+		u.MemoryHours += hours * float64(record.VM.Memory)
+	}
+
+	aggregated := make([]*Usage, 0, len(usage))
+	for _, u := range usage {
+		aggregated = append(aggregated, u)
+	}
+	sort.Slice(aggregated, func(i, j int) bool { return aggregated[i].ID < aggregated[j].ID })
+
+	return aggregated, nil
+}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	accountingXML = "xml/accounting.xml"
+	showbackXML   = "xml/showback.xml"
+)
+
+var _ = ginkgo.Describe("Accounting", func() {
+	var (
+		doc     *etree.Document
+		records []*History
+		err     error
+	)
+
+	ginkgo.BeforeEach(func() {
+		doc = etree.NewDocument()
+		err = doc.ReadFromFile(accountingXML)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		elements := doc.FindElements("HISTORY_RECORDS/HISTORY")
+		records = make([]*History, len(elements))
+		for i, e := range elements {
+			records[i], err = ParseHistory(e)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+	})
+
+	ginkgo.Describe("ParseHistory", func() {
+		ginkgo.It("should parse history record with virtual machine", func() {
+			gomega.Expect(records).To(gomega.HaveLen(3))
+			gomega.Expect(records[0].OID).To(gomega.Equal(12))
+			gomega.Expect(records[0].Hostname).To(gomega.Equal("node-1"))
+			gomega.Expect(records[0].VM).To(gomega.Equal(&HistoryVM{ID: 12, Name: "web-1", UID: 5, GID: 100,
+				UName: "alice", GName: "research", CPU: 0.5, Memory: 2048}))
+			gomega.Expect(records[1].ETime).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should return an error when virtual machine has no owner", func() {
+			vm := doc.FindElement("HISTORY_RECORDS/HISTORY/VM")
+			vm.RemoveChild(vm.SelectElement("UID"))
+
+			_, err = ParseHistory(doc.FindElement("HISTORY_RECORDS/HISTORY"))
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Describe("ParseShowback", func() {
+		ginkgo.It("should parse showback record", func() {
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(showbackXML)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var showback *Showback
+			showback, err = ParseShowback(doc.FindElement("SHOWBACK_RECORDS/SHOWBACK"))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(showback).To(gomega.Equal(&Showback{VMID: 12, VMName: "web-1", UID: 5, GID: 100,
+				UName: "alice", GName: "research", Year: 2018, Month: 12, CPUCost: 1.5, MemoryCost: 2.25,
+				DiskCost: 0.25, TotalCost: 4, Hours: 8.5}))
+		})
+	})
+
+	ginkgo.Describe("AggregateUsage", func() {
+		var usage []*Usage
+
+		ginkgo.It("should aggregate usage per user", func() {
+			usage, err = AggregateUsage(records, UsageByUser, time.Time{}, time.Unix(1546315200, 0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage).To(gomega.Equal([]*Usage{
+				{ID: 5, Name: "alice", CPUHours: 3, MemoryHours: 12288},
+				{ID: 6, Name: "bob", CPUHours: 4, MemoryHours: 8192}}))
+		})
+
+		ginkgo.It("should aggregate usage per group", func() {
+			usage, err = AggregateUsage(records, UsageByGroup, time.Time{}, time.Unix(1546315200, 0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage).To(gomega.Equal([]*Usage{{ID: 100, Name: "research", CPUHours: 7,
+				MemoryHours: 20480}}))
+		})
+
+		ginkgo.It("should count only usage in the time range", func() {
+			usage, err = AggregateUsage(records, UsageByUser, time.Unix(1546300800, 0), time.Unix(1546304400, 0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage).To(gomega.Equal([]*Usage{
+				{ID: 5, Name: "alice", CPUHours: 0.5, MemoryHours: 2048},
+				{ID: 6, Name: "bob", CPUHours: 2, MemoryHours: 4096}}))
+		})
+
+		ginkgo.It("should skip records which weren't running", func() {
+			records[2].RSTime = nil
+
+			usage, err = AggregateUsage(records, UsageByUser, time.Time{}, time.Unix(1546315200, 0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage).To(gomega.Equal([]*Usage{{ID: 5, Name: "alice", CPUHours: 3, MemoryHours: 12288}}))
+		})
+
+		ginkgo.It("should end running period at ETIME when RETIME is missing", func() {
+			etime := time.Unix(1546311600, 0)
+			records[1].ETime = &etime
+
+			usage, err = AggregateUsage(records, UsageByUser, time.Time{}, time.Unix(1546315200, 0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage[0]).To(gomega.Equal(&Usage{ID: 5, Name: "alice", CPUHours: 2.5,
+				MemoryHours: 10240}))
+		})
+
+		ginkgo.It("should return an error for records not retrieved by accounting", func() {
+			records[1].VM = nil
+
+			_, err = AggregateUsage(records, UsageByUser, time.Time{}, time.Time{})
+			gomega.Expect(err).To(gomega.Equal(errors.ErrHistoryNoVM))
+		})
+	})
+})
@@ -1,12 +1,36 @@
 package resources
 
-import "github.com/beevik/etree"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+)
 
 // Cluster structure represents OpenNebula cluster
 type Cluster struct {
 	Resource
 }
 
+// ClusterCapacity structure represents summary of capacity of the hosts in a cluster. CPU is in percents
+// (100 per core) and memory in KB. Reserved capacity is not available to virtual machines, allocated
+// capacity is requested by the virtual machines and used capacity is measured on the hosts.
+type ClusterCapacity struct {
+	ClusterID       int `json:"cluster_id"`
+	Hosts           int `json:"hosts"`
+	TotalCPU        int `json:"total_cpu"`
+	ReservedCPU     int `json:"reserved_cpu"`
+	AllocatedCPU    int `json:"allocated_cpu"`
+	UsedCPU         int `json:"used_cpu"`
+	AvailableCPU    int `json:"available_cpu"`
+	TotalMemory     int `json:"total_memory"`
+	ReservedMemory  int `json:"reserved_memory"`
+	AllocatedMemory int `json:"allocated_memory"`
+	UsedMemory      int `json:"used_memory"`
+	AvailableMemory int `json:"available_memory"`
+}
+
 // CreateClusterWithID constructs User with id
 func CreateClusterWithID(id int) *Cluster {
 	return &Cluster{*CreateResource("CLUSTER", id)}
@@ -31,3 +55,143 @@ func (c *Cluster) Datastores() ([]int, error) {
 func (c *Cluster) VirtualNetworks() ([]int, error) {
 	return c.arrayOfIDs("VNETS")
 }
+
+// reserved gets reservation from the template of given Cluster, missing reservation is zero
+func (c *Cluster) reserved(tag string) (int, error) {
+	if reservedValue(c.XMLData, tag) == "" {
+		return 0, nil
+	}
+
+	return c.intAttribute("TEMPLATE/" + tag)
+}
+
+// ReservedCPU gets cpu reserved on each host of given Cluster. Reservation in percents (e.g. 10%) depends
+// on the capacity of the host, so it can't be returned and error is returned instead, ClusterCapacitySummary
+// supports it.
+func (c *Cluster) ReservedCPU() (int, error) {
+	return c.reserved("RESERVED_CPU")
+}
+
+// ReservedMemory gets memory reserved on each host of given Cluster. Reservation in percents (e.g. 10%)
+// depends on the capacity of the host, so it can't be returned and error is returned instead,
+// ClusterCapacitySummary supports it.
+func (c *Cluster) ReservedMemory() (int, error) {
+	return c.reserved("RESERVED_MEM")
+}
+
+// reservedValue gets text of the reservation from the template in the element, missing reservation is empty
+func reservedValue(element *etree.Element, tag string) string {
+	e := element.FindElement("TEMPLATE/" + tag)
+	if e == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(e.Text())
+}
+
+// hostReservation gets reservation of the host with the given total capacity, reservation of the cluster
+// is used when the host has none. Reservation can be absolute or in percents of the total capacity.
+func hostReservation(host *Host, tag, clusterReservation string, total int) (int, error) {
+	value := reservedValue(host.XMLData, tag)
+	if value == "" {
+		value = clusterReservation
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	if strings.HasSuffix(value, "%") {
+		percents, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), bitSize64)
+		if err != nil {
+			return 0, err
+		}
+
+		return int(float64(total) * percents / 100), nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// ClusterCapacitySummary sums capacity of the hosts belonging to the cluster, other hosts are ignored.
+// Total capacity of a host is taken from TOTAL_CPU and TOTAL_MEM and reservation of the host (reservation
+// of the cluster when the host has none) is applied to it, the reservation can be absolute or in percents
+// of the total capacity. MAX_CPU and MAX_MEM are used when the totals are missing, they already exclude
+// the reservation, so no reservation is applied to them.
+func ClusterCapacitySummary(cluster *Cluster, hosts []*Host) (*ClusterCapacity, error) {
+	clusterID, err := cluster.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	hostIDs, err := cluster.Hosts()
+	if err != nil {
+		return nil, err
+	}
+
+	reservedCPU := reservedValue(cluster.XMLData, "RESERVED_CPU")
+	reservedMemory := reservedValue(cluster.XMLData, "RESERVED_MEM")
+
+	members := make(map[int]bool, len(hostIDs))
+	for _, id := range hostIDs {
+		members[id] = true
+	}
+
+	capacity := &ClusterCapacity{ClusterID: clusterID}
+	for _, host := range hosts {
+		hostID, err := host.ID()
+		if err != nil {
+			return nil, err
+		}
+
+		if !members[hostID] {
+			continue
+		}
+
+		share := host.XMLData.SelectElement("HOST_SHARE")
+		if share == nil {
+			return nil, &errors.XMLElementError{Path: "HOST_SHARE"}
+		}
+
+		values, err := parseIntsFromElement(share, []string{"MAX_CPU", "CPU_USAGE", "USED_CPU", "MAX_MEM",
+			"MEM_USAGE", "USED_MEM"})
+		if err != nil {
+			return nil, err
+		}
+
+		totals := parseIntsFromElementWithoutError(share, []string{"TOTAL_CPU", "TOTAL_MEM"})
+
+		hostReservedCPU := 0
+		if totals[0] != nil {
+			values[0] = *totals[0]
+			hostReservedCPU, err = hostReservation(host, "RESERVED_CPU", reservedCPU, values[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		hostReservedMemory := 0
+		if totals[1] != nil {
+			values[3] = *totals[1]
+			hostReservedMemory, err = hostReservation(host, "RESERVED_MEM", reservedMemory, values[3])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		capacity.Hosts++
+		capacity.TotalCPU += values[0]
+		capacity.ReservedCPU += hostReservedCPU
+		capacity.AllocatedCPU += values[1]
+		capacity.UsedCPU += values[2]
+		capacity.TotalMemory += values[3]
+		capacity.ReservedMemory += hostReservedMemory
+		capacity.AllocatedMemory += values[4]
+		capacity.UsedMemory += values[5]
+	}
+
+	capacity.AvailableCPU = capacity.TotalCPU - capacity.ReservedCPU - capacity.AllocatedCPU
+	capacity.AvailableMemory = capacity.TotalMemory - capacity.ReservedMemory - capacity.AllocatedMemory
+
+	return capacity, nil
+}
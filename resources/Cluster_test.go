@@ -105,4 +105,110 @@ var _ = ginkgo.Describe("Cluster", func() {
 			})
 		})
 	})
+
+	ginkgo.Describe("capacity", func() {
+		var hosts []*Host
+
+		ginkgo.BeforeEach(func() {
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(clusterXML)
+			cluster = CreateClusterFromXML(doc.Root())
+
+			hostDoc := etree.NewDocument()
+			if err == nil {
+				err = hostDoc.ReadFromFile(hostXML)
+			}
+
+			hosts = make([]*Host, 0)
+			for _, id := range []string{"937", "938", "934"} {
+				element := hostDoc.Root().Copy()
+				element.SelectElement("ID").SetText(id)
+				hosts = append(hosts, CreateHostFromXML(element))
+			}
+
+			// the second host reports total capacity and less allocated memory
+			share := hosts[1].XMLData.SelectElement("HOST_SHARE")
+			share.CreateElement("TOTAL_CPU").SetText("4000")
+			share.CreateElement("TOTAL_MEM").SetText("131803272")
+			share.SelectElement("MEM_USAGE").SetText("62914560")
+		})
+
+		ginkgo.It("should get reservations of cluster", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			gomega.Expect(cluster.ReservedCPU()).To(gomega.Equal(0))
+			gomega.Expect(cluster.ReservedMemory()).To(gomega.Equal(4194304))
+		})
+
+		ginkgo.It("should return zero reservations when cluster has none", func() {
+			gomega.Expect(CreateClusterWithID(42).ReservedCPU()).To(gomega.Equal(0))
+			gomega.Expect(CreateClusterWithID(42).ReservedMemory()).To(gomega.Equal(0))
+		})
+
+		ginkgo.It("should sum capacity of the hosts in cluster", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var capacity *ClusterCapacity
+			capacity, err = ClusterCapacitySummary(cluster, hosts)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(capacity).To(gomega.Equal(&ClusterCapacity{ClusterID: 120, Hosts: 2, TotalCPU: 8000,
+				ReservedCPU: 0, AllocatedCPU: 7000, UsedCPU: 240, AvailableCPU: 1000, TotalMemory: 259412240,
+				ReservedMemory: 4194304, AllocatedMemory: 188743680, UsedMemory: 171837616,
+				AvailableMemory: 66474256}))
+		})
+
+		ginkgo.It("should not apply reservation to host with only MAX_CPU and MAX_MEM", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var capacity *ClusterCapacity
+			capacity, err = ClusterCapacitySummary(cluster, hosts[:1])
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(capacity.TotalMemory).To(gomega.Equal(127608968))
+			gomega.Expect(capacity.ReservedMemory).To(gomega.BeZero())
+			gomega.Expect(capacity.AvailableMemory).To(gomega.Equal(127608968 - 125829120))
+		})
+
+		ginkgo.It("should prefer reservation of the host to reservation of the cluster", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			template := hosts[1].XMLData.SelectElement("TEMPLATE")
+			template.CreateElement("RESERVED_CPU").SetText("500")
+			template.CreateElement("RESERVED_MEM").SetText("1048576")
+
+			var capacity *ClusterCapacity
+			capacity, err = ClusterCapacitySummary(cluster, hosts)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(capacity.ReservedCPU).To(gomega.Equal(500))
+			gomega.Expect(capacity.AvailableCPU).To(gomega.Equal(500))
+			gomega.Expect(capacity.ReservedMemory).To(gomega.Equal(1048576))
+			gomega.Expect(capacity.AvailableMemory).To(gomega.Equal(69619984))
+		})
+
+		ginkgo.It("should apply reservation in percents of total capacity of the host", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			cluster.XMLData.FindElement("TEMPLATE/RESERVED_CPU").SetText("10%")
+			cluster.XMLData.FindElement("TEMPLATE/RESERVED_MEM").SetText("5%")
+
+			_, err = cluster.ReservedCPU()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			var capacity *ClusterCapacity
+			capacity, err = ClusterCapacitySummary(cluster, hosts)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(capacity.ReservedCPU).To(gomega.Equal(400))
+			gomega.Expect(capacity.AvailableCPU).To(gomega.Equal(600))
+			gomega.Expect(capacity.ReservedMemory).To(gomega.Equal(6590163))
+			gomega.Expect(capacity.AvailableMemory).To(gomega.Equal(64078397))
+		})
+
+		ginkgo.It("should return an error for host without host share", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			hosts[0].XMLData.RemoveChild(hosts[0].XMLData.SelectElement("HOST_SHARE"))
+
+			_, err = ClusterCapacitySummary(cluster, hosts)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"encoding/json"
+
+	"github.com/beevik/etree"
+)
+
+// Document structure represents OpenNebula document. Document is a generic object of given type stored
+// by tools built on OpenNebula, e.g. service templates and services of OneFlow.
+type Document struct {
+	Resource
+}
+
+// Document types used by OneFlow, other tools should use their own types.
+const (
+	// DocumentTypeService - document is a OneFlow service
+	DocumentTypeService = 100
+	// DocumentTypeServiceTemplate - document is a OneFlow service template
+	DocumentTypeServiceTemplate = 101
+)
+
+// CreateDocumentWithID constructs document with given ID.
+func CreateDocumentWithID(id int) *Document {
+	return &Document{*CreateResource("DOCUMENT", id)}
+}
+
+// CreateDocumentFromXML constructs document with full xml data.
+func CreateDocumentFromXML(XMLdata *etree.Element) *Document {
+	return &Document{Resource: Resource{XMLData: XMLdata}}
+}
+
+// User gets user ID of given document.
+func (d *Document) User() (int, error) {
+	return d.intAttribute("UID")
+}
+
+// Group gets group ID of given document.
+func (d *Document) Group() (int, error) {
+	return d.intAttribute("GID")
+}
+
+// Permissions gets document permissions.
+func (d *Document) Permissions() (*Permissions, error) {
+	return d.permissions()
+}
+
+// Lock gets lock of given document, nil is returned when the document is not locked.
+func (d *Document) Lock() (*Lock, error) {
+	return d.lock()
+}
+
+// Type gets type of given document.
+func (d *Document) Type() (int, error) {
+	return d.intAttribute("TYPE")
+}
+
+// Body gets body of given document.
+func (d *Document) Body() (string, error) {
+	return d.Attribute("TEMPLATE/BODY")
+}
+
+// UnmarshalBody parses JSON body of given document and stores the result in the value pointed to by object.
+func (d *Document) UnmarshalBody(object interface{}) error {
+	body, err := d.Body()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(body), object)
+}
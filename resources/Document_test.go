@@ -0,0 +1,96 @@
+package resources
+
+import (
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	documentXML = "xml/document.xml"
+)
+
+var _ = ginkgo.Describe("Document", func() {
+	var (
+		doc      *etree.Document
+		document *Document
+		err      error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create document with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(documentXML)
+			document = CreateDocumentFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all Document attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(document).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(document.ID()).To(gomega.Equal(12))
+			gomega.Expect(document.Name()).To(gomega.Equal("web-app"))
+			gomega.Expect(document.User()).To(gomega.Equal(46))
+			gomega.Expect(document.Group()).To(gomega.Equal(113))
+			gomega.Expect(document.Type()).To(gomega.Equal(1000))
+			gomega.Expect(document.Body()).To(gomega.Equal(
+				`{"owner":"web-team","replicas":3,"vms":[57612,57613,57614]}`))
+
+			var permissions *Permissions
+			permissions, err = document.Permissions()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(permissions.User.Manage).To(gomega.Equal(true))
+			gomega.Expect(permissions.Group.Manage).To(gomega.Equal(false))
+
+			var lock *Lock
+			lock, err = document.Lock()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(lock.Level).To(gomega.Equal(LockManage))
+		})
+
+		ginkgo.It("should unmarshal Document body", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var app struct {
+				Owner    string `json:"owner"`
+				Replicas int    `json:"replicas"`
+				VMs      []int  `json:"vms"`
+			}
+			err = document.UnmarshalBody(&app)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(app.Owner).To(gomega.Equal("web-team"))
+			gomega.Expect(app.Replicas).To(gomega.Equal(3))
+			gomega.Expect(app.VMs).To(gomega.Equal([]int{57612, 57613, 57614}))
+		})
+
+		ginkgo.It("should return an error for body which is not JSON", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			document.XMLData.FindElement("TEMPLATE/BODY").SetText("OWNER=web-team")
+
+			var app map[string]interface{}
+			err = document.UnmarshalBody(&app)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("when document has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			document = CreateDocumentWithID(42)
+		})
+
+		ginkgo.It("should create document", func() {
+			gomega.Expect(document.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that document doesn't have type and body", func() {
+			_, err = document.Type()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			var app map[string]interface{}
+			err = document.UnmarshalBody(&app)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
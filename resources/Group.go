@@ -2,6 +2,7 @@ package resources
 
 import (
 	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
 )
 
 // Group structure represents group resource.
@@ -30,3 +31,18 @@ func (g *Group) Users() ([]int, error) {
 func (g *Group) Admins() ([]int, error) {
 	return g.arrayOfIDs("ADMINS")
 }
+
+// Quotas returns quotas of the given group.
+func (g *Group) Quotas() (*Quotas, error) {
+	return ParseQuotas(g.XMLData)
+}
+
+// DefaultQuotas returns default quotas applied to the groups.
+func (g *Group) DefaultQuotas() (*Quotas, error) {
+	element := g.XMLData.SelectElement("DEFAULT_GROUP_QUOTAS")
+	if element == nil {
+		return nil, &errors.XMLElementError{Path: "DEFAULT_GROUP_QUOTAS"}
+	}
+
+	return ParseQuotas(element)
+}
@@ -33,6 +33,20 @@ var _ = ginkgo.Describe("Group", func() {
 			gomega.Expect(group.Users()).To(gomega.HaveLen(1))
 			gomega.Expect(group.Admins()).To(gomega.HaveLen(3))
 		})
+
+		ginkgo.It("should find empty group quotas", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var quotas *Quotas
+			quotas, err = group.Quotas()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(quotas).To(gomega.Equal(&Quotas{Datastores: []*DatastoreQuota{},
+				Networks: []*NetworkQuota{}, Images: []*ImageQuota{}}))
+
+			quotas, err = group.DefaultQuotas()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(quotas.VM).To(gomega.BeNil())
+		})
 	})
 
 	ginkgo.Describe("create group", func() {
@@ -57,6 +71,16 @@ var _ = ginkgo.Describe("Group", func() {
 				gomega.Expect(err).To(gomega.HaveOccurred())
 			})
 
+			ginkgo.It("should return that group doesn't have quotas", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				_, err = group.Quotas()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+
+				_, err = group.DefaultQuotas()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+
 			ginkgo.It("should return that group doesn't have users", func() {
 				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
 
@@ -0,0 +1,197 @@
+package resources
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// Hook structure represents OpenNebula hook. Hook runs a command when an API call is made (API hook)
+// or when a resource gets to a state (state hook).
+type Hook struct {
+	Resource
+}
+
+// HookTypeMap contains string representation of HookType.
+var HookTypeMap = map[HookType]string{
+	HookTypeAPI:   "api",
+	HookTypeState: "state",
+}
+
+// HookType - event the hook is triggered by
+type HookType int
+
+const (
+	// HookTypeAPI - hook is triggered by an API call
+	HookTypeAPI HookType = iota
+	// HookTypeState - hook is triggered by a state change of a resource
+	HookTypeState
+)
+
+// APIHook represents definition of API hook. Call is the name of the XML-RPC method, e.g. one.vm.allocate.
+type APIHook struct {
+	Call string `json:"call"`
+}
+
+// StateHook represents definition of state hook. Resource is the type of the resource (VM, HOST or IMAGE),
+// the hook is triggered when the resource gets to State (and LCMState for virtual machines). For virtual
+// machines On may be used instead of the states, e.g. RUNNING or CUSTOM when the states are given.
+type StateHook struct {
+	Resource string `json:"resource"`
+	State    string `json:"state"`
+	LCMState string `json:"lcm_state"`
+	On       string `json:"on"`
+}
+
+// HookExecutionRecord represents one execution of a hook. Stdout and Stderr are decoded output
+// of the command, ExitCode is the exit code of the command. Retry is set for executions created
+// by retrying an earlier execution.
+type HookExecutionRecord struct {
+	HookID      int       `json:"hook_id"`
+	ExecutionID int       `json:"execution_id"`
+	Timestamp   time.Time `json:"timestamp"`
+	Command     string    `json:"command"`
+	Arguments   string    `json:"arguments"`
+	ExitCode    int       `json:"exit_code"`
+	Stdout      string    `json:"stdout"`
+	Stderr      string    `json:"stderr"`
+	RemoteHost  string    `json:"remote_host"`
+	Retry       bool      `json:"retry"`
+}
+
+// CreateHookWithID constructs hook with given ID.
+func CreateHookWithID(id int) *Hook {
+	return &Hook{*CreateResource("HOOK", id)}
+}
+
+// CreateHookFromXML constructs hook with full xml data.
+func CreateHookFromXML(XMLdata *etree.Element) *Hook {
+	return &Hook{Resource: Resource{XMLData: XMLdata}}
+}
+
+// Type gets type of given hook.
+func (h *Hook) Type() (HookType, error) {
+	hookType, err := h.Attribute("TYPE")
+	if err != nil {
+		return -1, err
+	}
+
+	t, err := findHookTypeByValue(hookType)
+	if err != nil {
+		return -1, err
+	}
+
+	return *t, nil
+}
+
+// Command gets command run by given hook.
+func (h *Hook) Command() (string, error) {
+	return h.Attribute("TEMPLATE/COMMAND")
+}
+
+// Arguments gets arguments of the command run by given hook, e.g. "$API" or "$TEMPLATE".
+// Empty string is returned when the command has no arguments.
+func (h *Hook) Arguments() string {
+	return parseStringsFromElementWithoutError(h.XMLData, []string{"TEMPLATE/ARGUMENTS"})[0]
+}
+
+// Remote returns true when the command of given hook is run on the host of the resource.
+func (h *Hook) Remote() bool {
+	return stringToBool(parseStringsFromElementWithoutError(h.XMLData, []string{"TEMPLATE/REMOTE"})[0])
+}
+
+// APIHook gets definition of given API hook, nil is returned for the other types of hooks.
+func (h *Hook) APIHook() (*APIHook, error) {
+	hookType, err := h.Type()
+	if err != nil || hookType != HookTypeAPI {
+		return nil, err
+	}
+
+	call, err := h.Attribute("TEMPLATE/CALL")
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIHook{Call: call}, nil
+}
+
+// StateHook gets definition of given state hook, nil is returned for the other types of hooks.
+func (h *Hook) StateHook() (*StateHook, error) {
+	hookType, err := h.Type()
+	if err != nil || hookType != HookTypeState {
+		return nil, err
+	}
+
+	resource, err := h.Attribute("TEMPLATE/RESOURCE")
+	if err != nil {
+		return nil, err
+	}
+
+	states := parseStringsFromElementWithoutError(h.XMLData, []string{"TEMPLATE/STATE", "TEMPLATE/LCM_STATE",
+		"TEMPLATE/ON"})
+
+	return &StateHook{Resource: resource, State: states[0], LCMState: states[1], On: states[2]}, nil
+}
+
+// ExecutionRecords gets the last execution records of given hook.
+func (h *Hook) ExecutionRecords() ([]*HookExecutionRecord, error) {
+	elements := h.XMLData.FindElements("HOOKLOG/HOOK_EXECUTION_RECORD")
+
+	records := make([]*HookExecutionRecord, len(elements))
+	var err error
+
+	for i, e := range elements {
+		if records[i], err = ParseHookExecutionRecord(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// ParseHookExecutionRecord parses execution record from HOOK_EXECUTION_RECORD element, e.g. element
+// of one.hooklog.info result or hook info. Standard and error output of the command are base64 encoded
+// in the element.
+func ParseHookExecutionRecord(element *etree.Element) (*HookExecutionRecord, error) {
+	ints, err := parseIntsFromElement(element, []string{"HOOK_ID", "EXECUTION_ID", "TIMESTAMP",
+		"EXECUTION_RESULT/CODE"})
+	if err != nil {
+		return nil, err
+	}
+
+	strs := parseStringsFromElementWithoutError(element, []string{"EXECUTION_RESULT/COMMAND", "ARGUMENTS",
+		"EXECUTION_RESULT/STDOUT", "EXECUTION_RESULT/STDERR", "REMOTE_HOST", "RETRY"})
+
+	record := &HookExecutionRecord{HookID: ints[0], ExecutionID: ints[1], Timestamp: time.Unix(int64(ints[2]), 0),
+		ExitCode: ints[3], Command: strs[0], Arguments: strs[1], RemoteHost: strs[4],
+		Retry: strings.EqualFold(strs[5], "YES")}
+
+	for i, target := range []*string{&record.Stdout, &record.Stderr} {
+		output, err := base64.StdEncoding.DecodeString(strs[i+2])
+		if err != nil {
+			return nil, &errors.XMLElementError{Path: "EXECUTION_RESULT/" + []string{"STDOUT", "STDERR"}[i]}
+		}
+		*target = string(output)
+	}
+
+	return record, nil
+}
+
+// Success returns true when the command of the hook execution exited with zero exit code.
+func (r *HookExecutionRecord) Success() bool {
+	return r.ExitCode == 0
+}
+
+func findHookTypeByValue(value string) (*HookType, error) {
+	for key, val := range HookTypeMap {
+		if val == value {
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find HookType of value: %s", value)
+}
@@ -0,0 +1,141 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	hookXML = "xml/hook.xml"
+)
+
+var _ = ginkgo.Describe("Hook", func() {
+	var (
+		doc  *etree.Document
+		hook *Hook
+		err  error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create hook with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(hookXML)
+			hook = CreateHookFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all Hook attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(hook).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(hook.ID()).To(gomega.Equal(3))
+			gomega.Expect(hook.Name()).To(gomega.Equal("notify-deploy"))
+			gomega.Expect(hook.Type()).To(gomega.Equal(HookTypeAPI))
+			gomega.Expect(hook.Command()).To(gomega.Equal("/var/lib/one/remotes/hooks/notify.sh"))
+			gomega.Expect(hook.Arguments()).To(gomega.Equal("$API"))
+			gomega.Expect(hook.Remote()).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should find API hook definition", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			gomega.Expect(hook.APIHook()).To(gomega.Equal(&APIHook{Call: "one.vm.deploy"}))
+			gomega.Expect(hook.StateHook()).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should find state hook definition", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			hook.XMLData.SelectElement("TYPE").SetText("state")
+			template := hook.XMLData.SelectElement("TEMPLATE")
+			template.CreateElement("RESOURCE").SetText("VM")
+			template.CreateElement("ON").SetText("CUSTOM")
+			template.CreateElement("STATE").SetText("ACTIVE")
+			template.CreateElement("LCM_STATE").SetText("RUNNING")
+
+			gomega.Expect(hook.StateHook()).To(gomega.Equal(&StateHook{Resource: "VM", State: "ACTIVE",
+				LCMState: "RUNNING", On: "CUSTOM"}))
+			gomega.Expect(hook.APIHook()).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should return an error for unknown hook type", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			hook.XMLData.SelectElement("TYPE").SetText("cron")
+
+			_, err = hook.Type()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			_, err = hook.APIHook()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should find all Hook execution records", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var records []*HookExecutionRecord
+			records, err = hook.ExecutionRecords()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(records).To(gomega.Equal([]*HookExecutionRecord{
+				{HookID: 3, ExecutionID: 0, Timestamp: time.Unix(1571300000, 0),
+					Command:   "/var/lib/one/remotes/hooks/notify.sh",
+					Arguments: "PENBTEw+PFBBUkFNRVRFUlM+PC9QQVJBTUVURVJTPjwvQ0FMTD4=", ExitCode: 0,
+					Stdout: "VM 57612 deployed on host-7"},
+				{HookID: 3, ExecutionID: 1, Timestamp: time.Unix(1571300600, 0),
+					Command:   "/var/lib/one/remotes/hooks/notify.sh",
+					Arguments: "PENBTEw+PFBBUkFNRVRFUlM+PC9QQVJBTUVURVJTPjwvQ0FMTD4=", ExitCode: 1,
+					Stderr: "warning: slow response", Retry: true},
+			}))
+			gomega.Expect(records[0].Success()).To(gomega.BeTrue())
+			gomega.Expect(records[1].Success()).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should return an error for record with output which is not base64 encoded", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			hook.XMLData.FindElement("HOOKLOG/HOOK_EXECUTION_RECORD/EXECUTION_RESULT/STDOUT").SetText("not base64!")
+
+			_, err = hook.ExecutionRecords()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should return an error for record without exit code", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			result := hook.XMLData.FindElement("HOOKLOG/HOOK_EXECUTION_RECORD/EXECUTION_RESULT")
+			result.RemoveChild(result.SelectElement("CODE"))
+
+			_, err = hook.ExecutionRecords()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("when hook has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			hook = CreateHookWithID(42)
+		})
+
+		ginkgo.It("should create hook", func() {
+			gomega.Expect(hook.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that hook doesn't have type and command", func() {
+			_, err = hook.Type()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			_, err = hook.Command()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			gomega.Expect(hook.Arguments()).To(gomega.BeEmpty())
+			gomega.Expect(hook.Remote()).To(gomega.BeFalse())
+
+			var records []*HookExecutionRecord
+			records, err = hook.ExecutionRecords()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(records).To(gomega.HaveLen(0))
+		})
+	})
+})
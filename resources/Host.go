@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"time"
+
 	"github.com/onego-project/onego/errors"
 
 	"github.com/beevik/etree"
@@ -62,20 +64,20 @@ var HostStateMap = map[HostState]string{
 
 // PCI structure represents hardware for Host
 type PCI struct {
-	Address      string
-	Bus          string
-	Class        string
-	ClassName    string
-	Device       string
-	DeviceName   string
-	Domain       string
-	Function     string
-	ShortAddress string
-	Slot         string
-	Type         string
-	Vendor       string
-	VendorName   string
-	VMID         string
+	Address      string `json:"address"`
+	Bus          string `json:"bus"`
+	Class        string `json:"class"`
+	ClassName    string `json:"class_name"`
+	Device       string `json:"device"`
+	DeviceName   string `json:"device_name"`
+	Domain       string `json:"domain"`
+	Function     string `json:"function"`
+	ShortAddress string `json:"short_address"`
+	Slot         string `json:"slot"`
+	Type         string `json:"type"`
+	Vendor       string `json:"vendor"`
+	VendorName   string `json:"vendor_name"`
+	VMID         string `json:"vm_id"`
 }
 
 // CreateHostWithID constructs Host with id
@@ -130,6 +132,11 @@ func (h *Host) CPUUsage() (int, error) {
 	return h.intAttribute("HOST_SHARE/CPU_USAGE")
 }
 
+// Monitoring gets the last monitoring sample of given Host
+func (h *Host) Monitoring() (*HostMonitoring, error) {
+	return ParseHostMonitoring(h.XMLData)
+}
+
 // MaxDisk gets maximal disk size
 func (h *Host) MaxDisk() (int, error) {
 	return h.intAttribute("HOST_SHARE/MAX_DISK")
@@ -230,3 +237,96 @@ func createPCIFromElement(element *etree.Element) (*PCI, error) {
 func (h *Host) VirtualMachines() ([]int, error) {
 	return h.arrayOfIDs("VMS")
 }
+
+// HostInfo structure contains information about a host decoded from its XML data.
+// It doesn't depend on the XML data, so it can be cached or serialized (e.g. to JSON).
+type HostInfo struct {
+	ID                 int        `json:"id"`
+	Name               string     `json:"name"`
+	State              HostState  `json:"state"`
+	IMMad              string     `json:"im_mad"`
+	VMMad              string     `json:"vm_mad"`
+	LastMonitoringTime *time.Time `json:"last_monitoring_time,omitempty"`
+	Cluster            int        `json:"cluster"`
+	ClusterName        string     `json:"cluster_name"`
+	DiskUsage          int        `json:"disk_usage"`
+	MemoryUsage        int        `json:"memory_usage"`
+	CPUUsage           int        `json:"cpu_usage"`
+	MaxDisk            int        `json:"max_disk"`
+	MaxMemory          int        `json:"max_memory"`
+	MaxCPU             int        `json:"max_cpu"`
+	FreeDisk           int        `json:"free_disk"`
+	FreeMemory         int        `json:"free_memory"`
+	FreeCPU            int        `json:"free_cpu"`
+	UsedDisk           int        `json:"used_disk"`
+	UsedMemory         int        `json:"used_memory"`
+	UsedCPU            int        `json:"used_cpu"`
+	RunningVMs         int        `json:"running_vms"`
+	Datastores         []int      `json:"datastores"`
+	PCIDevices         []*PCI     `json:"pci_devices"`
+	VirtualMachines    []int      `json:"virtual_machines"`
+}
+
+// Decode decodes all the information about given Host at once.
+func (h *Host) Decode() (*HostInfo, error) {
+	parsedInts, err := parseIntsFromElement(h.XMLData, []string{"ID", "STATE", "CLUSTER_ID",
+		"HOST_SHARE/DISK_USAGE", "HOST_SHARE/MEM_USAGE", "HOST_SHARE/CPU_USAGE", "HOST_SHARE/MAX_DISK",
+		"HOST_SHARE/MAX_MEM", "HOST_SHARE/MAX_CPU", "HOST_SHARE/FREE_DISK", "HOST_SHARE/FREE_MEM",
+		"HOST_SHARE/FREE_CPU", "HOST_SHARE/USED_DISK", "HOST_SHARE/USED_MEM", "HOST_SHARE/USED_CPU",
+		"HOST_SHARE/RUNNING_VMS"})
+	if err != nil {
+		return nil, err
+	}
+
+	parsedStrings, err := parseStringsFromElement(h.XMLData, []string{"NAME", "IM_MAD", "VM_MAD", "CLUSTER"})
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := parseTimesFromElement(h.XMLData, []string{"LAST_MON_TIME"})
+	if err != nil {
+		return nil, err
+	}
+
+	datastores, err := h.Datastores()
+	if err != nil {
+		return nil, err
+	}
+
+	pciDevices, err := h.PCIDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	vms, err := h.VirtualMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostInfo{
+		ID:                 parsedInts[0],
+		Name:               parsedStrings[0],
+		State:              HostState(parsedInts[1]),
+		IMMad:              parsedStrings[1],
+		VMMad:              parsedStrings[2],
+		LastMonitoringTime: times[0],
+		Cluster:            parsedInts[2],
+		ClusterName:        parsedStrings[3],
+		DiskUsage:          parsedInts[3],
+		MemoryUsage:        parsedInts[4],
+		CPUUsage:           parsedInts[5],
+		MaxDisk:            parsedInts[6],
+		MaxMemory:          parsedInts[7],
+		MaxCPU:             parsedInts[8],
+		FreeDisk:           parsedInts[9],
+		FreeMemory:         parsedInts[10],
+		FreeCPU:            parsedInts[11],
+		UsedDisk:           parsedInts[12],
+		UsedMemory:         parsedInts[13],
+		UsedCPU:            parsedInts[14],
+		RunningVMs:         parsedInts[15],
+		Datastores:         datastores,
+		PCIDevices:         pciDevices,
+		VirtualMachines:    vms,
+	}, nil
+}
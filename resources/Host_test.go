@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"time"
+
 	"github.com/beevik/etree"
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
@@ -89,6 +91,25 @@ var _ = ginkgo.Describe("Host", func() {
 				gomega.Expect(vmIDs[0]).To(gomega.Equal(42810))
 				gomega.Expect(vmIDs[1]).To(gomega.Equal(42868))
 			})
+
+			ginkgo.It("should decode all host attributes", func() {
+				var info *HostInfo
+				info, err = host.Decode()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(info.ID).To(gomega.Equal(934))
+				gomega.Expect(info.Name).To(gomega.Equal("gorbag.ics"))
+				gomega.Expect(info.IMMad).To(gomega.Equal("kvm"))
+				gomega.Expect(info.Cluster).To(gomega.Equal(118))
+				gomega.Expect(info.ClusterName).To(gomega.Equal("gorbag"))
+				gomega.Expect(info.MaxCPU).To(gomega.Equal(4000))
+				gomega.Expect(info.FreeMemory).To(gomega.Equal(45884464))
+				gomega.Expect(info.RunningVMs).To(gomega.Equal(18))
+				gomega.Expect(info.VirtualMachines).To(gomega.Equal([]int{42810, 42868}))
+
+				lastMonitoringTime := time.Unix(1539689293, 0)
+				gomega.Expect(info.LastMonitoringTime).To(gomega.Equal(&lastMonitoringTime))
+			})
 		})
 
 		ginkgo.Context("when host has only ID", func() {
@@ -106,6 +127,11 @@ var _ = ginkgo.Describe("Host", func() {
 					err = nil
 				})
 
+				ginkgo.It("shouldn't decode host", func() {
+					_, err = host.Decode()
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+
 				ginkgo.It("should return that host doesn't have name", func() {
 					_, err = host.Name()
 					gomega.Expect(err).To(gomega.HaveOccurred())
@@ -86,13 +86,13 @@ const (
 
 // ImageSnapshot represents snapshot created from Image
 type ImageSnapshot struct {
-	Active   string
-	Children string
-	Date     *time.Time
-	ID       int
-	Name     string
-	Parent   int
-	Size     int
+	Active   string     `json:"active"`
+	Children string     `json:"children"`
+	Date     *time.Time `json:"date,omitempty"`
+	ID       int        `json:"id"`
+	Name     string     `json:"name"`
+	Parent   int        `json:"parent"`
+	Size     int        `json:"size"`
 }
 
 // CreateImageWithID constructs Image with id
@@ -264,3 +264,102 @@ func createImageSnapshotFromElement(element *etree.Element) (*ImageSnapshot, err
 	return &ImageSnapshot{Active: active, Children: children, Date: date,
 		ID: id, Name: name, Parent: parent, Size: size}, nil
 }
+
+// ImageInfo structure contains information about an image decoded from its XML data.
+// It doesn't depend on the XML data, so it can be cached or serialized (e.g. to JSON).
+type ImageInfo struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	User             int              `json:"user"`
+	UserName         string           `json:"user_name"`
+	Group            int              `json:"group"`
+	GroupName        string           `json:"group_name"`
+	Permissions      *Permissions     `json:"permissions"`
+	Type             ImageType        `json:"type"`
+	DiskType         DiskType         `json:"disk_type"`
+	Persistent       bool             `json:"persistent"`
+	RegistrationTime *time.Time       `json:"registration_time,omitempty"`
+	Source           string           `json:"source"`
+	Path             string           `json:"path"`
+	FileSystemType   string           `json:"file_system_type"`
+	Size             int              `json:"size"`
+	State            ImageState       `json:"state"`
+	RunningVMs       int              `json:"running_vms"`
+	Datastore        int              `json:"datastore"`
+	DatastoreName    string           `json:"datastore_name"`
+	VirtualMachines  []int            `json:"virtual_machines"`
+	Clones           []int            `json:"clones"`
+	AppClones        []int            `json:"app_clones"`
+	Snapshots        []*ImageSnapshot `json:"snapshots"`
+}
+
+// Decode decodes all the information about given Image at once.
+func (i *Image) Decode() (*ImageInfo, error) {
+	parsedInts, err := parseIntsFromElement(i.XMLData, []string{"ID", "UID", "GID", "TYPE", "DISK_TYPE",
+		"PERSISTENT", "SIZE", "STATE", "RUNNING_VMS", "DATASTORE_ID"})
+	if err != nil {
+		return nil, err
+	}
+
+	parsedStrings, err := parseStringsFromElement(i.XMLData, []string{"NAME", "UNAME", "GNAME", "SOURCE", "PATH",
+		"FSTYPE", "DATASTORE"})
+	if err != nil {
+		return nil, err
+	}
+
+	registrationTime, err := i.RegistrationTime()
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := i.Permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	vms, err := i.VirtualMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	clones, err := i.Clones()
+	if err != nil {
+		return nil, err
+	}
+
+	appClones, err := i.AppClones()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := i.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageInfo{
+		ID:               parsedInts[0],
+		Name:             parsedStrings[0],
+		User:             parsedInts[1],
+		UserName:         parsedStrings[1],
+		Group:            parsedInts[2],
+		GroupName:        parsedStrings[2],
+		Permissions:      permissions,
+		Type:             ImageType(parsedInts[3]),
+		DiskType:         DiskType(parsedInts[4]),
+		Persistent:       intToBool(parsedInts[5]),
+		RegistrationTime: registrationTime,
+		Source:           parsedStrings[3],
+		Path:             parsedStrings[4],
+		FileSystemType:   parsedStrings[5],
+		Size:             parsedInts[6],
+		State:            ImageState(parsedInts[7]),
+		RunningVMs:       parsedInts[8],
+		Datastore:        parsedInts[9],
+		DatastoreName:    parsedStrings[6],
+		VirtualMachines:  vms,
+		Clones:           clones,
+		AppClones:        appClones,
+		Snapshots:        snapshots,
+	}, nil
+}
@@ -125,6 +125,23 @@ var _ = ginkgo.Describe("Image", func() {
 
 				gomega.Expect(snapshots).To(gomega.HaveLen(2))
 			})
+
+			ginkgo.It("should decode all image attributes", func() {
+				var info *ImageInfo
+				info, err = image.Decode()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(info.ID).To(gomega.Equal(123))
+				gomega.Expect(info.Name).To(gomega.Equal("tty-linux-local"))
+				gomega.Expect(info.UserName).To(gomega.Equal("oneadmin"))
+				gomega.Expect(info.Persistent).To(gomega.BeFalse())
+				gomega.Expect(info.State).To(gomega.Equal(ImageStateUsed))
+				gomega.Expect(info.Datastore).To(gomega.Equal(101))
+				gomega.Expect(info.DatastoreName).To(gomega.Equal("local_image_ds"))
+				gomega.Expect(info.VirtualMachines).To(gomega.Equal([]int{84, 88}))
+				gomega.Expect(info.AppClones).To(gomega.HaveLen(4))
+				gomega.Expect(info.Snapshots).To(gomega.HaveLen(2))
+			})
 		})
 
 		ginkgo.Context("when image has only ID", func() {
@@ -142,6 +159,11 @@ var _ = ginkgo.Describe("Image", func() {
 					err = nil
 				})
 
+				ginkgo.It("shouldn't decode image", func() {
+					_, err = image.Decode()
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+
 				ginkgo.It("should return that image doesn't have name", func() {
 					_, err = image.Name()
 					gomega.Expect(err).To(gomega.HaveOccurred())
@@ -0,0 +1,52 @@
+package resources
+
+import "time"
+
+// LockLevel type to choose which actions are blocked by the lock of a resource.
+type LockLevel int
+
+const (
+	// LockNone - resource is not locked
+	LockNone LockLevel = iota
+	// LockUse - use, manage and admin actions are blocked
+	LockUse
+	// LockManage - manage and admin actions are blocked
+	LockManage
+	// LockAdmin - admin actions are blocked
+	LockAdmin
+	// LockAll - all actions are blocked
+	LockAll
+)
+
+// Lock structure represents lock of a resource.
+type Lock struct {
+	Level     LockLevel  `json:"level"`
+	Owner     int        `json:"owner"`
+	Time      *time.Time `json:"time"`
+	RequestID int        `json:"request_id"`
+}
+
+// lock gets lock of the resource, nil is returned when the resource is not locked.
+func (r *Resource) lock() (*Lock, error) {
+	element := r.XMLData.SelectElement("LOCK")
+	if element == nil {
+		return nil, nil
+	}
+
+	ints, err := parseIntsFromElement(element, []string{"LOCKED", "OWNER"})
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := parseTimesFromElement(element, []string{"TIME"})
+	if err != nil {
+		return nil, err
+	}
+
+	requestID := -1
+	if parsed := parseIntsFromElementWithoutError(element, []string{"REQ_ID"})[0]; parsed != nil {
+		requestID = *parsed
+	}
+
+	return &Lock{Level: LockLevel(ints[0]), Owner: ints[1], Time: times[0], RequestID: requestID}, nil
+}
@@ -0,0 +1,86 @@
+package resources
+
+import "github.com/beevik/etree"
+
+// Marketplace structure represents OpenNebula marketplace. Marketplace stores marketplace apps
+// (e.g. images exported from a datastore) which can be imported to datastores of any zone.
+type Marketplace struct {
+	Resource
+}
+
+// MarketplaceStateMap contains string representation of MarketplaceState
+var MarketplaceStateMap = map[MarketplaceState]string{
+	MarketplaceStateEnabled:  "ENABLED",
+	MarketplaceStateDisabled: "DISABLED",
+}
+
+// MarketplaceState - state of marketplace
+type MarketplaceState int
+
+const (
+	// MarketplaceStateEnabled - marketplace is enabled and its apps can be used
+	MarketplaceStateEnabled MarketplaceState = iota
+	// MarketplaceStateDisabled - marketplace is disabled, no apps can be exported to it
+	MarketplaceStateDisabled
+)
+
+// CreateMarketplaceWithID constructs marketplace with given ID.
+func CreateMarketplaceWithID(id int) *Marketplace {
+	return &Marketplace{*CreateResource("MARKETPLACE", id)}
+}
+
+// CreateMarketplaceFromXML constructs marketplace with full xml data.
+func CreateMarketplaceFromXML(XMLdata *etree.Element) *Marketplace {
+	return &Marketplace{Resource: Resource{XMLData: XMLdata}}
+}
+
+// User gets user ID of given marketplace.
+func (m *Marketplace) User() (int, error) {
+	return m.intAttribute("UID")
+}
+
+// Group gets group ID of given marketplace.
+func (m *Marketplace) Group() (int, error) {
+	return m.intAttribute("GID")
+}
+
+// Permissions gets marketplace permissions.
+func (m *Marketplace) Permissions() (*Permissions, error) {
+	return m.permissions()
+}
+
+// MarketMad gets MARKET_MAD (driver) of given marketplace, e.g. http or s3.
+func (m *Marketplace) MarketMad() (string, error) {
+	return m.Attribute("MARKET_MAD")
+}
+
+// Zone gets ID of the zone of given marketplace.
+func (m *Marketplace) Zone() (int, error) {
+	return m.intAttribute("ZONE_ID")
+}
+
+// State gets state of given marketplace.
+func (m *Marketplace) State() (MarketplaceState, error) {
+	i, err := m.intAttribute("STATE")
+	return MarketplaceState(i), err
+}
+
+// TotalMB gets total size of given marketplace in MB.
+func (m *Marketplace) TotalMB() (int, error) {
+	return m.intAttribute("TOTAL_MB")
+}
+
+// FreeMB gets free size of given marketplace in MB.
+func (m *Marketplace) FreeMB() (int, error) {
+	return m.intAttribute("FREE_MB")
+}
+
+// UsedMB gets used size of given marketplace in MB.
+func (m *Marketplace) UsedMB() (int, error) {
+	return m.intAttribute("USED_MB")
+}
+
+// Apps gets array of IDs of marketplace apps of given marketplace.
+func (m *Marketplace) Apps() ([]int, error) {
+	return m.arrayOfIDs("MARKETPLACEAPPS")
+}
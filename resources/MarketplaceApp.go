@@ -0,0 +1,174 @@
+package resources
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+// MarketplaceApp structure represents OpenNebula marketplace app, e.g. an image exported to a marketplace.
+type MarketplaceApp struct {
+	Resource
+}
+
+// MarketplaceAppStateMap contains string representation of MarketplaceAppState
+var MarketplaceAppStateMap = map[MarketplaceAppState]string{
+	MarketplaceAppStateInit:     "INIT",
+	MarketplaceAppStateReady:    "READY",
+	MarketplaceAppStateLocked:   "LOCKED",
+	MarketplaceAppStateError:    "ERROR",
+	MarketplaceAppStateDisabled: "DISABLED",
+}
+
+// MarketplaceAppState - state of marketplace app
+type MarketplaceAppState int
+
+const (
+	// MarketplaceAppStateInit - marketplace app is being initialized
+	MarketplaceAppStateInit MarketplaceAppState = iota
+	// MarketplaceAppStateReady - marketplace app is ready to use
+	MarketplaceAppStateReady
+	// MarketplaceAppStateLocked - marketplace app is being exported or imported
+	MarketplaceAppStateLocked
+	// MarketplaceAppStateError - export of the marketplace app failed
+	MarketplaceAppStateError
+	// MarketplaceAppStateDisabled - marketplace app is disabled and cannot be imported
+	MarketplaceAppStateDisabled
+)
+
+// MarketplaceAppTypeMap contains string representation of MarketplaceAppType
+var MarketplaceAppTypeMap = map[MarketplaceAppType]string{
+	MarketplaceAppTypeUnknown:         "UNKNOWN",
+	MarketplaceAppTypeImage:           "IMAGE",
+	MarketplaceAppTypeVMTemplate:      "VMTEMPLATE",
+	MarketplaceAppTypeServiceTemplate: "SERVICE_TEMPLATE",
+}
+
+// MarketplaceAppType - type of marketplace app
+type MarketplaceAppType int
+
+const (
+	// MarketplaceAppTypeUnknown - unknown type of marketplace app
+	MarketplaceAppTypeUnknown MarketplaceAppType = iota
+	// MarketplaceAppTypeImage - marketplace app is an image
+	MarketplaceAppTypeImage
+	// MarketplaceAppTypeVMTemplate - marketplace app is a virtual machine template
+	MarketplaceAppTypeVMTemplate
+	// MarketplaceAppTypeServiceTemplate - marketplace app is a service template
+	MarketplaceAppTypeServiceTemplate
+)
+
+// CreateMarketplaceAppWithID constructs marketplace app with given ID.
+func CreateMarketplaceAppWithID(id int) *MarketplaceApp {
+	return &MarketplaceApp{*CreateResource("MARKETPLACEAPP", id)}
+}
+
+// CreateMarketplaceAppFromXML constructs marketplace app with full xml data.
+func CreateMarketplaceAppFromXML(XMLdata *etree.Element) *MarketplaceApp {
+	return &MarketplaceApp{Resource: Resource{XMLData: XMLdata}}
+}
+
+// User gets user ID of given marketplace app.
+func (ma *MarketplaceApp) User() (int, error) {
+	return ma.intAttribute("UID")
+}
+
+// Group gets group ID of given marketplace app.
+func (ma *MarketplaceApp) Group() (int, error) {
+	return ma.intAttribute("GID")
+}
+
+// Permissions gets marketplace app permissions.
+func (ma *MarketplaceApp) Permissions() (*Permissions, error) {
+	return ma.permissions()
+}
+
+// Lock gets lock of given marketplace app, nil is returned when the marketplace app is not locked.
+func (ma *MarketplaceApp) Lock() (*Lock, error) {
+	return ma.lock()
+}
+
+// RegistrationTime gets registration time of given marketplace app.
+func (ma *MarketplaceApp) RegistrationTime() (*time.Time, error) {
+	return ma.registrationTime()
+}
+
+// Marketplace gets ID of the marketplace of given marketplace app.
+func (ma *MarketplaceApp) Marketplace() (int, error) {
+	return ma.intAttribute("MARKETPLACE_ID")
+}
+
+// MarketplaceName gets name of the marketplace of given marketplace app.
+func (ma *MarketplaceApp) MarketplaceName() (string, error) {
+	return ma.Attribute("MARKETPLACE")
+}
+
+// Zone gets ID of the zone of given marketplace app.
+func (ma *MarketplaceApp) Zone() (int, error) {
+	return ma.intAttribute("ZONE_ID")
+}
+
+// Origin gets ID of the resource (e.g. image) the marketplace app was exported from, -1 is returned
+// when the marketplace app was not exported from this zone.
+func (ma *MarketplaceApp) Origin() (int, error) {
+	return ma.intAttribute("ORIGIN_ID")
+}
+
+// Source gets source of given marketplace app.
+func (ma *MarketplaceApp) Source() (string, error) {
+	return ma.Attribute("SOURCE")
+}
+
+// MD5 gets MD5 checksum of given marketplace app.
+func (ma *MarketplaceApp) MD5() (string, error) {
+	return ma.Attribute("MD5")
+}
+
+// Size gets size of given marketplace app in MB.
+func (ma *MarketplaceApp) Size() (int, error) {
+	return ma.intAttribute("SIZE")
+}
+
+// Description gets description of given marketplace app.
+func (ma *MarketplaceApp) Description() (string, error) {
+	return ma.Attribute("DESCRIPTION")
+}
+
+// Version gets version of given marketplace app.
+func (ma *MarketplaceApp) Version() (string, error) {
+	return ma.Attribute("VERSION")
+}
+
+// Format gets format of given marketplace app, e.g. raw or qcow2.
+func (ma *MarketplaceApp) Format() (string, error) {
+	return ma.Attribute("FORMAT")
+}
+
+// AppTemplate gets decoded template (APPTEMPLATE64) of the resource created when given marketplace app
+// is imported.
+func (ma *MarketplaceApp) AppTemplate() (string, error) {
+	encoded, err := ma.Attribute("APPTEMPLATE64")
+	if err != nil {
+		return "", err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
+
+// State gets state of given marketplace app.
+func (ma *MarketplaceApp) State() (MarketplaceAppState, error) {
+	i, err := ma.intAttribute("STATE")
+	return MarketplaceAppState(i), err
+}
+
+// Type gets type of given marketplace app.
+func (ma *MarketplaceApp) Type() (MarketplaceAppType, error) {
+	i, err := ma.intAttribute("TYPE")
+	return MarketplaceAppType(i), err
+}
@@ -0,0 +1,95 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	marketplaceAppXML = "xml/marketplaceApp.xml"
+)
+
+var _ = ginkgo.Describe("MarketplaceApp", func() {
+	var (
+		doc            *etree.Document
+		marketplaceApp *MarketplaceApp
+		err            error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create marketplace app with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(marketplaceAppXML)
+			marketplaceApp = CreateMarketplaceAppFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all MarketplaceApp attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(marketplaceApp).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(marketplaceApp.ID()).To(gomega.Equal(7))
+			gomega.Expect(marketplaceApp.Name()).To(gomega.Equal("debian-10"))
+			gomega.Expect(marketplaceApp.User()).To(gomega.Equal(46))
+			gomega.Expect(marketplaceApp.Group()).To(gomega.Equal(113))
+			gomega.Expect(marketplaceApp.Marketplace()).To(gomega.Equal(100))
+			gomega.Expect(marketplaceApp.MarketplaceName()).To(gomega.Equal("golden"))
+			gomega.Expect(marketplaceApp.Zone()).To(gomega.Equal(0))
+			gomega.Expect(marketplaceApp.Origin()).To(gomega.Equal(42))
+			gomega.Expect(marketplaceApp.Source()).To(gomega.Equal("http://market.example.com/debian-10"))
+			gomega.Expect(marketplaceApp.MD5()).To(gomega.Equal("0a3e1e4b9b3a5c7f4c1b3f55c0b6d1a2"))
+			gomega.Expect(marketplaceApp.Size()).To(gomega.Equal(2048))
+			gomega.Expect(marketplaceApp.Description()).To(gomega.Equal("Debian 10 golden image"))
+			gomega.Expect(marketplaceApp.Version()).To(gomega.Equal("1.2"))
+			gomega.Expect(marketplaceApp.Format()).To(gomega.Equal("qcow2"))
+			gomega.Expect(marketplaceApp.AppTemplate()).To(gomega.Equal(
+				"DEV_PREFIX=\"vd\"\nDRIVER=\"qcow2\"\nTYPE=\"OS\""))
+			gomega.Expect(marketplaceApp.State()).To(gomega.Equal(MarketplaceAppStateReady))
+			gomega.Expect(marketplaceApp.Type()).To(gomega.Equal(MarketplaceAppTypeImage))
+
+			regTime := time.Unix(int64(1546300800), 0)
+			gomega.Expect(marketplaceApp.RegistrationTime()).To(gomega.Equal(&regTime))
+
+			var permissions *Permissions
+			permissions, err = marketplaceApp.Permissions()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(permissions.User.Manage).To(gomega.Equal(true))
+			gomega.Expect(permissions.Group.Manage).To(gomega.Equal(false))
+
+			var lock *Lock
+			lock, err = marketplaceApp.Lock()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(lock.Level).To(gomega.Equal(LockUse))
+		})
+
+		ginkgo.It("should return an error for malformed app template", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			marketplaceApp.XMLData.SelectElement("APPTEMPLATE64").SetText("not base64!")
+
+			_, err = marketplaceApp.AppTemplate()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("when marketplace app has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			marketplaceApp = CreateMarketplaceAppWithID(42)
+		})
+
+		ginkgo.It("should create marketplace app", func() {
+			gomega.Expect(marketplaceApp.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that marketplace app doesn't have marketplace and app template", func() {
+			_, err = marketplaceApp.Marketplace()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			_, err = marketplaceApp.AppTemplate()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	marketplaceXML = "xml/marketplace.xml"
+)
+
+var _ = ginkgo.Describe("Marketplace", func() {
+	var (
+		doc         *etree.Document
+		marketplace *Marketplace
+		err         error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create marketplace with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(marketplaceXML)
+			marketplace = CreateMarketplaceFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all Marketplace attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(marketplace).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(marketplace.ID()).To(gomega.Equal(100))
+			gomega.Expect(marketplace.Name()).To(gomega.Equal("golden"))
+			gomega.Expect(marketplace.User()).To(gomega.Equal(0))
+			gomega.Expect(marketplace.Group()).To(gomega.Equal(0))
+			gomega.Expect(marketplace.MarketMad()).To(gomega.Equal("http"))
+			gomega.Expect(marketplace.Zone()).To(gomega.Equal(0))
+			gomega.Expect(marketplace.State()).To(gomega.Equal(MarketplaceStateEnabled))
+			gomega.Expect(marketplace.TotalMB()).To(gomega.Equal(1048576))
+			gomega.Expect(marketplace.FreeMB()).To(gomega.Equal(1040384))
+			gomega.Expect(marketplace.UsedMB()).To(gomega.Equal(8192))
+			gomega.Expect(marketplace.Apps()).To(gomega.Equal([]int{3, 7}))
+
+			var permissions *Permissions
+			permissions, err = marketplace.Permissions()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(permissions.User.Manage).To(gomega.Equal(true))
+			gomega.Expect(permissions.Other.Use).To(gomega.Equal(true))
+		})
+	})
+
+	ginkgo.Context("when marketplace has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			marketplace = CreateMarketplaceWithID(42)
+		})
+
+		ginkgo.It("should create marketplace", func() {
+			gomega.Expect(marketplace.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that marketplace doesn't have apps and driver", func() {
+			gomega.Expect(marketplace.Apps()).To(gomega.HaveLen(0))
+
+			_, err = marketplace.MarketMad()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,190 @@
+package resources
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+)
+
+// VirtualMachineMonitoring structure represents a monitoring sample of a virtual machine. CPU is in percents
+// of one CPU and memory in KB. Network and disk values are cumulative counters (bytes and number
+// of operations) since the virtual machine was started.
+type VirtualMachineMonitoring struct {
+	VMID                int       `json:"vm_id"`
+	Timestamp           time.Time `json:"timestamp"`
+	CPU                 float64   `json:"cpu"`
+	Memory              int       `json:"memory"`
+	NetRX               int64     `json:"net_rx"`
+	NetTX               int64     `json:"net_tx"`
+	DiskReadBytes       int64     `json:"disk_read_bytes"`
+	DiskWriteBytes      int64     `json:"disk_write_bytes"`
+	DiskReadOperations  int64     `json:"disk_read_operations"`
+	DiskWriteOperations int64     `json:"disk_write_operations"`
+}
+
+// VirtualMachineRate structure represents usage of a virtual machine in the interval between two
+// monitoring samples. CPU and memory are taken from the sample at the end of the interval, counters
+// are converted to values per second.
+type VirtualMachineRate struct {
+	VMID                int       `json:"vm_id"`
+	Start               time.Time `json:"start"`
+	End                 time.Time `json:"end"`
+	CPU                 float64   `json:"cpu"`
+	Memory              int       `json:"memory"`
+	NetRX               float64   `json:"net_rx"`
+	NetTX               float64   `json:"net_tx"`
+	DiskReadBytes       float64   `json:"disk_read_bytes"`
+	DiskWriteBytes      float64   `json:"disk_write_bytes"`
+	DiskReadOperations  float64   `json:"disk_read_operations"`
+	DiskWriteOperations float64   `json:"disk_write_operations"`
+}
+
+// monitoringCounters are tags of the cumulative counters in the order of the fields.
+var monitoringCounters = []string{"NETRX", "NETTX", "DISKRDBYTES", "DISKWRBYTES", "DISKRDIOPS", "DISKWRIOPS"}
+
+// ParseVirtualMachineMonitoring parses monitoring sample from VM element containing ID, LAST_POLL
+// and MONITORING elements, e.g. element of one.vm.monitoring result or virtual machine info.
+// Values missing in the sample are zero.
+func ParseVirtualMachineMonitoring(element *etree.Element) (*VirtualMachineMonitoring, error) {
+	ints, err := parseIntsFromElement(element, []string{"ID", "LAST_POLL"})
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &VirtualMachineMonitoring{VMID: ints[0], Timestamp: time.Unix(int64(ints[1]), 0)}
+
+	monitoring := element.SelectElement("MONITORING")
+	if monitoring == nil {
+		return sample, nil
+	}
+
+	values, err := parseMonitoringValues(monitoring, append([]string{"CPU", "MEMORY"}, monitoringCounters...))
+	if err != nil {
+		return nil, err
+	}
+
+	sample.CPU = values[0]
+	sample.Memory = int(values[1])
+	sample.NetRX = int64(values[2])
+	sample.NetTX = int64(values[3])
+	sample.DiskReadBytes = int64(values[4])
+	sample.DiskWriteBytes = int64(values[5])
+	sample.DiskReadOperations = int64(values[6])
+	sample.DiskWriteOperations = int64(values[7])
+
+	return sample, nil
+}
+
+// parseMonitoringValues parses values of given tags, missing values are zero.
+func parseMonitoringValues(element *etree.Element, tags []string) ([]float64, error) {
+	values := make([]float64, len(tags))
+	for i, tag := range tags {
+		e := element.SelectElement(tag)
+		if e == nil || e.Text() == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(e.Text(), bitSize64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// MonitoringRates converts monitoring samples to rates in the intervals between consecutive samples
+// of each virtual machine. Samples may be in any order and may belong to more virtual machines.
+// When a counter decreases (e.g. the virtual machine was restarted), the counter is considered
+// to start from zero. Rates are sorted by virtual machine ID and time.
+func MonitoringRates(samples []*VirtualMachineMonitoring) []*VirtualMachineRate {
+	sorted := make([]*VirtualMachineMonitoring, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].VMID != sorted[j].VMID {
+			return sorted[i].VMID < sorted[j].VMID
+		}
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	rates := make([]*VirtualMachineRate, 0)
+	for i := 1; i < len(sorted); i++ {
+		previous, current := sorted[i-1], sorted[i]
+		if previous.VMID != current.VMID {
+			continue
+		}
+
+		seconds := current.Timestamp.Sub(previous.Timestamp).Seconds()
+		if seconds <= 0 {
+			continue
+		}
+
+		rate := func(previous, current int64) float64 {
+			if current < previous {
+				previous = 0
+			}
+			return float64(current-previous) / seconds
+		}
+
+		rates = append(rates, &VirtualMachineRate{VMID: current.VMID, Start: previous.Timestamp,
+			End: current.Timestamp, CPU: current.CPU, Memory: current.Memory,
+			NetRX:               rate(previous.NetRX, current.NetRX),
+			NetTX:               rate(previous.NetTX, current.NetTX),
+			DiskReadBytes:       rate(previous.DiskReadBytes, current.DiskReadBytes),
+			DiskWriteBytes:      rate(previous.DiskWriteBytes, current.DiskWriteBytes),
+			DiskReadOperations:  rate(previous.DiskReadOperations, current.DiskReadOperations),
+			DiskWriteOperations: rate(previous.DiskWriteOperations, current.DiskWriteOperations)})
+	}
+
+	return rates
+}
+
+// HostMonitoring structure represents a monitoring sample of a host. CPU is in percents (100 per core),
+// memory in KB and disk in MB. Usage values are allocated by the virtual machines, used values are
+// measured on the host.
+type HostMonitoring struct {
+	HostID      int       `json:"host_id"`
+	Timestamp   time.Time `json:"timestamp"`
+	MaxCPU      int       `json:"max_cpu"`
+	CPUUsage    int       `json:"cpu_usage"`
+	UsedCPU     int       `json:"used_cpu"`
+	FreeCPU     int       `json:"free_cpu"`
+	MaxMemory   int       `json:"max_memory"`
+	MemoryUsage int       `json:"memory_usage"`
+	UsedMemory  int       `json:"used_memory"`
+	FreeMemory  int       `json:"free_memory"`
+	MaxDisk     int       `json:"max_disk"`
+	DiskUsage   int       `json:"disk_usage"`
+	UsedDisk    int       `json:"used_disk"`
+	FreeDisk    int       `json:"free_disk"`
+	RunningVMs  int       `json:"running_vms"`
+}
+
+// ParseHostMonitoring parses monitoring sample from HOST element containing ID, LAST_MON_TIME
+// and HOST_SHARE elements, e.g. element of one.host.monitoring result or host info.
+func ParseHostMonitoring(element *etree.Element) (*HostMonitoring, error) {
+	ints, err := parseIntsFromElement(element, []string{"ID", "LAST_MON_TIME"})
+	if err != nil {
+		return nil, err
+	}
+
+	share := element.SelectElement("HOST_SHARE")
+	if share == nil {
+		return nil, &errors.XMLElementError{Path: "HOST_SHARE"}
+	}
+
+	values, err := parseIntsFromElement(share, []string{"MAX_CPU", "CPU_USAGE", "USED_CPU", "FREE_CPU", "MAX_MEM",
+		"MEM_USAGE", "USED_MEM", "FREE_MEM", "MAX_DISK", "DISK_USAGE", "USED_DISK", "FREE_DISK", "RUNNING_VMS"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostMonitoring{HostID: ints[0], Timestamp: time.Unix(int64(ints[1]), 0), MaxCPU: values[0],
+		CPUUsage: values[1], UsedCPU: values[2], FreeCPU: values[3], MaxMemory: values[4], MemoryUsage: values[5],
+		UsedMemory: values[6], FreeMemory: values[7], MaxDisk: values[8], DiskUsage: values[9],
+		UsedDisk: values[10], FreeDisk: values[11], RunningVMs: values[12]}, nil
+}
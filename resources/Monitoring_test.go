@@ -0,0 +1,124 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Monitoring", func() {
+	ginkgo.Describe("ParseVirtualMachineMonitoring", func() {
+		var (
+			vm  *VirtualMachine
+			err error
+		)
+
+		ginkgo.BeforeEach(func() {
+			doc := etree.NewDocument()
+			err = doc.ReadFromFile(vmXML)
+			vm = CreateVirtualMachineFromXML(doc.Root())
+		})
+
+		ginkgo.It("should parse the last monitoring of virtual machine", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var sample *VirtualMachineMonitoring
+			sample, err = vm.Monitoring()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(sample).To(gomega.Equal(&VirtualMachineMonitoring{VMID: 57502,
+				Timestamp: time.Unix(1543406223, 0), CPU: 1, Memory: 2097152, NetRX: 12983215634, NetTX: 48708945}))
+		})
+
+		ginkgo.It("should return an error for wrong value", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			vm.XMLData.FindElement("MONITORING/NETRX").SetText("lots")
+
+			_, err = vm.Monitoring()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should return an error when virtual machine has no last poll", func() {
+			_, err = CreateVirtualMachineWithID(1).Monitoring()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Describe("MonitoringRates", func() {
+		start := time.Unix(1546300800, 0)
+
+		ginkgo.It("should compute rates between consecutive samples of each virtual machine", func() {
+			rates := MonitoringRates([]*VirtualMachineMonitoring{
+				{VMID: 2, Timestamp: start.Add(10 * time.Second), CPU: 20, NetTX: 300},
+				{VMID: 1, Timestamp: start.Add(10 * time.Second), CPU: 50, Memory: 1024, NetRX: 2000,
+					DiskReadBytes: 100},
+				{VMID: 1, Timestamp: start, CPU: 10, Memory: 512, NetRX: 1000},
+				{VMID: 2, Timestamp: start, CPU: 40, NetTX: 100},
+				{VMID: 1, Timestamp: start.Add(10 * time.Second), CPU: 60, NetRX: 3000},
+			})
+
+			gomega.Expect(rates).To(gomega.Equal([]*VirtualMachineRate{
+				{VMID: 1, Start: start, End: start.Add(10 * time.Second), CPU: 50, Memory: 1024, NetRX: 100,
+					DiskReadBytes: 10},
+				{VMID: 2, Start: start, End: start.Add(10 * time.Second), CPU: 20, NetTX: 20},
+			}))
+		})
+
+		ginkgo.It("should restart the counters when they decrease", func() {
+			rates := MonitoringRates([]*VirtualMachineMonitoring{
+				{VMID: 1, Timestamp: start, NetRX: 1000, NetTX: 1000},
+				{VMID: 1, Timestamp: start.Add(time.Minute), NetRX: 120, NetTX: 1600},
+			})
+
+			gomega.Expect(rates).To(gomega.HaveLen(1))
+			gomega.Expect(rates[0].NetRX).To(gomega.Equal(float64(2)))
+			gomega.Expect(rates[0].NetTX).To(gomega.Equal(float64(10)))
+		})
+	})
+
+	ginkgo.Describe("ParseHostMonitoring", func() {
+		var (
+			host *Host
+			err  error
+		)
+
+		ginkgo.BeforeEach(func() {
+			doc := etree.NewDocument()
+			err = doc.ReadFromFile(hostXML)
+			host = CreateHostFromXML(doc.Root())
+		})
+
+		ginkgo.It("should parse the last monitoring of host", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var sample *HostMonitoring
+			sample, err = host.Monitoring()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(sample).To(gomega.Equal(&HostMonitoring{HostID: 934, Timestamp: time.Unix(1539689293, 0),
+				MaxCPU: 4000, CPUUsage: 3500, UsedCPU: 120, FreeCPU: 3880, MaxMemory: 127608968,
+				MemoryUsage: 125829120, UsedMemory: 85918808, FreeMemory: 45884464, MaxDisk: 368, DiskUsage: 0,
+				UsedDisk: 110, FreeDisk: 239, RunningVMs: 18}))
+		})
+
+		ginkgo.It("should return an error for missing value", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			share := host.XMLData.SelectElement("HOST_SHARE")
+			share.RemoveChild(share.SelectElement("USED_CPU"))
+
+			_, err = host.Monitoring()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should return an error when host has no host share", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			host.XMLData.RemoveChild(host.XMLData.SelectElement("HOST_SHARE"))
+
+			_, err = host.Monitoring()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
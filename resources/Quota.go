@@ -0,0 +1,168 @@
+package resources
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+)
+
+// Special quota limits
+const (
+	// QuotaDefault - limit is taken from the default quotas
+	QuotaDefault = -1
+	// QuotaUnlimited - no limit
+	QuotaUnlimited = -2
+)
+
+// VMQuota structure represents limits and usage of virtual machine resources. Running and system disk
+// quotas are zero when OpenNebula doesn't report them.
+type VMQuota struct {
+	VMs                int     `json:"vms"`
+	VMsUsed            int     `json:"vms_used"`
+	CPU                float64 `json:"cpu"`
+	CPUUsed            float64 `json:"cpu_used"`
+	Memory             int     `json:"memory"`
+	MemoryUsed         int     `json:"memory_used"`
+	RunningVMs         int     `json:"running_vms"`
+	RunningVMsUsed     int     `json:"running_vms_used"`
+	RunningCPU         float64 `json:"running_cpu"`
+	RunningCPUUsed     float64 `json:"running_cpu_used"`
+	RunningMemory      int     `json:"running_memory"`
+	RunningMemoryUsed  int     `json:"running_memory_used"`
+	SystemDiskSize     int     `json:"system_disk_size"`
+	SystemDiskSizeUsed int     `json:"system_disk_size_used"`
+}
+
+// DatastoreQuota structure represents limits and usage of a datastore.
+type DatastoreQuota struct {
+	ID         int `json:"id"`
+	Images     int `json:"images"`
+	ImagesUsed int `json:"images_used"`
+	Size       int `json:"size"`
+	SizeUsed   int `json:"size_used"`
+}
+
+// NetworkQuota structure represents limits and usage of a virtual network.
+type NetworkQuota struct {
+	ID         int `json:"id"`
+	Leases     int `json:"leases"`
+	LeasesUsed int `json:"leases_used"`
+}
+
+// ImageQuota structure represents limits and usage of an image.
+type ImageQuota struct {
+	ID             int `json:"id"`
+	RunningVMs     int `json:"running_vms"`
+	RunningVMsUsed int `json:"running_vms_used"`
+}
+
+// Quotas structure contains all the quotas of a user or group. VM is nil when no virtual machine
+// quota is set.
+type Quotas struct {
+	VM         *VMQuota          `json:"vm,omitempty"`
+	Datastores []*DatastoreQuota `json:"datastores"`
+	Networks   []*NetworkQuota   `json:"networks"`
+	Images     []*ImageQuota     `json:"images"`
+}
+
+// ParseQuotas parses quotas from element containing DATASTORE_QUOTA, NETWORK_QUOTA, VM_QUOTA
+// and IMAGE_QUOTA elements, e.g. USER, GROUP or DEFAULT_USER_QUOTAS.
+func ParseQuotas(element *etree.Element) (*Quotas, error) {
+	for _, tag := range []string{"DATASTORE_QUOTA", "NETWORK_QUOTA", "VM_QUOTA", "IMAGE_QUOTA"} {
+		if element.SelectElement(tag) == nil {
+			return nil, &errors.XMLElementError{Path: tag}
+		}
+	}
+
+	quotas := &Quotas{}
+
+	if e := element.FindElement("VM_QUOTA/VM"); e != nil {
+		values, err := parseQuotaValues(e, []string{"VMS", "VMS_USED", "CPU", "CPU_USED", "MEMORY",
+			"MEMORY_USED"})
+		if err != nil {
+			return nil, err
+		}
+
+		// running and system disk quotas are missing in older versions of OpenNebula
+		optional, err := parseOptionalQuotaValues(e, []string{"RUNNING_VMS", "RUNNING_VMS_USED", "RUNNING_CPU",
+			"RUNNING_CPU_USED", "RUNNING_MEMORY", "RUNNING_MEMORY_USED", "SYSTEM_DISK_SIZE",
+			"SYSTEM_DISK_SIZE_USED"})
+		if err != nil {
+			return nil, err
+		}
+
+		quotas.VM = &VMQuota{VMs: int(values[0]), VMsUsed: int(values[1]), CPU: values[2], CPUUsed: values[3],
+			Memory: int(values[4]), MemoryUsed: int(values[5]), RunningVMs: int(optional[0]),
+			RunningVMsUsed: int(optional[1]), RunningCPU: optional[2], RunningCPUUsed: optional[3],
+			RunningMemory: int(optional[4]), RunningMemoryUsed: int(optional[5]), SystemDiskSize: int(optional[6]),
+			SystemDiskSizeUsed: int(optional[7])}
+	}
+
+	elements := element.FindElements("DATASTORE_QUOTA/DATASTORE")
+	quotas.Datastores = make([]*DatastoreQuota, len(elements))
+	for i, e := range elements {
+		values, err := parseQuotaValues(e, []string{"ID", "IMAGES", "IMAGES_USED", "SIZE", "SIZE_USED"})
+		if err != nil {
+			return nil, err
+		}
+
+		quotas.Datastores[i] = &DatastoreQuota{ID: int(values[0]), Images: int(values[1]),
+			ImagesUsed: int(values[2]), Size: int(values[3]), SizeUsed: int(values[4])}
+	}
+
+	elements = element.FindElements("NETWORK_QUOTA/NETWORK")
+	quotas.Networks = make([]*NetworkQuota, len(elements))
+	for i, e := range elements {
+		values, err := parseQuotaValues(e, []string{"ID", "LEASES", "LEASES_USED"})
+		if err != nil {
+			return nil, err
+		}
+
+		quotas.Networks[i] = &NetworkQuota{ID: int(values[0]), Leases: int(values[1]), LeasesUsed: int(values[2])}
+	}
+
+	elements = element.FindElements("IMAGE_QUOTA/IMAGE")
+	quotas.Images = make([]*ImageQuota, len(elements))
+	for i, e := range elements {
+		values, err := parseQuotaValues(e, []string{"ID", "RVMS", "RVMS_USED"})
+		if err != nil {
+			return nil, err
+		}
+
+		quotas.Images[i] = &ImageQuota{ID: int(values[0]), RunningVMs: int(values[1]),
+			RunningVMsUsed: int(values[2])}
+	}
+
+	return quotas, nil
+}
+
+// parseQuotaValues parses quota values, CPU limits can be fractional, so all the values are parsed as floats.
+func parseQuotaValues(element *etree.Element, tags []string) ([]float64, error) {
+	for _, tag := range tags {
+		if element.SelectElement(tag) == nil {
+			return nil, &errors.XMLElementError{Path: tag}
+		}
+	}
+
+	return parseOptionalQuotaValues(element, tags)
+}
+
+// parseOptionalQuotaValues parses quota values like parseQuotaValues, missing values are zero.
+func parseOptionalQuotaValues(element *etree.Element, tags []string) ([]float64, error) {
+	values := make([]float64, len(tags))
+	for i, tag := range tags {
+		e := element.SelectElement(tag)
+		if e == nil {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(e.Text(), bitSize64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
@@ -20,16 +20,16 @@ type Resource struct {
 
 // Permissions structure represents permissions
 type Permissions struct {
-	User  PermissionGroup
-	Group PermissionGroup
-	Other PermissionGroup
+	User  PermissionGroup `json:"user"`
+	Group PermissionGroup `json:"group"`
+	Other PermissionGroup `json:"other"`
 }
 
 // PermissionGroup structure to create permission structure
 type PermissionGroup struct {
-	Use    bool
-	Manage bool
-	Admin  bool
+	Use    bool `json:"use"`
+	Manage bool `json:"manage"`
+	Admin  bool `json:"admin"`
 }
 
 const invalidCode = -1
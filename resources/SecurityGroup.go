@@ -0,0 +1,199 @@
+package resources
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// SecurityGroup structure represents OpenNebula Security Group.
+type SecurityGroup struct {
+	Resource
+}
+
+// SecurityGroupProtocolMap contains string representation of SecurityGroupProtocol.
+var SecurityGroupProtocolMap = map[SecurityGroupProtocol]string{
+	SecurityGroupProtocolAll:    "ALL",
+	SecurityGroupProtocolTCP:    "TCP",
+	SecurityGroupProtocolUDP:    "UDP",
+	SecurityGroupProtocolICMP:   "ICMP",
+	SecurityGroupProtocolICMPv6: "ICMPv6",
+	SecurityGroupProtocolIPSec:  "IPSEC",
+}
+
+// SecurityGroupProtocol - protocol of security group rule
+type SecurityGroupProtocol int
+
+const (
+	// SecurityGroupProtocolAll - all protocols
+	SecurityGroupProtocolAll SecurityGroupProtocol = iota
+	// SecurityGroupProtocolTCP - TCP protocol
+	SecurityGroupProtocolTCP
+	// SecurityGroupProtocolUDP - UDP protocol
+	SecurityGroupProtocolUDP
+	// SecurityGroupProtocolICMP - ICMP protocol
+	SecurityGroupProtocolICMP
+	// SecurityGroupProtocolICMPv6 - ICMP protocol for IPv6
+	SecurityGroupProtocolICMPv6
+	// SecurityGroupProtocolIPSec - IPsec protocol
+	SecurityGroupProtocolIPSec
+)
+
+// SecurityGroupRuleTypeMap contains string representation of SecurityGroupRuleType.
+var SecurityGroupRuleTypeMap = map[SecurityGroupRuleType]string{
+	SecurityGroupRuleTypeInbound:  "inbound",
+	SecurityGroupRuleTypeOutbound: "outbound",
+}
+
+// SecurityGroupRuleType - direction of traffic the security group rule applies to
+type SecurityGroupRuleType int
+
+const (
+	// SecurityGroupRuleTypeInbound - rule for incoming traffic
+	SecurityGroupRuleTypeInbound SecurityGroupRuleType = iota
+	// SecurityGroupRuleTypeOutbound - rule for outgoing traffic
+	SecurityGroupRuleTypeOutbound
+)
+
+// SecurityGroupRule represents one rule of security group.
+// Range is a comma separated list of ports and port ranges, e.g. "22,80:90".
+// IP and Size define the address range the rule applies to, NetworkID defines
+// the virtual network the rule applies to instead.
+type SecurityGroupRule struct {
+	Protocol   SecurityGroupProtocol
+	RuleType   SecurityGroupRuleType
+	Range      string
+	IP         net.IP
+	Size       *int
+	ICMPType   *int
+	ICMPv6Type *int
+	NetworkID  *int
+}
+
+// CreateSecurityGroupWithID constructs Security Group with given ID.
+func CreateSecurityGroupWithID(id int) *SecurityGroup {
+	return &SecurityGroup{*CreateResource("SECURITY_GROUP", id)}
+}
+
+// CreateSecurityGroupFromXML constructs Security Group with full xml data.
+func CreateSecurityGroupFromXML(XMLdata *etree.Element) *SecurityGroup {
+	return &SecurityGroup{Resource: Resource{XMLData: XMLdata}}
+}
+
+// User gets user ID of given Security Group.
+func (sg *SecurityGroup) User() (int, error) {
+	return sg.intAttribute("UID")
+}
+
+// Group gets group ID of given Security Group.
+func (sg *SecurityGroup) Group() (int, error) {
+	return sg.intAttribute("GID")
+}
+
+// Permissions gets Security Group permissions.
+func (sg *SecurityGroup) Permissions() (*Permissions, error) {
+	return sg.permissions()
+}
+
+// Description gets description of given Security Group.
+func (sg *SecurityGroup) Description() (string, error) {
+	return sg.Attribute("TEMPLATE/DESCRIPTION")
+}
+
+// UpdatedVirtualMachines gets array of IDs of virtual machines with up to date rules.
+func (sg *SecurityGroup) UpdatedVirtualMachines() ([]int, error) {
+	return sg.arrayOfIDs("UPDATED_VMS")
+}
+
+// OutdatedVirtualMachines gets array of IDs of virtual machines waiting for rules to be updated.
+func (sg *SecurityGroup) OutdatedVirtualMachines() ([]int, error) {
+	return sg.arrayOfIDs("OUTDATED_VMS")
+}
+
+// UpdatingVirtualMachines gets array of IDs of virtual machines with rules being updated.
+func (sg *SecurityGroup) UpdatingVirtualMachines() ([]int, error) {
+	return sg.arrayOfIDs("UPDATING_VMS")
+}
+
+// ErrorVirtualMachines gets array of IDs of virtual machines which failed to update rules.
+func (sg *SecurityGroup) ErrorVirtualMachines() ([]int, error) {
+	return sg.arrayOfIDs("ERROR_VMS")
+}
+
+// Rules gets an array of rules of given Security Group.
+func (sg *SecurityGroup) Rules() ([]*SecurityGroupRule, error) {
+	elements := sg.XMLData.FindElements("TEMPLATE/RULE")
+	if len(elements) == 0 {
+		return make([]*SecurityGroupRule, 0), nil
+	}
+
+	rules := make([]*SecurityGroupRule, len(elements))
+	var err error
+
+	for i, e := range elements {
+		rules[i], err = createSecurityGroupRuleFromElement(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+func createSecurityGroupRuleFromElement(element *etree.Element) (*SecurityGroupRule, error) {
+	if element == nil {
+		return nil, &errors.XMLElementError{Path: "TEMPLATE/RULE"}
+	}
+
+	parsedStrings, err := parseStringsFromElement(element, []string{"PROTOCOL", "RULE_TYPE"})
+	if err != nil {
+		return nil, err
+	}
+
+	protocol, err := findSecurityGroupProtocolByValue(parsedStrings[0])
+	if err != nil {
+		return nil, err
+	}
+
+	ruleType, err := findSecurityGroupRuleTypeByValue(parsedStrings[1])
+	if err != nil {
+		return nil, err
+	}
+
+	// occurrence 0 - 1 (we can ignore error)
+	ruleRange := parseStringsFromElementWithoutError(element, []string{"RANGE"})[0]
+	ip := parseIPsFromElementWithoutError(element, []string{"IP"})[0]
+	parsedInts := parseIntsFromElementWithoutError(element, []string{"SIZE", "ICMP_TYPE", "ICMPv6_TYPE",
+		"NETWORK_ID"})
+
+	return &SecurityGroupRule{
+		Protocol:   *protocol,
+		RuleType:   *ruleType,
+		Range:      ruleRange,
+		IP:         ip,
+		Size:       parsedInts[0],
+		ICMPType:   parsedInts[1],
+		ICMPv6Type: parsedInts[2],
+		NetworkID:  parsedInts[3],
+	}, nil
+}
+
+func findSecurityGroupProtocolByValue(value string) (*SecurityGroupProtocol, error) {
+	for key, val := range SecurityGroupProtocolMap {
+		if val == value {
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find SecurityGroupProtocol of value: %s", value)
+}
+
+func findSecurityGroupRuleTypeByValue(value string) (*SecurityGroupRuleType, error) {
+	for key, val := range SecurityGroupRuleTypeMap {
+		if val == value {
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find SecurityGroupRuleType of value: %s", value)
+}
@@ -0,0 +1,130 @@
+package resources
+
+import (
+	"net"
+
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	securityGroupXML = "xml/securityGroup.xml"
+)
+
+var _ = ginkgo.Describe("SecurityGroup", func() {
+	var (
+		doc           *etree.Document
+		securityGroup *SecurityGroup
+		err           error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create security group with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(securityGroupXML)
+			securityGroup = CreateSecurityGroupFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all SecurityGroup attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(securityGroup).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(securityGroup.ID()).To(gomega.Equal(105))
+			gomega.Expect(securityGroup.Name()).To(gomega.Equal("web-servers"))
+			gomega.Expect(securityGroup.User()).To(gomega.Equal(46))
+			gomega.Expect(securityGroup.Group()).To(gomega.Equal(113))
+			gomega.Expect(securityGroup.Description()).To(gomega.Equal("HTTP and SSH access"))
+
+			var permissions *Permissions
+			permissions, err = securityGroup.Permissions()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(permissions.User.Use).To(gomega.Equal(true))
+			gomega.Expect(permissions.User.Manage).To(gomega.Equal(true))
+			gomega.Expect(permissions.User.Admin).To(gomega.Equal(false))
+			gomega.Expect(permissions.Group.Use).To(gomega.Equal(true))
+			gomega.Expect(permissions.Group.Manage).To(gomega.Equal(false))
+			gomega.Expect(permissions.Group.Admin).To(gomega.Equal(false))
+			gomega.Expect(permissions.Other.Use).To(gomega.Equal(false))
+			gomega.Expect(permissions.Other.Manage).To(gomega.Equal(false))
+			gomega.Expect(permissions.Other.Admin).To(gomega.Equal(false))
+
+			gomega.Expect(securityGroup.UpdatedVirtualMachines()).To(gomega.Equal([]int{57502, 57510}))
+			gomega.Expect(securityGroup.OutdatedVirtualMachines()).To(gomega.Equal([]int{57533}))
+			gomega.Expect(securityGroup.UpdatingVirtualMachines()).To(gomega.Equal([]int{}))
+			gomega.Expect(securityGroup.ErrorVirtualMachines()).To(gomega.Equal([]int{57540}))
+		})
+
+		ginkgo.It("should find all SecurityGroup rules", func() {
+			var rules []*SecurityGroupRule
+			rules, err = securityGroup.Rules()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(rules).To(gomega.HaveLen(4))
+
+			gomega.Expect(rules[0].Protocol).To(gomega.Equal(SecurityGroupProtocolTCP))
+			gomega.Expect(rules[0].RuleType).To(gomega.Equal(SecurityGroupRuleTypeInbound))
+			gomega.Expect(rules[0].Range).To(gomega.Equal("22,80:90"))
+			gomega.Expect(rules[0].IP).To(gomega.BeNil())
+			gomega.Expect(rules[0].Size).To(gomega.BeNil())
+			gomega.Expect(rules[0].NetworkID).To(gomega.BeNil())
+
+			size := 256
+			gomega.Expect(rules[1].Protocol).To(gomega.Equal(SecurityGroupProtocolAll))
+			gomega.Expect(rules[1].RuleType).To(gomega.Equal(SecurityGroupRuleTypeOutbound))
+			gomega.Expect(rules[1].Range).To(gomega.Equal(""))
+			gomega.Expect(rules[1].IP).To(gomega.Equal(net.ParseIP("10.0.0.0")))
+			gomega.Expect(rules[1].Size).To(gomega.Equal(&size))
+
+			icmpType := 8
+			networkID := 738
+			gomega.Expect(rules[2].Protocol).To(gomega.Equal(SecurityGroupProtocolICMP))
+			gomega.Expect(rules[2].ICMPType).To(gomega.Equal(&icmpType))
+			gomega.Expect(rules[2].NetworkID).To(gomega.Equal(&networkID))
+
+			icmpv6Type := 128
+			gomega.Expect(rules[3].Protocol).To(gomega.Equal(SecurityGroupProtocolICMPv6))
+			gomega.Expect(rules[3].ICMPType).To(gomega.BeNil())
+			gomega.Expect(rules[3].ICMPv6Type).To(gomega.Equal(&icmpv6Type))
+		})
+	})
+
+	ginkgo.Describe("create SecurityGroup", func() {
+		ginkgo.It("should create SecurityGroup", func() {
+			securityGroup = CreateSecurityGroupWithID(42)
+
+			gomega.Expect(securityGroup.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.Context("when SecurityGroup doesn't have given attribute", func() {
+			ginkgo.BeforeEach(func() {
+				securityGroup = CreateSecurityGroupWithID(42)
+				err = nil
+			})
+
+			ginkgo.It("should return that SecurityGroup doesn't have name", func() {
+				_, err = securityGroup.Name()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+
+			ginkgo.It("should return that SecurityGroup doesn't have user", func() {
+				_, err = securityGroup.User()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+
+			ginkgo.It("should return that SecurityGroup doesn't have group", func() {
+				_, err = securityGroup.Group()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+
+			ginkgo.It("should return that SecurityGroup doesn't have permissions", func() {
+				_, err = securityGroup.Permissions()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+
+			ginkgo.It("should return that SecurityGroup doesn't have any rules", func() {
+				gomega.Expect(securityGroup.Rules()).To(gomega.BeEmpty())
+			})
+		})
+	})
+})
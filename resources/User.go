@@ -115,3 +115,18 @@ func (u *User) LoginTokens() ([]LoginToken, error) {
 	}
 	return loginTokens, nil
 }
+
+// Quotas returns quotas of the given user
+func (u *User) Quotas() (*Quotas, error) {
+	return ParseQuotas(u.XMLData)
+}
+
+// DefaultQuotas returns default quotas applied to the users
+func (u *User) DefaultQuotas() (*Quotas, error) {
+	element := u.XMLData.SelectElement("DEFAULT_USER_QUOTAS")
+	if element == nil {
+		return nil, &errors.XMLElementError{Path: "DEFAULT_USER_QUOTAS"}
+	}
+
+	return ParseQuotas(element)
+}
@@ -49,6 +49,66 @@ var _ = ginkgo.Describe("User", func() {
 			gomega.Expect(loginTokens[0].ExpirationTime).To(gomega.Equal(&expTime))
 			gomega.Expect(loginTokens[0].Token).To(gomega.Equal("325efc70635143dd1b33a8d73d0a1aa159515dd2"))
 		})
+
+		ginkgo.It("should find user quotas", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var quotas *Quotas
+			quotas, err = user.Quotas()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(quotas.VM).To(gomega.Equal(&VMQuota{VMs: 5, VMsUsed: 1, CPU: 4.5, CPUUsed: 0.5,
+				Memory: 8192, MemoryUsed: 1024, RunningVMs: QuotaDefault, RunningVMsUsed: 1,
+				RunningCPU: QuotaDefault, RunningCPUUsed: 0.5, RunningMemory: QuotaDefault,
+				RunningMemoryUsed: 1024, SystemDiskSize: QuotaDefault}))
+			gomega.Expect(quotas.Datastores).To(gomega.Equal([]*DatastoreQuota{{ID: 1, Images: 10, ImagesUsed: 2,
+				Size: 20480, SizeUsed: 4096}}))
+			gomega.Expect(quotas.Networks).To(gomega.Equal([]*NetworkQuota{{ID: 3, Leases: QuotaUnlimited,
+				LeasesUsed: 1}}))
+			gomega.Expect(quotas.Images).To(gomega.Equal([]*ImageQuota{{ID: 7, RunningVMs: QuotaDefault,
+				RunningVMsUsed: 1}}))
+		})
+
+		ginkgo.It("should find default user quotas", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var quotas *Quotas
+			quotas, err = user.DefaultQuotas()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(quotas.VM).NotTo(gomega.BeNil())
+			gomega.Expect(quotas.VM.VMs).To(gomega.Equal(10))
+			gomega.Expect(quotas.VM.CPU).To(gomega.Equal(float64(QuotaUnlimited)))
+			gomega.Expect(quotas.Datastores).To(gomega.BeEmpty())
+			gomega.Expect(quotas.Networks).To(gomega.BeEmpty())
+			gomega.Expect(quotas.Images).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should treat running and system disk quotas as optional", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			vm := user.XMLData.FindElement("VM_QUOTA/VM")
+			for _, tag := range []string{"RUNNING_VMS", "RUNNING_VMS_USED", "RUNNING_CPU", "RUNNING_CPU_USED",
+				"RUNNING_MEMORY", "RUNNING_MEMORY_USED", "SYSTEM_DISK_SIZE", "SYSTEM_DISK_SIZE_USED"} {
+				vm.RemoveChild(vm.SelectElement(tag))
+			}
+
+			var quotas *Quotas
+			quotas, err = user.Quotas()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(quotas.VM).To(gomega.Equal(&VMQuota{VMs: 5, VMsUsed: 1, CPU: 4.5, CPUUsed: 0.5,
+				Memory: 8192, MemoryUsed: 1024}))
+		})
+
+		ginkgo.It("should return an error when quota value is missing", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			user.XMLData.FindElement("NETWORK_QUOTA/NETWORK").RemoveChild(
+				user.XMLData.FindElement("NETWORK_QUOTA/NETWORK/LEASES"))
+
+			_, quotasErr := user.Quotas()
+			gomega.Expect(quotasErr).To(gomega.HaveOccurred())
+		})
 	})
 
 	ginkgo.Describe("create user", func() {
@@ -108,6 +168,16 @@ var _ = ginkgo.Describe("User", func() {
 				gomega.Expect(err).To(gomega.HaveOccurred())
 			})
 
+			ginkgo.It("should return that user doesn't have quotas", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				_, err = user.Quotas()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+
+				_, err = user.DefaultQuotas()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+
 			ginkgo.It("should return empty array of LoginTokens and no error", func() {
 				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
 
@@ -0,0 +1,169 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// VMGroup structure represents OpenNebula VM group. VM group defines roles of virtual machines
+// and placement (affinity) rules of the roles.
+type VMGroup struct {
+	Resource
+}
+
+// VMGroupPolicyMap contains string representation of VMGroupPolicy.
+var VMGroupPolicyMap = map[VMGroupPolicy]string{
+	VMGroupPolicyNone:        "NONE",
+	VMGroupPolicyAffined:     "AFFINED",
+	VMGroupPolicyAntiAffined: "ANTI_AFFINED",
+}
+
+// VMGroupPolicy - placement policy of virtual machines within a role
+type VMGroupPolicy int
+
+const (
+	// VMGroupPolicyNone - virtual machines of the role are placed without restrictions
+	VMGroupPolicyNone VMGroupPolicy = iota
+	// VMGroupPolicyAffined - virtual machines of the role are placed on the same host
+	VMGroupPolicyAffined
+	// VMGroupPolicyAntiAffined - virtual machines of the role are placed on different hosts
+	VMGroupPolicyAntiAffined
+)
+
+// VMGroupRole represents one role of VM group. HostAffined and HostAntiAffined are IDs of hosts
+// the virtual machines of the role have to be placed on or must not be placed on.
+type VMGroupRole struct {
+	ID              int           `json:"id"`
+	Name            string        `json:"name"`
+	Policy          VMGroupPolicy `json:"policy"`
+	HostAffined     []int         `json:"host_affined"`
+	HostAntiAffined []int         `json:"host_anti_affined"`
+	VirtualMachines []int         `json:"virtual_machines"`
+}
+
+// CreateVMGroupWithID constructs VM group with given ID.
+func CreateVMGroupWithID(id int) *VMGroup {
+	return &VMGroup{*CreateResource("VM_GROUP", id)}
+}
+
+// CreateVMGroupFromXML constructs VM group with full xml data.
+func CreateVMGroupFromXML(XMLdata *etree.Element) *VMGroup {
+	return &VMGroup{Resource: Resource{XMLData: XMLdata}}
+}
+
+// User gets user ID of given VM group.
+func (vg *VMGroup) User() (int, error) {
+	return vg.intAttribute("UID")
+}
+
+// Group gets group ID of given VM group.
+func (vg *VMGroup) Group() (int, error) {
+	return vg.intAttribute("GID")
+}
+
+// Permissions gets VM group permissions.
+func (vg *VMGroup) Permissions() (*Permissions, error) {
+	return vg.permissions()
+}
+
+// Description gets description of given VM group.
+func (vg *VMGroup) Description() (string, error) {
+	return vg.Attribute("TEMPLATE/DESCRIPTION")
+}
+
+// Lock gets lock of given VM group, nil is returned when the VM group is not locked.
+func (vg *VMGroup) Lock() (*Lock, error) {
+	return vg.lock()
+}
+
+// Roles gets an array of roles of given VM group.
+func (vg *VMGroup) Roles() ([]*VMGroupRole, error) {
+	elements := vg.XMLData.FindElements("ROLES/ROLE")
+
+	roles := make([]*VMGroupRole, len(elements))
+	var err error
+
+	for i, e := range elements {
+		roles[i], err = createVMGroupRoleFromElement(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return roles, nil
+}
+
+// AffinedRoles gets sets of roles whose virtual machines are placed on the same host.
+func (vg *VMGroup) AffinedRoles() [][]string {
+	return vg.roleSets("TEMPLATE/AFFINED")
+}
+
+// AntiAffinedRoles gets sets of roles whose virtual machines are placed on different hosts.
+func (vg *VMGroup) AntiAffinedRoles() [][]string {
+	return vg.roleSets("TEMPLATE/ANTI_AFFINED")
+}
+
+// roleSets parses rules with comma separated names of roles, e.g. "db, web".
+func (vg *VMGroup) roleSets(path string) [][]string {
+	elements := vg.XMLData.FindElements(path)
+
+	sets := make([][]string, len(elements))
+	for i, e := range elements {
+		names := strings.Split(e.Text(), ",")
+		for j := range names {
+			names[j] = strings.TrimSpace(names[j])
+		}
+		sets[i] = names
+	}
+	return sets
+}
+
+func createVMGroupRoleFromElement(element *etree.Element) (*VMGroupRole, error) {
+	id, err := intAttributeFromElement(element, "ID")
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := attributeFromElement(element, "NAME")
+	if err != nil {
+		return nil, err
+	}
+
+	role := &VMGroupRole{ID: id, Name: name, Policy: VMGroupPolicyNone}
+
+	if policy := parseStringsFromElementWithoutError(element, []string{"POLICY"})[0]; policy != "" {
+		p, err := findVMGroupPolicyByValue(policy)
+		if err != nil {
+			return nil, err
+		}
+		role.Policy = *p
+	}
+
+	// IDs are comma separated lists, e.g. "1,2"
+	tags := []string{"HOST_AFFINED", "HOST_ANTI_AFFINED", "VMS"}
+	lists := parseStringsFromElementWithoutError(element, tags)
+	for i, target := range []*[]int{&role.HostAffined, &role.HostAntiAffined, &role.VirtualMachines} {
+		*target = make([]int, 0)
+		if lists[i] == "" {
+			continue
+		}
+
+		if *target, err = parseIntsFromString(lists[i]); err != nil {
+			return nil, &errors.XMLElementError{Path: "ROLE/" + tags[i]}
+		}
+	}
+
+	return role, nil
+}
+
+func findVMGroupPolicyByValue(value string) (*VMGroupPolicy, error) {
+	for key, val := range VMGroupPolicyMap {
+		if val == value {
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find VMGroupPolicy of value: %s", value)
+}
@@ -0,0 +1,127 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	vmGroupXML = "xml/vmGroup.xml"
+)
+
+var _ = ginkgo.Describe("VMGroup", func() {
+	var (
+		doc     *etree.Document
+		vmGroup *VMGroup
+		err     error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create VM group with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(vmGroupXML)
+			vmGroup = CreateVMGroupFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all VMGroup attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(vmGroup).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(vmGroup.ID()).To(gomega.Equal(12))
+			gomega.Expect(vmGroup.Name()).To(gomega.Equal("database"))
+			gomega.Expect(vmGroup.User()).To(gomega.Equal(46))
+			gomega.Expect(vmGroup.Group()).To(gomega.Equal(113))
+			gomega.Expect(vmGroup.Description()).To(gomega.Equal("database replicas"))
+
+			var permissions *Permissions
+			permissions, err = vmGroup.Permissions()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(permissions.User.Manage).To(gomega.Equal(true))
+			gomega.Expect(permissions.Group.Use).To(gomega.Equal(true))
+			gomega.Expect(permissions.Other.Use).To(gomega.Equal(false))
+
+			var lock *Lock
+			lock, err = vmGroup.Lock()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(lock.Level).To(gomega.Equal(LockManage))
+			gomega.Expect(lock.Owner).To(gomega.Equal(46))
+			gomega.Expect(*lock.Time).To(gomega.BeTemporally("==", time.Unix(1546300800, 0)))
+			gomega.Expect(lock.RequestID).To(gomega.Equal(-1))
+		})
+
+		ginkgo.It("should find all VMGroup roles", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var roles []*VMGroupRole
+			roles, err = vmGroup.Roles()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(roles).To(gomega.Equal([]*VMGroupRole{
+				{ID: 0, Name: "replica", Policy: VMGroupPolicyAntiAffined, HostAffined: []int{},
+					HostAntiAffined: []int{937, 938}, VirtualMachines: []int{57502, 57510}},
+				{ID: 1, Name: "proxy", Policy: VMGroupPolicyNone, HostAffined: []int{940},
+					HostAntiAffined: []int{}, VirtualMachines: []int{}},
+				{ID: 2, Name: "backup", Policy: VMGroupPolicyNone, HostAffined: []int{}, HostAntiAffined: []int{},
+					VirtualMachines: []int{}},
+			}))
+		})
+
+		ginkgo.It("should find affinity rules between roles", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			gomega.Expect(vmGroup.AffinedRoles()).To(gomega.Equal([][]string{{"proxy", "backup"}}))
+			gomega.Expect(vmGroup.AntiAffinedRoles()).To(gomega.Equal([][]string{{"replica", "backup"}}))
+		})
+
+		ginkgo.It("should return an error for unknown policy", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			vmGroup.XMLData.FindElement("ROLES/ROLE/POLICY").SetText("SOMEWHERE")
+
+			_, err = vmGroup.Roles()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should return an error for wrong list of virtual machines", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			vmGroup.XMLData.FindElement("ROLES/ROLE/VMS").SetText("1,two")
+
+			_, err = vmGroup.Roles()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("when VM group has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			vmGroup = CreateVMGroupWithID(42)
+		})
+
+		ginkgo.It("should create VM group", func() {
+			gomega.Expect(vmGroup.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that VM group is not locked", func() {
+			var lock *Lock
+			lock, err = vmGroup.Lock()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(lock).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should return that VM group doesn't have roles", func() {
+			var roles []*VMGroupRole
+			roles, err = vmGroup.Roles()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(roles).To(gomega.HaveLen(0))
+			gomega.Expect(vmGroup.AffinedRoles()).To(gomega.HaveLen(0))
+		})
+
+		ginkgo.It("should return that VM group doesn't have name", func() {
+			_, err = vmGroup.Name()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+})
@@ -199,19 +199,35 @@ type VMContext *etree.Element
 
 // Disk structure represents disk of Virtual Machine.
 type Disk struct {
-	ClusterID   int
-	DatastoreID int
-	DevPrefix   string
-	DiskID      int
-	DiskType    DiskType
-	Driver      string
-	ImageID     int
-	ImageState  ImageState
-	ReadOnly    bool
-	Size        int
-	Target      string
-	TmMad       string
-	Type        DiskType
+	ClusterID   int        `json:"cluster_id"`
+	DatastoreID int        `json:"datastore_id"`
+	DevPrefix   string     `json:"dev_prefix"`
+	DiskID      int        `json:"disk_id"`
+	DiskType    DiskType   `json:"disk_type"`
+	Driver      string     `json:"driver"`
+	ImageID     int        `json:"image_id"`
+	ImageState  ImageState `json:"image_state"`
+	ReadOnly    bool       `json:"read_only"`
+	Size        int        `json:"size"`
+	Target      string     `json:"target"`
+	TmMad       string     `json:"tm_mad"`
+	Type        DiskType   `json:"type"`
+
+	// Snapshots contains the root snapshots (without parent) of the disk, the rest of the snapshots
+	// are available through their Children.
+	Snapshots []*DiskSnapshot `json:"snapshots"`
+}
+
+// DiskSnapshot structure represents snapshot of a Virtual Machine disk.
+// Snapshots of a disk form a tree, Children contains snapshots created on top of the given snapshot.
+type DiskSnapshot struct {
+	ID       int             `json:"id"`
+	Name     string          `json:"name"`
+	Date     *time.Time      `json:"date,omitempty"`
+	Parent   int             `json:"parent"`
+	Size     int             `json:"size"`
+	Active   bool            `json:"active"`
+	Children []*DiskSnapshot `json:"children,omitempty"`
 }
 
 // GraphicsTypeMap to convert GraphicsType to string.
@@ -236,43 +252,43 @@ const (
 	GraphicsTypeNone
 )
 
-// Graphics represents graphics of Virtual Machine.
+// Graphics represents graphics of Virtual Machine. Password is not serialized to JSON.
 type Graphics struct {
-	Listen         net.IP
-	Password       string
-	Port           *int
-	RandomPassword bool
-	Type           GraphicsType
-	KeyMap         string
+	Listen         net.IP       `json:"listen,omitempty"`
+	Password       string       `json:"-"`
+	Port           *int         `json:"port,omitempty"`
+	RandomPassword bool         `json:"random_password"`
+	Type           GraphicsType `json:"type"`
+	KeyMap         string       `json:"key_map"`
 }
 
 // NIC represents Network Interface of VM.
 type NIC struct {
-	XMLName        xml.Name `xml:"NIC,omitempty"`
-	AddressRangeID int      `xml:"AR_ID,omitempty"`
-	Bridge         string   `xml:"BRIDGE,omitempty"`
-	ClusterIDs     []int    `xml:"CLUSTER_ID,omitempty"`
-	IP             net.IP   `xml:"IP,omitempty"`
-	IP6Global      net.IP   `xml:"IP6_GLOBAL,omitempty"`
-	IP6Link        net.IP   `xml:"IP6_LINK,omitempty"`
-	IP6Ula         net.IP   `xml:"IP6_ULA,omitempty"`
-	Mac            string   `xml:"MAC,omitempty"`
-	MTU            *int     `xml:"MTU,omitempty"`
-	Network        string   `xml:"NETWORK,omitempty"`
-	NetworkID      int      `xml:"NETWORK_ID,omitempty"`
-	NicID          int      `xml:"NIC_ID,omitempty"`
-	Target         string   `xml:"TARGET,omitempty"`
-	VnMad          string   `xml:"VN_MAD,omitempty"`
+	XMLName        xml.Name `xml:"NIC,omitempty" json:"-"`
+	AddressRangeID int      `xml:"AR_ID,omitempty" json:"address_range_id"`
+	Bridge         string   `xml:"BRIDGE,omitempty" json:"bridge"`
+	ClusterIDs     []int    `xml:"CLUSTER_ID,omitempty" json:"cluster_ids,omitempty"`
+	IP             net.IP   `xml:"IP,omitempty" json:"ip,omitempty"`
+	IP6Global      net.IP   `xml:"IP6_GLOBAL,omitempty" json:"ip6_global,omitempty"`
+	IP6Link        net.IP   `xml:"IP6_LINK,omitempty" json:"ip6_link,omitempty"`
+	IP6Ula         net.IP   `xml:"IP6_ULA,omitempty" json:"ip6_ula,omitempty"`
+	Mac            string   `xml:"MAC,omitempty" json:"mac"`
+	MTU            *int     `xml:"MTU,omitempty" json:"mtu,omitempty"`
+	Network        string   `xml:"NETWORK,omitempty" json:"network"`
+	NetworkID      int      `xml:"NETWORK_ID,omitempty" json:"network_id"`
+	NicID          int      `xml:"NIC_ID,omitempty" json:"nic_id"`
+	Target         string   `xml:"TARGET,omitempty" json:"target"`
+	VnMad          string   `xml:"VN_MAD,omitempty" json:"vn_mad"`
 }
 
 // OperatingSystem represents OS of VM.
 type OperatingSystem struct {
-	Architecture ArchitectureType
-	Boot         string
-	Bootloader   string
-	KernelCMD    string
-	Machine      string
-	Root         string
+	Architecture ArchitectureType `json:"architecture"`
+	Boot         string           `json:"boot"`
+	Bootloader   string           `json:"bootloader"`
+	KernelCMD    string           `json:"kernel_cmd"`
+	Machine      string           `json:"machine"`
+	Root         string           `json:"root"`
 }
 
 // ArchitectureTypeMap to convert architecture type to string.
@@ -293,8 +309,8 @@ const (
 
 // Raw represents Raw of Virtual Machine.
 type Raw struct {
-	Data string
-	Type string
+	Data string `json:"data"`
+	Type string `json:"type"`
 }
 
 // UserTemplate represents XML data of VM User Template.
@@ -302,23 +318,32 @@ type UserTemplate *etree.Element
 
 // History structure represents history record of Virtual Machine.
 type History struct {
-	OID         int
-	Seq         int
-	Hostname    string
-	HID         *int
-	CID         *int
-	STime       *time.Time
-	ETime       *time.Time
-	VMMad       string
-	TmMad       string
-	DatastoreID *int
-	PSTime      *time.Time
-	PETime      *time.Time
-	RSTime      *time.Time
-	RETime      *time.Time
-	ESTime      *time.Time
-	EETime      *time.Time
-	Action      Action
+	OID         int        `json:"oid"`
+	Seq         int        `json:"seq"`
+	Hostname    string     `json:"hostname"`
+	HID         *int       `json:"hid,omitempty"`
+	CID         *int       `json:"cid,omitempty"`
+	STime       *time.Time `json:"stime,omitempty"`
+	ETime       *time.Time `json:"etime,omitempty"`
+	VMMad       string     `json:"vm_mad"`
+	TmMad       string     `json:"tm_mad"`
+	DatastoreID *int       `json:"datastore_id,omitempty"`
+	PSTime      *time.Time `json:"pstime,omitempty"`
+	PETime      *time.Time `json:"petime,omitempty"`
+	RSTime      *time.Time `json:"rstime,omitempty"`
+	RETime      *time.Time `json:"retime,omitempty"`
+	ESTime      *time.Time `json:"estime,omitempty"`
+	EETime      *time.Time `json:"eetime,omitempty"`
+	Action      Action     `json:"action"`
+	VM          *HistoryVM `json:"vm,omitempty"`
+}
+
+// Snapshot structure represents snapshot of Virtual Machine.
+type Snapshot struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Time         *time.Time `json:"time,omitempty"`
+	HypervisorID string     `json:"hypervisor_id"`
 }
 
 // ActionMap to convert Action to string representation.
@@ -630,6 +655,11 @@ func createDiskFromElement(element *etree.Element) (*Disk, error) {
 		return nil, err
 	}
 
+	snapshots, err := createDiskSnapshotsFromElement(findDiskSnapshotsElement(element, parsedInts[2]))
+	if err != nil {
+		return nil, err
+	}
+
 	return &Disk{
 		ClusterID:   parsedInts[0],
 		DatastoreID: parsedInts[1],
@@ -644,6 +674,87 @@ func createDiskFromElement(element *etree.Element) (*Disk, error) {
 		Target:      parsedStrings[4],
 		TmMad:       parsedStrings[5],
 		Type:        *ttype,
+		Snapshots:   snapshots,
+	}, nil
+}
+
+// findDiskSnapshotsElement finds SNAPSHOTS element of the disk with given ID in the VM XML data
+// the disk element belongs to.
+func findDiskSnapshotsElement(diskElement *etree.Element, diskID int) *etree.Element {
+	template := diskElement.Parent()
+	if template == nil || template.Parent() == nil {
+		return nil
+	}
+
+	for _, e := range template.Parent().SelectElements("SNAPSHOTS") {
+		id, err := intAttributeFromElement(e, "DISK_ID")
+		if err == nil && id == diskID {
+			return e
+		}
+	}
+	return nil
+}
+
+// createDiskSnapshotsFromElement creates a tree of snapshots from SNAPSHOTS element of a disk. The returned
+// array contains the root snapshots (without parent), the rest of the snapshots are available through
+// their Children.
+func createDiskSnapshotsFromElement(element *etree.Element) ([]*DiskSnapshot, error) {
+	if element == nil {
+		return make([]*DiskSnapshot, 0), nil
+	}
+
+	elements := element.SelectElements("SNAPSHOT")
+
+	snapshots := make([]*DiskSnapshot, len(elements))
+	byID := make(map[int]*DiskSnapshot, len(elements))
+	var err error
+
+	for i, e := range elements {
+		snapshots[i], err = createDiskSnapshotFromElement(e)
+		if err != nil {
+			return nil, err
+		}
+		byID[snapshots[i].ID] = snapshots[i]
+	}
+
+	roots := make([]*DiskSnapshot, 0)
+	for _, snapshot := range snapshots {
+		parent, ok := byID[snapshot.Parent]
+		if !ok {
+			roots = append(roots, snapshot)
+			continue
+		}
+		parent.Children = append(parent.Children, snapshot)
+	}
+	return roots, nil
+}
+
+func createDiskSnapshotFromElement(element *etree.Element) (*DiskSnapshot, error) {
+	if element == nil {
+		return nil, &errors.XMLElementError{Path: "SNAPSHOTS/SNAPSHOT"}
+	}
+
+	parsedInts, err := parseIntsFromElement(element, []string{"ID", "PARENT", "SIZE"})
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := parseTimesFromElement(element, []string{"DATE"})
+	if err != nil {
+		return nil, err
+	}
+
+	// occurrence 0 - 1 (we can ignore error)
+	parsedStrings := parseStringsFromElementWithoutError(element, []string{"NAME", "ACTIVE"})
+
+	return &DiskSnapshot{
+		ID:       parsedInts[0],
+		Name:     parsedStrings[0],
+		Date:     times[0],
+		Parent:   parsedInts[1],
+		Size:     parsedInts[2],
+		Active:   stringToBool(parsedStrings[1]),
+		Children: make([]*DiskSnapshot, 0),
 	}, nil
 }
 
@@ -701,6 +812,11 @@ func (vm *VirtualMachine) Memory() (int, error) {
 	return vm.intAttribute("TEMPLATE/MEMORY")
 }
 
+// Monitoring gets the last monitoring sample of given VM.
+func (vm *VirtualMachine) Monitoring() (*VirtualMachineMonitoring, error) {
+	return ParseVirtualMachineMonitoring(vm.XMLData)
+}
+
 // NICs gets an array of NICs of given VM.
 func (vm *VirtualMachine) NICs() ([]*NIC, error) {
 	elements := vm.XMLData.FindElements("TEMPLATE/NIC")
@@ -787,6 +903,9 @@ func (vm *VirtualMachine) OperatingSystem() (*OperatingSystem, error) {
 			break
 		}
 	}
+	if archType == nil {
+		return nil, &errors.XMLElementError{Path: "TEMPLATE/OS/ARCH"}
+	}
 
 	return &OperatingSystem{Architecture: *archType,
 		Boot:       stringsWithoutError[0],
@@ -870,7 +989,15 @@ func createHistoryFromElement(element *etree.Element) (*History, error) {
 	timesWithoutError := parseTimesFromElementWithoutError(element, []string{"PSTIME", "PETIME", "RSTIME", "RETIME",
 		"ESTIME", "EETIME"})
 
+	var vm *HistoryVM
+	if e := element.SelectElement("VM"); e != nil {
+		if vm, err = createHistoryVMFromElement(e); err != nil {
+			return nil, err
+		}
+	}
+
 	return &History{
+		VM:          vm,
 		Hostname:    stringsWithoutError[0],
 		VMMad:       stringsWithoutError[1],
 		TmMad:       stringsWithoutError[2],
@@ -890,3 +1017,175 @@ func createHistoryFromElement(element *etree.Element) (*History, error) {
 		EETime:      timesWithoutError[5],
 	}, nil
 }
+
+// Snapshots gets an array of Snapshot structures of given VM.
+func (vm *VirtualMachine) Snapshots() ([]*Snapshot, error) {
+	elements := vm.XMLData.FindElements("TEMPLATE/SNAPSHOT")
+	if len(elements) == 0 {
+		return make([]*Snapshot, 0), nil
+	}
+
+	snapshots := make([]*Snapshot, len(elements))
+	var err error
+
+	for i, e := range elements {
+		snapshots[i], err = createSnapshotFromElement(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+func createSnapshotFromElement(element *etree.Element) (*Snapshot, error) {
+	if element == nil {
+		return nil, &errors.XMLElementError{Path: "TEMPLATE/SNAPSHOT"}
+	}
+
+	parsedInts, err := parseIntsFromElement(element, []string{"SNAPSHOT_ID"})
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := parseTimesFromElement(element, []string{"TIME"})
+	if err != nil {
+		return nil, err
+	}
+
+	// occurrence 0 - 1 (we can ignore error)
+	parsedStrings := parseStringsFromElementWithoutError(element, []string{"NAME", "HYPERVISOR_ID"})
+
+	return &Snapshot{
+		ID:           parsedInts[0],
+		Name:         parsedStrings[0],
+		Time:         times[0],
+		HypervisorID: parsedStrings[1],
+	}, nil
+}
+
+// VirtualMachineInfo structure contains information about a virtual machine decoded from its XML data.
+// It doesn't depend on the XML data, so it can be cached or serialized (e.g. to JSON). JSON doesn't contain
+// the password of the graphics.
+type VirtualMachineInfo struct {
+	ID              int                    `json:"id"`
+	Name            string                 `json:"name"`
+	User            int                    `json:"user"`
+	UserName        string                 `json:"user_name"`
+	Group           int                    `json:"group"`
+	GroupName       string                 `json:"group_name"`
+	Permissions     *Permissions           `json:"permissions"`
+	LastPoll        *time.Time             `json:"last_poll,omitempty"`
+	State           VirtualMachineState    `json:"state"`
+	LCMState        VirtualMachineLcmState `json:"lcm_state"`
+	PrevState       VirtualMachineState    `json:"prev_state"`
+	PrevLCMState    VirtualMachineLcmState `json:"prev_lcm_state"`
+	Reschedule      bool                   `json:"reschedule"`
+	STime           *time.Time             `json:"stime,omitempty"`
+	ETime           *time.Time             `json:"etime,omitempty"`
+	DeployID        string                 `json:"deploy_id"`
+	CPU             float64                `json:"cpu"`
+	VCPU            *int                   `json:"vcpu,omitempty"`
+	Memory          int                    `json:"memory"`
+	TemplateID      *int                   `json:"template_id,omitempty"`
+	Disks           []*Disk                `json:"disks"`
+	NICs            []*NIC                 `json:"nics"`
+	Graphics        *Graphics              `json:"graphics"`
+	OperatingSystem *OperatingSystem       `json:"os,omitempty"`
+	Snapshots       []*Snapshot            `json:"snapshots"`
+	HistoryRecords  []*History             `json:"history_records"`
+}
+
+// Decode decodes all the information about given VM at once.
+func (vm *VirtualMachine) Decode() (*VirtualMachineInfo, error) {
+	parsedInts, err := parseIntsFromElement(vm.XMLData, []string{"ID", "UID", "GID", "STATE", "LCM_STATE",
+		"PREV_STATE", "PREV_LCM_STATE", "RESCHED", "TEMPLATE/MEMORY"})
+	if err != nil {
+		return nil, err
+	}
+
+	parsedStrings, err := parseStringsFromElement(vm.XMLData, []string{"NAME", "UNAME", "GNAME", "DEPLOY_ID"})
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := parseTimesFromElement(vm.XMLData, []string{"LAST_POLL", "STIME", "ETIME"})
+	if err != nil {
+		return nil, err
+	}
+
+	// occurrence 0 - 1 (we can ignore error)
+	intsWithoutError := parseIntsFromElementWithoutError(vm.XMLData, []string{"TEMPLATE/VCPU",
+		"TEMPLATE/TEMPLATE_ID"})
+
+	permissions, err := vm.Permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	cpu, err := vm.CPU()
+	if err != nil {
+		return nil, err
+	}
+
+	disks, err := vm.Disks()
+	if err != nil {
+		return nil, err
+	}
+
+	nics, err := vm.NICs()
+	if err != nil {
+		return nil, err
+	}
+
+	graphics, err := vm.Graphics()
+	if err != nil {
+		return nil, err
+	}
+
+	// OS is optional, the VM may have no OS section or OS without architecture
+	operatingSystem, err := vm.OperatingSystem()
+	if _, ok := err.(*errors.XMLElementError); ok {
+		operatingSystem = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := vm.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := vm.HistoryRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	return &VirtualMachineInfo{
+		ID:              parsedInts[0],
+		Name:            parsedStrings[0],
+		User:            parsedInts[1],
+		UserName:        parsedStrings[1],
+		Group:           parsedInts[2],
+		GroupName:       parsedStrings[2],
+		Permissions:     permissions,
+		LastPoll:        times[0],
+		State:           VirtualMachineState(parsedInts[3]),
+		LCMState:        VirtualMachineLcmState(parsedInts[4]),
+		PrevState:       VirtualMachineState(parsedInts[5]),
+		PrevLCMState:    VirtualMachineLcmState(parsedInts[6]),
+		Reschedule:      intToBool(parsedInts[7]),
+		STime:           times[1],
+		ETime:           times[2],
+		DeployID:        parsedStrings[3],
+		CPU:             cpu,
+		VCPU:            intsWithoutError[0],
+		Memory:          parsedInts[8],
+		TemplateID:      intsWithoutError[1],
+		Disks:           disks,
+		NICs:            nics,
+		Graphics:        graphics,
+		OperatingSystem: operatingSystem,
+		Snapshots:       snapshots,
+		HistoryRecords:  history,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"encoding/json"
 	"net"
 	"time"
 
@@ -92,6 +93,34 @@ var _ = ginkgo.Describe("VirtualMachine", func() {
 				gomega.Expect(disks[1].ReadOnly).To(gomega.Equal(false))
 			})
 
+			ginkgo.It("should find VM Disk Snapshots", func() {
+				var disks []*Disk
+				disks, err = virtualMachine.Disks()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				snapshots := disks[0].Snapshots
+				gomega.Expect(snapshots).To(gomega.HaveLen(1))
+
+				root := snapshots[0]
+				gomega.Expect(root.ID).To(gomega.Equal(0))
+				gomega.Expect(root.Name).To(gomega.Equal("clean-install"))
+				gomega.Expect(root.Parent).To(gomega.Equal(-1))
+				gomega.Expect(root.Size).To(gomega.Equal(10240))
+				gomega.Expect(root.Active).To(gomega.Equal(false))
+
+				date := time.Unix(int64(1548709800), 0)
+				gomega.Expect(root.Date).To(gomega.Equal(&date))
+
+				gomega.Expect(root.Children).To(gomega.HaveLen(2))
+				gomega.Expect(root.Children[0].ID).To(gomega.Equal(1))
+				gomega.Expect(root.Children[0].Name).To(gomega.Equal("with-nginx"))
+				gomega.Expect(root.Children[0].Children).To(gomega.BeEmpty())
+				gomega.Expect(root.Children[1].ID).To(gomega.Equal(2))
+				gomega.Expect(root.Children[1].Active).To(gomega.Equal(true))
+
+				gomega.Expect(disks[1].Snapshots).To(gomega.BeEmpty())
+			})
+
 			ginkgo.It("should find VM Template Graphics attributes", func() {
 				var graphics *Graphics
 				graphics, err = virtualMachine.Graphics()
@@ -149,6 +178,21 @@ var _ = ginkgo.Describe("VirtualMachine", func() {
 				gomega.Expect(historyRecords[0].Action).To(gomega.Equal(Action(19)))
 			})
 
+			ginkgo.It("should find VM Template Snapshot attributes", func() {
+				var snapshots []*Snapshot
+				snapshots, err = virtualMachine.Snapshots()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(snapshots).To(gomega.HaveLen(2))
+				gomega.Expect(snapshots[0].ID).To(gomega.Equal(0))
+				gomega.Expect(snapshots[0].Name).To(gomega.Equal("before-upgrade"))
+				gomega.Expect(snapshots[0].HypervisorID).To(gomega.Equal("onesnap-0"))
+
+				snapshotTime := time.Unix(int64(1548709500), 0)
+				gomega.Expect(snapshots[0].Time).To(gomega.Equal(&snapshotTime))
+				gomega.Expect(snapshots[1].ID).To(gomega.Equal(1))
+				gomega.Expect(snapshots[1].Name).To(gomega.Equal("after-upgrade"))
+			})
+
 			ginkgo.It("should find other VM Template attributes", func() {
 				var template *etree.Element
 				template, err = virtualMachine.Template()
@@ -175,6 +219,71 @@ var _ = ginkgo.Describe("VirtualMachine", func() {
 				gomega.Expect(virtualMachine.VCPU()).To(gomega.Equal(1))
 			})
 
+			ginkgo.It("should decode all VM attributes", func() {
+				var info *VirtualMachineInfo
+				info, err = virtualMachine.Decode()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(info.ID).To(gomega.Equal(57502))
+				gomega.Expect(info.Name).To(gomega.Equal("METACLOUD"))
+				gomega.Expect(info.User).To(gomega.Equal(46))
+				gomega.Expect(info.UserName).To(gomega.Equal("someuser"))
+				gomega.Expect(info.Group).To(gomega.Equal(113))
+				gomega.Expect(info.GroupName).To(gomega.Equal("cloud-devel"))
+				gomega.Expect(info.Permissions.User.Manage).To(gomega.BeTrue())
+				gomega.Expect(info.LastPoll.Unix()).To(gomega.Equal(int64(1543406223)))
+				gomega.Expect(info.State).To(gomega.Equal(VirtualMachineStateActive))
+				gomega.Expect(info.LCMState).To(gomega.Equal(VirtualMachineRunning))
+				gomega.Expect(info.Reschedule).To(gomega.BeFalse())
+				gomega.Expect(info.STime.Unix()).To(gomega.Equal(int64(1540931164)))
+				gomega.Expect(info.ETime).To(gomega.BeNil())
+				gomega.Expect(info.DeployID).To(gomega.Equal("one-57502"))
+				gomega.Expect(info.CPU).To(gomega.Equal(0.25))
+				gomega.Expect(*info.VCPU).To(gomega.Equal(1))
+				gomega.Expect(info.Memory).To(gomega.Equal(2048))
+				gomega.Expect(*info.TemplateID).To(gomega.Equal(4572))
+				gomega.Expect(info.Disks).To(gomega.HaveLen(2))
+				gomega.Expect(info.NICs).To(gomega.HaveLen(1))
+				gomega.Expect(info.Graphics.Type).To(gomega.Equal(GraphicsTypeVNC))
+				gomega.Expect(info.OperatingSystem.Architecture).To(gomega.Equal(ArchitectureTypeX86_64))
+				gomega.Expect(info.Snapshots).To(gomega.HaveLen(2))
+				gomega.Expect(info.HistoryRecords).To(gomega.HaveLen(2))
+			})
+
+			ginkgo.It("should serialize decoded VM to JSON", func() {
+				var info *VirtualMachineInfo
+				info, err = virtualMachine.Decode()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var data []byte
+				data, err = json.Marshal(info)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var decoded VirtualMachineInfo
+				err = json.Unmarshal(data, &decoded)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(decoded.ID).To(gomega.Equal(57502))
+				gomega.Expect(decoded.Disks[0].DiskID).To(gomega.Equal(info.Disks[0].DiskID))
+				gomega.Expect(decoded.NICs[0].IP.Equal(info.NICs[0].IP)).To(gomega.BeTrue())
+				gomega.Expect(decoded.HistoryRecords[1].Seq).To(gomega.Equal(info.HistoryRecords[1].Seq))
+
+				gomega.Expect(decoded.Disks[0].Snapshots).To(gomega.HaveLen(1))
+				gomega.Expect(decoded.Disks[0].Snapshots[0].Name).To(gomega.Equal("clean-install"))
+				gomega.Expect(decoded.Disks[0].Snapshots[0].Date.Equal(*info.Disks[0].Snapshots[0].Date)).To(
+					gomega.BeTrue())
+				gomega.Expect(decoded.Disks[0].Snapshots[0].Children).To(gomega.HaveLen(2))
+				gomega.Expect(decoded.Disks[0].Snapshots[0].Children[1].Active).To(gomega.BeTrue())
+				gomega.Expect(decoded.Disks[1].Snapshots).To(gomega.BeEmpty())
+
+				var raw map[string]interface{}
+				err = json.Unmarshal(data, &raw)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(raw).To(gomega.HaveKey("lcm_state"))
+				gomega.Expect(raw).To(gomega.HaveKey("history_records"))
+				gomega.Expect(raw["graphics"]).NotTo(gomega.HaveKey("password"))
+				gomega.Expect(string(data)).NotTo(gomega.ContainSubstring(info.Graphics.Password))
+			})
+
 			ginkgo.Context("when virtualMachine has only ID", func() {
 				var virtualMachine *VirtualMachine
 
@@ -333,6 +442,19 @@ var _ = ginkgo.Describe("VirtualMachine", func() {
 						gomega.Expect(err).NotTo(gomega.HaveOccurred())
 						gomega.Expect(historyRecords).Should(gomega.HaveLen(0))
 					})
+
+					ginkgo.It("shouldn't decode virtualMachine", func() {
+						_, err = virtualMachine.Decode()
+						gomega.Expect(err).To(gomega.HaveOccurred())
+					})
+
+					ginkgo.It("should return that virtualMachine doesn't have snapshots", func() {
+						var snapshots []*Snapshot
+
+						snapshots, err = virtualMachine.Snapshots()
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+						gomega.Expect(snapshots).Should(gomega.HaveLen(0))
+					})
 				})
 			})
 		})
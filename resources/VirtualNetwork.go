@@ -15,16 +15,16 @@ type VirtualNetwork struct {
 
 // AddressRange structure represents Address Range in Virtual Network
 type AddressRange struct {
-	XMLName    xml.Name `xml:"AR,omitempty"`
-	ID         *int     `xml:"AR_ID,omitempty"`
-	IP         net.IP   `xml:"IP,omitempty"`
-	Mac        string   `xml:"MAC,omitempty"`
-	Size       *int     `xml:"SIZE,omitempty"`
-	Type       string   `xml:"TYPE,omitempty"`
-	MacEnd     string   `xml:"MAC_END,omitempty"`
-	IPEnd      net.IP   `xml:"IP_END,omitempty"`
-	UsedLeases *int     `xml:"USED_LEASES,omitempty"`
-	Leases     []*Lease `xml:"LEASES,omitempty"`
+	XMLName    xml.Name `xml:"AR,omitempty" json:"-"`
+	ID         *int     `xml:"AR_ID,omitempty" json:"id,omitempty"`
+	IP         net.IP   `xml:"IP,omitempty" json:"ip,omitempty"`
+	Mac        string   `xml:"MAC,omitempty" json:"mac"`
+	Size       *int     `xml:"SIZE,omitempty" json:"size,omitempty"`
+	Type       string   `xml:"TYPE,omitempty" json:"type"`
+	MacEnd     string   `xml:"MAC_END,omitempty" json:"mac_end"`
+	IPEnd      net.IP   `xml:"IP_END,omitempty" json:"ip_end,omitempty"`
+	UsedLeases *int     `xml:"USED_LEASES,omitempty" json:"used_leases,omitempty"`
+	Leases     []*Lease `xml:"LEASES,omitempty" json:"leases,omitempty"`
 }
 
 // Reservation structure to reserve network address in OpenNebula virtual network.
@@ -40,10 +40,10 @@ type Reservation struct {
 
 // Lease structure represents Lease in Address Range
 type Lease struct {
-	XMLName          xml.Name `xml:"LEASE,omitempty"`
-	IP               net.IP   `xml:"IP,omitempty"`
-	Mac              string   `xml:"MAC,omitempty"`
-	VirtualMachineID *int     `xml:"VM,omitempty"`
+	XMLName          xml.Name `xml:"LEASE,omitempty" json:"-"`
+	IP               net.IP   `xml:"IP,omitempty" json:"ip,omitempty"`
+	Mac              string   `xml:"MAC,omitempty" json:"mac"`
+	VirtualMachineID *int     `xml:"VM,omitempty" json:"virtual_machine_id,omitempty"`
 }
 
 // CreateVirtualNetworkWithID constructs VirtualNetwork with id
@@ -206,3 +206,81 @@ func createLeaseFromElement(element *etree.Element) (*Lease, error) {
 
 	return &Lease{IP: ip, Mac: mac, VirtualMachineID: &vm}, nil
 }
+
+// VirtualNetworkInfo structure contains information about a virtual network decoded from its XML data.
+// It doesn't depend on the XML data, so it can be cached or serialized (e.g. to JSON).
+type VirtualNetworkInfo struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	User            int             `json:"user"`
+	UserName        string          `json:"user_name"`
+	Group           int             `json:"group"`
+	GroupName       string          `json:"group_name"`
+	Permissions     *Permissions    `json:"permissions"`
+	Clusters        []int           `json:"clusters"`
+	Bridge          string          `json:"bridge"`
+	ParentNetworkID *int            `json:"parent_network_id,omitempty"`
+	VnMad           string          `json:"vn_mad"`
+	PhysicalDevice  string          `json:"physical_device"`
+	VirtualLanID    *int            `json:"virtual_lan_id,omitempty"`
+	UsedLeases      int             `json:"used_leases"`
+	VirtualRouters  []int           `json:"virtual_routers"`
+	AddressRanges   []*AddressRange `json:"address_ranges"`
+}
+
+// Decode decodes all the information about given virtual network at once.
+func (vn *VirtualNetwork) Decode() (*VirtualNetworkInfo, error) {
+	parsedInts, err := parseIntsFromElement(vn.XMLData, []string{"ID", "UID", "GID", "USED_LEASES"})
+	if err != nil {
+		return nil, err
+	}
+
+	parsedStrings, err := parseStringsFromElement(vn.XMLData, []string{"NAME", "UNAME", "GNAME", "BRIDGE",
+		"VN_MAD"})
+	if err != nil {
+		return nil, err
+	}
+
+	// parent network, physical device and VLAN ID may be empty (we can ignore error)
+	intsWithoutError := parseIntsFromElementWithoutError(vn.XMLData, []string{"PARENT_NETWORK_ID", "VLAN_ID"})
+	stringsWithoutError := parseStringsFromElementWithoutError(vn.XMLData, []string{"PHYDEV"})
+
+	permissions, err := vn.Permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := vn.Clusters()
+	if err != nil {
+		return nil, err
+	}
+
+	virtualRouters, err := vn.VirtualRouters()
+	if err != nil {
+		return nil, err
+	}
+
+	addressRanges, err := vn.AddressRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	return &VirtualNetworkInfo{
+		ID:              parsedInts[0],
+		Name:            parsedStrings[0],
+		User:            parsedInts[1],
+		UserName:        parsedStrings[1],
+		Group:           parsedInts[2],
+		GroupName:       parsedStrings[2],
+		Permissions:     permissions,
+		Clusters:        clusters,
+		Bridge:          parsedStrings[3],
+		ParentNetworkID: intsWithoutError[0],
+		VnMad:           parsedStrings[4],
+		PhysicalDevice:  stringsWithoutError[0],
+		VirtualLanID:    intsWithoutError[1],
+		UsedLeases:      parsedInts[3],
+		VirtualRouters:  virtualRouters,
+		AddressRanges:   addressRanges,
+	}, nil
+}
@@ -100,6 +100,26 @@ var _ = ginkgo.Describe("VirtualNetwork", func() {
 
 				gomega.Expect(objects).To(gomega.HaveLen(5))
 			})
+
+			ginkgo.It("should decode all virtual network attributes", func() {
+				var info *VirtualNetworkInfo
+				info, err = virtualNetwork.Decode()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				parentNetworkID := 11
+				virtualLanID := 22
+
+				gomega.Expect(info.ID).To(gomega.Equal(738))
+				gomega.Expect(info.Name).To(gomega.Equal("metacloud"))
+				gomega.Expect(info.GroupName).To(gomega.Equal("metacloud"))
+				gomega.Expect(info.Clusters).To(gomega.Equal([]int{112, 117, 118, 119}))
+				gomega.Expect(info.Bridge).To(gomega.Equal("onebr0"))
+				gomega.Expect(info.ParentNetworkID).To(gomega.Equal(&parentNetworkID))
+				gomega.Expect(info.PhysicalDevice).To(gomega.Equal("asdf"))
+				gomega.Expect(info.VirtualLanID).To(gomega.Equal(&virtualLanID))
+				gomega.Expect(info.UsedLeases).To(gomega.Equal(108))
+				gomega.Expect(info.AddressRanges).To(gomega.HaveLen(5))
+			})
 		})
 
 		ginkgo.Context("when virtualNetwork has only ID", func() {
@@ -117,6 +137,11 @@ var _ = ginkgo.Describe("VirtualNetwork", func() {
 					err = nil
 				})
 
+				ginkgo.It("shouldn't decode virtualNetwork", func() {
+					_, err = virtualNetwork.Decode()
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+
 				ginkgo.It("should return that virtualNetwork doesn't have name", func() {
 					_, err = virtualNetwork.Name()
 					gomega.Expect(err).To(gomega.HaveOccurred())
@@ -0,0 +1,99 @@
+package resources
+
+import (
+	"net"
+
+	"github.com/beevik/etree"
+)
+
+// VirtualRouter structure represents OpenNebula virtual router. Virtual router is a group of virtual machines
+// routing traffic between the virtual networks it is connected to.
+type VirtualRouter struct {
+	Resource
+}
+
+// VirtualRouterNIC represents network interface of virtual router. IP is set only for floating IP leased
+// by the virtual router itself, the virtual machines of the router get their own leases.
+type VirtualRouterNIC struct {
+	NicID      int    `json:"nic_id"`
+	Network    string `json:"network"`
+	NetworkID  int    `json:"network_id"`
+	IP         net.IP `json:"ip"`
+	FloatingIP bool   `json:"floating_ip"`
+	Management bool   `json:"management"`
+}
+
+// CreateVirtualRouterWithID constructs virtual router with given ID.
+func CreateVirtualRouterWithID(id int) *VirtualRouter {
+	return &VirtualRouter{*CreateResource("VROUTER", id)}
+}
+
+// CreateVirtualRouterFromXML constructs virtual router with full xml data.
+func CreateVirtualRouterFromXML(XMLdata *etree.Element) *VirtualRouter {
+	return &VirtualRouter{Resource: Resource{XMLData: XMLdata}}
+}
+
+// User gets user ID of given virtual router.
+func (vr *VirtualRouter) User() (int, error) {
+	return vr.intAttribute("UID")
+}
+
+// Group gets group ID of given virtual router.
+func (vr *VirtualRouter) Group() (int, error) {
+	return vr.intAttribute("GID")
+}
+
+// Permissions gets virtual router permissions.
+func (vr *VirtualRouter) Permissions() (*Permissions, error) {
+	return vr.permissions()
+}
+
+// Description gets description of given virtual router.
+func (vr *VirtualRouter) Description() (string, error) {
+	return vr.Attribute("TEMPLATE/DESCRIPTION")
+}
+
+// Lock gets lock of given virtual router, nil is returned when the virtual router is not locked.
+func (vr *VirtualRouter) Lock() (*Lock, error) {
+	return vr.lock()
+}
+
+// VirtualMachines gets array of IDs of virtual machines of given virtual router.
+func (vr *VirtualRouter) VirtualMachines() ([]int, error) {
+	return vr.arrayOfIDs("VMS")
+}
+
+// NICs gets an array of NICs of given virtual router.
+func (vr *VirtualRouter) NICs() ([]*VirtualRouterNIC, error) {
+	elements := vr.XMLData.FindElements("TEMPLATE/NIC")
+
+	nics := make([]*VirtualRouterNIC, len(elements))
+	var err error
+
+	for i, e := range elements {
+		nics[i], err = createVirtualRouterNICFromElement(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nics, nil
+}
+
+func createVirtualRouterNICFromElement(element *etree.Element) (*VirtualRouterNIC, error) {
+	parsedInts, err := parseIntsFromElement(element, []string{"NIC_ID", "NETWORK_ID"})
+	if err != nil {
+		return nil, err
+	}
+
+	parsedStrings := parseStringsFromElementWithoutError(element, []string{"NETWORK", "FLOATING_IP",
+		"VROUTER_MANAGEMENT"})
+
+	return &VirtualRouterNIC{
+		NicID:      parsedInts[0],
+		Network:    parsedStrings[0],
+		NetworkID:  parsedInts[1],
+		IP:         parseIPsFromElementWithoutError(element, []string{"IP"})[0],
+		FloatingIP: stringToBool(parsedStrings[1]),
+		Management: stringToBool(parsedStrings[2]),
+	}, nil
+}
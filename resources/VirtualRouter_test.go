@@ -0,0 +1,94 @@
+package resources
+
+import (
+	"net"
+
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	virtualRouterXML = "xml/virtualRouter.xml"
+)
+
+var _ = ginkgo.Describe("VirtualRouter", func() {
+	var (
+		doc           *etree.Document
+		virtualRouter *VirtualRouter
+		err           error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create virtual router with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(virtualRouterXML)
+			virtualRouter = CreateVirtualRouterFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all VirtualRouter attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(virtualRouter).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(virtualRouter.ID()).To(gomega.Equal(7))
+			gomega.Expect(virtualRouter.Name()).To(gomega.Equal("gateway"))
+			gomega.Expect(virtualRouter.User()).To(gomega.Equal(46))
+			gomega.Expect(virtualRouter.Group()).To(gomega.Equal(113))
+			gomega.Expect(virtualRouter.Description()).To(gomega.Equal("public gateway"))
+			gomega.Expect(virtualRouter.VirtualMachines()).To(gomega.Equal([]int{57612, 57613}))
+
+			var permissions *Permissions
+			permissions, err = virtualRouter.Permissions()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(permissions.User.Manage).To(gomega.Equal(true))
+			gomega.Expect(permissions.Group.Manage).To(gomega.Equal(false))
+
+			var lock *Lock
+			lock, err = virtualRouter.Lock()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(lock.Level).To(gomega.Equal(LockUse))
+		})
+
+		ginkgo.It("should find all VirtualRouter NICs", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var nics []*VirtualRouterNIC
+			nics, err = virtualRouter.NICs()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(nics).To(gomega.Equal([]*VirtualRouterNIC{
+				{NicID: 0, Network: "public", NetworkID: 64, IP: net.ParseIP("147.251.17.2"), FloatingIP: true},
+				{NicID: 1, Network: "private", NetworkID: 65, Management: true},
+			}))
+		})
+
+		ginkgo.It("should return an error for NIC without network", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			virtualRouter.XMLData.FindElement("TEMPLATE/NIC").RemoveChild(
+				virtualRouter.XMLData.FindElement("TEMPLATE/NIC/NETWORK_ID"))
+
+			_, err = virtualRouter.NICs()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("when virtual router has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			virtualRouter = CreateVirtualRouterWithID(42)
+		})
+
+		ginkgo.It("should create virtual router", func() {
+			gomega.Expect(virtualRouter.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that virtual router doesn't have virtual machines and NICs", func() {
+			gomega.Expect(virtualRouter.VirtualMachines()).To(gomega.HaveLen(0))
+
+			var nics []*VirtualRouterNIC
+			nics, err = virtualRouter.NICs()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(nics).To(gomega.HaveLen(0))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package resources
+
+import (
+	"github.com/beevik/etree"
+)
+
+// Zone structure represents OpenNebula zone. Every zone of a federation is managed by its own
+// OpenNebula frontend reachable on the endpoint of the zone.
+type Zone struct {
+	Resource
+}
+
+// ZoneServer represents one server of the zone, servers of a zone run in high availability mode.
+type ZoneServer struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+}
+
+// CreateZoneWithID constructs zone with given ID.
+func CreateZoneWithID(id int) *Zone {
+	return &Zone{*CreateResource("ZONE", id)}
+}
+
+// CreateZoneFromXML constructs zone with full xml data.
+func CreateZoneFromXML(XMLdata *etree.Element) *Zone {
+	return &Zone{Resource: Resource{XMLData: XMLdata}}
+}
+
+// Endpoint gets XML-RPC endpoint of given zone, e.g. http://one.example.com:2633/RPC2.
+func (z *Zone) Endpoint() (string, error) {
+	return z.Attribute("TEMPLATE/ENDPOINT")
+}
+
+// Servers gets an array of servers of given zone.
+func (z *Zone) Servers() ([]*ZoneServer, error) {
+	elements := z.XMLData.FindElements("SERVER_POOL/SERVER")
+
+	servers := make([]*ZoneServer, len(elements))
+	var err error
+
+	for i, e := range elements {
+		servers[i], err = createZoneServerFromElement(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return servers, nil
+}
+
+func createZoneServerFromElement(element *etree.Element) (*ZoneServer, error) {
+	id, err := intAttributeFromElement(element, "ID")
+	if err != nil {
+		return nil, err
+	}
+
+	parsedStrings := parseStringsFromElementWithoutError(element, []string{"NAME", "ENDPOINT"})
+
+	return &ZoneServer{
+		ID:       id,
+		Name:     parsedStrings[0],
+		Endpoint: parsedStrings[1],
+	}, nil
+}
@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"github.com/beevik/etree"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	zoneXML = "xml/zone.xml"
+)
+
+var _ = ginkgo.Describe("Zone", func() {
+	var (
+		doc  *etree.Document
+		zone *Zone
+		err  error
+	)
+
+	ginkgo.Describe("getters", func() {
+		ginkgo.BeforeEach(func() {
+			// create zone with data
+			doc = etree.NewDocument()
+			err = doc.ReadFromFile(zoneXML)
+			zone = CreateZoneFromXML(doc.Root())
+		})
+
+		ginkgo.It("should find all Zone attributes", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+			gomega.Expect(zone).ShouldNot(gomega.BeNil())
+
+			gomega.Expect(zone.ID()).To(gomega.Equal(101))
+			gomega.Expect(zone.Name()).To(gomega.Equal("brno"))
+			gomega.Expect(zone.Endpoint()).To(gomega.Equal("http://one-brno.example.com:2633/RPC2"))
+		})
+
+		ginkgo.It("should find all Zone servers", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var servers []*ZoneServer
+			servers, err = zone.Servers()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(servers).To(gomega.Equal([]*ZoneServer{
+				{ID: 0, Name: "one-brno-1", Endpoint: "http://10.0.0.11:2633/RPC2"},
+				{ID: 1, Name: "one-brno-2", Endpoint: "http://10.0.0.12:2633/RPC2"},
+			}))
+		})
+
+		ginkgo.It("should return an error for server without ID", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			server := zone.XMLData.FindElement("SERVER_POOL/SERVER")
+			server.RemoveChild(server.SelectElement("ID"))
+
+			_, err = zone.Servers()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("when zone has only ID", func() {
+		ginkgo.BeforeEach(func() {
+			zone = CreateZoneWithID(42)
+		})
+
+		ginkgo.It("should create zone", func() {
+			gomega.Expect(zone.ID()).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should return that zone doesn't have endpoint and servers", func() {
+			_, err = zone.Endpoint()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			var servers []*ZoneServer
+			servers, err = zone.Servers()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(servers).To(gomega.HaveLen(0))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+)
+
+// ACLService structure to manage OpenNebula ACL rules.
+type ACLService struct {
+	Service
+}
+
+// Add adds a new ACL rule. It returns the rule with ID assigned by OpenNebula.
+func (as *ACLService) Add(ctx context.Context, rule resources.ACLRule) (*resources.ACLRule, error) {
+	user, resource, rights, zone := rule.Encode()
+
+	args := []interface{}{user, resource, rights}
+	if zone != "" {
+		args = append(args, zone)
+	}
+
+	resArr, err := as.call(ctx, "one.acl.addrule", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.ID = int(resArr[resultIndex].ResultInt())
+
+	return &rule, nil
+}
+
+// Delete deletes the given ACL rule.
+func (as *ACLService) Delete(ctx context.Context, rule resources.ACLRule) error {
+	if rule.ID < 0 {
+		return errors.ErrACLRuleNoID
+	}
+
+	_, err := as.call(ctx, "one.acl.delrule", rule.ID)
+
+	return err
+}
+
+// List retrieves all the ACL rules.
+func (as *ACLService) List(ctx context.Context) ([]*resources.ACLRule, error) {
+	doc, err := as.list(ctx, "one.acl.info")
+	if err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("ACL_POOL/ACL")
+
+	rules := make([]*resources.ACLRule, len(elements))
+	for i, e := range elements {
+		if rules[i], err = aclRuleFromXML(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+func aclRuleFromXML(e *etree.Element) (*resources.ACLRule, error) {
+	var values [5]string
+	for i, tag := range []string{"ID", "USER", "RESOURCE", "RIGHTS", "ZONE"} {
+		element := e.SelectElement(tag)
+		if element == nil {
+			return nil, &errors.XMLElementError{Path: tag}
+		}
+		values[i] = element.Text()
+	}
+
+	id, err := strconv.Atoi(values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := resources.ParseACLRule(values[1], values[2], values[3], values[4])
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+
+	return rule, nil
+}
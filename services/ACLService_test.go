@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	aclAdd           = "records/onetest/acl/add"
+	aclAddMalformed  = "records/onetest/acl/addMalformed"
+	aclList          = "records/onetest/acl/list"
+	aclDelete        = "records/onetest/acl/delete"
+	aclDeleteUnknown = "records/onetest/acl/deleteUnknown"
+)
+
+var _ = ginkgo.Describe("ACL Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("add ACL rule", func() {
+		var rule *resources.ACLRule
+
+		ginkgo.Context("when rule is correct", func() {
+			ginkgo.BeforeEach(func() {
+				recName = aclAdd
+			})
+
+			ginkgo.It("should add the rule", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				rule, err = requests.ACLRuleBuilder().ForGroup(*resources.CreateGroupWithID(100)).
+					On(resources.VMKind, resources.NetworkKind).InCluster(*resources.CreateClusterWithID(0)).
+					Allow(requests.Use, requests.Manage).Build()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(rule.String()).To(gomega.Equal("@100 VM+NET/%0 USE+MANAGE"))
+
+				rule, err = client.ACLService.Add(context.TODO(), *rule)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(rule.ID).To(gomega.Equal(3))
+			})
+		})
+
+		ginkgo.Context("when rule has no rights", func() {
+			ginkgo.BeforeEach(func() {
+				recName = aclAddMalformed
+			})
+
+			ginkgo.It("should return that rule is malformed", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				rule, err = requests.ACLRuleBuilder().ForAll().On(resources.ImageKind).WithID(5).InAllZones().
+					Build()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				rule, err = client.ACLService.Add(context.TODO(), *rule)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(rule).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when group has no ID", func() {
+			ginkgo.It("should return that rule can't be built", func() {
+				rule, err = requests.ACLRuleBuilder().ForGroup(resources.Group{}).On(resources.VMKind).
+					AllowCreate().Build()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(rule).To(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("list ACL rules", func() {
+		ginkgo.BeforeEach(func() {
+			recName = aclList
+		})
+
+		ginkgo.It("should return all the rules", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var rules []*resources.ACLRule
+			rules, err = client.ACLService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(rules).To(gomega.HaveLen(4))
+
+			gomega.Expect(rules[1].String()).To(gomega.Equal("* ZONE/* USE *"))
+			gomega.Expect(rules[3].ID).To(gomega.Equal(3))
+			gomega.Expect(rules[3].User).To(gomega.Equal(resources.ACLScope{Type: resources.ACLScopeGroup, ID: 100}))
+			gomega.Expect(rules[3].Kinds).To(gomega.Equal(resources.VMKind | resources.NetworkKind))
+			gomega.Expect(rules[3].Resources).To(gomega.Equal(resources.ACLScope{Type: resources.ACLScopeCluster}))
+			gomega.Expect(rules[3].Rights).To(gomega.Equal(resources.ACLRightUse | resources.ACLRightManage))
+			gomega.Expect(rules[3].Zone).To(gomega.Equal(&resources.ACLScope{Type: resources.ACLScopeIndividual}))
+		})
+	})
+
+	ginkgo.Describe("delete ACL rule", func() {
+		ginkgo.Context("when rule exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = aclDelete
+			})
+
+			ginkgo.It("should delete the rule", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ACLService.Delete(context.TODO(), resources.ACLRule{ID: 3})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when rule doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = aclDeleteUnknown
+			})
+
+			ginkgo.It("should return that rule doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ACLService.Delete(context.TODO(), resources.ACLRule{ID: 3})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when rule has no ID", func() {
+			ginkgo.It("should return that rule has no ID", func() {
+				err = client.ACLService.Delete(context.TODO(), resources.ACLRule{ID: -1})
+				gomega.Expect(err).To(gomega.Equal(errors.ErrACLRuleNoID))
+			})
+		})
+	})
+})
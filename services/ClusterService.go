@@ -200,3 +200,32 @@ func (cs *ClusterService) List(ctx context.Context) ([]*resources.Cluster, error
 
 	return clusters, nil
 }
+
+// Capacity retrieves summary of capacity of the hosts in the cluster including reservations
+// of the cluster and current usage.
+func (cs *ClusterService) Capacity(ctx context.Context, cluster resources.Cluster) (*resources.ClusterCapacity,
+	error) {
+	clusterID, err := cluster.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := cs.RetrieveInfo(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := cs.list(ctx, "one.hostpool.info")
+	if err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("HOST_POOL/HOST")
+
+	hosts := make([]*resources.Host, len(elements))
+	for i, e := range elements {
+		hosts[i] = resources.CreateHostFromXML(e)
+	}
+
+	return resources.ClusterCapacitySummary(info, hosts)
+}
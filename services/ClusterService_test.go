@@ -53,6 +53,9 @@ const (
 	clusterRetrieveInfo = "records/cluster/retrieveInfo"
 
 	clusterList = "records/cluster/list"
+
+	clusterCapacity        = "records/onetest/cluster/capacity"
+	clusterCapacityUnknown = "records/onetest/cluster/capacityUnknown"
 )
 
 var _ = ginkgo.Describe("Cluster Service", func() {
@@ -1048,4 +1051,40 @@ var _ = ginkgo.Describe("Cluster Service", func() {
 			gomega.Expect(clusters).ShouldNot(gomega.BeNil())
 		})
 	})
+
+	ginkgo.Describe("cluster capacity", func() {
+		var capacity *resources.ClusterCapacity
+
+		ginkgo.Context("when cluster exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = clusterCapacity
+			})
+
+			ginkgo.It("should return summary of capacity of the hosts in cluster", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				capacity, err = client.ClusterService.Capacity(context.TODO(), *resources.CreateClusterWithID(150))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(capacity).To(gomega.Equal(&resources.ClusterCapacity{ClusterID: 150, Hosts: 2,
+					TotalCPU: 1600, ReservedCPU: 200, AllocatedCPU: 300, UsedCPU: 220, AvailableCPU: 1100,
+					TotalMemory: 33554432, ReservedMemory: 2097152, AllocatedMemory: 3145728, UsedMemory: 3670016,
+					AvailableMemory: 28311552}))
+			})
+		})
+
+		ginkgo.Context("when cluster doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = clusterCapacityUnknown
+			})
+
+			ginkgo.It("should return an error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				capacity, err = client.ClusterService.Capacity(context.TODO(),
+					*resources.CreateClusterWithID(nonExistingClusterID))
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(capacity).To(gomega.BeNil())
+			})
+		})
+	})
 })
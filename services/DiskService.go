@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/resources"
+)
+
+// DiskService structure to manage disks and disk snapshots of a virtual machine.
+type DiskService struct {
+	Service
+}
+
+// Attach attaches a new disk to the virtual machine.
+func (ds *DiskService) Attach(ctx context.Context, vm resources.VirtualMachine,
+	disk blueprint.DiskBlueprint) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	diskText, err := disk.Render()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.vm.attach", vmID, "<TEMPLATE>"+diskText+"</TEMPLATE>")
+
+	return err
+}
+
+// Detach detaches a disk from a virtual machine.
+func (ds *DiskService) Detach(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.vm.detach", vmID, disk.DiskID)
+
+	return err
+}
+
+// Resize changes size of a disk of a virtual machine. New size (in MB) has to be greater than the current one.
+func (ds *DiskService) Resize(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk,
+	size int) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.vm.diskresize", vmID, disk.DiskID, strconv.Itoa(size))
+
+	return err
+}
+
+// SaveAs saves a disk of a virtual machine as a new image with given name and type.
+// Snapshot ID -1 saves the current state of the disk, otherwise the given disk snapshot is saved.
+func (ds *DiskService) SaveAs(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk,
+	imageName string, imageType resources.ImageType, snapshotID int) (*resources.Image, error) {
+	vmID, err := vm.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ds.call(ctx, "one.vm.disksaveas", vmID, disk.DiskID, imageName,
+		resources.ImageTypeMap[imageType], snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	is := &ImageService{Service: ds.Service}
+
+	return is.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// CreateSnapshot creates a new snapshot of a disk of a virtual machine.
+// Returned snapshot contains only ID and name, the rest of the snapshot
+// attributes can be found in VM info (see resources.Disk.Snapshots).
+func (ds *DiskService) CreateSnapshot(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk,
+	name string) (*resources.DiskSnapshot, error) {
+	vmID, err := vm.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ds.call(ctx, "one.vm.disksnapshotcreate", vmID, disk.DiskID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resources.DiskSnapshot{ID: int(resArr[resultIndex].ResultInt()), Name: name}, nil
+}
+
+// DeleteSnapshot deletes a snapshot of a disk of a virtual machine.
+func (ds *DiskService) DeleteSnapshot(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk,
+	snapshot resources.DiskSnapshot) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.vm.disksnapshotdelete", vmID, disk.DiskID, snapshot.ID)
+
+	return err
+}
+
+// RevertSnapshot reverts a disk of a virtual machine to a snapshot.
+func (ds *DiskService) RevertSnapshot(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk,
+	snapshot resources.DiskSnapshot) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.vm.disksnapshotrevert", vmID, disk.DiskID, snapshot.ID)
+
+	return err
+}
+
+// RenameSnapshot renames a snapshot of a disk of a virtual machine.
+func (ds *DiskService) RenameSnapshot(ctx context.Context, vm resources.VirtualMachine, disk resources.Disk,
+	snapshot resources.DiskSnapshot, name string) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.vm.disksnapshotrename", vmID, disk.DiskID, snapshot.ID, name)
+
+	return err
+}
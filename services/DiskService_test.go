@@ -0,0 +1,732 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	virtualMachineDiskAttach               = "records/onetest/virtualMachine/disk/attach"
+	virtualMachineDiskAttachWrongID        = "records/onetest/virtualMachine/disk/attachWrongID"
+	virtualMachineDiskAttachWrongBlueprint = "records/onetest/virtualMachine/disk/attachWrongBlueprint"
+
+	virtualMachineDiskResize          = "records/onetest/virtualMachine/disk/resize"
+	virtualMachineDiskResizeSmaller   = "records/onetest/virtualMachine/disk/resizeSmaller"
+	virtualMachineDiskResizeWrongID   = "records/onetest/virtualMachine/disk/resizeWrongID"
+	virtualMachineDiskResizeWrongDisk = "records/onetest/virtualMachine/disk/resizeWrongDisk"
+
+	virtualMachineDiskSaveAs         = "records/onetest/virtualMachine/disk/saveAs"
+	virtualMachineDiskSaveAsExisting = "records/onetest/virtualMachine/disk/saveAsExisting"
+	virtualMachineDiskSaveAsWrongID  = "records/onetest/virtualMachine/disk/saveAsWrongID"
+
+	virtualMachineDiskDetach          = "records/onetest/virtualMachine/disk/detach"
+	virtualMachineDiskDetachWrongID   = "records/onetest/virtualMachine/disk/detachWrongID"
+	virtualMachineDiskDetachWrongDisk = "records/onetest/virtualMachine/disk/detachWrongDisk"
+
+	virtualMachineDiskSnapshotCreate        = "records/onetest/virtualMachine/disk/snapshotCreate"
+	virtualMachineDiskSnapshotCreateWrongID = "records/onetest/virtualMachine/disk/snapshotCreateWrongID"
+
+	virtualMachineDiskSnapshotRename        = "records/onetest/virtualMachine/disk/snapshotRename"
+	virtualMachineDiskSnapshotRenameEmpty   = "records/onetest/virtualMachine/disk/snapshotRenameEmpty"
+	virtualMachineDiskSnapshotRenameWrongID = "records/onetest/virtualMachine/disk/snapshotRenameWrongID"
+	virtualMachineDiskSnapshotRevert        = "records/onetest/virtualMachine/disk/snapshotRevert"
+	virtualMachineDiskSnapshotRevertWrongID = "records/onetest/virtualMachine/disk/snapshotRevertWrongID"
+	virtualMachineDiskSnapshotRevertUnknown = "records/onetest/virtualMachine/disk/snapshotRevertUnknown"
+	virtualMachineDiskSnapshotDelete        = "records/onetest/virtualMachine/disk/snapshotDelete"
+	virtualMachineDiskSnapshotDeleteActive  = "records/onetest/virtualMachine/disk/snapshotDeleteActive"
+	virtualMachineDiskSnapshotDeleteWrongID = "records/onetest/virtualMachine/disk/snapshotDeleteWrongID"
+)
+
+var _ = ginkgo.Describe("Disk Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingVirtualMachineID = 140
+	var nonExistingVirtualMachineID = 1000
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("attach disk", func() {
+		var (
+			virtualMachine    *resources.VirtualMachine
+			oneVirtualMachine *resources.VirtualMachine
+			diskBlueprint     *blueprint.DiskBlueprint
+		)
+
+		ginkgo.Context("when virtual machine exists and disk is correct", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineDiskAttach
+
+				virtualMachine = resources.CreateVirtualMachineWithID(existingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+
+				diskBlueprint = blueprint.CreateDiskBlueprint()
+				diskBlueprint.SetImageID(21)
+			})
+
+			ginkgo.It("should attach disk to a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Attach(context.TODO(), *virtualMachine, *diskBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether disk was really attached in OpenNebula
+				oneVirtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(),
+					existingVirtualMachineID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var disks []*resources.Disk
+				disks, err = oneVirtualMachine.Disks()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(disks).To(gomega.HaveLen(2))
+				gomega.Expect(disks[1].DiskID).To(gomega.Equal(1))
+				gomega.Expect(disks[1].ImageID).To(gomega.Equal(21))
+			})
+		})
+
+		ginkgo.Context("when virtual machine doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineDiskAttachWrongID
+
+				virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+
+				diskBlueprint = blueprint.CreateDiskBlueprint()
+				diskBlueprint.SetImageID(21)
+			})
+
+			ginkgo.It("shouldn't attach disk to a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Attach(context.TODO(), *virtualMachine, *diskBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual machine exists but disk is not correct", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineDiskAttachWrongBlueprint
+
+				virtualMachine = resources.CreateVirtualMachineWithID(existingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("shouldn't attach disk to a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Attach(context.TODO(), *virtualMachine, *blueprint.CreateDiskBlueprint())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual machine is empty", func() {
+			ginkgo.It("shouldn't attach disk to a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Attach(context.TODO(), resources.VirtualMachine{},
+					*blueprint.CreateDiskBlueprint())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't attach disk to a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Attach(context.TODO(),
+					*resources.CreateVirtualMachineWithID(existingVirtualMachineID), blueprint.DiskBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("resize disk", func() {
+		var (
+			virtualMachine    *resources.VirtualMachine
+			oneVirtualMachine *resources.VirtualMachine
+		)
+
+		ginkgo.Context("when virtual machine exists", func() {
+			ginkgo.BeforeEach(func() {
+				virtualMachine = resources.CreateVirtualMachineWithID(existingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.When("when new size is greater than current one", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskResize
+				})
+
+				ginkgo.It("should resize disk of a given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.Resize(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0}, 4096)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether disk was really resized in OpenNebula
+					oneVirtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(),
+						existingVirtualMachineID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					var disks []*resources.Disk
+					disks, err = oneVirtualMachine.Disks()
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(disks[0].Size).To(gomega.Equal(4096))
+				})
+			})
+
+			ginkgo.When("when new size is smaller than current one", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskResizeSmaller
+				})
+
+				ginkgo.It("shouldn't resize disk of a given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.Resize(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0}, 1024)
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.When("when disk doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskResizeWrongDisk
+				})
+
+				ginkgo.It("shouldn't resize disk of a given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.Resize(context.TODO(), *virtualMachine, resources.Disk{DiskID: 7}, 8192)
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when virtual machine doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineDiskResizeWrongID
+
+				virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("shouldn't resize disk of a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Resize(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0}, 4096)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual machine is empty", func() {
+			ginkgo.It("shouldn't resize disk of a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Resize(context.TODO(), resources.VirtualMachine{}, resources.Disk{}, 4096)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("save disk as image", func() {
+		var (
+			virtualMachine *resources.VirtualMachine
+			image          *resources.Image
+		)
+
+		ginkgo.Context("when virtual machine exists", func() {
+			ginkgo.BeforeEach(func() {
+				virtualMachine = resources.CreateVirtualMachineWithID(existingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.When("when image name is not taken", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSaveAs
+				})
+
+				ginkgo.It("should save disk as a new image", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					image, err = client.DiskService.SaveAs(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0},
+						"debian-9-backup", resources.ImageTypeOs, -1)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(image).ShouldNot(gomega.BeNil())
+					gomega.Expect(image.ID()).To(gomega.Equal(22))
+					gomega.Expect(image.Name()).To(gomega.Equal("debian-9-backup"))
+					gomega.Expect(image.Type()).To(gomega.Equal(resources.ImageTypeOs))
+				})
+			})
+
+			ginkgo.When("when image name is taken", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSaveAsExisting
+				})
+
+				ginkgo.It("shouldn't save disk as a new image", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					image, err = client.DiskService.SaveAs(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0},
+						"debian-9", resources.ImageTypeOs, -1)
+					gomega.Expect(err).To(gomega.HaveOccurred())
+					gomega.Expect(image).Should(gomega.BeNil())
+				})
+			})
+		})
+
+		ginkgo.Context("when virtual machine doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineDiskSaveAsWrongID
+
+				virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("shouldn't save disk as a new image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.DiskService.SaveAs(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0},
+					"backup", resources.ImageTypeOs, -1)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual machine is empty", func() {
+			ginkgo.It("shouldn't save disk as a new image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.DiskService.SaveAs(context.TODO(), resources.VirtualMachine{}, resources.Disk{},
+					"backup", resources.ImageTypeOs, -1)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("detach disk", func() {
+		var (
+			virtualMachine    *resources.VirtualMachine
+			oneVirtualMachine *resources.VirtualMachine
+		)
+
+		ginkgo.Context("when virtual machine exists", func() {
+			ginkgo.BeforeEach(func() {
+				virtualMachine = resources.CreateVirtualMachineWithID(existingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.When("when virtual machine has given disk", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskDetach
+				})
+
+				ginkgo.It("should detach disk from a given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.Detach(context.TODO(), *virtualMachine, resources.Disk{DiskID: 1})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether disk was really detached in OpenNebula
+					oneVirtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(),
+						existingVirtualMachineID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					var disks []*resources.Disk
+					disks, err = oneVirtualMachine.Disks()
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(disks).To(gomega.HaveLen(1))
+					gomega.Expect(disks[0].DiskID).To(gomega.Equal(0))
+				})
+			})
+
+			ginkgo.When("when virtual machine doesn't have given disk", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskDetachWrongDisk
+				})
+
+				ginkgo.It("shouldn't detach disk from a given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.Detach(context.TODO(), *virtualMachine, resources.Disk{DiskID: 7})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when virtual machine doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineDiskDetachWrongID
+
+				virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("shouldn't detach disk from a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Detach(context.TODO(), *virtualMachine, resources.Disk{DiskID: 0})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual machine is empty", func() {
+			ginkgo.It("shouldn't detach disk from a given virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.DiskService.Detach(context.TODO(), resources.VirtualMachine{}, resources.Disk{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("disk snapshots", func() {
+		var (
+			virtualMachine    *resources.VirtualMachine
+			oneVirtualMachine *resources.VirtualMachine
+			disk              resources.Disk
+			snapshot          *resources.DiskSnapshot
+			snapshots         []*resources.DiskSnapshot
+		)
+
+		var snapshotVirtualMachineID = 141
+
+		retrieveSnapshots := func() ([]*resources.DiskSnapshot, error) {
+			oneVirtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(),
+				snapshotVirtualMachineID)
+			if err != nil {
+				return nil, err
+			}
+
+			var disks []*resources.Disk
+			disks, err = oneVirtualMachine.Disks()
+			if err != nil {
+				return nil, err
+			}
+
+			return disks[0].Snapshots, nil
+		}
+
+		ginkgo.BeforeEach(func() {
+			virtualMachine = resources.CreateVirtualMachineWithID(snapshotVirtualMachineID)
+			if virtualMachine == nil {
+				err = errors.ErrNoVirtualMachine
+			}
+
+			disk = resources.Disk{DiskID: 0}
+		})
+
+		ginkgo.Describe("create disk snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSnapshotCreate
+				})
+
+				ginkgo.It("should create a new disk snapshot on top of the active one", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					snapshot, err = client.DiskService.CreateSnapshot(context.TODO(), *virtualMachine, disk,
+						"with-nginx")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(snapshot).ShouldNot(gomega.BeNil())
+					gomega.Expect(snapshot.ID).To(gomega.Equal(1))
+					gomega.Expect(snapshot.Name).To(gomega.Equal("with-nginx"))
+
+					// check whether snapshot was really created in OpenNebula
+					snapshots, err = retrieveSnapshots()
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(snapshots).To(gomega.HaveLen(1))
+					gomega.Expect(snapshots[0].ID).To(gomega.Equal(0))
+					gomega.Expect(snapshots[0].Active).To(gomega.Equal(false))
+					gomega.Expect(snapshots[0].Children).To(gomega.HaveLen(1))
+					gomega.Expect(snapshots[0].Children[0].ID).To(gomega.Equal(1))
+					gomega.Expect(snapshots[0].Children[0].Parent).To(gomega.Equal(0))
+					gomega.Expect(snapshots[0].Children[0].Active).To(gomega.Equal(true))
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSnapshotCreateWrongID
+
+					virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				})
+
+				ginkgo.It("shouldn't create a new disk snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					snapshot, err = client.DiskService.CreateSnapshot(context.TODO(), *virtualMachine, disk,
+						"with-nginx")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+					gomega.Expect(snapshot).Should(gomega.BeNil())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("shouldn't create a new disk snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					snapshot, err = client.DiskService.CreateSnapshot(context.TODO(), resources.VirtualMachine{},
+						disk, "with-nginx")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+					gomega.Expect(snapshot).Should(gomega.BeNil())
+				})
+			})
+		})
+
+		ginkgo.Describe("rename disk snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.When("when new name is not empty", func() {
+					ginkgo.BeforeEach(func() {
+						recName = virtualMachineDiskSnapshotRename
+					})
+
+					ginkgo.It("should rename given disk snapshot", func() {
+						gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+						err = client.DiskService.RenameSnapshot(context.TODO(), *virtualMachine, disk,
+							resources.DiskSnapshot{ID: 1}, "nginx")
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+						// check whether snapshot was really renamed in OpenNebula
+						snapshots, err = retrieveSnapshots()
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+						gomega.Expect(snapshots[0].Children[0].Name).To(gomega.Equal("nginx"))
+					})
+				})
+
+				ginkgo.When("when new name is empty", func() {
+					ginkgo.BeforeEach(func() {
+						recName = virtualMachineDiskSnapshotRenameEmpty
+					})
+
+					ginkgo.It("shouldn't rename given disk snapshot", func() {
+						gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+						err = client.DiskService.RenameSnapshot(context.TODO(), *virtualMachine, disk,
+							resources.DiskSnapshot{ID: 1}, "")
+						gomega.Expect(err).To(gomega.HaveOccurred())
+					})
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSnapshotRenameWrongID
+
+					virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				})
+
+				ginkgo.It("shouldn't rename given disk snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.RenameSnapshot(context.TODO(), *virtualMachine, disk,
+						resources.DiskSnapshot{ID: 1}, "nginx")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("shouldn't rename given disk snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.RenameSnapshot(context.TODO(), resources.VirtualMachine{}, disk,
+						resources.DiskSnapshot{ID: 1}, "nginx")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Describe("revert disk snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.When("when disk snapshot exists", func() {
+					ginkgo.BeforeEach(func() {
+						recName = virtualMachineDiskSnapshotRevert
+					})
+
+					ginkgo.It("should revert disk to given snapshot", func() {
+						gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+						err = client.DiskService.RevertSnapshot(context.TODO(), *virtualMachine, disk,
+							resources.DiskSnapshot{ID: 0})
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+						// check whether snapshot is really active in OpenNebula
+						snapshots, err = retrieveSnapshots()
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+						gomega.Expect(snapshots[0].Active).To(gomega.Equal(true))
+						gomega.Expect(snapshots[0].Children[0].Active).To(gomega.Equal(false))
+					})
+				})
+
+				ginkgo.When("when disk snapshot doesn't exist", func() {
+					ginkgo.BeforeEach(func() {
+						recName = virtualMachineDiskSnapshotRevertUnknown
+					})
+
+					ginkgo.It("shouldn't revert disk to given snapshot", func() {
+						gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+						err = client.DiskService.RevertSnapshot(context.TODO(), *virtualMachine, disk,
+							resources.DiskSnapshot{ID: 42})
+						gomega.Expect(err).To(gomega.HaveOccurred())
+					})
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSnapshotRevertWrongID
+
+					virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				})
+
+				ginkgo.It("shouldn't revert disk to given snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.RevertSnapshot(context.TODO(), *virtualMachine, disk,
+						resources.DiskSnapshot{ID: 0})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("shouldn't revert disk to given snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.RevertSnapshot(context.TODO(), resources.VirtualMachine{}, disk,
+						resources.DiskSnapshot{ID: 0})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Describe("delete disk snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.When("when disk snapshot is not active", func() {
+					ginkgo.BeforeEach(func() {
+						recName = virtualMachineDiskSnapshotDelete
+					})
+
+					ginkgo.It("should delete given disk snapshot", func() {
+						gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+						err = client.DiskService.DeleteSnapshot(context.TODO(), *virtualMachine, disk,
+							resources.DiskSnapshot{ID: 1})
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+						// check whether snapshot was really deleted in OpenNebula
+						snapshots, err = retrieveSnapshots()
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+						gomega.Expect(snapshots).To(gomega.HaveLen(1))
+						gomega.Expect(snapshots[0].Children).To(gomega.BeEmpty())
+					})
+				})
+
+				ginkgo.When("when disk snapshot is active", func() {
+					ginkgo.BeforeEach(func() {
+						recName = virtualMachineDiskSnapshotDeleteActive
+					})
+
+					ginkgo.It("shouldn't delete given disk snapshot", func() {
+						gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+						err = client.DiskService.DeleteSnapshot(context.TODO(), *virtualMachine, disk,
+							resources.DiskSnapshot{ID: 0})
+						gomega.Expect(err).To(gomega.HaveOccurred())
+					})
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDiskSnapshotDeleteWrongID
+
+					virtualMachine = resources.CreateVirtualMachineWithID(nonExistingVirtualMachineID)
+				})
+
+				ginkgo.It("shouldn't delete given disk snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.DeleteSnapshot(context.TODO(), *virtualMachine, disk,
+						resources.DiskSnapshot{ID: 1})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("shouldn't delete given disk snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.DiskService.DeleteSnapshot(context.TODO(), resources.VirtualMachine{}, disk,
+						resources.DiskSnapshot{ID: 1})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+	})
+})
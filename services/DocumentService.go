@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+)
+
+// DocumentService structure to manage OpenNebula documents of one type. Documents of the other types
+// are not allocated nor listed by the service.
+type DocumentService struct {
+	Service
+	DocumentType int
+}
+
+// Allocate allocates a new document in OpenNebula.
+func (ds *DocumentService) Allocate(ctx context.Context, blueprint blueprint.Interface) (*resources.Document, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ds.call(ctx, "one.document.allocate", blueprintText, ds.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return ds.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// AllocateObject allocates a new document with given name and JSON encoding of given object as its body.
+func (ds *DocumentService) AllocateObject(ctx context.Context, name string,
+	object interface{}) (*resources.Document, error) {
+	documentBlueprint := blueprint.CreateAllocateDocumentBlueprint()
+	documentBlueprint.SetName(name)
+	if err := documentBlueprint.MarshalBody(object); err != nil {
+		return nil, err
+	}
+
+	return ds.Allocate(ctx, documentBlueprint)
+}
+
+// Clone clones an existing document.
+func (ds *DocumentService) Clone(ctx context.Context, document resources.Document,
+	name string) (*resources.Document, error) {
+	documentID, err := document.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ds.call(ctx, "one.document.clone", documentID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ds.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Delete deletes the given document from the pool.
+func (ds *DocumentService) Delete(ctx context.Context, document resources.Document) error {
+	documentID, err := document.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.document.delete", documentID)
+
+	return err
+}
+
+// Update merges or replaces the document template contents.
+func (ds *DocumentService) Update(ctx context.Context, document resources.Document,
+	blueprint blueprint.Interface, updateType UpdateType) (*resources.Document, error) {
+	documentID, err := document.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ds.call(ctx, "one.document.update", documentID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return ds.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// UpdateObject replaces body of the document with JSON encoding of given object, the rest of the document
+// template is kept.
+func (ds *DocumentService) UpdateObject(ctx context.Context, document resources.Document,
+	object interface{}) (*resources.Document, error) {
+	documentBlueprint := blueprint.CreateUpdateDocumentBlueprint()
+	if err := documentBlueprint.MarshalBody(object); err != nil {
+		return nil, err
+	}
+
+	return ds.Update(ctx, document, documentBlueprint, Merge)
+}
+
+// Chmod changes the permission bits of a document.
+func (ds *DocumentService) Chmod(ctx context.Context, document resources.Document,
+	request requests.PermissionRequest) error {
+	documentID, err := document.ID()
+	if err != nil {
+		return err
+	}
+
+	return ds.chmod(ctx, "one.document.chmod", documentID, request)
+}
+
+// Chown changes the ownership of a document.
+func (ds *DocumentService) Chown(ctx context.Context, document resources.Document,
+	request requests.OwnershipRequest) error {
+	documentID, err := document.ID()
+	if err != nil {
+		return err
+	}
+
+	return ds.chown(ctx, "one.document.chown", documentID, request)
+}
+
+// Rename renames a document.
+func (ds *DocumentService) Rename(ctx context.Context, document resources.Document, name string) error {
+	documentID, err := document.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.call(ctx, "one.document.rename", documentID, name)
+
+	return err
+}
+
+// Lock locks a document, actions of given level and the levels above are blocked.
+func (ds *DocumentService) Lock(ctx context.Context, document resources.Document,
+	level resources.LockLevel) error {
+	documentID, err := document.ID()
+	if err != nil {
+		return err
+	}
+
+	return ds.lock(ctx, "one.document.lock", documentID, level)
+}
+
+// Unlock unlocks a document.
+func (ds *DocumentService) Unlock(ctx context.Context, document resources.Document) error {
+	documentID, err := document.ID()
+	if err != nil {
+		return err
+	}
+
+	return ds.unlock(ctx, "one.document.unlock", documentID)
+}
+
+// RetrieveInfo retrieves information for the document.
+func (ds *DocumentService) RetrieveInfo(ctx context.Context, documentID int) (*resources.Document, error) {
+	doc, err := ds.retrieveInfo(ctx, "one.document.info", documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateDocumentFromXML(doc.Root()), nil
+}
+
+// RetrieveObject retrieves information for the document and decodes its JSON body to the value pointed
+// to by object.
+func (ds *DocumentService) RetrieveObject(ctx context.Context, documentID int,
+	object interface{}) (*resources.Document, error) {
+	document, err := ds.RetrieveInfo(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = document.UnmarshalBody(object); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (ds *DocumentService) list(ctx context.Context, filterFlag, pageOffset,
+	pageSize int) ([]*resources.Document, error) {
+	resArr, err := ds.call(ctx, "one.documentpool.info", filterFlag, pageOffset, pageSize, ds.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("DOCUMENT_POOL/DOCUMENT")
+
+	documents := make([]*resources.Document, len(elements))
+	for i, e := range elements {
+		documents[i] = resources.CreateDocumentFromXML(e)
+	}
+
+	return documents, nil
+}
+
+// ListAll retrieves information for all the documents in the pool.
+func (ds *DocumentService) ListAll(ctx context.Context, filter OwnershipFilter) ([]*resources.Document, error) {
+	return ds.list(ctx, int(filter), pageOffsetDefault, pageSizeDefault)
+}
+
+// ListAllForUser retrieves information for all the documents for the given user in the pool.
+func (ds *DocumentService) ListAllForUser(ctx context.Context, user resources.User) ([]*resources.Document, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return ds.list(ctx, userID, pageOffsetDefault, pageSizeDefault)
+}
+
+// List retrieves information for a part of the documents in the pool with a given pagination.
+func (ds *DocumentService) List(ctx context.Context, pageOffset, pageSize int,
+	filter OwnershipFilter) ([]*resources.Document, error) {
+	return ds.list(ctx, int(filter), (pageOffset-1)*pageSize, -pageSize)
+}
+
+// ListForUser retrieves information for a part of the documents for given user in the pool
+// with a given pagination.
+func (ds *DocumentService) ListForUser(ctx context.Context, user resources.User, pageOffset,
+	pageSize int) ([]*resources.Document, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return ds.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
+}
@@ -0,0 +1,783 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	documentAllocate       = "records/onetest/document/allocate"
+	documentAllocateNoName = "records/onetest/document/allocateNoName"
+
+	documentClone        = "records/onetest/document/clone"
+	documentCloneUnknown = "records/onetest/document/cloneUnknown"
+
+	documentDelete        = "records/onetest/document/delete"
+	documentDeleteWrongID = "records/onetest/document/deleteWrongID"
+
+	documentUpdateObject  = "records/onetest/document/updateObject"
+	documentUpdateUnknown = "records/onetest/document/updateUnknown"
+
+	documentChmod        = "records/onetest/document/chmod"
+	documentChmodUnknown = "records/onetest/document/chmodUnknown"
+
+	documentChown        = "records/onetest/document/chown"
+	documentChownUnknown = "records/onetest/document/chownUnknown"
+
+	documentRename        = "records/onetest/document/rename"
+	documentRenameEmpty   = "records/onetest/document/renameEmpty"
+	documentRenameUnknown = "records/onetest/document/renameUnknown"
+
+	documentLock        = "records/onetest/document/lock"
+	documentLockUnknown = "records/onetest/document/lockUnknown"
+
+	documentRetrieveInfo        = "records/onetest/document/retrieveInfo"
+	documentRetrieveInfoUnknown = "records/onetest/document/retrieveInfoUnknown"
+	documentRetrieveObject      = "records/onetest/document/retrieveObject"
+
+	documentListAllAll       = "records/onetest/document/listAllAll"
+	documentListAllOtherType = "records/onetest/document/listAllOtherType"
+	documentListAllForUser   = "records/onetest/document/listAllForUser"
+	documentListPagination   = "records/onetest/document/listPagination"
+)
+
+// appDocument is the custom object stored in the documents of the tests.
+type appDocument struct {
+	Owner    string `json:"owner"`
+	Replicas int    `json:"replicas"`
+	VMs      []int  `json:"vms"`
+}
+
+var _ = ginkgo.Describe("Document Service", func() {
+	var (
+		recName         string
+		rec             *recorder.Recorder
+		client          *onego.Client
+		documentService *services.DocumentService
+		err             error
+	)
+
+	var documentType = 1000
+
+	var existingDocumentID = 0
+	var deletedDocumentID = 1
+	var nonExistingDocumentID = 420
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+
+		documentService = client.DocumentService(documentType)
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate document", func() {
+		var document *resources.Document
+
+		ginkgo.Context("when object is given", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentAllocate
+			})
+
+			ginkgo.It("should create new document with the object as its body", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.AllocateObject(context.TODO(), "api",
+					appDocument{Owner: "api-team", Replicas: 2, VMs: []int{300, 301}})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(document).ShouldNot(gomega.BeNil())
+				gomega.Expect(document.ID()).To(gomega.Equal(4))
+				gomega.Expect(document.Name()).To(gomega.Equal("api"))
+				gomega.Expect(document.Type()).To(gomega.Equal(documentType))
+
+				var app appDocument
+				err = document.UnmarshalBody(&app)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(app).To(gomega.Equal(appDocument{Owner: "api-team", Replicas: 2, VMs: []int{300, 301}}))
+			})
+		})
+
+		ginkgo.Context("when name is missing", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentAllocateNoName
+			})
+
+			ginkgo.It("shouldn't create new document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				documentBlueprint := blueprint.CreateAllocateDocumentBlueprint()
+				documentBlueprint.SetBody("{}")
+
+				document, err = documentService.Allocate(context.TODO(), documentBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(document).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when object cannot be encoded", func() {
+			ginkgo.It("shouldn't create new document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.AllocateObject(context.TODO(), "channel", make(chan int))
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(document).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.Allocate(context.TODO(), &blueprint.DocumentBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(document).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("clone document", func() {
+		var document *resources.Document
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentClone
+			})
+
+			ginkgo.It("should create copy of the document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.Clone(context.TODO(), *resources.CreateDocumentWithID(existingDocumentID),
+					"web-app-copy")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(document).ShouldNot(gomega.BeNil())
+				gomega.Expect(document.ID()).To(gomega.Equal(5))
+				gomega.Expect(document.Name()).To(gomega.Equal("web-app-copy"))
+				gomega.Expect(document.Type()).To(gomega.Equal(documentType))
+
+				var app appDocument
+				err = document.UnmarshalBody(&app)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(app.Owner).To(gomega.Equal("web-team"))
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentCloneUnknown
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.Clone(context.TODO(),
+					*resources.CreateDocumentWithID(nonExistingDocumentID), "unknown-copy")
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(document).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when document has no ID", func() {
+			ginkgo.It("should return that document has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.Clone(context.TODO(), resources.Document{}, "copy")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(document).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete document", func() {
+		var (
+			document    *resources.Document
+			oneDocument *resources.Document
+		)
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentDelete
+
+				document = resources.CreateDocumentWithID(deletedDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should delete document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Delete(context.TODO(), *document)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether document was really deleted in OpenNebula
+				oneDocument, err = documentService.RetrieveInfo(context.TODO(), deletedDocumentID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneDocument).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentDeleteWrongID
+
+				document = resources.CreateDocumentWithID(nonExistingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Delete(context.TODO(), *document)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when document has no ID", func() {
+			ginkgo.It("should return that document has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Delete(context.TODO(), resources.Document{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update document", func() {
+		var (
+			document    *resources.Document
+			retDocument *resources.Document
+		)
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentUpdateObject
+
+				document = resources.CreateDocumentWithID(existingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should replace body of given document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retDocument, err = documentService.UpdateObject(context.TODO(), *document,
+					appDocument{Owner: "web-team", Replicas: 4, VMs: []int{200, 201, 202, 203}})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retDocument).ShouldNot(gomega.BeNil())
+				gomega.Expect(retDocument.Attribute("TEMPLATE/NAME")).To(gomega.Equal("web-app"))
+
+				var app appDocument
+				err = retDocument.UnmarshalBody(&app)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(app.Replicas).To(gomega.Equal(4))
+				gomega.Expect(app.VMs).To(gomega.HaveLen(4))
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentUpdateUnknown
+
+				document = resources.CreateDocumentWithID(nonExistingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				documentBlueprint := blueprint.CreateUpdateDocumentBlueprint()
+				if documentBlueprint == nil {
+					err = errors.ErrNoDocumentBlueprint
+				}
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				documentBlueprint.SetBody("{}")
+
+				retDocument, err = documentService.Update(context.TODO(), *document, documentBlueprint,
+					services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retDocument).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retDocument, err = documentService.Update(context.TODO(),
+					*resources.CreateDocumentWithID(existingDocumentID), &blueprint.DocumentBlueprint{},
+					services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retDocument).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("document chmod", func() {
+		var (
+			document    *resources.Document
+			oneDocument *resources.Document
+			permRequest requests.PermissionRequest
+		)
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentChmod
+
+				document = resources.CreateDocumentWithID(existingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should change permission of given document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.Group,
+					requests.Use).Allow(requests.Group, requests.Manage).Build()
+
+				err = documentService.Chmod(context.TODO(), *document, permRequest)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chmod was really changed in OpenNebula
+				oneDocument, err = documentService.RetrieveInfo(context.TODO(), existingDocumentID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneDocument).ShouldNot(gomega.BeNil())
+
+				var perm *resources.Permissions
+				perm, err = oneDocument.Permissions()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(perm.Group.Use).To(gomega.Equal(true))
+				gomega.Expect(perm.Group.Manage).To(gomega.Equal(true))
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentChmodUnknown
+
+				document = resources.CreateDocumentWithID(nonExistingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.User,
+					requests.Manage).Build()
+
+				err = documentService.Chmod(context.TODO(), *document, permRequest)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when document has no ID", func() {
+			ginkgo.It("should return that document has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Chmod(context.TODO(), resources.Document{}, requests.PermissionRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("document chown", func() {
+		var (
+			document     *resources.Document
+			oneDocument  *resources.Document
+			ownershipReq requests.OwnershipRequest
+		)
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentChown
+
+				document = resources.CreateDocumentWithID(existingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should change owner of given document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				userID := 80
+				groupID := 180
+
+				ownershipReq = requests.CreateOwnershipRequestBuilder().User(*resources.CreateUserWithID(userID)).
+					Group(*resources.CreateGroupWithID(groupID)).Build()
+
+				err = documentService.Chown(context.TODO(), *document, ownershipReq)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chown was really changed in OpenNebula
+				oneDocument, err = documentService.RetrieveInfo(context.TODO(), existingDocumentID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneDocument).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(oneDocument.User()).To(gomega.Equal(userID))
+				gomega.Expect(oneDocument.Group()).To(gomega.Equal(groupID))
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentChownUnknown
+
+				document = resources.CreateDocumentWithID(nonExistingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Chown(context.TODO(), *document, requests.CreateOwnershipRequestBuilder().Build())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when document has no ID", func() {
+			ginkgo.It("should return that document has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Chown(context.TODO(), resources.Document{}, requests.OwnershipRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("document rename", func() {
+		var (
+			document    *resources.Document
+			oneDocument *resources.Document
+		)
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				document = resources.CreateDocumentWithID(existingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = documentRename
+				})
+
+				ginkgo.It("should change name of given document", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = documentService.Rename(context.TODO(), *document, "web")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneDocument, err = documentService.RetrieveInfo(context.TODO(), existingDocumentID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneDocument).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneDocument.Name()).To(gomega.Equal("web"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = documentRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given document", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = documentService.Rename(context.TODO(), *document, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentRenameUnknown
+
+				document = resources.CreateDocumentWithID(nonExistingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Rename(context.TODO(), *document, "document")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when document has no ID", func() {
+			ginkgo.It("should return that document has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Rename(context.TODO(), resources.Document{}, "document")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("document lock", func() {
+		var (
+			document    *resources.Document
+			oneDocument *resources.Document
+		)
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentLock
+
+				document = resources.CreateDocumentWithID(existingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should lock and unlock given document", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Lock(context.TODO(), *document, resources.LockManage)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether document was really locked in OpenNebula
+				oneDocument, err = documentService.RetrieveInfo(context.TODO(), existingDocumentID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var lock *resources.Lock
+				lock, err = oneDocument.Lock()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lock).ShouldNot(gomega.BeNil())
+				gomega.Expect(lock.Level).To(gomega.Equal(resources.LockManage))
+
+				_, err = documentService.UpdateObject(context.TODO(), *document, appDocument{Owner: "nobody"})
+				gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+
+				err = documentService.Unlock(context.TODO(), *document)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				oneDocument, err = documentService.RetrieveInfo(context.TODO(), existingDocumentID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneDocument.Lock()).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentLockUnknown
+
+				document = resources.CreateDocumentWithID(nonExistingDocumentID)
+				if document == nil {
+					err = errors.ErrNoDocument
+				}
+			})
+
+			ginkgo.It("should return that document with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Lock(context.TODO(), *document, resources.LockUse)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when document has no ID", func() {
+			ginkgo.It("should return that document has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = documentService.Unlock(context.TODO(), resources.Document{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("document retrieve info", func() {
+		var document *resources.Document
+
+		ginkgo.Context("when document exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentRetrieveInfo
+			})
+
+			ginkgo.It("should return document with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.RetrieveInfo(context.TODO(), existingDocumentID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(document).ShouldNot(gomega.BeNil())
+				gomega.Expect(document.ID()).To(gomega.Equal(existingDocumentID))
+				gomega.Expect(document.Name()).To(gomega.Equal("web"))
+				gomega.Expect(document.Type()).To(gomega.Equal(documentType))
+			})
+		})
+
+		ginkgo.Context("when document doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given document doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				document, err = documentService.RetrieveInfo(context.TODO(), nonExistingDocumentID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(document).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("document retrieve object", func() {
+		var (
+			document *resources.Document
+			app      appDocument
+		)
+
+		ginkgo.BeforeEach(func() {
+			recName = documentRetrieveObject
+		})
+
+		ginkgo.It("should decode body of given document to the object", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			document, err = documentService.RetrieveObject(context.TODO(), existingDocumentID, &app)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(document).ShouldNot(gomega.BeNil())
+			gomega.Expect(app).To(gomega.Equal(appDocument{Owner: "web-team", Replicas: 4,
+				VMs: []int{200, 201, 202, 203}}))
+		})
+	})
+
+	ginkgo.Describe("document list all", func() {
+		var documents []*resources.Document
+
+		ginkgo.Context("when documents of the type exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentListAllAll
+			})
+
+			ginkgo.It("should return list of all documents of the type with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				documents, err = documentService.ListAll(context.TODO(), services.OwnershipFilterAll)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(documents).To(gomega.HaveLen(4))
+				gomega.Expect(documents[0].Name()).To(gomega.Equal("web"))
+				gomega.Expect(documents[3].Name()).To(gomega.Equal("web-app-copy"))
+			})
+		})
+
+		ginkgo.Context("when service manages other type", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentListAllOtherType
+			})
+
+			ginkgo.It("should return only documents of the other type", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				documents, err = client.DocumentService(resources.DocumentTypeService).ListAll(context.TODO(),
+					services.OwnershipFilterAll)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(documents).To(gomega.HaveLen(1))
+				gomega.Expect(documents[0].Name()).To(gomega.Equal("flow-service"))
+			})
+		})
+	})
+
+	ginkgo.Describe("document list all for user", func() {
+		var documents []*resources.Document
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = documentListAllForUser
+			})
+
+			ginkgo.It("should return documents with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				documents, err = documentService.ListAllForUser(context.TODO(), *resources.CreateUserWithID(80))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(documents).To(gomega.HaveLen(1))
+				gomega.Expect(documents[0].ID()).To(gomega.Equal(existingDocumentID))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				documents, err = documentService.ListAllForUser(context.TODO(), resources.User{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(documents).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("document list with pagination", func() {
+		var documents []*resources.Document
+
+		ginkgo.BeforeEach(func() {
+			recName = documentListPagination
+		})
+
+		ginkgo.It("should return documents with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			documents, err = documentService.List(context.TODO(), 2, 2, services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(documents).To(gomega.HaveLen(2))
+			gomega.Expect(documents[0].ID()).To(gomega.Equal(4))
+			gomega.Expect(documents[1].ID()).To(gomega.Equal(5))
+		})
+	})
+})
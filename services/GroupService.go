@@ -111,3 +111,35 @@ func (gs *GroupService) AddAdmin(ctx context.Context, group resources.Group, adm
 func (gs *GroupService) RemoveAdmin(ctx context.Context, group resources.Group, admin resources.User) error {
 	return gs.manageAdmin(ctx, "one.group.deladmin", group, admin)
 }
+
+// SetQuota sets quota limits of the given group.
+func (gs *GroupService) SetQuota(ctx context.Context, group resources.Group,
+	blueprint blueprint.Interface) (*resources.Group, error) {
+	groupID, err := group.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := gs.call(ctx, "one.group.quota", groupID, blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return gs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// DefaultQuotas retrieves the default quotas applied to all the groups.
+func (gs *GroupService) DefaultQuotas(ctx context.Context) (*resources.Quotas, error) {
+	return gs.defaultQuotas(ctx, "one.groupquota.info")
+}
+
+// SetDefaultQuotas sets the default quotas applied to all the groups.
+func (gs *GroupService) SetDefaultQuotas(ctx context.Context,
+	blueprint blueprint.Interface) (*resources.Quotas, error) {
+	return gs.updateDefaultQuotas(ctx, "one.groupquota.update", blueprint)
+}
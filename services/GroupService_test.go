@@ -22,6 +22,7 @@ import (
 const (
 	existingGroupID    = 120
 	nonExistingGroupID = 158
+	quotaGroupID       = 130
 )
 
 const (
@@ -40,6 +41,11 @@ const (
 
 	groupList = "records/group/list"
 
+	groupSetQuota         = "records/onetest/group/setQuota"
+	groupSetQuotaUnknown  = "records/onetest/group/setQuotaUnknown"
+	groupDefaultQuotas    = "records/onetest/group/defaultQuotas"
+	groupSetDefaultQuotas = "records/onetest/group/setDefaultQuotas"
+
 	groupAddAdmin        = "records/group/addAdmin"
 	groupAddAdminUnknown = "records/group/addAdminUnknown"
 	groupAddAdminFail    = "records/group/addAdminFail"
@@ -469,4 +475,92 @@ var _ = ginkgo.Describe("Group", func() {
 			})
 		})
 	})
+
+	ginkgo.Describe("set group quota", func() {
+		var (
+			group *resources.Group
+			qb    *blueprint.QuotaBlueprint
+		)
+
+		ginkgo.BeforeEach(func() {
+			qb = blueprint.CreateQuotaBlueprint()
+			qb.SetRunningVMs(2)
+			qb.AddImageQuota(0, 4)
+		})
+
+		ginkgo.Context("when group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = groupSetQuota
+			})
+
+			ginkgo.It("should set quota limits and return group with usage", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				group, err = client.GroupService.SetQuota(context.TODO(), *resources.CreateGroupWithID(quotaGroupID), qb)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(group).ShouldNot(gomega.BeNil())
+
+				var quotas *resources.Quotas
+				quotas, err = group.Quotas()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas.VM.RunningVMs).To(gomega.Equal(2))
+				gomega.Expect(quotas.VM.RunningVMsUsed).To(gomega.Equal(1))
+				gomega.Expect(quotas.Images).To(gomega.Equal([]*resources.ImageQuota{{ID: 0, RunningVMs: 4}}))
+			})
+		})
+
+		ginkgo.Context("when group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = groupSetQuotaUnknown
+			})
+
+			ginkgo.It("should return an error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				group, err = client.GroupService.SetQuota(context.TODO(),
+					*resources.CreateGroupWithID(nonExistingGroupID), qb)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(group).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("default group quotas", func() {
+		var quotas *resources.Quotas
+
+		ginkgo.Context("when retrieving default quotas", func() {
+			ginkgo.BeforeEach(func() {
+				recName = groupDefaultQuotas
+			})
+
+			ginkgo.It("should return empty default quotas", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				quotas, err = client.GroupService.DefaultQuotas(context.TODO())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas).ShouldNot(gomega.BeNil())
+				gomega.Expect(quotas.VM).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when setting default quotas", func() {
+			ginkgo.BeforeEach(func() {
+				recName = groupSetDefaultQuotas
+			})
+
+			ginkgo.It("should return updated default quotas", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				qb := blueprint.CreateQuotaBlueprint()
+				qb.SetVMs(50)
+				qb.AddDatastoreQuota(1, resources.QuotaUnlimited, 102400)
+
+				quotas, err = client.GroupService.SetDefaultQuotas(context.TODO(), qb)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas.VM.VMs).To(gomega.Equal(50))
+				gomega.Expect(quotas.Datastores).To(gomega.Equal([]*resources.DatastoreQuota{{ID: 1,
+					Images: resources.QuotaUnlimited, Size: 102400}}))
+			})
+		})
+	})
 })
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/resources"
+)
+
+// HookService structure to manage OpenNebula hooks.
+type HookService struct {
+	Service
+}
+
+// HookExecutionFilter - hook executions with result
+type HookExecutionFilter int
+
+const (
+	// HookExecutionFilterError - executions which failed
+	HookExecutionFilterError HookExecutionFilter = iota - 1
+	// HookExecutionFilterAll - all executions
+	HookExecutionFilterAll
+	// HookExecutionFilterSuccess - executions which succeeded
+	HookExecutionFilterSuccess
+)
+
+// allHooks stands for all hooks in one.hooklog.info
+const allHooks = -1
+
+// Allocate allocates a new hook in OpenNebula.
+func (hs *HookService) Allocate(ctx context.Context, blueprint blueprint.Interface) (*resources.Hook, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := hs.call(ctx, "one.hook.allocate", blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return hs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Delete deletes the given hook from the pool.
+func (hs *HookService) Delete(ctx context.Context, hook resources.Hook) error {
+	hookID, err := hook.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = hs.call(ctx, "one.hook.delete", hookID)
+
+	return err
+}
+
+// Update merges or replaces the hook template contents.
+func (hs *HookService) Update(ctx context.Context, hook resources.Hook, blueprint blueprint.Interface,
+	updateType UpdateType) (*resources.Hook, error) {
+	hookID, err := hook.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := hs.call(ctx, "one.hook.update", hookID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return hs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Rename renames a hook.
+func (hs *HookService) Rename(ctx context.Context, hook resources.Hook, name string) error {
+	hookID, err := hook.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = hs.call(ctx, "one.hook.rename", hookID, name)
+
+	return err
+}
+
+// Retry runs the command of the hook again with the arguments of given execution. The result is stored
+// as a new execution record.
+func (hs *HookService) Retry(ctx context.Context, hook resources.Hook, executionID int) error {
+	hookID, err := hook.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = hs.call(ctx, "one.hook.retry", hookID, executionID)
+
+	return err
+}
+
+// RetrieveInfo retrieves information for the hook including its last execution records.
+func (hs *HookService) RetrieveInfo(ctx context.Context, hookID int) (*resources.Hook, error) {
+	doc, err := hs.retrieveInfo(ctx, "one.hook.info", hookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateHookFromXML(doc.Root()), nil
+}
+
+func (hs *HookService) list(ctx context.Context, pageOffset, pageSize int) ([]*resources.Hook, error) {
+	resArr, err := hs.call(ctx, "one.hookpool.info", int(OwnershipFilterAll), pageOffset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("HOOK_POOL/HOOK")
+
+	hooks := make([]*resources.Hook, len(elements))
+	for i, e := range elements {
+		hooks[i] = resources.CreateHookFromXML(e)
+	}
+
+	return hooks, nil
+}
+
+// ListAll retrieves information for all the hooks in the pool.
+func (hs *HookService) ListAll(ctx context.Context) ([]*resources.Hook, error) {
+	return hs.list(ctx, pageOffsetDefault, pageSizeDefault)
+}
+
+// List retrieves information for a part of the hooks in the pool with a given pagination.
+func (hs *HookService) List(ctx context.Context, pageOffset, pageSize int) ([]*resources.Hook, error) {
+	return hs.list(ctx, (pageOffset-1)*pageSize, -pageSize)
+}
+
+func (hs *HookService) log(ctx context.Context, hookID int, start, end time.Time,
+	filter HookExecutionFilter) ([]*resources.HookExecutionRecord, error) {
+	resArr, err := hs.call(ctx, "one.hooklog.info", unixTimeOrNone(start), unixTimeOrNone(end), hookID,
+		int(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("HOOKLOG/HOOK_EXECUTION_RECORD")
+
+	records := make([]*resources.HookExecutionRecord, len(elements))
+	for i, e := range elements {
+		if records[i], err = resources.ParseHookExecutionRecord(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// Log retrieves execution records of all the hooks which were executed in the time range from start to end
+// and whose result matches the filter. Zero start or end means no limit.
+func (hs *HookService) Log(ctx context.Context, start, end time.Time,
+	filter HookExecutionFilter) ([]*resources.HookExecutionRecord, error) {
+	return hs.log(ctx, allHooks, start, end, filter)
+}
+
+// LogForHook retrieves execution records of the given hook which were executed in the time range from start
+// to end and whose result matches the filter. Zero start or end means no limit.
+func (hs *HookService) LogForHook(ctx context.Context, hook resources.Hook, start, end time.Time,
+	filter HookExecutionFilter) ([]*resources.HookExecutionRecord, error) {
+	hookID, err := hook.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return hs.log(ctx, hookID, start, end, filter)
+}
@@ -0,0 +1,602 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	hookAllocateAPI       = "records/onetest/hook/allocateAPI"
+	hookAllocateState     = "records/onetest/hook/allocateState"
+	hookAllocateNoCommand = "records/onetest/hook/allocateNoCommand"
+
+	hookDelete        = "records/onetest/hook/delete"
+	hookDeleteWrongID = "records/onetest/hook/deleteWrongID"
+
+	hookUpdateMerge   = "records/onetest/hook/updateMerge"
+	hookUpdateUnknown = "records/onetest/hook/updateUnknown"
+
+	hookRename        = "records/onetest/hook/rename"
+	hookRenameEmpty   = "records/onetest/hook/renameEmpty"
+	hookRenameUnknown = "records/onetest/hook/renameUnknown"
+
+	hookRetrieveInfo        = "records/onetest/hook/retrieveInfo"
+	hookRetrieveInfoUnknown = "records/onetest/hook/retrieveInfoUnknown"
+
+	hookRetry                 = "records/onetest/hook/retry"
+	hookRetryUnknownExecution = "records/onetest/hook/retryUnknownExecution"
+
+	hookListAll        = "records/onetest/hook/listAll"
+	hookListPagination = "records/onetest/hook/listPagination"
+
+	hookLogAll          = "records/onetest/hook/logAll"
+	hookLogSince        = "records/onetest/hook/logSince"
+	hookLogForHookError = "records/onetest/hook/logForHookError"
+)
+
+var _ = ginkgo.Describe("Hook Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingHookID = 0
+	var stateHookID = 1
+	var deletedHookID = 2
+	var nonExistingHookID = 420
+
+	var now = time.Unix(1792249200, 0)
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate hook", func() {
+		var (
+			hook          *resources.Hook
+			hookBlueprint *blueprint.HookBlueprint
+		)
+
+		ginkgo.Context("when hook is API hook", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookAllocateAPI
+
+				hookBlueprint = blueprint.CreateAllocateHookBlueprint()
+				hookBlueprint.SetName("notify-user")
+				hookBlueprint.SetCommand("notify.sh")
+				hookBlueprint.SetArguments("$API")
+				hookBlueprint.SetAPIHook(resources.APIHook{Call: "one.user.allocate"})
+			})
+
+			ginkgo.It("should create new API hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hook, err = client.HookService.Allocate(context.TODO(), hookBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(hook).ShouldNot(gomega.BeNil())
+				gomega.Expect(hook.ID()).To(gomega.Equal(3))
+				gomega.Expect(hook.Name()).To(gomega.Equal("notify-user"))
+				gomega.Expect(hook.Type()).To(gomega.Equal(resources.HookTypeAPI))
+				gomega.Expect(hook.Command()).To(gomega.Equal("notify.sh"))
+				gomega.Expect(hook.Arguments()).To(gomega.Equal("$API"))
+				gomega.Expect(hook.APIHook()).To(gomega.Equal(&resources.APIHook{Call: "one.user.allocate"}))
+			})
+		})
+
+		ginkgo.Context("when hook is state hook", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookAllocateState
+
+				hookBlueprint = blueprint.CreateAllocateHookBlueprint()
+				hookBlueprint.SetName("vm-done")
+				hookBlueprint.SetCommand("cleanup.sh")
+				hookBlueprint.SetRemote(true)
+				hookBlueprint.SetStateHook(resources.StateHook{Resource: "VM", State: "DONE",
+					LCMState: "LCM_INIT"})
+			})
+
+			ginkgo.It("should create new state hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hook, err = client.HookService.Allocate(context.TODO(), hookBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(hook).ShouldNot(gomega.BeNil())
+				gomega.Expect(hook.ID()).To(gomega.Equal(4))
+				gomega.Expect(hook.Type()).To(gomega.Equal(resources.HookTypeState))
+				gomega.Expect(hook.Remote()).To(gomega.BeTrue())
+				gomega.Expect(hook.StateHook()).To(gomega.Equal(&resources.StateHook{Resource: "VM",
+					State: "DONE", LCMState: "LCM_INIT"}))
+			})
+		})
+
+		ginkgo.Context("when command is missing", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookAllocateNoCommand
+
+				hookBlueprint = blueprint.CreateAllocateHookBlueprint()
+				hookBlueprint.SetName("no-command")
+				hookBlueprint.SetAPIHook(resources.APIHook{Call: "one.vm.allocate"})
+			})
+
+			ginkgo.It("shouldn't create new hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hook, err = client.HookService.Allocate(context.TODO(), hookBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(hook).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hook, err = client.HookService.Allocate(context.TODO(), &blueprint.HookBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(hook).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete hook", func() {
+		var (
+			hook    *resources.Hook
+			oneHook *resources.Hook
+		)
+
+		ginkgo.Context("when hook exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookDelete
+
+				hook = resources.CreateHookWithID(deletedHookID)
+				if hook == nil {
+					err = errors.ErrNoHook
+				}
+			})
+
+			ginkgo.It("should delete hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Delete(context.TODO(), *hook)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether hook was really deleted in OpenNebula
+				oneHook, err = client.HookService.RetrieveInfo(context.TODO(), deletedHookID)
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(oneHook).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when hook doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookDeleteWrongID
+
+				hook = resources.CreateHookWithID(nonExistingHookID)
+				if hook == nil {
+					err = errors.ErrNoHook
+				}
+			})
+
+			ginkgo.It("should return that hook with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Delete(context.TODO(), *hook)
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+			})
+		})
+
+		ginkgo.Context("when hook has no ID", func() {
+			ginkgo.It("should return that hook has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Delete(context.TODO(), resources.Hook{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update hook", func() {
+		var (
+			hook    *resources.Hook
+			retHook *resources.Hook
+		)
+
+		ginkgo.Context("when hook exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookUpdateMerge
+
+				hook = resources.CreateHookWithID(existingHookID)
+				if hook == nil {
+					err = errors.ErrNoHook
+				}
+			})
+
+			ginkgo.It("should merge template of given hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hookBlueprint := blueprint.CreateUpdateHookBlueprint()
+				if hookBlueprint == nil {
+					err = errors.ErrNoHookBlueprint
+				}
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				hookBlueprint.SetArguments("$API --verbose")
+
+				retHook, err = client.HookService.Update(context.TODO(), *hook, hookBlueprint, services.Merge)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retHook).ShouldNot(gomega.BeNil())
+				gomega.Expect(retHook.Arguments()).To(gomega.Equal("$API --verbose"))
+				gomega.Expect(retHook.APIHook()).To(gomega.Equal(&resources.APIHook{Call: "one.zone.info"}))
+			})
+		})
+
+		ginkgo.Context("when hook doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookUpdateUnknown
+
+				hook = resources.CreateHookWithID(nonExistingHookID)
+				if hook == nil {
+					err = errors.ErrNoHook
+				}
+			})
+
+			ginkgo.It("should return that hook with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hookBlueprint := blueprint.CreateUpdateHookBlueprint()
+				hookBlueprint.SetArguments("$API")
+
+				retHook, err = client.HookService.Update(context.TODO(), *hook, hookBlueprint, services.Merge)
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(retHook).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when hook has no ID", func() {
+			ginkgo.It("should return that hook has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retHook, err = client.HookService.Update(context.TODO(), resources.Hook{},
+					blueprint.CreateUpdateHookBlueprint(), services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retHook).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("hook rename", func() {
+		var (
+			hook    *resources.Hook
+			oneHook *resources.Hook
+		)
+
+		ginkgo.Context("when hook exists", func() {
+			ginkgo.BeforeEach(func() {
+				hook = resources.CreateHookWithID(existingHookID)
+				if hook == nil {
+					err = errors.ErrNoHook
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = hookRename
+				})
+
+				ginkgo.It("should change name of given hook", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.HookService.Rename(context.TODO(), *hook, "raft-status")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneHook, err = client.HookService.RetrieveInfo(context.TODO(), existingHookID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneHook).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneHook.Name()).To(gomega.Equal("raft-status"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = hookRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given hook", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.HookService.Rename(context.TODO(), *hook, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when hook doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookRenameUnknown
+
+				hook = resources.CreateHookWithID(nonExistingHookID)
+				if hook == nil {
+					err = errors.ErrNoHook
+				}
+			})
+
+			ginkgo.It("should return that hook with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Rename(context.TODO(), *hook, "hook")
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+			})
+		})
+
+		ginkgo.Context("when hook has no ID", func() {
+			ginkgo.It("should return that hook has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Rename(context.TODO(), resources.Hook{}, "hook")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("hook retrieve info", func() {
+		var hook *resources.Hook
+
+		ginkgo.Context("when hook exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookRetrieveInfo
+			})
+
+			ginkgo.It("should return hook with its execution records", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hook, err = client.HookService.RetrieveInfo(context.TODO(), existingHookID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(hook).ShouldNot(gomega.BeNil())
+				gomega.Expect(hook.ID()).To(gomega.Equal(existingHookID))
+				gomega.Expect(hook.Command()).To(gomega.Equal("raft.sh"))
+
+				var records []*resources.HookExecutionRecord
+				records, err = hook.ExecutionRecords()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(2))
+
+				gomega.Expect(records[0].ExecutionID).To(gomega.Equal(0))
+				gomega.Expect(records[0].Timestamp).To(gomega.Equal(now.Add(-2 * time.Hour)))
+				gomega.Expect(records[0].Success()).To(gomega.BeTrue())
+				gomega.Expect(records[0].Stdout).To(gomega.Equal("leader: one-1"))
+
+				gomega.Expect(records[1].ExecutionID).To(gomega.Equal(1))
+				gomega.Expect(records[1].Timestamp).To(gomega.Equal(now.Add(-time.Hour)))
+				gomega.Expect(records[1].ExitCode).To(gomega.Equal(1))
+				gomega.Expect(records[1].Stderr).To(gomega.Equal("timeout"))
+				gomega.Expect(records[1].Command).To(gomega.Equal("raft.sh"))
+				gomega.Expect(records[1].Retry).To(gomega.BeFalse())
+			})
+		})
+
+		ginkgo.Context("when hook doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given hook doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hook, err = client.HookService.RetrieveInfo(context.TODO(), nonExistingHookID)
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(hook).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("hook retry", func() {
+		var (
+			hook    *resources.Hook
+			oneHook *resources.Hook
+		)
+
+		ginkgo.BeforeEach(func() {
+			hook = resources.CreateHookWithID(existingHookID)
+			if hook == nil {
+				err = errors.ErrNoHook
+			}
+		})
+
+		ginkgo.Context("when execution exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookRetry
+			})
+
+			ginkgo.It("should run the hook again", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Retry(context.TODO(), *hook, 1)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether hook was really executed in OpenNebula
+				oneHook, err = client.HookService.RetrieveInfo(context.TODO(), existingHookID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var records []*resources.HookExecutionRecord
+				records, err = oneHook.ExecutionRecords()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(3))
+				gomega.Expect(records[2].ExecutionID).To(gomega.Equal(2))
+				gomega.Expect(records[2].Success()).To(gomega.BeTrue())
+				gomega.Expect(records[2].Retry).To(gomega.BeTrue())
+				gomega.Expect(records[2].Arguments).To(gomega.Equal(records[1].Arguments))
+			})
+		})
+
+		ginkgo.Context("when execution doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookRetryUnknownExecution
+			})
+
+			ginkgo.It("should return that execution doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Retry(context.TODO(), *hook, 42)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when hook has no ID", func() {
+			ginkgo.It("should return that hook has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.HookService.Retry(context.TODO(), resources.Hook{}, 0)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("hook list", func() {
+		var hooks []*resources.Hook
+
+		ginkgo.Context("when all hooks are listed", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookListAll
+			})
+
+			ginkgo.It("should return list of all hooks with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hooks, err = client.HookService.ListAll(context.TODO())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(hooks).To(gomega.HaveLen(4))
+				gomega.Expect(hooks[0].Name()).To(gomega.Equal("raft-status"))
+				gomega.Expect(hooks[1].Name()).To(gomega.Equal("vm-running"))
+				gomega.Expect(hooks[1].StateHook()).To(gomega.Equal(&resources.StateHook{Resource: "VM",
+					On: "RUNNING"}))
+			})
+		})
+
+		ginkgo.Context("when pagination is given", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookListPagination
+			})
+
+			ginkgo.It("should return hooks of the page", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				hooks, err = client.HookService.List(context.TODO(), 2, 2)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(hooks).To(gomega.HaveLen(2))
+				gomega.Expect(hooks[0].ID()).To(gomega.Equal(3))
+				gomega.Expect(hooks[1].ID()).To(gomega.Equal(4))
+			})
+		})
+	})
+
+	ginkgo.Describe("hook log", func() {
+		var records []*resources.HookExecutionRecord
+
+		ginkgo.Context("when all executions are requested", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookLogAll
+			})
+
+			ginkgo.It("should return execution records of all hooks", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				records, err = client.HookService.Log(context.TODO(), time.Time{}, time.Time{},
+					services.HookExecutionFilterAll)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(4))
+				gomega.Expect(records[2].HookID).To(gomega.Equal(stateHookID))
+				gomega.Expect(records[2].Command).To(gomega.Equal("running.sh"))
+				gomega.Expect(records[2].Timestamp).To(gomega.Equal(now))
+			})
+		})
+
+		ginkgo.Context("when start of time range is given", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookLogSince
+			})
+
+			ginkgo.It("should return executions since the start", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				records, err = client.HookService.Log(context.TODO(), now.Add(-90*time.Minute), time.Time{},
+					services.HookExecutionFilterAll)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(3))
+				gomega.Expect(records[0].ExecutionID).To(gomega.Equal(1))
+			})
+		})
+
+		ginkgo.Context("when failed executions of one hook are requested", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hookLogForHookError
+			})
+
+			ginkgo.It("should return failed executions of the hook", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				records, err = client.HookService.LogForHook(context.TODO(),
+					*resources.CreateHookWithID(existingHookID), time.Time{}, time.Time{},
+					services.HookExecutionFilterError)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(1))
+				gomega.Expect(records[0].ExecutionID).To(gomega.Equal(1))
+				gomega.Expect(records[0].Stderr).To(gomega.Equal("timeout"))
+			})
+		})
+
+		ginkgo.Context("when hook has no ID", func() {
+			ginkgo.It("should return that hook has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				records, err = client.HookService.LogForHook(context.TODO(), resources.Hook{}, time.Time{},
+					time.Time{}, services.HookExecutionFilterAll)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(records).Should(gomega.BeNil())
+			})
+		})
+	})
+})
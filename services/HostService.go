@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 
+	"github.com/beevik/etree"
+
 	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
 	"github.com/onego-project/onego/resources"
 )
 
@@ -112,6 +115,54 @@ func (hs *HostService) Rename(ctx context.Context, host resources.Host, name str
 	return err
 }
 
+// WaitForState waits until the host reaches given state and returns the host info.
+// Waiting fails when the host reaches HostError, DefaultBackoff is used between the checks.
+func (hs *HostService) WaitForState(ctx context.Context, host resources.Host,
+	state resources.HostState) (*resources.Host, error) {
+	return hs.WaitForStateWithBackoff(ctx, host, state, DefaultBackoff)
+}
+
+// WaitForStateWithBackoff waits until the host reaches given state with given backoff
+// between the checks. See WaitForState.
+func (hs *HostService) WaitForStateWithBackoff(ctx context.Context, host resources.Host,
+	state resources.HostState, backoff Backoff) (*resources.Host, error) {
+	hostID, err := host.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	var oneHost *resources.Host
+
+	err = waitFor(ctx, backoff, func() (bool, error) {
+		var checkErr error
+		oneHost, checkErr = hs.RetrieveInfo(ctx, hostID)
+		if checkErr != nil {
+			return false, checkErr
+		}
+
+		currentState, checkErr := oneHost.State()
+		if checkErr != nil {
+			return false, checkErr
+		}
+
+		if currentState == state {
+			return true, nil
+		}
+
+		if currentState == resources.HostError {
+			return false, &errors.StateError{Resource: "host", ID: hostID,
+				State: resources.HostStateMap[currentState]}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oneHost, nil
+}
+
 // RetrieveInfo retrieves information for the host.
 func (hs *HostService) RetrieveInfo(ctx context.Context, hostID int) (*resources.Host, error) {
 	doc, err := hs.retrieveInfo(ctx, "one.host.info", hostID)
@@ -138,3 +189,43 @@ func (hs *HostService) List(ctx context.Context) ([]*resources.Host, error) {
 
 	return hosts, nil
 }
+
+func hostMonitoringFromXML(doc *etree.Document) ([]*resources.HostMonitoring, error) {
+	elements := doc.FindElements("MONITORING_DATA/HOST")
+
+	samples := make([]*resources.HostMonitoring, len(elements))
+	for i, e := range elements {
+		sample, err := resources.ParseHostMonitoring(e)
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = sample
+	}
+
+	return samples, nil
+}
+
+// Monitoring retrieves monitoring samples of the host kept by OpenNebula.
+func (hs *HostService) Monitoring(ctx context.Context, host resources.Host) ([]*resources.HostMonitoring, error) {
+	hostID, err := host.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := hs.retrieveInfo(ctx, "one.host.monitoring", hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	return hostMonitoringFromXML(doc)
+}
+
+// PoolMonitoring retrieves monitoring samples of all the hosts in the pool.
+func (hs *HostService) PoolMonitoring(ctx context.Context) ([]*resources.HostMonitoring, error) {
+	doc, err := hs.list(ctx, "one.hostpool.monitoring")
+	if err != nil {
+		return nil, err
+	}
+
+	return hostMonitoringFromXML(doc)
+}
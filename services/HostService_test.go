@@ -5,6 +5,7 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/dnaeon/go-vcr/recorder"
@@ -41,6 +42,10 @@ const (
 	hostRetrieveInfo = "records/host/retrieveInfo"
 
 	hostList = "records/host/list"
+
+	hostMonitoring        = "records/onetest/host/monitoring"
+	hostMonitoringUnknown = "records/onetest/host/monitoringUnknown"
+	hostPoolMonitoring    = "records/onetest/host/poolMonitoring"
 )
 
 var _ = ginkgo.Describe("Host Service", func() {
@@ -613,4 +618,63 @@ var _ = ginkgo.Describe("Host Service", func() {
 			gomega.Expect(hosts).ShouldNot(gomega.BeNil())
 		})
 	})
+
+	ginkgo.Describe("monitoring", func() {
+		var samples []*resources.HostMonitoring
+
+		ginkgo.Context("when host exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hostMonitoring
+			})
+
+			ginkgo.It("should return monitoring samples of the host", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				samples, err = client.HostService.Monitoring(context.TODO(), *resources.CreateHostWithID(40))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(samples).To(gomega.HaveLen(2))
+				gomega.Expect(samples[0].HostID).To(gomega.Equal(40))
+				gomega.Expect(samples[0].Timestamp).To(gomega.BeTemporally("==",
+					time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)))
+				gomega.Expect(samples[0].MaxCPU).To(gomega.Equal(700))
+				gomega.Expect(samples[0].CPUUsage).To(gomega.Equal(200))
+				gomega.Expect(samples[0].UsedCPU).To(gomega.Equal(150))
+				gomega.Expect(samples[0].MemoryUsage).To(gomega.Equal(2097152))
+				gomega.Expect(samples[0].RunningVMs).To(gomega.Equal(1))
+				gomega.Expect(samples[1].UsedCPU).To(gomega.Equal(180))
+				gomega.Expect(samples[1].UsedMemory).To(gomega.Equal(2621440))
+			})
+		})
+
+		ginkgo.Context("when host doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hostMonitoringUnknown
+			})
+
+			ginkgo.It("should return an error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				samples, err = client.HostService.Monitoring(context.TODO(), *resources.CreateHostWithID(1000))
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(samples).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when retrieving monitoring of the pool", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hostPoolMonitoring
+			})
+
+			ginkgo.It("should return monitoring samples of all hosts", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				samples, err = client.HostService.PoolMonitoring(context.TODO())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(samples).To(gomega.HaveLen(3))
+				gomega.Expect(samples[2].HostID).To(gomega.Equal(41))
+				gomega.Expect(samples[2].UsedCPU).To(gomega.Equal(40))
+				gomega.Expect(samples[2].CPUUsage).To(gomega.Equal(100))
+			})
+		})
+	})
 })
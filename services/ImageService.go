@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"io"
 
 	"github.com/beevik/etree"
 	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
 	"github.com/onego-project/onego/requests"
 	"github.com/onego-project/onego/resources"
 )
@@ -38,6 +40,62 @@ func (is *ImageService) Allocate(ctx context.Context, blueprint blueprint.Interf
 	return is.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
 }
 
+// Upload stages the content using the transport and allocates a new image from the staged file.
+// PATH of the blueprint is set to the path of the staged file. Size of the content in bytes is used to report
+// the progress, it is -1 when unknown. Progress may be nil, cancelling the context stops the upload.
+func (is *ImageService) Upload(ctx context.Context, transport ImageTransport, imageBlueprint *blueprint.ImageBlueprint,
+	datastore resources.Datastore, content io.Reader, size int64, progress ProgressFunc) (*resources.Image, error) {
+	name := imageBlueprint.XMLData.Root().SelectElement("NAME")
+	if name == nil {
+		return nil, &errors.XMLElementError{Path: "NAME"}
+	}
+
+	path, err := transport.Upload(ctx, name.Text(), &progressReader{ctx: ctx, reader: content, total: size,
+		progress: progress})
+	if err != nil {
+		return nil, err
+	}
+
+	imageBlueprint.SetPath(path)
+
+	return is.Allocate(ctx, imageBlueprint, datastore)
+}
+
+// Download writes content of the image stored in its source to the writer using the transport and returns
+// the number of bytes written. Progress may be nil, cancelling the context stops the download.
+func (is *ImageService) Download(ctx context.Context, transport ImageTransport, image resources.Image,
+	w io.Writer, progress ProgressFunc) (int64, error) {
+	imageID, err := image.ID()
+	if err != nil {
+		return 0, err
+	}
+
+	oneImage, err := is.RetrieveInfo(ctx, imageID)
+	if err != nil {
+		return 0, err
+	}
+
+	source, err := oneImage.Source()
+	if err != nil {
+		return 0, err
+	}
+
+	// size of the image is in MB
+	total := int64(-1)
+	var size int
+	if size, err = oneImage.Size(); err == nil && size > 0 {
+		total = int64(size) * 1024 * 1024
+	}
+
+	content, err := transport.Download(ctx, source)
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close() //nolint
+
+	return io.Copy(w, &progressReader{ctx: ctx, reader: content, total: total, progress: progress})
+}
+
 // Clone clones an existing image.
 func (is *ImageService) Clone(ctx context.Context, image resources.Image, name string,
 	datastore resources.Datastore) (*resources.Image, error) {
@@ -217,6 +275,54 @@ func (is *ImageService) FlattenSnapshot(ctx context.Context, image resources.Ima
 	return err
 }
 
+// WaitForState waits until the image reaches given state and returns the image info.
+// Waiting fails when the image reaches ImageStateError, DefaultBackoff is used between the checks.
+func (is *ImageService) WaitForState(ctx context.Context, image resources.Image,
+	state resources.ImageState) (*resources.Image, error) {
+	return is.WaitForStateWithBackoff(ctx, image, state, DefaultBackoff)
+}
+
+// WaitForStateWithBackoff waits until the image reaches given state with given backoff
+// between the checks. See WaitForState.
+func (is *ImageService) WaitForStateWithBackoff(ctx context.Context, image resources.Image,
+	state resources.ImageState, backoff Backoff) (*resources.Image, error) {
+	imageID, err := image.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	var oneImage *resources.Image
+
+	err = waitFor(ctx, backoff, func() (bool, error) {
+		var checkErr error
+		oneImage, checkErr = is.RetrieveInfo(ctx, imageID)
+		if checkErr != nil {
+			return false, checkErr
+		}
+
+		currentState, checkErr := oneImage.State()
+		if checkErr != nil {
+			return false, checkErr
+		}
+
+		if currentState == state {
+			return true, nil
+		}
+
+		if currentState == resources.ImageStateError {
+			return false, &errors.StateError{Resource: "image", ID: imageID,
+				State: resources.ImageStateMap[currentState]}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oneImage, nil
+}
+
 // RetrieveInfo retrieves information for the image.
 func (is *ImageService) RetrieveInfo(ctx context.Context, imageID int) (*resources.Image, error) {
 	doc, err := is.retrieveInfo(ctx, "one.image.info", imageID)
@@ -279,3 +385,49 @@ func (is *ImageService) ListForUser(ctx context.Context, user resources.User, pa
 
 	return is.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
 }
+
+// Iterate returns iterator over the images in the pool which belong to given owner(s) in ownership filter.
+// Pages of IteratorPageSizeDefault images are retrieved lazily while iterating.
+func (is *ImageService) Iterate(ctx context.Context, filter OwnershipFilter) *ImageIterator {
+	return is.IterateWithPageSize(ctx, IteratorPageSizeDefault, filter)
+}
+
+// IterateWithPageSize returns iterator over the images in the pool which belong to given owner(s)
+// in ownership filter. Pages of given size are retrieved lazily while iterating.
+func (is *ImageService) IterateWithPageSize(ctx context.Context, pageSize int,
+	filter OwnershipFilter) *ImageIterator {
+	it := &ImageIterator{}
+	it.pager = newPager(ctx, pageSize, func(ctx context.Context, offset, pageSize int) (int, error) {
+		page, err := is.list(ctx, int(filter), offset, -pageSize)
+		if err != nil {
+			return 0, err
+		}
+
+		it.page = page
+		return len(page), nil
+	})
+
+	return it
+}
+
+// Find retrieves the images in the pool matching given query.
+func (is *ImageService) Find(ctx context.Context, query *PoolQuery) ([]*resources.Image, error) {
+	if query.err != nil {
+		return nil, query.err
+	}
+
+	images := make([]*resources.Image, 0)
+
+	it := is.Iterate(ctx, OwnershipFilter(query.ownership))
+	for it.Next() {
+		if query.Matches(&it.Value().Resource) {
+			images = append(images, it.Value())
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// ImageTransport transfers content of images between the client and the frontend.
+type ImageTransport interface {
+	// Upload stages the content with given name and returns the path the frontend can register
+	// the image from.
+	Upload(ctx context.Context, name string, content io.Reader) (string, error)
+	// Download opens content of the image stored in source, source is the SOURCE of the image.
+	Download(ctx context.Context, source string) (io.ReadCloser, error)
+}
+
+// ProgressFunc is called during transfer of an image with the number of bytes transferred so far
+// and the total number of bytes, total is -1 when the size of the content is unknown.
+type ProgressFunc func(transferred, total int64)
+
+// HTTPImageTransport structure transfers images over HTTP. Content is uploaded to UploadURL as multipart
+// form with file field (the way Sunstone upload endpoint, e.g. http://sunstone:9869/upload, accepts it),
+// the response body is the path of the staged file. Images are downloaded from DownloadURL with the source
+// of the image appended, e.g. from a web server serving the datastores directory.
+type HTTPImageTransport struct {
+	UploadURL   string
+	DownloadURL string
+	Client      *http.Client
+	// Username and Password are used for basic authentication when Username is not empty
+	Username string
+	Password string
+}
+
+// Upload stages the content on the upload endpoint.
+func (t *HTTPImageTransport) Upload(ctx context.Context, name string, content io.Reader) (string, error) {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	go func() {
+		part, err := form.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.Copy(part, content)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err) //nolint
+	}()
+
+	request, err := t.request(ctx, http.MethodPost, t.UploadURL, body)
+	if err != nil {
+		body.Close() //nolint
+		return "", err
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+
+	response, err := t.do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close() //nolint
+
+	path, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(path)), nil
+}
+
+// Download opens the image on the download endpoint.
+func (t *HTTPImageTransport) Download(ctx context.Context, source string) (io.ReadCloser, error) {
+	downloadURL := strings.TrimSuffix(t.DownloadURL, "/") + "/" + strings.TrimPrefix(source, "/")
+
+	request, err := t.request(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := t.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func (t *HTTPImageTransport) request(ctx context.Context, method, url string,
+	body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Username != "" {
+		request.SetBasicAuth(t.Username, t.Password)
+	}
+
+	return request.WithContext(ctx), nil
+}
+
+func (t *HTTPImageTransport) do(request *http.Request) (*http.Response, error) {
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		response.Body.Close() //nolint
+
+		url := *request.URL
+		url.User = nil
+		return nil, &errors.TransferError{URL: url.String(), StatusCode: response.StatusCode,
+			Status: response.Status}
+	}
+
+	return response, nil
+}
+
+// progressReader reports progress of reading and stops reading when the context is done.
+type progressReader struct {
+	ctx         context.Context
+	reader      io.Reader
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	if err := pr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := pr.reader.Read(p)
+	pr.transferred += int64(n)
+	if pr.progress != nil && n > 0 {
+		pr.progress(pr.transferred, pr.total)
+	}
+
+	return n, err
+}
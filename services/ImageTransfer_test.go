@@ -0,0 +1,283 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	imageTransferUpload         = "records/onetest/imageTransfer/upload"
+	imageTransferUploadFailed   = "records/imageTransfer/uploadFailed"
+	imageTransferUploadCanceled = "records/imageTransfer/uploadCanceled"
+	imageTransferUploadNoName   = "records/imageTransfer/uploadNoName"
+
+	imageTransferDownload         = "records/onetest/imageTransfer/download"
+	imageTransferDownloadCanceled = "records/onetest/imageTransfer/downloadCanceled"
+	imageTransferDownloadEmpty    = "records/imageTransfer/downloadEmpty"
+)
+
+var _ = ginkgo.Describe("Image Transfer", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+
+		server    *httptest.Server
+		transport *services.HTTPImageTransport
+		uploaded  bytes.Buffer
+		requested string
+		content   []byte
+	)
+
+	ginkgo.BeforeEach(func() {
+		uploaded.Reset()
+		requested = ""
+		content = bytes.Repeat([]byte("qcow2"), 20000)
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/upload":
+				file, header, err := r.FormFile("file")
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				defer file.Close() //nolint
+
+				if _, err = uploaded.ReadFrom(file); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write([]byte("/var/tmp/sunstone/upload/" + header.Filename + "\n")) //nolint
+			case "/failing":
+				http.Error(w, "no space left on device", http.StatusInternalServerError)
+			default:
+				requested = strings.TrimPrefix(r.URL.Path, "/datastores")
+				w.Write(content) //nolint
+			}
+		}))
+
+		transport = &services.HTTPImageTransport{UploadURL: server.URL + "/upload",
+			DownloadURL: server.URL + "/datastores/"}
+	})
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+		server.Close()
+	})
+
+	ginkgo.Describe("upload image", func() {
+		var (
+			image          *resources.Image
+			imageBlueprint *blueprint.ImageBlueprint
+			transferred    []int64
+			total          int64
+		)
+
+		progress := func(t, s int64) {
+			transferred = append(transferred, t)
+			total = s
+		}
+
+		ginkgo.BeforeEach(func() {
+			imageBlueprint = blueprint.CreateAllocateImageBlueprint()
+			imageBlueprint.SetElement("NAME", "ubuntu.qcow2")
+			transferred = nil
+			total = 0
+		})
+
+		ginkgo.Context("when upload endpoint accepts the content", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferUpload
+			})
+
+			ginkgo.It("should stage the content and allocate new image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.ImageService.Upload(context.TODO(), transport, imageBlueprint,
+					*resources.CreateDatastoreWithID(1), bytes.NewReader(content), int64(len(content)), progress)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(image).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(uploaded.Bytes()).To(gomega.Equal(content))
+				gomega.Expect(image.Name()).To(gomega.Equal("ubuntu.qcow2"))
+				gomega.Expect(image.Path()).To(gomega.Equal("/var/tmp/sunstone/upload/ubuntu.qcow2"))
+
+				gomega.Expect(transferred).NotTo(gomega.BeEmpty())
+				gomega.Expect(transferred[len(transferred)-1]).To(gomega.Equal(int64(len(content))))
+				gomega.Expect(total).To(gomega.Equal(int64(len(content))))
+			})
+		})
+
+		ginkgo.Context("when upload endpoint fails", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferUploadFailed
+			})
+
+			ginkgo.It("should return transfer error without allocating an image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				transport.UploadURL = server.URL + "/failing"
+
+				image, err = client.ImageService.Upload(context.TODO(), transport, imageBlueprint,
+					*resources.CreateDatastoreWithID(1), bytes.NewReader(content), -1, nil)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).To(gomega.BeNil())
+
+				transferErr, ok := err.(*errors.TransferError)
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(transferErr.StatusCode).To(gomega.Equal(http.StatusInternalServerError))
+			})
+		})
+
+		ginkgo.Context("when upload is canceled", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferUploadCanceled
+			})
+
+			ginkgo.It("should stop the upload without allocating an image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				ctx, cancel := context.WithCancel(context.TODO())
+				defer cancel()
+
+				image, err = client.ImageService.Upload(ctx, transport, imageBlueprint,
+					*resources.CreateDatastoreWithID(1), bytes.NewReader(content), int64(len(content)),
+					func(int64, int64) { cancel() })
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint has no name", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferUploadNoName
+			})
+
+			ginkgo.It("should return that name is missing", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.ImageService.Upload(context.TODO(), transport,
+					blueprint.CreateAllocateImageBlueprint(), *resources.CreateDatastoreWithID(1),
+					bytes.NewReader(content), -1, nil)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).To(gomega.BeNil())
+				gomega.Expect(uploaded.Len()).To(gomega.BeZero())
+			})
+		})
+	})
+
+	ginkgo.Describe("download image", func() {
+		var (
+			written     int64
+			transferred int64
+			total       int64
+			downloaded  *bytes.Buffer
+		)
+
+		ginkgo.BeforeEach(func() {
+			downloaded = &bytes.Buffer{}
+			transferred, total = 0, 0
+		})
+
+		ginkgo.Context("when image exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferDownload
+			})
+
+			ginkgo.It("should write content of the image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				written, err = client.ImageService.Download(context.TODO(), transport,
+					*resources.CreateImageWithID(240), downloaded, func(t, s int64) {
+						transferred, total = t, s
+					})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(written).To(gomega.Equal(int64(len(content))))
+				gomega.Expect(downloaded.Bytes()).To(gomega.Equal(content))
+
+				gomega.Expect(requested).To(gomega.HavePrefix("/var/lib/one//datastores/1/"))
+				gomega.Expect(transferred).To(gomega.Equal(int64(len(content))))
+				gomega.Expect(total).To(gomega.Equal(int64(1024 * 1024)))
+			})
+		})
+
+		ginkgo.Context("when download is canceled", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferDownloadCanceled
+			})
+
+			ginkgo.It("should stop the download", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				ctx, cancel := context.WithCancel(context.TODO())
+				defer cancel()
+
+				written, err = client.ImageService.Download(ctx, transport, *resources.CreateImageWithID(240),
+					downloaded, func(int64, int64) { cancel() })
+				gomega.Expect(err).To(gomega.MatchError(context.Canceled))
+				gomega.Expect(written).To(gomega.BeNumerically("<", len(content)))
+			})
+		})
+
+		ginkgo.Context("when image is empty", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageTransferDownloadEmpty
+			})
+
+			ginkgo.It("should return that image has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				written, err = client.ImageService.Download(context.TODO(), transport, resources.Image{},
+					downloaded, nil)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(written).To(gomega.BeZero())
+				gomega.Expect(requested).To(gomega.BeEmpty())
+			})
+		})
+	})
+})
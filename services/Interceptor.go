@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/onego-project/xmlrpc"
+)
+
+// Invoker makes the XML-RPC call of the method, arguments of the call don't contain the token.
+type Invoker func(ctx context.Context, methodName string, args []interface{}) ([]*xmlrpc.Result, error)
+
+// Interceptor intercepts XML-RPC calls made by the services the way gRPC unary client interceptors do.
+// It receives name of the method and arguments of the call without the token and makes the call using
+// invoker, so it can see (or change) the result and error of the call, e.g. to log the calls, create tracing
+// spans or count the calls. Failed calls are retried inside of the invoker, the interceptor is called once
+// per call.
+type Interceptor func(ctx context.Context, methodName string, args []interface{},
+	invoker Invoker) ([]*xmlrpc.Result, error)
+
+// chainInterceptors creates invoker calling the interceptors in given order, the first interceptor
+// is the outermost one and the last one calls invoker.
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, methodName string, args []interface{}) ([]*xmlrpc.Result, error) {
+			return interceptor(ctx, methodName, args, next)
+		}
+	}
+
+	return invoker
+}
@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onego-project/xmlrpc"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	interceptorInfo        = "records/onetest/interceptor/info"
+	interceptorInfoWrongID = "records/onetest/interceptor/infoWrongID"
+	interceptorInfoRetried = "records/onetest/interceptor/infoRetried"
+	interceptorRejected    = "records/interceptor/rejected"
+)
+
+var _ = ginkgo.Describe("Interceptor", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+
+		flaky   *flakyTransport
+		options []onego.ClientOption
+		calls   []string
+	)
+
+	// tracing creates interceptor recording method name, arguments and error of every call
+	tracing := func(name string) services.Interceptor {
+		return func(ctx context.Context, methodName string, args []interface{},
+			invoker services.Invoker) ([]*xmlrpc.Result, error) {
+			calls = append(calls, fmt.Sprintf("%s before %s %v", name, methodName, args))
+			resArr, err := invoker(ctx, methodName, args)
+			calls = append(calls, fmt.Sprintf("%s after %d %v", name, len(resArr), err != nil))
+			return resArr, err
+		}
+	}
+
+	ginkgo.BeforeEach(func() {
+		flaky = &flakyTransport{}
+		options = []onego.ClientOption{onego.WithInterceptors(tracing("outer"), tracing("inner"))}
+		calls = nil
+	})
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		flaky.next = rec
+		clientHTTP := &http.Client{
+			Transport: flaky, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClientWithOptions(endpoint, token,
+			append([]onego.ClientOption{onego.WithHTTPClient(clientHTTP)}, options...)...)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("successful call", func() {
+		ginkgo.BeforeEach(func() {
+			recName = interceptorInfo
+		})
+
+		ginkgo.It("should pass the call through the interceptors in given order", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var vm *resources.VirtualMachine
+			vm, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 320)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(vm.Name()).To(gomega.Equal("traced"))
+
+			gomega.Expect(calls).To(gomega.Equal([]string{
+				"outer before one.vm.info [320]",
+				"inner before one.vm.info [320]",
+				"inner after 3 false",
+				"outer after 3 false",
+			}))
+		})
+	})
+
+	ginkgo.Describe("failed call", func() {
+		ginkgo.BeforeEach(func() {
+			recName = interceptorInfoWrongID
+		})
+
+		ginkgo.It("should pass the error through the interceptors", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			_, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 9999)
+			gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+
+			gomega.Expect(calls).To(gomega.Equal([]string{
+				"outer before one.vm.info [9999]",
+				"inner before one.vm.info [9999]",
+				"inner after 0 true",
+				"outer after 0 true",
+			}))
+		})
+	})
+
+	ginkgo.Describe("retried call", func() {
+		ginkgo.BeforeEach(func() {
+			recName = interceptorInfoRetried
+			flaky.failures = 1
+			options = append(options, onego.WithRetryPolicy(services.RetryPolicy{MaxAttempts: 2,
+				Backoff: services.Backoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}}))
+		})
+
+		ginkgo.It("should call the interceptors once for all attempts", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			_, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 320)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(flaky.attempts).To(gomega.Equal(2))
+			gomega.Expect(calls).To(gomega.HaveLen(4))
+		})
+	})
+
+	ginkgo.Describe("rejected call", func() {
+		ginkgo.BeforeEach(func() {
+			recName = interceptorRejected
+			options = append(options, onego.WithInterceptors(func(ctx context.Context, methodName string,
+				args []interface{}, invoker services.Invoker) ([]*xmlrpc.Result, error) {
+				return nil, errors.ErrNoClient
+			}))
+		})
+
+		ginkgo.It("should return error of the interceptor without making the call", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			err = client.VirtualMachineService.Rename(context.TODO(), *resources.CreateVirtualMachineWithID(320),
+				"untraced")
+			gomega.Expect(err).To(gomega.Equal(errors.ErrNoClient))
+			gomega.Expect(flaky.attempts).To(gomega.BeZero())
+			gomega.Expect(calls).To(gomega.Equal([]string{
+				"outer before one.vm.rename [320 untraced]",
+				"inner before one.vm.rename [320 untraced]",
+				"inner after 0 true",
+				"outer after 0 true",
+			}))
+		})
+	})
+})
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+)
+
+// IteratorPageSizeDefault is number of resources fetched at once by iterators created without page size.
+const IteratorPageSizeDefault = 100
+
+// Iterator walks through resources of a pool. Pages of the pool are fetched lazily,
+// so the whole pool is never loaded into memory at once.
+//
+//	it := client.VirtualMachineService.Iterate(ctx, services.OwnershipFilterAll, services.AnyStateExceptDone)
+//	for it.Next() {
+//		vm := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator interface {
+	// Next advances the iterator to the next resource. It returns false when there are
+	// no more resources or when an error occurred.
+	Next() bool
+	// Err returns the error which stopped the iteration, if any.
+	Err() error
+}
+
+// pager contains the state shared by all pool iterators. Function fetch retrieves one page
+// of the pool starting at given offset, stores it and returns number of resources retrieved.
+type pager struct {
+	ctx      context.Context
+	pageSize int
+	offset   int
+	index    int
+	length   int
+	last     bool
+	err      error
+	fetch    func(ctx context.Context, offset, pageSize int) (int, error)
+}
+
+func newPager(ctx context.Context, pageSize int,
+	fetch func(ctx context.Context, offset, pageSize int) (int, error)) pager {
+	p := pager{ctx: ctx, pageSize: pageSize, index: -1, fetch: fetch}
+	// OpenNebula treats page size one (end of range -1) as a request for the rest of the pool
+	if pageSize < 2 {
+		p.err = errors.ErrPageSize
+	}
+
+	return p
+}
+
+// Next advances the iterator to the next resource, fetching next page of the pool when needed.
+func (p *pager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+
+	if p.index+1 < p.length {
+		p.index++
+		return true
+	}
+
+	if p.last {
+		return false
+	}
+
+	n, err := p.fetch(p.ctx, p.offset, p.pageSize)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.offset += n
+	p.index = 0
+	p.length = n
+	p.last = n < p.pageSize
+
+	return n > 0
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// VirtualMachineIterator iterates over virtual machines in the pool.
+type VirtualMachineIterator struct {
+	pager
+	page []*resources.VirtualMachine
+}
+
+// Value returns the current virtual machine.
+func (it *VirtualMachineIterator) Value() *resources.VirtualMachine {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+
+	return it.page[it.index]
+}
+
+// ImageIterator iterates over images in the pool.
+type ImageIterator struct {
+	pager
+	page []*resources.Image
+}
+
+// Value returns the current image.
+func (it *ImageIterator) Value() *resources.Image {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+
+	return it.page[it.index]
+}
+
+// TemplateIterator iterates over templates in the pool.
+type TemplateIterator struct {
+	pager
+	page []*resources.Template
+}
+
+// Value returns the current template.
+func (it *TemplateIterator) Value() *resources.Template {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+
+	return it.page[it.index]
+}
+
+// VirtualNetworkIterator iterates over virtual networks in the pool.
+type VirtualNetworkIterator struct {
+	pager
+	page []*resources.VirtualNetwork
+}
+
+// Value returns the current virtual network.
+func (it *VirtualNetworkIterator) Value() *resources.VirtualNetwork {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+
+	return it.page[it.index]
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	virtualMachineIterate = "records/onetest/virtualMachine/iterate"
+	imageIterate          = "records/onetest/image/iterate"
+	templateIterate       = "records/onetest/template/iterate"
+	virtualNetworkIterate = "records/onetest/virtualNetwork/iterate"
+)
+
+var _ = ginkgo.Describe("Iterator", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+		ids     []int
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+
+		ids = nil
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("iterate virtual machines", func() {
+		ginkgo.BeforeEach(func() {
+			recName = virtualMachineIterate
+		})
+
+		ginkgo.It("should walk through all the pages", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			it := client.VirtualMachineService.IterateWithPageSize(context.TODO(), 2, services.OwnershipFilterAll,
+				services.AnyStateExceptDone)
+			gomega.Expect(it.Value()).To(gomega.BeNil())
+
+			for it.Next() {
+				var id int
+				id, err = it.Value().ID()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				ids = append(ids, id)
+			}
+			gomega.Expect(it.Err()).NotTo(gomega.HaveOccurred())
+			gomega.Expect(ids).To(gomega.Equal([]int{200, 201, 202, 203, 204}))
+
+			gomega.Expect(it.Next()).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should fail with too small page size", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			it := client.VirtualMachineService.IterateWithPageSize(context.TODO(), 1, services.OwnershipFilterAll,
+				services.AnyStateExceptDone)
+			gomega.Expect(it.Next()).To(gomega.BeFalse())
+			gomega.Expect(it.Err()).To(gomega.Equal(errors.ErrPageSize))
+			gomega.Expect(it.Value()).To(gomega.BeNil())
+		})
+	})
+
+	ginkgo.Describe("iterate images", func() {
+		ginkgo.BeforeEach(func() {
+			recName = imageIterate
+		})
+
+		ginkgo.It("should walk through all the pages", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			it := client.ImageService.IterateWithPageSize(context.TODO(), 3, services.OwnershipFilterAll)
+			for it.Next() {
+				var id int
+				id, err = it.Value().ID()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				ids = append(ids, id)
+			}
+			gomega.Expect(it.Err()).NotTo(gomega.HaveOccurred())
+			gomega.Expect(ids).To(gomega.Equal([]int{200, 201, 202, 203, 204}))
+		})
+	})
+
+	ginkgo.Describe("iterate templates", func() {
+		ginkgo.BeforeEach(func() {
+			recName = templateIterate
+		})
+
+		ginkgo.It("should walk through all the pages", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			it := client.TemplateService.IterateWithPageSize(context.TODO(), 5, services.OwnershipFilterAll)
+			for it.Next() {
+				var id int
+				id, err = it.Value().ID()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				ids = append(ids, id)
+			}
+			gomega.Expect(it.Err()).NotTo(gomega.HaveOccurred())
+			gomega.Expect(ids).To(gomega.Equal([]int{200, 201, 202, 203, 204}))
+		})
+	})
+
+	ginkgo.Describe("iterate virtual networks", func() {
+		ginkgo.BeforeEach(func() {
+			recName = virtualNetworkIterate
+		})
+
+		ginkgo.It("should walk through the pool", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			it := client.VirtualNetworkService.Iterate(context.TODO(), services.OwnershipFilterAll)
+			for it.Next() {
+				var id int
+				id, err = it.Value().ID()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				ids = append(ids, id)
+			}
+			gomega.Expect(it.Err()).NotTo(gomega.HaveOccurred())
+			gomega.Expect(ids).To(gomega.Equal([]int{200, 201, 202, 203, 204}))
+		})
+	})
+})
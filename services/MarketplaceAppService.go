@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+)
+
+// MarketplaceAppService structure to manage OpenNebula marketplace app.
+type MarketplaceAppService struct {
+	Service
+}
+
+// Allocate allocates a new marketplace app in the given marketplace.
+func (mas *MarketplaceAppService) Allocate(ctx context.Context, blueprint blueprint.Interface,
+	marketplace resources.Marketplace) (*resources.MarketplaceApp, error) {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := mas.call(ctx, "one.marketapp.allocate", blueprintText, marketplaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mas.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// ExportImage exports the image to the marketplace as a new marketplace app with given name.
+func (mas *MarketplaceAppService) ExportImage(ctx context.Context, image resources.Image,
+	marketplace resources.Marketplace, name string) (*resources.MarketplaceApp, error) {
+	imageID, err := image.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	appBlueprint := blueprint.CreateAllocateMarketplaceAppBlueprint()
+	appBlueprint.SetName(name)
+	appBlueprint.SetOriginID(imageID)
+	appBlueprint.SetType(resources.MarketplaceAppTypeImage)
+
+	return mas.Allocate(ctx, appBlueprint, marketplace)
+}
+
+// Import imports the marketplace app to the datastore as a new image with given name.
+func (mas *MarketplaceAppService) Import(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	datastore resources.Datastore, name string) (*resources.Image, error) {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	imageBlueprint := blueprint.CreateAllocateImageBlueprint()
+	imageBlueprint.SetName(name)
+	imageBlueprint.SetElement("FROM_APP", strconv.Itoa(marketplaceAppID))
+
+	imageService := ImageService{Service: mas.Service}
+
+	return imageService.Allocate(ctx, imageBlueprint, datastore)
+}
+
+func (mas *MarketplaceAppService) enable(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	enable bool) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = mas.call(ctx, "one.marketapp.enable", marketplaceAppID, enable)
+
+	return err
+}
+
+// Enable enables a marketplace app.
+func (mas *MarketplaceAppService) Enable(ctx context.Context, marketplaceApp resources.MarketplaceApp) error {
+	return mas.enable(ctx, marketplaceApp, true)
+}
+
+// Disable disables a marketplace app, disabled marketplace app cannot be imported.
+func (mas *MarketplaceAppService) Disable(ctx context.Context, marketplaceApp resources.MarketplaceApp) error {
+	return mas.enable(ctx, marketplaceApp, false)
+}
+
+// Delete deletes the given marketplace app from the pool.
+func (mas *MarketplaceAppService) Delete(ctx context.Context, marketplaceApp resources.MarketplaceApp) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = mas.call(ctx, "one.marketapp.delete", marketplaceAppID)
+
+	return err
+}
+
+// Update merges or replaces the marketplace app template contents.
+func (mas *MarketplaceAppService) Update(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	blueprint blueprint.Interface, updateType UpdateType) (*resources.MarketplaceApp, error) {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := mas.call(ctx, "one.marketapp.update", marketplaceAppID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return mas.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Chmod changes the permission bits of a marketplace app.
+func (mas *MarketplaceAppService) Chmod(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	request requests.PermissionRequest) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	return mas.chmod(ctx, "one.marketapp.chmod", marketplaceAppID, request)
+}
+
+// Chown changes the ownership of a marketplace app.
+func (mas *MarketplaceAppService) Chown(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	request requests.OwnershipRequest) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	return mas.chown(ctx, "one.marketapp.chown", marketplaceAppID, request)
+}
+
+// Rename renames a marketplace app.
+func (mas *MarketplaceAppService) Rename(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	name string) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = mas.call(ctx, "one.marketapp.rename", marketplaceAppID, name)
+
+	return err
+}
+
+// Lock locks a marketplace app, actions of given level and the levels above are blocked.
+func (mas *MarketplaceAppService) Lock(ctx context.Context, marketplaceApp resources.MarketplaceApp,
+	level resources.LockLevel) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	return mas.lock(ctx, "one.marketapp.lock", marketplaceAppID, level)
+}
+
+// Unlock unlocks a marketplace app.
+func (mas *MarketplaceAppService) Unlock(ctx context.Context, marketplaceApp resources.MarketplaceApp) error {
+	marketplaceAppID, err := marketplaceApp.ID()
+	if err != nil {
+		return err
+	}
+
+	return mas.unlock(ctx, "one.marketapp.unlock", marketplaceAppID)
+}
+
+// RetrieveInfo retrieves information for the marketplace app.
+func (mas *MarketplaceAppService) RetrieveInfo(ctx context.Context,
+	marketplaceAppID int) (*resources.MarketplaceApp, error) {
+	doc, err := mas.retrieveInfo(ctx, "one.marketapp.info", marketplaceAppID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateMarketplaceAppFromXML(doc.Root()), nil
+}
+
+func (mas *MarketplaceAppService) list(ctx context.Context, filterFlag, pageOffset,
+	pageSize int) ([]*resources.MarketplaceApp, error) {
+	resArr, err := mas.call(ctx, "one.marketapppool.info", filterFlag, pageOffset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("MARKETPLACEAPP_POOL/MARKETPLACEAPP")
+
+	marketplaceApps := make([]*resources.MarketplaceApp, len(elements))
+	for i, e := range elements {
+		marketplaceApps[i] = resources.CreateMarketplaceAppFromXML(e)
+	}
+
+	return marketplaceApps, nil
+}
+
+// ListAll retrieves information for all the marketplace apps in the pool.
+func (mas *MarketplaceAppService) ListAll(ctx context.Context,
+	filter OwnershipFilter) ([]*resources.MarketplaceApp, error) {
+	return mas.list(ctx, int(filter), pageOffsetDefault, pageSizeDefault)
+}
+
+// ListAllForUser retrieves information for all the marketplace apps for the given user in the pool.
+func (mas *MarketplaceAppService) ListAllForUser(ctx context.Context,
+	user resources.User) ([]*resources.MarketplaceApp, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return mas.list(ctx, userID, pageOffsetDefault, pageSizeDefault)
+}
+
+// List retrieves information for a part of the marketplace apps in the pool with a given pagination.
+func (mas *MarketplaceAppService) List(ctx context.Context, pageOffset, pageSize int,
+	filter OwnershipFilter) ([]*resources.MarketplaceApp, error) {
+	return mas.list(ctx, int(filter), (pageOffset-1)*pageSize, -pageSize)
+}
+
+// ListForUser retrieves information for a part of the marketplace apps for given user in the pool
+// with a given pagination.
+func (mas *MarketplaceAppService) ListForUser(ctx context.Context, user resources.User, pageOffset,
+	pageSize int) ([]*resources.MarketplaceApp, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return mas.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
+}
@@ -0,0 +1,921 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	marketplaceAppAllocate                = "records/onetest/marketplaceApp/allocate"
+	marketplaceAppAllocateExisting        = "records/onetest/marketplaceApp/allocateExisting"
+	marketplaceAppAllocateDisabledMarket  = "records/onetest/marketplaceApp/allocateDisabledMarket"
+	marketplaceAppAllocateUnknownMarket   = "records/onetest/marketplaceApp/allocateUnknownMarket"
+	marketplaceAppExportImage             = "records/onetest/marketplaceApp/exportImage"
+	marketplaceAppExportImageUnknownImage = "records/onetest/marketplaceApp/exportImageUnknownImage"
+
+	marketplaceAppImport         = "records/onetest/marketplaceApp/import"
+	marketplaceAppImportDisabled = "records/onetest/marketplaceApp/importDisabled"
+
+	marketplaceAppDelete        = "records/onetest/marketplaceApp/delete"
+	marketplaceAppDeleteWrongID = "records/onetest/marketplaceApp/deleteWrongID"
+
+	marketplaceAppUpdateMerge   = "records/onetest/marketplaceApp/updateMerge"
+	marketplaceAppUpdateUnknown = "records/onetest/marketplaceApp/updateUnknown"
+
+	marketplaceAppChmod        = "records/onetest/marketplaceApp/chmod"
+	marketplaceAppChmodUnknown = "records/onetest/marketplaceApp/chmodUnknown"
+
+	marketplaceAppChown        = "records/onetest/marketplaceApp/chown"
+	marketplaceAppChownUnknown = "records/onetest/marketplaceApp/chownUnknown"
+
+	marketplaceAppRename        = "records/onetest/marketplaceApp/rename"
+	marketplaceAppRenameEmpty   = "records/onetest/marketplaceApp/renameEmpty"
+	marketplaceAppRenameUnknown = "records/onetest/marketplaceApp/renameUnknown"
+
+	marketplaceAppEnable        = "records/onetest/marketplaceApp/enable"
+	marketplaceAppEnableUnknown = "records/onetest/marketplaceApp/enableUnknown"
+
+	marketplaceAppLock        = "records/onetest/marketplaceApp/lock"
+	marketplaceAppLockUnknown = "records/onetest/marketplaceApp/lockUnknown"
+
+	marketplaceAppRetrieveInfo        = "records/onetest/marketplaceApp/retrieveInfo"
+	marketplaceAppRetrieveInfoUnknown = "records/onetest/marketplaceApp/retrieveInfoUnknown"
+
+	marketplaceAppListAllAll     = "records/onetest/marketplaceApp/listAllAll"
+	marketplaceAppListAllForUser = "records/onetest/marketplaceApp/listAllForUser"
+	marketplaceAppListPagination = "records/onetest/marketplaceApp/listPagination"
+)
+
+var _ = ginkgo.Describe("Marketplace App Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingMarketplaceAppID = 0
+	var deletedMarketplaceAppID = 1
+	var disabledMarketplaceAppID = 2
+	var nonExistingMarketplaceAppID = 420
+
+	var marketplaceID = 100
+	var disabledMarketplaceID = 101
+
+	var exportedImageID = 0
+	var datastoreID = 1
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate marketplace app", func() {
+		var (
+			marketplaceApp          *resources.MarketplaceApp
+			marketplaceAppBlueprint *blueprint.MarketplaceAppBlueprint
+		)
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppAllocate
+
+				marketplaceAppBlueprint = blueprint.CreateAllocateMarketplaceAppBlueprint()
+				marketplaceAppBlueprint.SetName("alpine-golden")
+				marketplaceAppBlueprint.SetType(resources.MarketplaceAppTypeImage)
+				marketplaceAppBlueprint.SetSize(1024)
+				marketplaceAppBlueprint.SetVersion("3.12")
+			})
+
+			ginkgo.It("should create new marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.Allocate(context.TODO(), marketplaceAppBlueprint,
+					*resources.CreateMarketplaceWithID(marketplaceID))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).ShouldNot(gomega.BeNil())
+				gomega.Expect(marketplaceApp.ID()).To(gomega.Equal(3))
+				gomega.Expect(marketplaceApp.Name()).To(gomega.Equal("alpine-golden"))
+				gomega.Expect(marketplaceApp.Marketplace()).To(gomega.Equal(marketplaceID))
+				gomega.Expect(marketplaceApp.Size()).To(gomega.Equal(1024))
+				gomega.Expect(marketplaceApp.Version()).To(gomega.Equal("3.12"))
+				gomega.Expect(marketplaceApp.State()).To(gomega.Equal(resources.MarketplaceAppStateReady))
+				gomega.Expect(marketplaceApp.Type()).To(gomega.Equal(resources.MarketplaceAppTypeImage))
+			})
+		})
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppAllocateExisting
+
+				marketplaceAppBlueprint = blueprint.CreateAllocateMarketplaceAppBlueprint()
+				marketplaceAppBlueprint.SetName("debian-golden")
+				marketplaceAppBlueprint.SetSize(1024)
+			})
+
+			ginkgo.It("should return that marketplace app already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.Allocate(context.TODO(), marketplaceAppBlueprint,
+					*resources.CreateMarketplaceWithID(marketplaceID))
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace is disabled", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppAllocateDisabledMarket
+
+				marketplaceAppBlueprint = blueprint.CreateAllocateMarketplaceAppBlueprint()
+				marketplaceAppBlueprint.SetName("debian-disabled")
+				marketplaceAppBlueprint.SetSize(1024)
+			})
+
+			ginkgo.It("shouldn't create new marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.Allocate(context.TODO(), marketplaceAppBlueprint,
+					*resources.CreateMarketplaceWithID(disabledMarketplaceID))
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppAllocateUnknownMarket
+
+				marketplaceAppBlueprint = blueprint.CreateAllocateMarketplaceAppBlueprint()
+				marketplaceAppBlueprint.SetName("debian-unknown")
+				marketplaceAppBlueprint.SetSize(1024)
+			})
+
+			ginkgo.It("should return that marketplace doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.Allocate(context.TODO(), marketplaceAppBlueprint,
+					*resources.CreateMarketplaceWithID(420))
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("shouldn't create new marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.Allocate(context.TODO(),
+					blueprint.CreateAllocateMarketplaceAppBlueprint(), resources.Marketplace{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("export image", func() {
+		var (
+			marketplaceApp *resources.MarketplaceApp
+			image          *resources.Image
+		)
+
+		ginkgo.Context("when image exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppExportImage
+			})
+
+			ginkgo.It("should create new marketplace app from the image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.ExportImage(context.TODO(),
+					*resources.CreateImageWithID(exportedImageID), *resources.CreateMarketplaceWithID(marketplaceID),
+					"debian-11")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).ShouldNot(gomega.BeNil())
+				gomega.Expect(marketplaceApp.ID()).To(gomega.Equal(4))
+				gomega.Expect(marketplaceApp.Name()).To(gomega.Equal("debian-11"))
+				gomega.Expect(marketplaceApp.Origin()).To(gomega.Equal(exportedImageID))
+				gomega.Expect(marketplaceApp.Size()).To(gomega.Equal(8192))
+				gomega.Expect(marketplaceApp.AppTemplate()).To(gomega.ContainSubstring("DEV_PREFIX=\"vd\""))
+
+				// check whether the image knows about its marketplace app
+				image, err = client.ImageService.RetrieveInfo(context.TODO(), exportedImageID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(image.AppClones()).To(gomega.Equal([]int{4}))
+			})
+		})
+
+		ginkgo.Context("when image doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppExportImageUnknownImage
+			})
+
+			ginkgo.It("should return that image doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.ExportImage(context.TODO(),
+					*resources.CreateImageWithID(420), *resources.CreateMarketplaceWithID(marketplaceID), "unknown")
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when image has no ID", func() {
+			ginkgo.It("should return that image has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.ExportImage(context.TODO(), resources.Image{},
+					*resources.CreateMarketplaceWithID(marketplaceID), "unknown")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("import marketplace app", func() {
+		var image *resources.Image
+
+		ginkgo.Context("when marketplace app is ready", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppImport
+			})
+
+			ginkgo.It("should create new image from the marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.MarketplaceAppService.Import(context.TODO(),
+					*resources.CreateMarketplaceAppWithID(existingMarketplaceAppID),
+					*resources.CreateDatastoreWithID(datastoreID), "debian-imported")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(image).ShouldNot(gomega.BeNil())
+				gomega.Expect(image.Name()).To(gomega.Equal("debian-imported"))
+				gomega.Expect(image.Datastore()).To(gomega.Equal(datastoreID))
+				gomega.Expect(image.Size()).To(gomega.Equal(2048))
+				gomega.Expect(image.Path()).To(gomega.Equal("http://market.example.com/0"))
+				gomega.Expect(image.Attribute("TEMPLATE/FROM_APP_NAME")).To(gomega.Equal("debian-golden"))
+			})
+		})
+
+		ginkgo.Context("when marketplace app is disabled", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppImportDisabled
+			})
+
+			ginkgo.It("shouldn't create new image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.MarketplaceAppService.Import(context.TODO(),
+					*resources.CreateMarketplaceAppWithID(disabledMarketplaceAppID),
+					*resources.CreateDatastoreWithID(datastoreID), "centos-imported")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				image, err = client.MarketplaceAppService.Import(context.TODO(), resources.MarketplaceApp{},
+					*resources.CreateDatastoreWithID(datastoreID), "unknown")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(image).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete marketplace app", func() {
+		var (
+			marketplaceApp    *resources.MarketplaceApp
+			oneMarketplaceApp *resources.MarketplaceApp
+		)
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppDelete
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(deletedMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should delete marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Delete(context.TODO(), *marketplaceApp)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether marketplace app was really deleted in OpenNebula
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					deletedMarketplaceAppID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplaceApp).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppDeleteWrongID
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Delete(context.TODO(), *marketplaceApp)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Delete(context.TODO(), resources.MarketplaceApp{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update marketplace app", func() {
+		var (
+			marketplaceApp          *resources.MarketplaceApp
+			marketplaceAppBlueprint *blueprint.MarketplaceAppBlueprint
+			retMarketplaceApp       *resources.MarketplaceApp
+		)
+
+		ginkgo.BeforeEach(func() {
+			marketplaceAppBlueprint = blueprint.CreateUpdateMarketplaceAppBlueprint()
+			if marketplaceAppBlueprint == nil {
+				err = errors.ErrNoMarketplaceAppBlueprint
+				return
+			}
+			marketplaceAppBlueprint.SetDescription("Debian golden image")
+			marketplaceAppBlueprint.SetVersion("1.1")
+		})
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppUpdateMerge
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(existingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should merge data of given marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retMarketplaceApp, err = client.MarketplaceAppService.Update(context.TODO(), *marketplaceApp,
+					marketplaceAppBlueprint, services.Merge)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retMarketplaceApp).ShouldNot(gomega.BeNil())
+				gomega.Expect(retMarketplaceApp.Description()).To(gomega.Equal("Debian golden image"))
+				gomega.Expect(retMarketplaceApp.Version()).To(gomega.Equal("1.1"))
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppUpdateUnknown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retMarketplaceApp, err = client.MarketplaceAppService.Update(context.TODO(), *marketplaceApp,
+					marketplaceAppBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retMarketplaceApp).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retMarketplaceApp, err = client.MarketplaceAppService.Update(context.TODO(),
+					*resources.CreateMarketplaceAppWithID(existingMarketplaceAppID),
+					&blueprint.MarketplaceAppBlueprint{}, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retMarketplaceApp).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app chmod", func() {
+		var (
+			marketplaceApp    *resources.MarketplaceApp
+			oneMarketplaceApp *resources.MarketplaceApp
+			permRequest       requests.PermissionRequest
+		)
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppChmod
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(existingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should change permission of given marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.Group,
+					requests.Use).Allow(requests.Other, requests.Use).Build()
+
+				err = client.MarketplaceAppService.Chmod(context.TODO(), *marketplaceApp, permRequest)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chmod was really changed in OpenNebula
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplaceApp).ShouldNot(gomega.BeNil())
+
+				var perm *resources.Permissions
+				perm, err = oneMarketplaceApp.Permissions()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(perm.Group.Use).To(gomega.Equal(true))
+				gomega.Expect(perm.Other.Use).To(gomega.Equal(true))
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppChmodUnknown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.User,
+					requests.Manage).Build()
+
+				err = client.MarketplaceAppService.Chmod(context.TODO(), *marketplaceApp, permRequest)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Chmod(context.TODO(), resources.MarketplaceApp{},
+					requests.PermissionRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app chown", func() {
+		var (
+			marketplaceApp    *resources.MarketplaceApp
+			oneMarketplaceApp *resources.MarketplaceApp
+			ownershipReq      requests.OwnershipRequest
+		)
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppChown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(existingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should change owner of given marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				userID := 80
+				groupID := 180
+
+				ownershipReq = requests.CreateOwnershipRequestBuilder().User(*resources.CreateUserWithID(userID)).
+					Group(*resources.CreateGroupWithID(groupID)).Build()
+
+				err = client.MarketplaceAppService.Chown(context.TODO(), *marketplaceApp, ownershipReq)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chown was really changed in OpenNebula
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplaceApp).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(oneMarketplaceApp.User()).To(gomega.Equal(userID))
+				gomega.Expect(oneMarketplaceApp.Group()).To(gomega.Equal(groupID))
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppChownUnknown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Chown(context.TODO(), *marketplaceApp,
+					requests.CreateOwnershipRequestBuilder().Build())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Chown(context.TODO(), resources.MarketplaceApp{},
+					requests.OwnershipRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app rename", func() {
+		var (
+			marketplaceApp    *resources.MarketplaceApp
+			oneMarketplaceApp *resources.MarketplaceApp
+		)
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				marketplaceApp = resources.CreateMarketplaceAppWithID(existingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = marketplaceAppRename
+				})
+
+				ginkgo.It("should change name of given marketplace app", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.MarketplaceAppService.Rename(context.TODO(), *marketplaceApp, "debian-10")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+						existingMarketplaceAppID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneMarketplaceApp).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneMarketplaceApp.Name()).To(gomega.Equal("debian-10"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = marketplaceAppRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given marketplace app", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.MarketplaceAppService.Rename(context.TODO(), *marketplaceApp, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppRenameUnknown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Rename(context.TODO(), *marketplaceApp, "app")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Rename(context.TODO(), resources.MarketplaceApp{}, "app")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app enable", func() {
+		var (
+			marketplaceApp    *resources.MarketplaceApp
+			oneMarketplaceApp *resources.MarketplaceApp
+		)
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppEnable
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(existingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should disable and enable given marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Disable(context.TODO(), *marketplaceApp)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether marketplace app was really disabled in OpenNebula
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplaceApp.State()).To(gomega.Equal(resources.MarketplaceAppStateDisabled))
+
+				err = client.MarketplaceAppService.Enable(context.TODO(), *marketplaceApp)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplaceApp.State()).To(gomega.Equal(resources.MarketplaceAppStateReady))
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppEnableUnknown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Enable(context.TODO(), *marketplaceApp)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Disable(context.TODO(), resources.MarketplaceApp{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app lock", func() {
+		var (
+			marketplaceApp    *resources.MarketplaceApp
+			oneMarketplaceApp *resources.MarketplaceApp
+		)
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppLock
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(existingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should lock and unlock given marketplace app", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Lock(context.TODO(), *marketplaceApp, resources.LockUse)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether marketplace app was really locked in OpenNebula
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var lock *resources.Lock
+				lock, err = oneMarketplaceApp.Lock()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lock).ShouldNot(gomega.BeNil())
+				gomega.Expect(lock.Level).To(gomega.Equal(resources.LockUse))
+
+				err = client.MarketplaceAppService.Rename(context.TODO(), *marketplaceApp, "debian-locked")
+				gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+
+				err = client.MarketplaceAppService.Unlock(context.TODO(), *marketplaceApp)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				oneMarketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplaceApp.Lock()).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppLockUnknown
+
+				marketplaceApp = resources.CreateMarketplaceAppWithID(nonExistingMarketplaceAppID)
+				if marketplaceApp == nil {
+					err = errors.ErrNoMarketplaceApp
+				}
+			})
+
+			ginkgo.It("should return that marketplace app with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Lock(context.TODO(), *marketplaceApp, resources.LockUse)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace app has no ID", func() {
+			ginkgo.It("should return that marketplace app has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceAppService.Unlock(context.TODO(), resources.MarketplaceApp{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app retrieve info", func() {
+		var marketplaceApp *resources.MarketplaceApp
+
+		ginkgo.Context("when marketplace app exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppRetrieveInfo
+			})
+
+			ginkgo.It("should return marketplace app with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					existingMarketplaceAppID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).ShouldNot(gomega.BeNil())
+				gomega.Expect(marketplaceApp.ID()).To(gomega.Equal(existingMarketplaceAppID))
+				gomega.Expect(marketplaceApp.Name()).To(gomega.Equal("debian-10"))
+				gomega.Expect(marketplaceApp.MarketplaceName()).To(gomega.Equal("golden"))
+				gomega.Expect(marketplaceApp.Source()).To(gomega.Equal("http://market.example.com/0"))
+			})
+		})
+
+		ginkgo.Context("when marketplace app doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given marketplace app doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApp, err = client.MarketplaceAppService.RetrieveInfo(context.TODO(),
+					nonExistingMarketplaceAppID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApp).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app list all", func() {
+		var marketplaceApps []*resources.MarketplaceApp
+
+		ginkgo.BeforeEach(func() {
+			recName = marketplaceAppListAllAll
+		})
+
+		ginkgo.It("should return list of all marketplace apps with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			marketplaceApps, err = client.MarketplaceAppService.ListAll(context.TODO(), services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(marketplaceApps).To(gomega.HaveLen(4))
+			gomega.Expect(marketplaceApps[0].Name()).To(gomega.Equal("debian-10"))
+			gomega.Expect(marketplaceApps[3].Name()).To(gomega.Equal("debian-11"))
+		})
+	})
+
+	ginkgo.Describe("marketplace app list all for user", func() {
+		var marketplaceApps []*resources.MarketplaceApp
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAppListAllForUser
+			})
+
+			ginkgo.It("should return marketplace apps with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApps, err = client.MarketplaceAppService.ListAllForUser(context.TODO(),
+					*resources.CreateUserWithID(80))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApps).To(gomega.HaveLen(1))
+				gomega.Expect(marketplaceApps[0].ID()).To(gomega.Equal(existingMarketplaceAppID))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplaceApps, err = client.MarketplaceAppService.ListAllForUser(context.TODO(), resources.User{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplaceApps).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace app list with pagination", func() {
+		var marketplaceApps []*resources.MarketplaceApp
+
+		ginkgo.BeforeEach(func() {
+			recName = marketplaceAppListPagination
+		})
+
+		ginkgo.It("should return marketplace apps with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			marketplaceApps, err = client.MarketplaceAppService.List(context.TODO(), 2, 2,
+				services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(marketplaceApps).To(gomega.HaveLen(2))
+			gomega.Expect(marketplaceApps[0].ID()).To(gomega.Equal(3))
+			gomega.Expect(marketplaceApps[1].ID()).To(gomega.Equal(4))
+		})
+	})
+})
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+)
+
+// MarketplaceService structure to manage OpenNebula marketplace.
+type MarketplaceService struct {
+	Service
+}
+
+// Allocate allocates a new marketplace in OpenNebula.
+func (ms *MarketplaceService) Allocate(ctx context.Context,
+	blueprint blueprint.Interface) (*resources.Marketplace, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ms.call(ctx, "one.market.allocate", blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Delete deletes the given marketplace from the pool, only marketplace without apps can be deleted.
+func (ms *MarketplaceService) Delete(ctx context.Context, marketplace resources.Marketplace) error {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ms.call(ctx, "one.market.delete", marketplaceID)
+
+	return err
+}
+
+// Update merges or replaces the marketplace template contents.
+func (ms *MarketplaceService) Update(ctx context.Context, marketplace resources.Marketplace,
+	blueprint blueprint.Interface, updateType UpdateType) (*resources.Marketplace, error) {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := ms.call(ctx, "one.market.update", marketplaceID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Chmod changes the permission bits of a marketplace.
+func (ms *MarketplaceService) Chmod(ctx context.Context, marketplace resources.Marketplace,
+	request requests.PermissionRequest) error {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return err
+	}
+
+	return ms.chmod(ctx, "one.market.chmod", marketplaceID, request)
+}
+
+// Chown changes the ownership of a marketplace.
+func (ms *MarketplaceService) Chown(ctx context.Context, marketplace resources.Marketplace,
+	request requests.OwnershipRequest) error {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return err
+	}
+
+	return ms.chown(ctx, "one.market.chown", marketplaceID, request)
+}
+
+// Rename renames a marketplace.
+func (ms *MarketplaceService) Rename(ctx context.Context, marketplace resources.Marketplace, name string) error {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ms.call(ctx, "one.market.rename", marketplaceID, name)
+
+	return err
+}
+
+func (ms *MarketplaceService) enable(ctx context.Context, marketplace resources.Marketplace, enable bool) error {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = ms.call(ctx, "one.market.enable", marketplaceID, enable)
+
+	return err
+}
+
+// Enable enables a marketplace.
+func (ms *MarketplaceService) Enable(ctx context.Context, marketplace resources.Marketplace) error {
+	return ms.enable(ctx, marketplace, true)
+}
+
+// Disable disables a marketplace, no apps can be exported to disabled marketplace.
+func (ms *MarketplaceService) Disable(ctx context.Context, marketplace resources.Marketplace) error {
+	return ms.enable(ctx, marketplace, false)
+}
+
+// RetrieveInfo retrieves information for the marketplace.
+func (ms *MarketplaceService) RetrieveInfo(ctx context.Context, marketplaceID int) (*resources.Marketplace, error) {
+	doc, err := ms.retrieveInfo(ctx, "one.market.info", marketplaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateMarketplaceFromXML(doc.Root()), nil
+}
+
+// List retrieves information for all of the marketplaces in the pool.
+func (ms *MarketplaceService) List(ctx context.Context) ([]*resources.Marketplace, error) {
+	doc, err := ms.list(ctx, "one.marketpool.info")
+	if err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("MARKETPLACE_POOL/MARKETPLACE")
+
+	marketplaces := make([]*resources.Marketplace, len(elements))
+	for i, e := range elements {
+		marketplaces[i] = resources.CreateMarketplaceFromXML(e)
+	}
+
+	return marketplaces, nil
+}
+
+// Apps retrieves information for all the apps of the marketplace.
+func (ms *MarketplaceService) Apps(ctx context.Context,
+	marketplace resources.Marketplace) ([]*resources.MarketplaceApp, error) {
+	marketplaceID, err := marketplace.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ms.RetrieveInfo(ctx, marketplaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	appIDs, err := info.Apps()
+	if err != nil {
+		return nil, err
+	}
+
+	appService := MarketplaceAppService{Service: ms.Service}
+
+	apps := make([]*resources.MarketplaceApp, len(appIDs))
+	for i, id := range appIDs {
+		apps[i], err = appService.RetrieveInfo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return apps, nil
+}
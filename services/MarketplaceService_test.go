@@ -0,0 +1,691 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	marketplaceAllocate         = "records/onetest/marketplace/allocate"
+	marketplaceAllocateExisting = "records/onetest/marketplace/allocateExisting"
+	marketplaceAllocateNoDriver = "records/onetest/marketplace/allocateNoDriver"
+
+	marketplaceDelete         = "records/onetest/marketplace/delete"
+	marketplaceDeleteNotEmpty = "records/onetest/marketplace/deleteNotEmpty"
+	marketplaceDeleteWrongID  = "records/onetest/marketplace/deleteWrongID"
+
+	marketplaceUpdateMerge   = "records/onetest/marketplace/updateMerge"
+	marketplaceUpdateUnknown = "records/onetest/marketplace/updateUnknown"
+
+	marketplaceChmod        = "records/onetest/marketplace/chmod"
+	marketplaceChmodUnknown = "records/onetest/marketplace/chmodUnknown"
+
+	marketplaceChown        = "records/onetest/marketplace/chown"
+	marketplaceChownUnknown = "records/onetest/marketplace/chownUnknown"
+
+	marketplaceRename        = "records/onetest/marketplace/rename"
+	marketplaceRenameEmpty   = "records/onetest/marketplace/renameEmpty"
+	marketplaceRenameUnknown = "records/onetest/marketplace/renameUnknown"
+
+	marketplaceEnable        = "records/onetest/marketplace/enable"
+	marketplaceEnableUnknown = "records/onetest/marketplace/enableUnknown"
+
+	marketplaceRetrieveInfo        = "records/onetest/marketplace/retrieveInfo"
+	marketplaceRetrieveInfoUnknown = "records/onetest/marketplace/retrieveInfoUnknown"
+
+	marketplaceList = "records/onetest/marketplace/list"
+
+	marketplaceApps = "records/onetest/marketplace/apps"
+)
+
+var _ = ginkgo.Describe("Marketplace Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingMarketplaceID = 100
+	var deletedMarketplaceID = 101
+	var nonExistingMarketplaceID = 420
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate marketplace", func() {
+		var (
+			marketplace          *resources.Marketplace
+			marketplaceBlueprint *blueprint.MarketplaceBlueprint
+		)
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAllocate
+
+				marketplaceBlueprint = blueprint.CreateAllocateMarketplaceBlueprint()
+				marketplaceBlueprint.SetName("private")
+				marketplaceBlueprint.SetMarketMad("http")
+				marketplaceBlueprint.SetBaseURL("http://private.example.com/")
+				marketplaceBlueprint.SetPublicDir("/var/www/private")
+			})
+
+			ginkgo.It("should create new marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplace, err = client.MarketplaceService.Allocate(context.TODO(), marketplaceBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(marketplace).ShouldNot(gomega.BeNil())
+				gomega.Expect(marketplace.ID()).To(gomega.Equal(103))
+				gomega.Expect(marketplace.Name()).To(gomega.Equal("private"))
+				gomega.Expect(marketplace.MarketMad()).To(gomega.Equal("http"))
+				gomega.Expect(marketplace.State()).To(gomega.Equal(resources.MarketplaceStateEnabled))
+				gomega.Expect(marketplace.Apps()).To(gomega.HaveLen(0))
+				gomega.Expect(marketplace.Attribute("TEMPLATE/BASE_URL")).To(
+					gomega.Equal("http://private.example.com/"))
+			})
+		})
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAllocateExisting
+
+				marketplaceBlueprint = blueprint.CreateAllocateMarketplaceBlueprint()
+				marketplaceBlueprint.SetName("golden")
+				marketplaceBlueprint.SetMarketMad("http")
+			})
+
+			ginkgo.It("should return that marketplace already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplace, err = client.MarketplaceService.Allocate(context.TODO(), marketplaceBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplace).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when driver is missing", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceAllocateNoDriver
+
+				marketplaceBlueprint = blueprint.CreateAllocateMarketplaceBlueprint()
+				marketplaceBlueprint.SetName("no-driver")
+			})
+
+			ginkgo.It("shouldn't create new marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplace, err = client.MarketplaceService.Allocate(context.TODO(), marketplaceBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplace).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplace, err = client.MarketplaceService.Allocate(context.TODO(), &blueprint.MarketplaceBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplace).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete marketplace", func() {
+		var (
+			marketplace    *resources.Marketplace
+			oneMarketplace *resources.Marketplace
+		)
+
+		ginkgo.Context("when marketplace is empty", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceDelete
+
+				marketplace = resources.CreateMarketplaceWithID(deletedMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should delete marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Delete(context.TODO(), *marketplace)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether marketplace was really deleted in OpenNebula
+				oneMarketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), deletedMarketplaceID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplace).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when marketplace contains apps", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceDeleteNotEmpty
+
+				marketplace = resources.CreateMarketplaceWithID(existingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace is not empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Delete(context.TODO(), *marketplace)
+				gomega.Expect(errors.IsActionNotAllowed(err)).To(gomega.BeTrue())
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceDeleteWrongID
+
+				marketplace = resources.CreateMarketplaceWithID(nonExistingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Delete(context.TODO(), *marketplace)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("should return that marketplace has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Delete(context.TODO(), resources.Marketplace{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update marketplace", func() {
+		var (
+			marketplace          *resources.Marketplace
+			marketplaceBlueprint *blueprint.MarketplaceBlueprint
+			retMarketplace       *resources.Marketplace
+		)
+
+		ginkgo.BeforeEach(func() {
+			marketplaceBlueprint = blueprint.CreateUpdateMarketplaceBlueprint()
+			if marketplaceBlueprint == nil {
+				err = errors.ErrNoMarketplaceBlueprint
+				return
+			}
+			marketplaceBlueprint.SetBaseURL("https://market.example.com/")
+		})
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceUpdateMerge
+
+				marketplace = resources.CreateMarketplaceWithID(existingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should merge data of given marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retMarketplace, err = client.MarketplaceService.Update(context.TODO(), *marketplace,
+					marketplaceBlueprint, services.Merge)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retMarketplace).ShouldNot(gomega.BeNil())
+				gomega.Expect(retMarketplace.Attribute("TEMPLATE/BASE_URL")).To(
+					gomega.Equal("https://market.example.com/"))
+				gomega.Expect(retMarketplace.Attribute("TEMPLATE/PUBLIC_DIR")).To(gomega.Equal("/var/www/market"))
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceUpdateUnknown
+
+				marketplace = resources.CreateMarketplaceWithID(nonExistingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retMarketplace, err = client.MarketplaceService.Update(context.TODO(), *marketplace,
+					marketplaceBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retMarketplace).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retMarketplace, err = client.MarketplaceService.Update(context.TODO(),
+					*resources.CreateMarketplaceWithID(existingMarketplaceID), &blueprint.MarketplaceBlueprint{},
+					services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retMarketplace).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace chmod", func() {
+		var (
+			marketplace    *resources.Marketplace
+			oneMarketplace *resources.Marketplace
+			permRequest    requests.PermissionRequest
+		)
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceChmod
+
+				marketplace = resources.CreateMarketplaceWithID(existingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should change permission of given marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.Group,
+					requests.Use).Allow(requests.Other, requests.Use).Build()
+
+				err = client.MarketplaceService.Chmod(context.TODO(), *marketplace, permRequest)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chmod was really changed in OpenNebula
+				oneMarketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), existingMarketplaceID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplace).ShouldNot(gomega.BeNil())
+
+				var perm *resources.Permissions
+				perm, err = oneMarketplace.Permissions()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(perm.Group.Use).To(gomega.Equal(true))
+				gomega.Expect(perm.Other.Use).To(gomega.Equal(true))
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceChmodUnknown
+
+				marketplace = resources.CreateMarketplaceWithID(nonExistingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.User,
+					requests.Manage).Build()
+
+				err = client.MarketplaceService.Chmod(context.TODO(), *marketplace, permRequest)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("should return that marketplace has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Chmod(context.TODO(), resources.Marketplace{},
+					requests.PermissionRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace chown", func() {
+		var (
+			marketplace    *resources.Marketplace
+			oneMarketplace *resources.Marketplace
+			ownershipReq   requests.OwnershipRequest
+		)
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceChown
+
+				marketplace = resources.CreateMarketplaceWithID(existingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should change owner of given marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				userID := 80
+				groupID := 180
+
+				ownershipReq = requests.CreateOwnershipRequestBuilder().User(*resources.CreateUserWithID(userID)).
+					Group(*resources.CreateGroupWithID(groupID)).Build()
+
+				err = client.MarketplaceService.Chown(context.TODO(), *marketplace, ownershipReq)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chown was really changed in OpenNebula
+				oneMarketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), existingMarketplaceID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplace).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(oneMarketplace.User()).To(gomega.Equal(userID))
+				gomega.Expect(oneMarketplace.Group()).To(gomega.Equal(groupID))
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceChownUnknown
+
+				marketplace = resources.CreateMarketplaceWithID(nonExistingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Chown(context.TODO(), *marketplace,
+					requests.CreateOwnershipRequestBuilder().Build())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("should return that marketplace has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Chown(context.TODO(), resources.Marketplace{},
+					requests.OwnershipRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace rename", func() {
+		var (
+			marketplace    *resources.Marketplace
+			oneMarketplace *resources.Marketplace
+		)
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				marketplace = resources.CreateMarketplaceWithID(existingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = marketplaceRename
+				})
+
+				ginkgo.It("should change name of given marketplace", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.MarketplaceService.Rename(context.TODO(), *marketplace, "gold")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneMarketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(),
+						existingMarketplaceID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneMarketplace).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneMarketplace.Name()).To(gomega.Equal("gold"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = marketplaceRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given marketplace", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.MarketplaceService.Rename(context.TODO(), *marketplace, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceRenameUnknown
+
+				marketplace = resources.CreateMarketplaceWithID(nonExistingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Rename(context.TODO(), *marketplace, "market")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("should return that marketplace has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Rename(context.TODO(), resources.Marketplace{}, "market")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace enable", func() {
+		var (
+			marketplace    *resources.Marketplace
+			oneMarketplace *resources.Marketplace
+		)
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceEnable
+
+				marketplace = resources.CreateMarketplaceWithID(existingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should disable and enable given marketplace", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Disable(context.TODO(), *marketplace)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether marketplace was really disabled in OpenNebula
+				oneMarketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), existingMarketplaceID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplace.State()).To(gomega.Equal(resources.MarketplaceStateDisabled))
+
+				err = client.MarketplaceService.Enable(context.TODO(), *marketplace)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				oneMarketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), existingMarketplaceID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneMarketplace.State()).To(gomega.Equal(resources.MarketplaceStateEnabled))
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceEnableUnknown
+
+				marketplace = resources.CreateMarketplaceWithID(nonExistingMarketplaceID)
+				if marketplace == nil {
+					err = errors.ErrNoMarketplace
+				}
+			})
+
+			ginkgo.It("should return that marketplace with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Enable(context.TODO(), *marketplace)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("should return that marketplace has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.MarketplaceService.Disable(context.TODO(), resources.Marketplace{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace retrieve info", func() {
+		var marketplace *resources.Marketplace
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceRetrieveInfo
+			})
+
+			ginkgo.It("should return marketplace with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), existingMarketplaceID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(marketplace).ShouldNot(gomega.BeNil())
+				gomega.Expect(marketplace.ID()).To(gomega.Equal(existingMarketplaceID))
+				gomega.Expect(marketplace.Name()).To(gomega.Equal("gold"))
+				gomega.Expect(marketplace.Apps()).To(gomega.Equal([]int{0, 1, 2, 3, 4}))
+				gomega.Expect(marketplace.UsedMB()).To(gomega.Equal(5 * 2048))
+			})
+		})
+
+		ginkgo.Context("when marketplace doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given marketplace doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				marketplace, err = client.MarketplaceService.RetrieveInfo(context.TODO(), nonExistingMarketplaceID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(marketplace).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("marketplace list", func() {
+		var marketplaces []*resources.Marketplace
+
+		ginkgo.BeforeEach(func() {
+			recName = marketplaceList
+		})
+
+		ginkgo.It("should return list of all marketplaces with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			marketplaces, err = client.MarketplaceService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(marketplaces).To(gomega.HaveLen(3))
+			gomega.Expect(marketplaces[0].Name()).To(gomega.Equal("gold"))
+			gomega.Expect(marketplaces[1].Name()).To(gomega.Equal("disabled"))
+			gomega.Expect(marketplaces[2].Name()).To(gomega.Equal("private"))
+		})
+	})
+
+	ginkgo.Describe("marketplace apps", func() {
+		var apps []*resources.MarketplaceApp
+
+		ginkgo.Context("when marketplace exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = marketplaceApps
+			})
+
+			ginkgo.It("should return apps of given marketplace with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				apps, err = client.MarketplaceService.Apps(context.TODO(),
+					*resources.CreateMarketplaceWithID(existingMarketplaceID))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(apps).To(gomega.HaveLen(5))
+				gomega.Expect(apps[0].Name()).To(gomega.Equal("debian-golden"))
+				gomega.Expect(apps[0].MarketplaceName()).To(gomega.Equal("gold"))
+			})
+		})
+
+		ginkgo.Context("when marketplace has no ID", func() {
+			ginkgo.It("should return that marketplace has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				apps, err = client.MarketplaceService.Apps(context.TODO(), resources.Marketplace{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(apps).Should(gomega.BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/onego-project/onego/resources"
+)
+
+// PoolQuery describes a search in a pool of resources. Ownership and virtual machine state are filtered
+// by OpenNebula, all the other conditions are evaluated locally on the retrieved resources.
+type PoolQuery struct {
+	ownership  int
+	state      StateFilter
+	conditions []func(r *resources.Resource) bool
+	err        error
+}
+
+// Query creates a query matching all the resources in the pool (except virtual machines in DONE state).
+func Query() *PoolQuery {
+	return &PoolQuery{ownership: int(OwnershipFilterAll), state: AnyStateExceptDone}
+}
+
+// Err returns the first error which occurred while building the query.
+func (q *PoolQuery) Err() error {
+	return q.err
+}
+
+func (q *PoolQuery) where(condition func(r *resources.Resource) bool) *PoolQuery {
+	q.conditions = append(q.conditions, condition)
+	return q
+}
+
+func (q *PoolQuery) id(object interface{ ID() (int, error) }) (int, bool) {
+	id, err := object.ID()
+	if err != nil {
+		if q.err == nil {
+			q.err = err
+		}
+		return 0, false
+	}
+
+	return id, true
+}
+
+// OwnedBy restricts the query to the resources belonging to given owner(s). Filtered by OpenNebula.
+func (q *PoolQuery) OwnedBy(filter OwnershipFilter) *PoolQuery {
+	q.ownership = int(filter)
+	return q
+}
+
+// OwnedByUser restricts the query to the resources belonging to given user. Filtered by OpenNebula.
+func (q *PoolQuery) OwnedByUser(user resources.User) *PoolQuery {
+	if userID, ok := q.id(&user); ok {
+		q.ownership = userID
+	}
+
+	return q
+}
+
+// InState restricts the query to the virtual machines in given state. Filtered by OpenNebula,
+// it has no effect on other resources.
+func (q *PoolQuery) InState(state StateFilter) *PoolQuery {
+	q.state = state
+	return q
+}
+
+// NameMatches restricts the query to the resources with name matching given shell pattern, e.g. "web-*".
+// The syntax of the pattern is the same as in path.Match.
+func (q *PoolQuery) NameMatches(pattern string) *PoolQuery {
+	if _, err := path.Match(pattern, ""); err != nil {
+		if q.err == nil {
+			q.err = err
+		}
+		return q
+	}
+
+	return q.where(func(r *resources.Resource) bool {
+		name, err := r.Attribute("NAME")
+		if err != nil {
+			return false
+		}
+
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// OnHost restricts the query to the virtual machines whose last history record belongs to given host.
+func (q *PoolQuery) OnHost(host resources.Host) *PoolQuery {
+	hostID, ok := q.id(&host)
+	if !ok {
+		return q
+	}
+
+	return q.where(func(r *resources.Resource) bool {
+		return intAttributeEquals(r, "HISTORY_RECORDS/HISTORY[-1]/HID", hostID)
+	})
+}
+
+// InCluster restricts the query to the resources in given cluster. Virtual machines are matched
+// by their last history record, other resources by their CLUSTER_ID or CLUSTERS elements.
+func (q *PoolQuery) InCluster(cluster resources.Cluster) *PoolQuery {
+	clusterID, ok := q.id(&cluster)
+	if !ok {
+		return q
+	}
+
+	return q.where(func(r *resources.Resource) bool {
+		if intAttributeEquals(r, "HISTORY_RECORDS/HISTORY[-1]/CID", clusterID) ||
+			intAttributeEquals(r, "CLUSTER_ID", clusterID) {
+			return true
+		}
+
+		for _, e := range r.XMLData.FindElements("CLUSTERS/ID") {
+			if id, err := strconv.Atoi(e.Text()); err == nil && id == clusterID {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// LabelEquals restricts the query to the resources with given attribute in user template
+// (or in template for resources without user template) set to given value.
+func (q *PoolQuery) LabelEquals(key, value string) *PoolQuery {
+	return q.where(func(r *resources.Resource) bool {
+		actual, err := r.Attribute("USER_TEMPLATE/" + key)
+		if err != nil {
+			actual, err = r.Attribute("TEMPLATE/" + key)
+		}
+
+		return err == nil && actual == value
+	})
+}
+
+// HasLabel restricts the query to the resources tagged with given label (in the comma separated
+// LABELS attribute used by Sunstone).
+func (q *PoolQuery) HasLabel(label string) *PoolQuery {
+	return q.where(func(r *resources.Resource) bool {
+		labels, err := r.Attribute("USER_TEMPLATE/LABELS")
+		if err != nil {
+			labels, err = r.Attribute("TEMPLATE/LABELS")
+		}
+		if err != nil {
+			return false
+		}
+
+		for _, l := range strings.Split(labels, ",") {
+			if strings.TrimSpace(l) == label {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Where restricts the query to the resources satisfying given condition.
+func (q *PoolQuery) Where(condition func(r *resources.Resource) bool) *PoolQuery {
+	return q.where(condition)
+}
+
+// Matches checks whether given resource satisfies all the locally evaluated conditions of the query.
+func (q *PoolQuery) Matches(r *resources.Resource) bool {
+	for _, condition := range q.conditions {
+		if !condition(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func intAttributeEquals(r *resources.Resource, path string, value int) bool {
+	text, err := r.Attribute(path)
+	if err != nil {
+		return false
+	}
+
+	actual, err := strconv.Atoi(text)
+	return err == nil && actual == value
+}
@@ -0,0 +1,178 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	virtualMachineFind = "records/onetest/virtualMachine/find"
+	templateFind       = "records/onetest/template/find"
+	virtualNetworkFind = "records/onetest/virtualNetwork/find"
+)
+
+var _ = ginkgo.Describe("Query", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("find virtual machines", func() {
+		var virtualMachines []*resources.VirtualMachine
+
+		ginkgo.BeforeEach(func() {
+			recName = virtualMachineFind
+		})
+
+		ginkgo.It("should find virtual machines matching all the conditions", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			query := services.Query().NameMatches("web-*").OnHost(*resources.CreateHostWithID(20)).
+				InCluster(*resources.CreateClusterWithID(0)).LabelEquals("ENV", "prod")
+
+			virtualMachines, err = client.VirtualMachineService.Find(context.TODO(), query)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualMachines).To(gomega.HaveLen(1))
+			gomega.Expect(virtualMachines[0].ID()).To(gomega.Equal(220))
+
+			query = services.Query().InCluster(*resources.CreateClusterWithID(120))
+
+			virtualMachines, err = client.VirtualMachineService.Find(context.TODO(), query)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualMachines).To(gomega.HaveLen(1))
+			gomega.Expect(virtualMachines[0].ID()).To(gomega.Equal(222))
+
+			query = services.Query().InState(services.Pending).NameMatches("db-*")
+
+			virtualMachines, err = client.VirtualMachineService.Find(context.TODO(), query)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualMachines).NotTo(gomega.BeNil())
+			gomega.Expect(virtualMachines).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should fail with wrong name pattern", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			query := services.Query().NameMatches("web-[")
+			gomega.Expect(query.Err()).To(gomega.Equal(path.ErrBadPattern))
+
+			virtualMachines, err = client.VirtualMachineService.Find(context.TODO(), query)
+			gomega.Expect(err).To(gomega.Equal(path.ErrBadPattern))
+			gomega.Expect(virtualMachines).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should fail with host without ID", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			virtualMachines, err = client.VirtualMachineService.Find(context.TODO(),
+				services.Query().OnHost(resources.Host{}))
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(virtualMachines).To(gomega.BeNil())
+		})
+	})
+
+	ginkgo.Describe("find templates", func() {
+		var templates []*resources.Template
+
+		ginkgo.BeforeEach(func() {
+			recName = templateFind
+		})
+
+		ginkgo.It("should find templates with label", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			templates, err = client.TemplateService.Find(context.TODO(), services.Query().HasLabel("web"))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(templates).To(gomega.HaveLen(1))
+			gomega.Expect(templates[0].ID()).To(gomega.Equal(220))
+
+			templates, err = client.TemplateService.Find(context.TODO(), services.Query().NameMatches("web-*"))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(templates).To(gomega.HaveLen(2))
+			gomega.Expect(templates[0].ID()).To(gomega.Equal(220))
+			gomega.Expect(templates[1].ID()).To(gomega.Equal(222))
+		})
+	})
+
+	ginkgo.Describe("find virtual networks", func() {
+		var virtualNetworks []*resources.VirtualNetwork
+
+		ginkgo.BeforeEach(func() {
+			recName = virtualNetworkFind
+		})
+
+		ginkgo.It("should find virtual networks in cluster", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			virtualNetworks, err = client.VirtualNetworkService.Find(context.TODO(),
+				services.Query().InCluster(*resources.CreateClusterWithID(120)))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualNetworks).To(gomega.HaveLen(1))
+			gomega.Expect(virtualNetworks[0].ID()).To(gomega.Equal(220))
+		})
+	})
+
+	ginkgo.Describe("match resources", func() {
+		ginkgo.BeforeEach(func() {
+			recName = virtualMachineFind
+		})
+
+		ginkgo.It("should evaluate custom conditions", func() {
+			vm := resources.CreateVirtualMachineWithID(42)
+
+			gomega.Expect(services.Query().Matches(&vm.Resource)).To(gomega.BeTrue())
+			gomega.Expect(services.Query().NameMatches("*").Matches(&vm.Resource)).To(gomega.BeFalse())
+			gomega.Expect(services.Query().Where(func(r *resources.Resource) bool {
+				id, _ := r.Attribute("ID")
+				return id == "42"
+			}).Matches(&vm.Resource)).To(gomega.BeTrue())
+		})
+	})
+})
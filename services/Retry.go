@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/xmlrpc"
+)
+
+// RetryClassifier decides whether the failed call of the XML-RPC method is made again.
+type RetryClassifier func(methodName string, err error) bool
+
+// RetryPolicy structure defines how failed XML-RPC calls are retried. The call is made at most MaxAttempts
+// times, delays between the attempts are defined by Backoff and randomized by Jitter, e.g. Jitter 0.2
+// changes each delay by up to 20 % in both directions. RetryOn decides which failures are retried,
+// RetryIdempotent is used when it is nil.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     Backoff
+	Jitter      float64
+	RetryOn     RetryClassifier
+}
+
+// DefaultRetryPolicy retries transient failures of idempotent calls.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3,
+	Backoff: Backoff{InitialDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second, Multiplier: 2},
+	Jitter:  0.2, RetryOn: RetryIdempotent}
+
+// idempotentActions are the last parts of names of the XML-RPC methods which don't change anything
+// in OpenNebula, e.g. one.vm.info or one.vmpool.accounting.
+var idempotentActions = map[string]bool{
+	"info":       true,
+	"monitoring": true,
+	"accounting": true,
+	"showback":   true,
+	"version":    true,
+	"config":     true,
+	"raftstatus": true,
+}
+
+// RetryIdempotent retries transient failures (see errors.IsTransient) of the calls which only retrieve
+// information, e.g. *.info methods and pool listings.
+func RetryIdempotent(methodName string, err error) bool {
+	action := methodName[strings.LastIndex(methodName, ".")+1:]
+	return idempotentActions[action] && errors.IsTransient(err)
+}
+
+// RetryTransient retries transient failures of all the calls including the ones which change resources.
+// A call which failed on the way back may be applied twice, e.g. the second allocate fails because
+// the name is already taken.
+func RetryTransient(methodName string, err error) bool {
+	return errors.IsTransient(err)
+}
+
+// do makes the call until it succeeds, the failure is not retried or the attempts are exhausted.
+// The error of the last attempt is returned, context error is returned when the context is done
+// while waiting for the next attempt.
+func (rp *RetryPolicy) do(ctx context.Context, methodName string,
+	call func() ([]*xmlrpc.Result, error)) ([]*xmlrpc.Result, error) {
+	retryOn := rp.RetryOn
+	if retryOn == nil {
+		retryOn = RetryIdempotent
+	}
+
+	backoff := rp.Backoff.withDefaults()
+	delay := backoff.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		resArr, err := call()
+		if err == nil || attempt >= rp.MaxAttempts || ctx.Err() != nil || !retryOn(methodName, err) {
+			return resArr, err
+		}
+
+		if err = sleep(ctx, rp.jitter(delay)); err != nil {
+			return nil, err
+		}
+
+		delay = backoff.next(delay)
+	}
+}
+
+func (rp *RetryPolicy) jitter(delay time.Duration) time.Duration {
+	if rp.Jitter <= 0 {
+		return delay
+	}
+
+	return delay + time.Duration(float64(delay)*rp.Jitter*(2*rand.Float64()-1)) //nolint
+}
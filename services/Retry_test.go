@@ -0,0 +1,247 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	retryInfo          = "records/onetest/retry/info"
+	retryInfoExhausted = "records/retry/infoExhausted"
+	retryInfoWrongID   = "records/onetest/retry/infoWrongID"
+	retryRename        = "records/retry/rename"
+	retryRenameOptIn   = "records/onetest/retry/renameOptIn"
+)
+
+// flakyTransport responds with 503 Service Unavailable to the first failures requests.
+type flakyTransport struct {
+	failures int
+	attempts int
+	next     http.RoundTripper
+}
+
+func (ft *flakyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ft.attempts++
+	if ft.attempts <= ft.failures {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable",
+			Body: ioutil.NopCloser(strings.NewReader("")), Request: r}, nil
+	}
+
+	return ft.next.RoundTrip(r)
+}
+
+var _ = ginkgo.Describe("Retry", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+
+		flaky  *flakyTransport
+		policy services.RetryPolicy
+	)
+
+	ginkgo.BeforeEach(func() {
+		flaky = &flakyTransport{}
+		policy = services.RetryPolicy{MaxAttempts: 3,
+			Backoff: services.Backoff{InitialDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond, Multiplier: 2},
+			Jitter:  0.5}
+	})
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		flaky.next = rec
+		clientHTTP := &http.Client{
+			Transport: flaky, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+		client.VirtualMachineService.RPC.Retry = &policy
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("idempotent call", func() {
+		var vm *resources.VirtualMachine
+
+		ginkgo.Context("when call fails less times than the maximal number of attempts", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryInfo
+				flaky.failures = 2
+			})
+
+			ginkgo.It("should retry the call until it succeeds", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vm, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 310)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vm.Name()).To(gomega.Equal("flaky"))
+				gomega.Expect(flaky.attempts).To(gomega.Equal(3))
+			})
+		})
+
+		ginkgo.Context("when call fails in all attempts", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryInfoExhausted
+				flaky.failures = 3
+			})
+
+			ginkgo.It("should return error of the last attempt", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vm, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 310)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsTransient(err)).To(gomega.BeTrue())
+				gomega.Expect(vm).To(gomega.BeNil())
+				gomega.Expect(flaky.attempts).To(gomega.Equal(3))
+			})
+		})
+
+		ginkgo.Context("when OpenNebula returns an error which is not transient", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryInfoWrongID
+			})
+
+			ginkgo.It("should return the error without retrying", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vm, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 9999)
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(vm).To(gomega.BeNil())
+				gomega.Expect(flaky.attempts).To(gomega.Equal(1))
+			})
+		})
+
+		ginkgo.Context("when context is done while waiting for the next attempt", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryInfoExhausted
+				flaky.failures = 3
+				policy.Backoff.InitialDelay = time.Minute
+			})
+
+			ginkgo.It("should return context error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+				defer cancel()
+
+				vm, err = client.VirtualMachineService.RetrieveInfo(ctx, 310)
+				gomega.Expect(err).To(gomega.MatchError(context.DeadlineExceeded))
+				gomega.Expect(vm).To(gomega.BeNil())
+				gomega.Expect(flaky.attempts).To(gomega.Equal(1))
+			})
+		})
+
+		ginkgo.Context("when backoff is zero value", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryInfoExhausted
+				flaky.failures = 3
+				policy.Backoff = services.Backoff{}
+			})
+
+			ginkgo.It("should wait with default delays", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+				defer cancel()
+
+				vm, err = client.VirtualMachineService.RetrieveInfo(ctx, 310)
+				gomega.Expect(err).To(gomega.MatchError(context.DeadlineExceeded))
+				gomega.Expect(vm).To(gomega.BeNil())
+				gomega.Expect(flaky.attempts).To(gomega.Equal(1))
+			})
+		})
+	})
+
+	ginkgo.Describe("mutating call", func() {
+		ginkgo.Context("when retry classifier is default", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryRename
+				flaky.failures = 1
+			})
+
+			ginkgo.It("should not retry the call", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualMachineService.Rename(context.TODO(), *resources.CreateVirtualMachineWithID(310),
+					"steady")
+				gomega.Expect(errors.IsTransient(err)).To(gomega.BeTrue())
+				gomega.Expect(flaky.attempts).To(gomega.Equal(1))
+			})
+		})
+
+		ginkgo.Context("when mutating calls are retried", func() {
+			ginkgo.BeforeEach(func() {
+				recName = retryRenameOptIn
+				flaky.failures = 1
+				policy.RetryOn = services.RetryTransient
+			})
+
+			ginkgo.It("should retry the call until it succeeds", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualMachineService.Rename(context.TODO(), *resources.CreateVirtualMachineWithID(310),
+					"steady")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(flaky.attempts).To(gomega.Equal(2))
+			})
+		})
+	})
+
+	ginkgo.Describe("classifiers", func() {
+		ginkgo.It("should retry only transient failures of idempotent calls by default", func() {
+			transient := &errors.CallError{Method: "one.vm.info", Err: errors.ErrNoClient}
+
+			gomega.Expect(services.RetryIdempotent("one.vm.info", transient)).To(gomega.BeTrue())
+			gomega.Expect(services.RetryIdempotent("one.vmpool.accounting", transient)).To(gomega.BeTrue())
+			gomega.Expect(services.RetryIdempotent("one.vm.action", transient)).To(gomega.BeFalse())
+			gomega.Expect(services.RetryIdempotent("one.vmpool.calculateshowback", transient)).To(gomega.BeFalse())
+			gomega.Expect(services.RetryIdempotent("one.vm.info", &errors.OpenNebulaError{
+				Code: errors.CodeNoExists})).To(gomega.BeFalse())
+			gomega.Expect(services.RetryIdempotent("one.vm.info", &errors.OpenNebulaError{
+				Code: errors.CodeInternal})).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should retry transient failures of all calls when opted in", func() {
+			gomega.Expect(services.RetryTransient("one.vm.action", &errors.CallError{Method: "one.vm.action",
+				Err: errors.ErrNoClient})).To(gomega.BeTrue())
+			gomega.Expect(services.RetryTransient("one.vm.action", &errors.OpenNebulaError{
+				Code: errors.CodeAction})).To(gomega.BeFalse())
+		})
+	})
+})
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+)
+
+// SecurityGroupService structure to manage OpenNebula security group.
+type SecurityGroupService struct {
+	Service
+}
+
+// Allocate allocates a new security group in OpenNebula.
+func (sgs *SecurityGroupService) Allocate(ctx context.Context,
+	blueprint blueprint.Interface) (*resources.SecurityGroup, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := sgs.call(ctx, "one.secgroup.allocate", blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return sgs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Clone clones an existing security group.
+func (sgs *SecurityGroupService) Clone(ctx context.Context, securityGroup resources.SecurityGroup,
+	name string) (*resources.SecurityGroup, error) {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := sgs.call(ctx, "one.secgroup.clone", securityGroupID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return sgs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Delete deletes the given security group from the pool.
+func (sgs *SecurityGroupService) Delete(ctx context.Context, securityGroup resources.SecurityGroup) error {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = sgs.call(ctx, "one.secgroup.delete", securityGroupID)
+
+	return err
+}
+
+// Update merges or replaces the security group template contents.
+// The rules are not propagated to the virtual machines until Commit is called.
+func (sgs *SecurityGroupService) Update(ctx context.Context, securityGroup resources.SecurityGroup,
+	blueprint blueprint.Interface, updateType UpdateType) (*resources.SecurityGroup, error) {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := sgs.call(ctx, "one.secgroup.update", securityGroupID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return sgs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Commit commits security group changes to the associated virtual machines.
+// If recovery is set, only the virtual machines in updating or error state are updated,
+// otherwise all the outdated virtual machines are updated.
+func (sgs *SecurityGroupService) Commit(ctx context.Context, securityGroup resources.SecurityGroup,
+	recovery bool) error {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = sgs.call(ctx, "one.secgroup.commit", securityGroupID, recovery)
+
+	return err
+}
+
+// Chmod changes the permission bits of a security group.
+func (sgs *SecurityGroupService) Chmod(ctx context.Context, securityGroup resources.SecurityGroup,
+	request requests.PermissionRequest) error {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	return sgs.chmod(ctx, "one.secgroup.chmod", securityGroupID, request)
+}
+
+// Chown changes the ownership of a security group.
+func (sgs *SecurityGroupService) Chown(ctx context.Context, securityGroup resources.SecurityGroup,
+	request requests.OwnershipRequest) error {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	return sgs.chown(ctx, "one.secgroup.chown", securityGroupID, request)
+}
+
+// Rename renames a security group.
+func (sgs *SecurityGroupService) Rename(ctx context.Context, securityGroup resources.SecurityGroup,
+	name string) error {
+	securityGroupID, err := securityGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = sgs.call(ctx, "one.secgroup.rename", securityGroupID, name)
+
+	return err
+}
+
+// RetrieveInfo retrieves information for the security group.
+func (sgs *SecurityGroupService) RetrieveInfo(ctx context.Context,
+	securityGroupID int) (*resources.SecurityGroup, error) {
+	doc, err := sgs.retrieveInfo(ctx, "one.secgroup.info", securityGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateSecurityGroupFromXML(doc.Root()), nil
+}
+
+func (sgs *SecurityGroupService) list(ctx context.Context, filterFlag, pageOffset,
+	pageSize int) ([]*resources.SecurityGroup, error) {
+	resArr, err := sgs.call(ctx, "one.secgrouppool.info", filterFlag, pageOffset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("SECURITY_GROUP_POOL/SECURITY_GROUP")
+
+	securityGroups := make([]*resources.SecurityGroup, len(elements))
+	for i, e := range elements {
+		securityGroups[i] = resources.CreateSecurityGroupFromXML(e)
+	}
+
+	return securityGroups, nil
+}
+
+// ListAll retrieves information for all the security groups in the pool.
+func (sgs *SecurityGroupService) ListAll(ctx context.Context,
+	filter OwnershipFilter) ([]*resources.SecurityGroup, error) {
+	return sgs.list(ctx, int(filter), pageOffsetDefault, pageSizeDefault)
+}
+
+// ListAllForUser retrieves information for all the security groups for the given user in the pool.
+func (sgs *SecurityGroupService) ListAllForUser(ctx context.Context,
+	user resources.User) ([]*resources.SecurityGroup, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return sgs.list(ctx, userID, pageOffsetDefault, pageSizeDefault)
+}
+
+// List retrieves information for a part of the security groups in the pool with a given pagination.
+func (sgs *SecurityGroupService) List(ctx context.Context, pageOffset, pageSize int,
+	filter OwnershipFilter) ([]*resources.SecurityGroup, error) {
+	return sgs.list(ctx, int(filter), (pageOffset-1)*pageSize, -pageSize)
+}
+
+// ListForUser retrieves information for a part of the security groups for given user in the pool
+// with a given pagination.
+func (sgs *SecurityGroupService) ListForUser(ctx context.Context, user resources.User, pageOffset,
+	pageSize int) ([]*resources.SecurityGroup, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return sgs.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
+}
@@ -0,0 +1,845 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/onego-project/onego/services"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	securityGroupAllocate         = "records/onetest/securityGroup/allocate"
+	securityGroupAllocateExisting = "records/onetest/securityGroup/allocateExisting"
+
+	securityGroupClone         = "records/onetest/securityGroup/clone"
+	securityGroupCloneExisting = "records/onetest/securityGroup/cloneExisting"
+
+	securityGroupDelete        = "records/onetest/securityGroup/delete"
+	securityGroupDeleteWrongID = "records/onetest/securityGroup/deleteWrongID"
+
+	securityGroupUpdateMerge   = "records/onetest/securityGroup/updateMerge"
+	securityGroupUpdateReplace = "records/onetest/securityGroup/updateReplace"
+	securityGroupUpdateUnknown = "records/onetest/securityGroup/updateUnknown"
+
+	securityGroupCommit        = "records/onetest/securityGroup/commit"
+	securityGroupCommitUnknown = "records/onetest/securityGroup/commitUnknown"
+
+	securityGroupChmod        = "records/onetest/securityGroup/chmod"
+	securityGroupChmodUnknown = "records/onetest/securityGroup/chmodUnknown"
+
+	securityGroupChown        = "records/onetest/securityGroup/chown"
+	securityGroupChownUnknown = "records/onetest/securityGroup/chownUnknown"
+
+	securityGroupRename        = "records/onetest/securityGroup/rename"
+	securityGroupRenameEmpty   = "records/onetest/securityGroup/renameEmpty"
+	securityGroupRenameUnknown = "records/onetest/securityGroup/renameUnknown"
+
+	securityGroupRetrieveInfo        = "records/onetest/securityGroup/retrieveInfo"
+	securityGroupRetrieveInfoUnknown = "records/onetest/securityGroup/retrieveInfoUnknown"
+
+	securityGroupListAllAll  = "records/onetest/securityGroup/listAllAll"
+	securityGroupListAllUser = "records/onetest/securityGroup/listAllUser"
+
+	securityGroupListAllForUser        = "records/onetest/securityGroup/listAllForUser"
+	securityGroupListAllForUserUnknown = "records/onetest/securityGroup/listAllForUserUnknown"
+
+	securityGroupListPagination = "records/onetest/securityGroup/listPagination"
+
+	securityGroupListForUser = "records/onetest/securityGroup/listForUser"
+)
+
+var _ = ginkgo.Describe("Security Group Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingSecurityGroupID = 100
+	var deletedSecurityGroupID = 101
+	var nonExistingSecurityGroupID = 420
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate security group", func() {
+		var (
+			securityGroup          *resources.SecurityGroup
+			securityGroupBlueprint *blueprint.SecurityGroupBlueprint
+			ruleBlueprint          *blueprint.SecurityGroupRuleBlueprint
+		)
+
+		ginkgo.BeforeEach(func() {
+			ruleBlueprint = blueprint.CreateSecurityGroupRuleBlueprint()
+			ruleBlueprint.SetProtocol(resources.SecurityGroupProtocolTCP)
+			ruleBlueprint.SetRuleType(resources.SecurityGroupRuleTypeInbound)
+			ruleBlueprint.SetRange("22")
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupAllocate
+
+				securityGroupBlueprint = blueprint.CreateAllocateSecurityGroupBlueprint()
+				securityGroupBlueprint.SetName("ssh-only")
+				securityGroupBlueprint.SetDescription("SSH access")
+				securityGroupBlueprint.SetRule(*ruleBlueprint)
+			})
+
+			ginkgo.It("should create new security group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroup, err = client.SecurityGroupService.Allocate(context.TODO(), securityGroupBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroup).ShouldNot(gomega.BeNil())
+				gomega.Expect(securityGroup.Name()).To(gomega.Equal("ssh-only"))
+				gomega.Expect(securityGroup.Description()).To(gomega.Equal("SSH access"))
+
+				var rules []*resources.SecurityGroupRule
+				rules, err = securityGroup.Rules()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(rules).To(gomega.HaveLen(1))
+				gomega.Expect(rules[0].Protocol).To(gomega.Equal(resources.SecurityGroupProtocolTCP))
+				gomega.Expect(rules[0].RuleType).To(gomega.Equal(resources.SecurityGroupRuleTypeInbound))
+				gomega.Expect(rules[0].Range).To(gomega.Equal("22"))
+			})
+		})
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupAllocateExisting
+
+				securityGroupBlueprint = blueprint.CreateAllocateSecurityGroupBlueprint()
+				securityGroupBlueprint.SetName("web")
+			})
+
+			ginkgo.It("should return that security group already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroup, err = client.SecurityGroupService.Allocate(context.TODO(), securityGroupBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(securityGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new security group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroup, err = client.SecurityGroupService.Allocate(context.TODO(),
+					&blueprint.SecurityGroupBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(securityGroup).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("clone security group", func() {
+		var (
+			securityGroup *resources.SecurityGroup
+			clone         *resources.SecurityGroup
+		)
+
+		ginkgo.BeforeEach(func() {
+			securityGroup = resources.CreateSecurityGroupWithID(existingSecurityGroupID)
+			if securityGroup == nil {
+				err = errors.ErrNoSecurityGroup
+			}
+		})
+
+		ginkgo.Context("when clone name is not taken", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupClone
+			})
+
+			ginkgo.It("should create new security group clone", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				clone, err = client.SecurityGroupService.Clone(context.TODO(), *securityGroup, "web-clone")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(clone).ShouldNot(gomega.BeNil())
+				gomega.Expect(clone.ID()).NotTo(gomega.Equal(existingSecurityGroupID))
+				gomega.Expect(clone.Name()).To(gomega.Equal("web-clone"))
+				gomega.Expect(clone.Rules()).To(gomega.HaveLen(2))
+			})
+		})
+
+		ginkgo.Context("when clone name is taken", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupCloneExisting
+			})
+
+			ginkgo.It("should return that security group already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				clone, err = client.SecurityGroupService.Clone(context.TODO(), *securityGroup, "monitoring")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(clone).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				clone, err = client.SecurityGroupService.Clone(context.TODO(), resources.SecurityGroup{}, "clone")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(clone).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete security group", func() {
+		var (
+			securityGroup    *resources.SecurityGroup
+			oneSecurityGroup *resources.SecurityGroup
+		)
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupDelete
+
+				securityGroup = resources.CreateSecurityGroupWithID(deletedSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should delete security group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Delete(context.TODO(), *securityGroup)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether security group was really deleted in OpenNebula
+				oneSecurityGroup, err = client.SecurityGroupService.RetrieveInfo(context.TODO(),
+					deletedSecurityGroupID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneSecurityGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupDeleteWrongID
+
+				securityGroup = resources.CreateSecurityGroupWithID(nonExistingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should return that security group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Delete(context.TODO(), *securityGroup)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Delete(context.TODO(), resources.SecurityGroup{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update security group", func() {
+		var (
+			securityGroup          *resources.SecurityGroup
+			securityGroupBlueprint *blueprint.SecurityGroupBlueprint
+			retSecurityGroup       *resources.SecurityGroup
+		)
+
+		ginkgo.BeforeEach(func() {
+			securityGroupBlueprint = blueprint.CreateUpdateSecurityGroupBlueprint()
+			if securityGroupBlueprint == nil {
+				err = errors.ErrNoSecurityGroupBlueprint
+				return
+			}
+			securityGroupBlueprint.SetDescription("dummy")
+		})
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				securityGroup = resources.CreateSecurityGroupWithID(existingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.When("when merge data of given security group", func() {
+				ginkgo.BeforeEach(func() {
+					recName = securityGroupUpdateMerge
+				})
+
+				ginkgo.It("should merge data of given security group", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					retSecurityGroup, err = client.SecurityGroupService.Update(context.TODO(), *securityGroup,
+						securityGroupBlueprint, services.Merge)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(retSecurityGroup).ShouldNot(gomega.BeNil())
+					gomega.Expect(retSecurityGroup.Description()).To(gomega.Equal("dummy"))
+					gomega.Expect(retSecurityGroup.Rules()).To(gomega.HaveLen(2))
+				})
+			})
+
+			ginkgo.When("when replace data of given security group", func() {
+				ginkgo.BeforeEach(func() {
+					recName = securityGroupUpdateReplace
+
+					ruleBlueprint := blueprint.CreateSecurityGroupRuleBlueprint()
+					ruleBlueprint.SetProtocol(resources.SecurityGroupProtocolUDP)
+					ruleBlueprint.SetRuleType(resources.SecurityGroupRuleTypeOutbound)
+					ruleBlueprint.SetIP("10.0.0.1")
+					ruleBlueprint.SetSize(16)
+					securityGroupBlueprint.SetRule(*ruleBlueprint)
+				})
+
+				ginkgo.It("should replace data of given security group", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					retSecurityGroup, err = client.SecurityGroupService.Update(context.TODO(), *securityGroup,
+						securityGroupBlueprint, services.Replace)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(retSecurityGroup).ShouldNot(gomega.BeNil())
+
+					var rules []*resources.SecurityGroupRule
+					rules, err = retSecurityGroup.Rules()
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(rules).To(gomega.HaveLen(1))
+					gomega.Expect(rules[0].Protocol).To(gomega.Equal(resources.SecurityGroupProtocolUDP))
+					gomega.Expect(rules[0].RuleType).To(gomega.Equal(resources.SecurityGroupRuleTypeOutbound))
+					gomega.Expect(rules[0].IP.String()).To(gomega.Equal("10.0.0.1"))
+					gomega.Expect(*rules[0].Size).To(gomega.Equal(16))
+				})
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupUpdateUnknown
+
+				securityGroup = resources.CreateSecurityGroupWithID(nonExistingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should return that security group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retSecurityGroup, err = client.SecurityGroupService.Update(context.TODO(), *securityGroup,
+					securityGroupBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retSecurityGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retSecurityGroup, err = client.SecurityGroupService.Update(context.TODO(),
+					*resources.CreateSecurityGroupWithID(existingSecurityGroupID),
+					&blueprint.SecurityGroupBlueprint{}, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retSecurityGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retSecurityGroup, err = client.SecurityGroupService.Update(context.TODO(), resources.SecurityGroup{},
+					securityGroupBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retSecurityGroup).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("commit security group", func() {
+		var securityGroup *resources.SecurityGroup
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupCommit
+
+				securityGroup = resources.CreateSecurityGroupWithID(existingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should commit security group changes", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Commit(context.TODO(), *securityGroup, false)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupCommitUnknown
+
+				securityGroup = resources.CreateSecurityGroupWithID(nonExistingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should return that security group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Commit(context.TODO(), *securityGroup, true)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Commit(context.TODO(), resources.SecurityGroup{}, false)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("security group chmod", func() {
+		var (
+			securityGroup    *resources.SecurityGroup
+			oneSecurityGroup *resources.SecurityGroup
+			permRequest      requests.PermissionRequest
+		)
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupChmod
+
+				securityGroup = resources.CreateSecurityGroupWithID(existingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should change permission of given security group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.Group,
+					requests.Use).Allow(requests.Other, requests.Use).Build()
+
+				err = client.SecurityGroupService.Chmod(context.TODO(), *securityGroup, permRequest)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chmod was really changed in OpenNebula
+				oneSecurityGroup, err = client.SecurityGroupService.RetrieveInfo(context.TODO(),
+					existingSecurityGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneSecurityGroup).ShouldNot(gomega.BeNil())
+
+				var perm *resources.Permissions
+				perm, err = oneSecurityGroup.Permissions()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(perm.Group.Use).To(gomega.Equal(true))
+				gomega.Expect(perm.Other.Use).To(gomega.Equal(true))
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupChmodUnknown
+
+				securityGroup = resources.CreateSecurityGroupWithID(nonExistingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should return that security group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.User,
+					requests.Manage).Build()
+
+				err = client.SecurityGroupService.Chmod(context.TODO(), *securityGroup, permRequest)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Chmod(context.TODO(), resources.SecurityGroup{},
+					requests.PermissionRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("security group chown", func() {
+		var (
+			securityGroup    *resources.SecurityGroup
+			oneSecurityGroup *resources.SecurityGroup
+			ownershipReq     requests.OwnershipRequest
+		)
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupChown
+
+				securityGroup = resources.CreateSecurityGroupWithID(existingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should change owner of given security group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				userID := 31
+				groupID := 120
+
+				ownershipReq = requests.CreateOwnershipRequestBuilder().User(*resources.CreateUserWithID(userID)).
+					Group(*resources.CreateGroupWithID(groupID)).Build()
+
+				err = client.SecurityGroupService.Chown(context.TODO(), *securityGroup, ownershipReq)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chown was really changed in OpenNebula
+				oneSecurityGroup, err = client.SecurityGroupService.RetrieveInfo(context.TODO(),
+					existingSecurityGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneSecurityGroup).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(oneSecurityGroup.User()).To(gomega.Equal(userID))
+				gomega.Expect(oneSecurityGroup.Group()).To(gomega.Equal(groupID))
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupChownUnknown
+
+				securityGroup = resources.CreateSecurityGroupWithID(nonExistingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should return that security group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Chown(context.TODO(), *securityGroup,
+					requests.CreateOwnershipRequestBuilder().Build())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Chown(context.TODO(), resources.SecurityGroup{},
+					requests.OwnershipRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("security group rename", func() {
+		var (
+			securityGroup    *resources.SecurityGroup
+			oneSecurityGroup *resources.SecurityGroup
+		)
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				securityGroup = resources.CreateSecurityGroupWithID(existingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = securityGroupRename
+				})
+
+				ginkgo.It("should change name of given security group", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.SecurityGroupService.Rename(context.TODO(), *securityGroup, "web-servers")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneSecurityGroup, err = client.SecurityGroupService.RetrieveInfo(context.TODO(),
+						existingSecurityGroupID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneSecurityGroup).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneSecurityGroup.Name()).To(gomega.Equal("web-servers"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = securityGroupRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given security group", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.SecurityGroupService.Rename(context.TODO(), *securityGroup, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupRenameUnknown
+
+				securityGroup = resources.CreateSecurityGroupWithID(nonExistingSecurityGroupID)
+				if securityGroup == nil {
+					err = errors.ErrNoSecurityGroup
+				}
+			})
+
+			ginkgo.It("should return that security group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Rename(context.TODO(), *securityGroup, "firewall")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when security group is empty", func() {
+			ginkgo.It("should return that security group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.SecurityGroupService.Rename(context.TODO(), resources.SecurityGroup{}, "firewall")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("security group retrieve info", func() {
+		var securityGroup *resources.SecurityGroup
+
+		ginkgo.Context("when security group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupRetrieveInfo
+			})
+
+			ginkgo.It("should return security group with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroup, err = client.SecurityGroupService.RetrieveInfo(context.TODO(), existingSecurityGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroup).ShouldNot(gomega.BeNil())
+				gomega.Expect(securityGroup.ID()).To(gomega.Equal(existingSecurityGroupID))
+				gomega.Expect(securityGroup.Name()).To(gomega.Equal("web-servers"))
+			})
+		})
+
+		ginkgo.Context("when security group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given security group doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroup, err = client.SecurityGroupService.RetrieveInfo(context.TODO(),
+					nonExistingSecurityGroupID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(securityGroup).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("security group list all", func() {
+		var securityGroups []*resources.SecurityGroup
+
+		ginkgo.Context("when ownership filter is set to All", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupListAllAll
+			})
+
+			ginkgo.It("should return list of all security groups with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListAll(context.TODO(), services.OwnershipFilterAll)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).To(gomega.HaveLen(6))
+				gomega.Expect(securityGroups[0].Name()).To(gomega.Equal("default"))
+			})
+		})
+
+		ginkgo.Context("when ownership filter is set to User", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupListAllUser
+			})
+
+			ginkgo.It("should return list of all security groups with full info belongs to the user", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListAll(context.TODO(),
+					services.OwnershipFilterUser)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).To(gomega.HaveLen(4))
+				gomega.Expect(securityGroups[0].ID()).To(gomega.Equal(0))
+				gomega.Expect(securityGroups[1].ID()).To(gomega.Equal(103))
+				gomega.Expect(securityGroups[2].ID()).To(gomega.Equal(104))
+				gomega.Expect(securityGroups[3].ID()).To(gomega.Equal(105))
+			})
+		})
+	})
+
+	ginkgo.Describe("security group list all for user", func() {
+		var securityGroups []*resources.SecurityGroup
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupListAllForUser
+			})
+
+			ginkgo.It("should return security groups with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListAllForUser(context.TODO(),
+					*resources.CreateUserWithID(31))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).To(gomega.HaveLen(2))
+				gomega.Expect(securityGroups[0].ID()).To(gomega.Equal(existingSecurityGroupID))
+				gomega.Expect(securityGroups[1].ID()).To(gomega.Equal(102))
+			})
+		})
+
+		ginkgo.Context("when user doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupListAllForUserUnknown
+			})
+
+			ginkgo.It("should return empty list of security groups (length 0)", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListAllForUser(context.TODO(),
+					*resources.CreateUserWithID(42))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).Should(gomega.Equal(make([]*resources.SecurityGroup, 0)))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListAllForUser(context.TODO(), resources.User{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("security group list with pagination", func() {
+		var securityGroups []*resources.SecurityGroup
+
+		ginkgo.BeforeEach(func() {
+			recName = securityGroupListPagination
+		})
+
+		ginkgo.It("should return security groups with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			securityGroups, err = client.SecurityGroupService.List(context.TODO(), 2, 2, services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(securityGroups).To(gomega.HaveLen(2))
+			gomega.Expect(securityGroups[0].ID()).To(gomega.Equal(102))
+			gomega.Expect(securityGroups[1].ID()).To(gomega.Equal(103))
+		})
+	})
+
+	ginkgo.Describe("security group list for user", func() {
+		var securityGroups []*resources.SecurityGroup
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = securityGroupListForUser
+			})
+
+			ginkgo.It("should return security groups with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListForUser(context.TODO(),
+					*resources.CreateUserWithID(0), 2, 2)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).To(gomega.HaveLen(2))
+				gomega.Expect(securityGroups[0].ID()).To(gomega.Equal(104))
+				gomega.Expect(securityGroups[1].ID()).To(gomega.Equal(105))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				securityGroups, err = client.SecurityGroupService.ListForUser(context.TODO(), resources.User{}, 2, 2)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(securityGroups).Should(gomega.BeNil())
+			})
+		})
+	})
+})
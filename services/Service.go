@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/onego-project/onego/blueprint"
 	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
 
 	"github.com/onego-project/onego/requests"
 
@@ -16,10 +19,40 @@ type Service struct {
 	RPC *RPC
 }
 
-// RPC structure represents XML-RPC client and token string
+// RPC structure represents XML-RPC client and token string. The token is provided by TokenSource when it is set.
+// Failed calls are retried according to Retry, every call is made only once when Retry is nil. Every call
+// passes through Interceptors. The token is never shown in errors and fmt output of *RPC.
 type RPC struct {
-	Client *xmlrpc.Client
-	Token  string
+	Client       *xmlrpc.Client
+	Token        string
+	TokenSource  TokenSource
+	Retry        *RetryPolicy
+	Interceptors []Interceptor
+}
+
+// String returns RPC description without the token.
+func (rpc *RPC) String() string {
+	token := rpc.Token
+	if token != "" {
+		token = redactedToken
+	}
+
+	return fmt.Sprintf("{Client:%p Token:%s TokenSource:%T Retry:%v Interceptors:%d}", rpc.Client, token,
+		rpc.TokenSource, rpc.Retry, len(rpc.Interceptors))
+}
+
+// GoString returns RPC description without the token for %#v verb.
+func (rpc *RPC) GoString() string {
+	return "services.RPC" + rpc.String()
+}
+
+// token returns token from the token source or the token string.
+func (rpc *RPC) token(ctx context.Context) (string, error) {
+	if rpc.TokenSource != nil {
+		return rpc.TokenSource.Token(ctx)
+	}
+
+	return rpc.Token, nil
 }
 
 // enum of result array index
@@ -41,22 +74,45 @@ const (
 )
 
 func (s *Service) call(ctx context.Context, methodName string, args ...interface{}) ([]*xmlrpc.Result, error) {
-	allArgs := append([]interface{}{s.RPC.Token}, args...)
+	return chainInterceptors(s.RPC.Interceptors, s.invoke)(ctx, methodName, args)
+}
 
-	result, err := s.RPC.Client.Call(ctx, methodName, allArgs...)
+// invoke makes the call with the token, failed call is retried according to the retry policy.
+func (s *Service) invoke(ctx context.Context, methodName string, args []interface{}) ([]*xmlrpc.Result, error) {
+	if s.RPC.Retry == nil {
+		return s.attempt(ctx, methodName, args)
+	}
+
+	return s.RPC.Retry.do(ctx, methodName, func() ([]*xmlrpc.Result, error) {
+		return s.attempt(ctx, methodName, args)
+	})
+}
+
+// attempt makes one XML-RPC call with the token prepended to the arguments, the token is hidden in errors.
+// The token is retrieved for every attempt, so a renewed token is used when the call is retried.
+func (s *Service) attempt(ctx context.Context, methodName string, args []interface{}) ([]*xmlrpc.Result, error) {
+	token, err := s.RPC.token(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	allArgs := append([]interface{}{token}, args...)
+
+	result, err := s.RPC.Client.Call(ctx, methodName, allArgs...)
+	if err != nil {
+		return nil, &errors.CallError{Method: methodName, Err: redact(err, token)}
+	}
+
 	resArr := result.ResultArray()
 	if !resArr[successIndex].ResultBoolean() {
+		message := redactString(resArr[resultIndex].ResultString(), token)
 		if len(resArr) == 4 {
 			return nil, &errors.OpenNebulaError{Code: int(resArr[errorCodeIndex].ResultInt()),
-				Message:  resArr[resultIndex].ResultString(),
+				Message:  message,
 				ObjectID: int(resArr[idObjectCausedErrorIndex].ResultInt())}
 		}
 		return nil, &errors.OpenNebulaError{Code: int(resArr[errorCodeIndex].ResultInt()),
-			Message: resArr[resultIndex].ResultString(), ObjectID: errors.NoObjectID}
+			Message: message, ObjectID: errors.NoObjectID}
 	}
 
 	return resArr, nil
@@ -121,3 +177,44 @@ func (s *Service) chown(ctx context.Context, methodName string, resourceID int,
 
 	return err
 }
+
+func (s *Service) lock(ctx context.Context, methodName string, resourceID int, level resources.LockLevel) error {
+	_, err := s.call(ctx, methodName, resourceID, int(level))
+
+	return err
+}
+
+func (s *Service) unlock(ctx context.Context, methodName string, resourceID int) error {
+	_, err := s.call(ctx, methodName, resourceID)
+
+	return err
+}
+
+func (s *Service) defaultQuotas(ctx context.Context, methodName string) (*resources.Quotas, error) {
+	doc, err := s.list(ctx, methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.ParseQuotas(doc.Root())
+}
+
+func (s *Service) updateDefaultQuotas(ctx context.Context, methodName string,
+	blueprint blueprint.Interface) (*resources.Quotas, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := s.call(ctx, methodName, blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	return resources.ParseQuotas(doc.Root())
+}
@@ -201,3 +201,49 @@ func (ts *TemplateService) ListForUser(ctx context.Context, user resources.User,
 
 	return ts.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
 }
+
+// Iterate returns iterator over the templates in the pool which belong to given owner(s) in ownership filter.
+// Pages of IteratorPageSizeDefault templates are retrieved lazily while iterating.
+func (ts *TemplateService) Iterate(ctx context.Context, filter OwnershipFilter) *TemplateIterator {
+	return ts.IterateWithPageSize(ctx, IteratorPageSizeDefault, filter)
+}
+
+// IterateWithPageSize returns iterator over the templates in the pool which belong to given owner(s)
+// in ownership filter. Pages of given size are retrieved lazily while iterating.
+func (ts *TemplateService) IterateWithPageSize(ctx context.Context, pageSize int,
+	filter OwnershipFilter) *TemplateIterator {
+	it := &TemplateIterator{}
+	it.pager = newPager(ctx, pageSize, func(ctx context.Context, offset, pageSize int) (int, error) {
+		page, err := ts.list(ctx, int(filter), offset, -pageSize)
+		if err != nil {
+			return 0, err
+		}
+
+		it.page = page
+		return len(page), nil
+	})
+
+	return it
+}
+
+// Find retrieves the templates in the pool matching given query.
+func (ts *TemplateService) Find(ctx context.Context, query *PoolQuery) ([]*resources.Template, error) {
+	if query.err != nil {
+		return nil, query.err
+	}
+
+	templates := make([]*resources.Template, 0)
+
+	it := ts.Iterate(ctx, OwnershipFilter(query.ownership))
+	for it.Next() {
+		if query.Matches(&it.Value().Resource) {
+			templates = append(templates, it.Value())
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
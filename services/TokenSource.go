@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	goerrors "errors"
+	"net/url"
+	"strings"
+
+	"github.com/onego-project/onego/errors"
+)
+
+// redactedToken replaces the token in errors and in fmt output of RPC.
+const redactedToken = "[REDACTED]"
+
+// TokenSource provides the token for XML-RPC calls, so the token doesn't have to be kept in RPC as a plain
+// string, e.g. it may be read from a secret store or renewed when it expires. Token is called before every call.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// redactedError replaces an error whose chain contains the token. The original error is dropped, so the token
+// can't be reached through errors.Unwrap or errors.As. The cause is a sanitized copy of the original error
+// (see sanitize), so errors.Is and errors.As still work with the errors of this module, url.Error
+// and context errors.
+type redactedError struct {
+	message string
+	cause   error
+}
+
+func (re *redactedError) Error() string {
+	return re.message
+}
+
+// Unwrap returns sanitized copy of the original error or nil.
+func (re *redactedError) Unwrap() error {
+	return re.cause
+}
+
+// redact hides the token in the error when the token is in the message of the error or of any error
+// in its chain.
+func redact(err error, token string) error {
+	if token == "" {
+		return err
+	}
+
+	for e := err; e != nil; e = goerrors.Unwrap(e) {
+		if strings.Contains(e.Error(), token) {
+			return &redactedError{message: redactString(err.Error(), token), cause: sanitize(err, token)}
+		}
+	}
+
+	return err
+}
+
+// sanitize returns copy of the first error of known type in the chain of the error with the token hidden,
+// or the context error the chain ends with. Nil is returned when there is no such error.
+func sanitize(err error, token string) error {
+	for e := err; e != nil; e = goerrors.Unwrap(e) {
+		switch typed := e.(type) {
+		case *errors.CallError:
+			return &errors.CallError{Method: typed.Method, Err: redact(typed.Err, token)}
+		case *errors.OpenNebulaError:
+			return &errors.OpenNebulaError{Code: typed.Code, Message: redactString(typed.Message, token),
+				ObjectID: typed.ObjectID}
+		case *url.Error:
+			return &url.Error{Op: typed.Op, URL: redactString(typed.URL, token), Err: redact(typed.Err, token)}
+		}
+
+		if e == context.Canceled || e == context.DeadlineExceeded {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// redactString hides the token in the text.
+func redactString(text, token string) string {
+	if token == "" {
+		return text
+	}
+
+	return strings.Replace(text, token, redactedToken, -1)
+}
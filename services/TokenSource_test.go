@@ -0,0 +1,230 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	goerrors "errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	tokenSourceInfo    = "records/onetest/tokenSource/info"
+	tokenSourceRenewed = "records/onetest/tokenSource/renewed"
+)
+
+// leakingTransport fails every request with error containing body of the request.
+type leakingTransport struct{}
+
+func (lt leakingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("unable to send %s", body)
+}
+
+// roundTripperFunc is an adapter to allow the use of ordinary functions as http.RoundTripper.
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+var _ = ginkgo.Describe("Token Source", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+
+		source services.TokenSource
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClientWithOptions(endpoint, "", onego.WithHTTPClient(clientHTTP),
+			onego.WithTokenSource(source))
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Context("when token source provides the token", func() {
+		var calls int
+
+		ginkgo.BeforeEach(func() {
+			recName = tokenSourceInfo
+			calls = 0
+			source = services.TokenSourceFunc(func(ctx context.Context) (string, error) {
+				calls++
+				return token, nil
+			})
+		})
+
+		ginkgo.It("should make the call with the token", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			var user *resources.User
+			user, err = client.UserService.RetrieveInfo(context.TODO(), 0)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(user.Name()).To(gomega.Equal("oneadmin"))
+			gomega.Expect(calls).To(gomega.Equal(1))
+			gomega.Expect(client.UserService.RPC.Token).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Context("when token is renewed before the call is retried", func() {
+		var calls int
+
+		ginkgo.BeforeEach(func() {
+			recName = tokenSourceRenewed
+			calls = 0
+			source = services.TokenSourceFunc(func(ctx context.Context) (string, error) {
+				calls++
+				if calls == 1 {
+					return "oneadmin:expired", nil
+				}
+				return token, nil
+			})
+		})
+
+		ginkgo.It("should ask the token source for every attempt", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			client.UserService.RPC.Retry = &services.RetryPolicy{MaxAttempts: 2,
+				Backoff: services.Backoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+				RetryOn: func(methodName string, err error) bool {
+					return errors.IsAuthentication(err)
+				}}
+
+			var user *resources.User
+			user, err = client.UserService.RetrieveInfo(context.TODO(), 0)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(user.Name()).To(gomega.Equal("oneadmin"))
+			gomega.Expect(calls).To(gomega.Equal(2))
+		})
+	})
+
+	ginkgo.Context("when token source fails", func() {
+		ginkgo.BeforeEach(func() {
+			recName = tokenSourceInfo
+			source = services.TokenSourceFunc(func(ctx context.Context) (string, error) {
+				return "", errors.ErrNoUser
+			})
+		})
+
+		ginkgo.It("should return error of the token source", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			_, err = client.UserService.RetrieveInfo(context.TODO(), 0)
+			gomega.Expect(err).To(gomega.Equal(errors.ErrNoUser))
+		})
+	})
+})
+
+var _ = ginkgo.Describe("Token redaction", func() {
+	var client *onego.Client
+
+	ginkgo.BeforeEach(func() {
+		client = onego.CreateClientWithOptions(endpoint, token,
+			onego.WithHTTPClient(&http.Client{Transport: leakingTransport{}}))
+	})
+
+	ginkgo.It("should hide the token in fmt output of RPC", func() {
+		rpc := client.UserService.RPC
+
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			gomega.Expect(fmt.Sprintf(format, rpc)).NotTo(gomega.ContainSubstring(token))
+			gomega.Expect(fmt.Sprintf(format, client.UserService)).NotTo(gomega.ContainSubstring(token))
+		}
+		gomega.Expect(fmt.Sprint(rpc)).To(gomega.ContainSubstring("Token:[REDACTED]"))
+	})
+
+	ginkgo.It("should hide the token in errors", func() {
+		_, err := client.UserService.RetrieveInfo(context.TODO(), 0)
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(err.Error()).NotTo(gomega.ContainSubstring(token))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("[REDACTED]"))
+		gomega.Expect(errors.IsTransient(err)).To(gomega.BeTrue())
+
+		for e := err; e != nil; e = goerrors.Unwrap(e) {
+			gomega.Expect(e.Error()).NotTo(gomega.ContainSubstring(token))
+		}
+
+		var urlErr *url.Error
+		gomega.Expect(goerrors.As(err, &urlErr)).To(gomega.BeTrue())
+		gomega.Expect(urlErr.Op).To(gomega.Equal("Post"))
+	})
+
+	ginkgo.It("should keep type and code of the failed call error", func() {
+		client = onego.CreateClientWithOptions(endpoint, token, onego.WithHTTPClient(&http.Client{
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return nil, fmt.Errorf("call failed: %w", &errors.CallError{Method: "one.user.login",
+					Err: &errors.OpenNebulaError{Code: errors.CodeAuthentication, Message: token + " expired",
+						ObjectID: errors.NoObjectID}})
+			})}))
+
+		_, err := client.UserService.RetrieveInfo(context.TODO(), 0)
+		gomega.Expect(err.Error()).NotTo(gomega.ContainSubstring(token))
+
+		var callErr *errors.CallError
+		gomega.Expect(goerrors.As(err, &callErr)).To(gomega.BeTrue())
+		gomega.Expect(callErr.Method).To(gomega.Equal("one.user.info"))
+
+		var oneErr *errors.OpenNebulaError
+		gomega.Expect(goerrors.As(err, &oneErr)).To(gomega.BeTrue())
+		gomega.Expect(oneErr.Code).To(gomega.Equal(errors.CodeAuthentication))
+		gomega.Expect(oneErr.Message).To(gomega.Equal("[REDACTED] expired"))
+		gomega.Expect(errors.IsAuthentication(err)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should keep context error of the failed call", func() {
+		client = onego.CreateClientWithOptions(endpoint, token, onego.WithHTTPClient(&http.Client{
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return nil, fmt.Errorf("call with %s failed: %w", token, context.Canceled)
+			})}))
+
+		_, err := client.UserService.RetrieveInfo(context.TODO(), 0)
+		gomega.Expect(err.Error()).NotTo(gomega.ContainSubstring(token))
+		gomega.Expect(goerrors.Is(err, context.Canceled)).To(gomega.BeTrue())
+	})
+})
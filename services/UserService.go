@@ -157,3 +157,35 @@ func (us *UserService) List(ctx context.Context) ([]*resources.User, error) {
 
 	return users, nil
 }
+
+// SetQuota sets quota limits of the given user
+func (us *UserService) SetQuota(ctx context.Context, user resources.User,
+	blueprint blueprint.Interface) (*resources.User, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := us.call(ctx, "one.user.quota", userID, blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return us.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// DefaultQuotas retrieves the default quotas applied to all the users
+func (us *UserService) DefaultQuotas(ctx context.Context) (*resources.Quotas, error) {
+	return us.defaultQuotas(ctx, "one.userquota.info")
+}
+
+// SetDefaultQuotas sets the default quotas applied to all the users
+func (us *UserService) SetDefaultQuotas(ctx context.Context,
+	blueprint blueprint.Interface) (*resources.Quotas, error) {
+	return us.updateDefaultQuotas(ctx, "one.userquota.update", blueprint)
+}
@@ -28,6 +28,7 @@ const (
 const (
 	idExistingUser    = 22
 	idNonExistingUser = 25
+	idQuotaUser       = 40
 
 	idExistingNotMainGroup = 118
 	idExistingGroup        = 120
@@ -51,6 +52,12 @@ const (
 	userPasswordEmpty       = "records/user/passwordEmpty"
 	userPasswordUnknownUser = "records/user/passwdUnknownUser"
 
+	userSetQuota            = "records/onetest/user/setQuota"
+	userSetQuotaUnknownUser = "records/onetest/user/setQuotaUnknownUser"
+	userSetQuotaWrongValue  = "records/onetest/user/setQuotaWrongValue"
+	userDefaultQuotas       = "records/onetest/user/defaultQuotas"
+	userSetDefaultQuotas    = "records/onetest/user/setDefaultQuotas"
+
 	userUpdateMerge       = "records/user/updateMerge"
 	userUpdateReplace     = "records/user/updateReplace"
 	userUpdateUnknownUser = "records/user/updateUnknownUser"
@@ -761,4 +768,123 @@ var _ = ginkgo.Describe("User Service", func() {
 			gomega.Expect(users).ShouldNot(gomega.BeNil())
 		})
 	})
+
+	ginkgo.Describe("set user quota", func() {
+		var (
+			user   *resources.User
+			quotas *resources.Quotas
+			qb     *blueprint.QuotaBlueprint
+		)
+
+		ginkgo.BeforeEach(func() {
+			qb = blueprint.CreateQuotaBlueprint()
+			qb.SetVMs(5)
+			qb.SetCPU(2.5)
+			qb.SetMemory(resources.QuotaUnlimited)
+			qb.AddDatastoreQuota(1, 10, 20480)
+		})
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = userSetQuota
+			})
+
+			ginkgo.It("should set quota limits and return user with usage", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				user, err = client.UserService.SetQuota(context.TODO(), *resources.CreateUserWithID(idQuotaUser), qb)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(user).ShouldNot(gomega.BeNil())
+
+				quotas, err = user.Quotas()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas.VM).ShouldNot(gomega.BeNil())
+				gomega.Expect(quotas.VM.VMs).To(gomega.Equal(5))
+				gomega.Expect(quotas.VM.VMsUsed).To(gomega.Equal(1))
+				gomega.Expect(quotas.VM.CPU).To(gomega.Equal(2.5))
+				gomega.Expect(quotas.VM.CPUUsed).To(gomega.Equal(0.5))
+				gomega.Expect(quotas.VM.Memory).To(gomega.Equal(resources.QuotaUnlimited))
+				gomega.Expect(quotas.VM.MemoryUsed).To(gomega.Equal(1024))
+				gomega.Expect(quotas.VM.RunningVMs).To(gomega.Equal(resources.QuotaDefault))
+				gomega.Expect(quotas.Datastores).To(gomega.Equal([]*resources.DatastoreQuota{{ID: 1, Images: 10,
+					Size: 20480}}))
+			})
+		})
+
+		ginkgo.Context("when user doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = userSetQuotaUnknownUser
+			})
+
+			ginkgo.It("should return an error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				user, err = client.UserService.SetQuota(context.TODO(), *resources.CreateUserWithID(idNonExistingUser),
+					qb)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(user).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when limit has wrong value", func() {
+			ginkgo.BeforeEach(func() {
+				recName = userSetQuotaWrongValue
+
+				qb.SetVMs(-5)
+			})
+
+			ginkgo.It("should return an error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				user, err = client.UserService.SetQuota(context.TODO(), *resources.CreateUserWithID(idQuotaUser), qb)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(user).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("default user quotas", func() {
+		var quotas *resources.Quotas
+
+		ginkgo.Context("when retrieving default quotas", func() {
+			ginkgo.BeforeEach(func() {
+				recName = userDefaultQuotas
+			})
+
+			ginkgo.It("should return empty default quotas", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				quotas, err = client.UserService.DefaultQuotas(context.TODO())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas).ShouldNot(gomega.BeNil())
+				gomega.Expect(quotas.VM).Should(gomega.BeNil())
+				gomega.Expect(quotas.Datastores).To(gomega.BeEmpty())
+			})
+		})
+
+		ginkgo.Context("when setting default quotas", func() {
+			ginkgo.BeforeEach(func() {
+				recName = userSetDefaultQuotas
+			})
+
+			ginkgo.It("should return updated default quotas", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				qb := blueprint.CreateQuotaBlueprint()
+				qb.SetRunningVMs(3)
+				qb.AddNetworkQuota(0, 20)
+
+				quotas, err = client.UserService.SetDefaultQuotas(context.TODO(), qb)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas.VM).ShouldNot(gomega.BeNil())
+				gomega.Expect(quotas.VM.RunningVMs).To(gomega.Equal(3))
+				gomega.Expect(quotas.VM.VMs).To(gomega.Equal(resources.QuotaDefault))
+				gomega.Expect(quotas.Networks).To(gomega.Equal([]*resources.NetworkQuota{{ID: 0, Leases: 20}}))
+
+				quotas, err = client.UserService.DefaultQuotas(context.TODO())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(quotas.VM.RunningVMs).To(gomega.Equal(3))
+			})
+		})
+	})
 })
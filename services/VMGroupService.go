@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+)
+
+// VMGroupService structure to manage OpenNebula VM group.
+type VMGroupService struct {
+	Service
+}
+
+// Allocate allocates a new VM group in OpenNebula.
+func (vgs *VMGroupService) Allocate(ctx context.Context, blueprint blueprint.Interface) (*resources.VMGroup, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := vgs.call(ctx, "one.vmgroup.allocate", blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return vgs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Delete deletes the given VM group from the pool.
+func (vgs *VMGroupService) Delete(ctx context.Context, vmGroup resources.VMGroup) error {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vgs.call(ctx, "one.vmgroup.delete", vmGroupID)
+
+	return err
+}
+
+// Update merges or replaces the VM group template contents.
+func (vgs *VMGroupService) Update(ctx context.Context, vmGroup resources.VMGroup, blueprint blueprint.Interface,
+	updateType UpdateType) (*resources.VMGroup, error) {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := vgs.call(ctx, "one.vmgroup.update", vmGroupID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return vgs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Chmod changes the permission bits of a VM group.
+func (vgs *VMGroupService) Chmod(ctx context.Context, vmGroup resources.VMGroup,
+	request requests.PermissionRequest) error {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	return vgs.chmod(ctx, "one.vmgroup.chmod", vmGroupID, request)
+}
+
+// Chown changes the ownership of a VM group.
+func (vgs *VMGroupService) Chown(ctx context.Context, vmGroup resources.VMGroup,
+	request requests.OwnershipRequest) error {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	return vgs.chown(ctx, "one.vmgroup.chown", vmGroupID, request)
+}
+
+// Rename renames a VM group.
+func (vgs *VMGroupService) Rename(ctx context.Context, vmGroup resources.VMGroup, name string) error {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vgs.call(ctx, "one.vmgroup.rename", vmGroupID, name)
+
+	return err
+}
+
+// Lock locks a VM group, actions of given level and the levels above are blocked.
+func (vgs *VMGroupService) Lock(ctx context.Context, vmGroup resources.VMGroup, level resources.LockLevel) error {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	return vgs.lock(ctx, "one.vmgroup.lock", vmGroupID, level)
+}
+
+// Unlock unlocks a VM group.
+func (vgs *VMGroupService) Unlock(ctx context.Context, vmGroup resources.VMGroup) error {
+	vmGroupID, err := vmGroup.ID()
+	if err != nil {
+		return err
+	}
+
+	return vgs.unlock(ctx, "one.vmgroup.unlock", vmGroupID)
+}
+
+// RetrieveInfo retrieves information for the VM group.
+func (vgs *VMGroupService) RetrieveInfo(ctx context.Context, vmGroupID int) (*resources.VMGroup, error) {
+	doc, err := vgs.retrieveInfo(ctx, "one.vmgroup.info", vmGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateVMGroupFromXML(doc.Root()), nil
+}
+
+func (vgs *VMGroupService) list(ctx context.Context, filterFlag, pageOffset,
+	pageSize int) ([]*resources.VMGroup, error) {
+	resArr, err := vgs.call(ctx, "one.vmgrouppool.info", filterFlag, pageOffset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("VM_GROUP_POOL/VM_GROUP")
+
+	vmGroups := make([]*resources.VMGroup, len(elements))
+	for i, e := range elements {
+		vmGroups[i] = resources.CreateVMGroupFromXML(e)
+	}
+
+	return vmGroups, nil
+}
+
+// ListAll retrieves information for all the VM groups in the pool.
+func (vgs *VMGroupService) ListAll(ctx context.Context, filter OwnershipFilter) ([]*resources.VMGroup, error) {
+	return vgs.list(ctx, int(filter), pageOffsetDefault, pageSizeDefault)
+}
+
+// ListAllForUser retrieves information for all the VM groups for the given user in the pool.
+func (vgs *VMGroupService) ListAllForUser(ctx context.Context, user resources.User) ([]*resources.VMGroup, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return vgs.list(ctx, userID, pageOffsetDefault, pageSizeDefault)
+}
+
+// List retrieves information for a part of the VM groups in the pool with a given pagination.
+func (vgs *VMGroupService) List(ctx context.Context, pageOffset, pageSize int,
+	filter OwnershipFilter) ([]*resources.VMGroup, error) {
+	return vgs.list(ctx, int(filter), (pageOffset-1)*pageSize, -pageSize)
+}
+
+// ListForUser retrieves information for a part of the VM groups for given user in the pool
+// with a given pagination.
+func (vgs *VMGroupService) ListForUser(ctx context.Context, user resources.User, pageOffset,
+	pageSize int) ([]*resources.VMGroup, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return vgs.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
+}
@@ -0,0 +1,871 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/onego-project/onego/services"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	vmGroupAllocate              = "records/onetest/vmGroup/allocate"
+	vmGroupAllocateExisting      = "records/onetest/vmGroup/allocateExisting"
+	vmGroupAllocateUndefinedRole = "records/onetest/vmGroup/allocateUndefinedRole"
+
+	vmGroupDelete        = "records/onetest/vmGroup/delete"
+	vmGroupDeleteWrongID = "records/onetest/vmGroup/deleteWrongID"
+	vmGroupDeleteInUse   = "records/onetest/vmGroup/deleteInUse"
+	vmGroupDeleteLocked  = "records/onetest/vmGroup/deleteLocked"
+
+	vmGroupUpdateMerge   = "records/onetest/vmGroup/updateMerge"
+	vmGroupUpdateUnknown = "records/onetest/vmGroup/updateUnknown"
+
+	vmGroupChmod        = "records/onetest/vmGroup/chmod"
+	vmGroupChmodUnknown = "records/onetest/vmGroup/chmodUnknown"
+
+	vmGroupChown        = "records/onetest/vmGroup/chown"
+	vmGroupChownUnknown = "records/onetest/vmGroup/chownUnknown"
+
+	vmGroupRename        = "records/onetest/vmGroup/rename"
+	vmGroupRenameEmpty   = "records/onetest/vmGroup/renameEmpty"
+	vmGroupRenameUnknown = "records/onetest/vmGroup/renameUnknown"
+
+	vmGroupLock        = "records/onetest/vmGroup/lock"
+	vmGroupLockUnknown = "records/onetest/vmGroup/lockUnknown"
+
+	vmGroupRetrieveInfo        = "records/onetest/vmGroup/retrieveInfo"
+	vmGroupRetrieveInfoUnknown = "records/onetest/vmGroup/retrieveInfoUnknown"
+
+	vmGroupAllocateVirtualMachine        = "records/onetest/vmGroup/allocateVirtualMachine"
+	vmGroupAllocateVirtualMachineUnknown = "records/onetest/vmGroup/allocateVirtualMachineUnknown"
+
+	vmGroupListAllAll = "records/onetest/vmGroup/listAllAll"
+
+	vmGroupListAllForUser = "records/onetest/vmGroup/listAllForUser"
+
+	vmGroupListPagination = "records/onetest/vmGroup/listPagination"
+
+	vmGroupListForUser = "records/onetest/vmGroup/listForUser"
+)
+
+var _ = ginkgo.Describe("VM Group Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingVMGroupID = 0
+	var deletedVMGroupID = 1
+	var lockedVMGroupID = 3
+	var usedVMGroupID = 4
+	var nonExistingVMGroupID = 420
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate VM group", func() {
+		var (
+			vmGroup          *resources.VMGroup
+			vmGroupBlueprint *blueprint.VMGroupBlueprint
+		)
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupAllocate
+
+				nodes := blueprint.CreateVMGroupRoleBlueprint()
+				nodes.SetName("nodes")
+				nodes.SetPolicy(resources.VMGroupPolicyAntiAffined)
+				nodes.SetHostAntiAffined([]int{3})
+
+				balancer := blueprint.CreateVMGroupRoleBlueprint()
+				balancer.SetName("balancer")
+
+				vmGroupBlueprint = blueprint.CreateAllocateVMGroupBlueprint()
+				vmGroupBlueprint.SetName("cache")
+				vmGroupBlueprint.SetDescription("memcached cluster")
+				vmGroupBlueprint.AddRole(*nodes)
+				vmGroupBlueprint.AddRole(*balancer)
+				vmGroupBlueprint.AddAntiAffined("nodes", "balancer")
+			})
+
+			ginkgo.It("should create new VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroup, err = client.VMGroupService.Allocate(context.TODO(), vmGroupBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vmGroup).ShouldNot(gomega.BeNil())
+				gomega.Expect(vmGroup.ID()).To(gomega.Equal(5))
+				gomega.Expect(vmGroup.Name()).To(gomega.Equal("cache"))
+				gomega.Expect(vmGroup.Description()).To(gomega.Equal("memcached cluster"))
+				gomega.Expect(vmGroup.AntiAffinedRoles()).To(gomega.Equal([][]string{{"nodes", "balancer"}}))
+
+				var roles []*resources.VMGroupRole
+				roles, err = vmGroup.Roles()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(roles).To(gomega.HaveLen(2))
+				gomega.Expect(roles[0].Name).To(gomega.Equal("nodes"))
+				gomega.Expect(roles[0].Policy).To(gomega.Equal(resources.VMGroupPolicyAntiAffined))
+				gomega.Expect(roles[0].HostAntiAffined).To(gomega.Equal([]int{3}))
+				gomega.Expect(roles[1].Name).To(gomega.Equal("balancer"))
+				gomega.Expect(roles[1].Policy).To(gomega.Equal(resources.VMGroupPolicyNone))
+			})
+		})
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupAllocateExisting
+
+				vmGroupBlueprint = blueprint.CreateAllocateVMGroupBlueprint()
+				vmGroupBlueprint.SetName("database")
+			})
+
+			ginkgo.It("should return that VM group already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroup, err = client.VMGroupService.Allocate(context.TODO(), vmGroupBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vmGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when affinity rule refers to undefined role", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupAllocateUndefinedRole
+
+				role := blueprint.CreateVMGroupRoleBlueprint()
+				role.SetName("web")
+
+				vmGroupBlueprint = blueprint.CreateAllocateVMGroupBlueprint()
+				vmGroupBlueprint.SetName("broken")
+				vmGroupBlueprint.AddRole(*role)
+				vmGroupBlueprint.AddAffined("web", "db")
+			})
+
+			ginkgo.It("shouldn't create new VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroup, err = client.VMGroupService.Allocate(context.TODO(), vmGroupBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vmGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroup, err = client.VMGroupService.Allocate(context.TODO(), &blueprint.VMGroupBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vmGroup).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete VM group", func() {
+		var (
+			vmGroup    *resources.VMGroup
+			oneVMGroup *resources.VMGroup
+		)
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupDelete
+
+				vmGroup = resources.CreateVMGroupWithID(deletedVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should delete VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Delete(context.TODO(), *vmGroup)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether VM group was really deleted in OpenNebula
+				oneVMGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), deletedVMGroupID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneVMGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupDeleteWrongID
+
+				vmGroup = resources.CreateVMGroupWithID(nonExistingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Delete(context.TODO(), *vmGroup)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when VM group contains virtual machines", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupDeleteInUse
+
+				vmGroup = resources.CreateVMGroupWithID(usedVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group can't be deleted", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Delete(context.TODO(), *vmGroup)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsActionNotAllowed(err)).To(gomega.BeTrue())
+			})
+		})
+
+		ginkgo.Context("when VM group is locked", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupDeleteLocked
+
+				vmGroup = resources.CreateVMGroupWithID(lockedVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group is locked", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Delete(context.TODO(), *vmGroup)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+			})
+		})
+
+		ginkgo.Context("when VM group is empty", func() {
+			ginkgo.It("should return that VM group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Delete(context.TODO(), resources.VMGroup{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update VM group", func() {
+		var (
+			vmGroup          *resources.VMGroup
+			vmGroupBlueprint *blueprint.VMGroupBlueprint
+			retVMGroup       *resources.VMGroup
+		)
+
+		ginkgo.BeforeEach(func() {
+			vmGroupBlueprint = blueprint.CreateUpdateVMGroupBlueprint()
+			if vmGroupBlueprint == nil {
+				err = errors.ErrNoVMGroupBlueprint
+				return
+			}
+			vmGroupBlueprint.SetDescription("replicated database")
+		})
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupUpdateMerge
+
+				vmGroup = resources.CreateVMGroupWithID(existingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should merge data of given VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVMGroup, err = client.VMGroupService.Update(context.TODO(), *vmGroup, vmGroupBlueprint,
+					services.Merge)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retVMGroup).ShouldNot(gomega.BeNil())
+				gomega.Expect(retVMGroup.Description()).To(gomega.Equal("replicated database"))
+				gomega.Expect(retVMGroup.Roles()).To(gomega.HaveLen(2))
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupUpdateUnknown
+
+				vmGroup = resources.CreateVMGroupWithID(nonExistingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVMGroup, err = client.VMGroupService.Update(context.TODO(), *vmGroup, vmGroupBlueprint,
+					services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retVMGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVMGroup, err = client.VMGroupService.Update(context.TODO(),
+					*resources.CreateVMGroupWithID(existingVMGroupID), &blueprint.VMGroupBlueprint{}, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retVMGroup).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when VM group is empty", func() {
+			ginkgo.It("should return that VM group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVMGroup, err = client.VMGroupService.Update(context.TODO(), resources.VMGroup{},
+					vmGroupBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retVMGroup).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group chmod", func() {
+		var (
+			vmGroup     *resources.VMGroup
+			oneVMGroup  *resources.VMGroup
+			permRequest requests.PermissionRequest
+		)
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupChmod
+
+				vmGroup = resources.CreateVMGroupWithID(existingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should change permission of given VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.Group,
+					requests.Use).Allow(requests.Other, requests.Use).Build()
+
+				err = client.VMGroupService.Chmod(context.TODO(), *vmGroup, permRequest)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chmod was really changed in OpenNebula
+				oneVMGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), existingVMGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVMGroup).ShouldNot(gomega.BeNil())
+
+				var perm *resources.Permissions
+				perm, err = oneVMGroup.Permissions()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(perm.Group.Use).To(gomega.Equal(true))
+				gomega.Expect(perm.Other.Use).To(gomega.Equal(true))
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupChmodUnknown
+
+				vmGroup = resources.CreateVMGroupWithID(nonExistingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.User,
+					requests.Manage).Build()
+
+				err = client.VMGroupService.Chmod(context.TODO(), *vmGroup, permRequest)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when VM group is empty", func() {
+			ginkgo.It("should return that VM group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Chmod(context.TODO(), resources.VMGroup{}, requests.PermissionRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group chown", func() {
+		var (
+			vmGroup      *resources.VMGroup
+			oneVMGroup   *resources.VMGroup
+			ownershipReq requests.OwnershipRequest
+		)
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupChown
+
+				vmGroup = resources.CreateVMGroupWithID(existingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should change owner of given VM group", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				userID := 60
+				groupID := 160
+
+				ownershipReq = requests.CreateOwnershipRequestBuilder().User(*resources.CreateUserWithID(userID)).
+					Group(*resources.CreateGroupWithID(groupID)).Build()
+
+				err = client.VMGroupService.Chown(context.TODO(), *vmGroup, ownershipReq)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chown was really changed in OpenNebula
+				oneVMGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), existingVMGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVMGroup).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(oneVMGroup.User()).To(gomega.Equal(userID))
+				gomega.Expect(oneVMGroup.Group()).To(gomega.Equal(groupID))
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupChownUnknown
+
+				vmGroup = resources.CreateVMGroupWithID(nonExistingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Chown(context.TODO(), *vmGroup,
+					requests.CreateOwnershipRequestBuilder().Build())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when VM group is empty", func() {
+			ginkgo.It("should return that VM group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Chown(context.TODO(), resources.VMGroup{}, requests.OwnershipRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group rename", func() {
+		var (
+			vmGroup    *resources.VMGroup
+			oneVMGroup *resources.VMGroup
+		)
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				vmGroup = resources.CreateVMGroupWithID(existingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = vmGroupRename
+				})
+
+				ginkgo.It("should change name of given VM group", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VMGroupService.Rename(context.TODO(), *vmGroup, "db-replicas")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneVMGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), existingVMGroupID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneVMGroup).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneVMGroup.Name()).To(gomega.Equal("db-replicas"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = vmGroupRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given VM group", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VMGroupService.Rename(context.TODO(), *vmGroup, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupRenameUnknown
+
+				vmGroup = resources.CreateVMGroupWithID(nonExistingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Rename(context.TODO(), *vmGroup, "database")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when VM group is empty", func() {
+			ginkgo.It("should return that VM group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Rename(context.TODO(), resources.VMGroup{}, "database")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group lock", func() {
+		var (
+			vmGroup    *resources.VMGroup
+			oneVMGroup *resources.VMGroup
+		)
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupLock
+
+				vmGroup = resources.CreateVMGroupWithID(existingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should block manage actions until VM group is unlocked", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Lock(context.TODO(), *vmGroup, resources.LockManage)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether VM group was really locked in OpenNebula
+				oneVMGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), existingVMGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var lock *resources.Lock
+				lock, err = oneVMGroup.Lock()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lock).ShouldNot(gomega.BeNil())
+				gomega.Expect(lock.Level).To(gomega.Equal(resources.LockManage))
+
+				err = client.VMGroupService.Rename(context.TODO(), *vmGroup, "locked-database")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+
+				err = client.VMGroupService.Unlock(context.TODO(), *vmGroup)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				err = client.VMGroupService.Rename(context.TODO(), *vmGroup, "database")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				oneVMGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), existingVMGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVMGroup.Name()).To(gomega.Equal("database"))
+				gomega.Expect(oneVMGroup.Lock()).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupLockUnknown
+
+				vmGroup = resources.CreateVMGroupWithID(nonExistingVMGroupID)
+				if vmGroup == nil {
+					err = errors.ErrNoVMGroup
+				}
+			})
+
+			ginkgo.It("should return that VM group with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Lock(context.TODO(), *vmGroup, resources.LockUse)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when VM group is empty", func() {
+			ginkgo.It("should return that VM group has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VMGroupService.Unlock(context.TODO(), resources.VMGroup{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group retrieve info", func() {
+		var vmGroup *resources.VMGroup
+
+		ginkgo.Context("when VM group exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupRetrieveInfo
+			})
+
+			ginkgo.It("should return VM group with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), usedVMGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vmGroup).ShouldNot(gomega.BeNil())
+				gomega.Expect(vmGroup.ID()).To(gomega.Equal(usedVMGroupID))
+				gomega.Expect(vmGroup.Name()).To(gomega.Equal("in-use"))
+
+				var roles []*resources.VMGroupRole
+				roles, err = vmGroup.Roles()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(roles).To(gomega.HaveLen(1))
+				gomega.Expect(roles[0].Name).To(gomega.Equal("app"))
+				gomega.Expect(roles[0].Policy).To(gomega.Equal(resources.VMGroupPolicyAffined))
+				gomega.Expect(roles[0].VirtualMachines).To(gomega.Equal([]int{280}))
+			})
+		})
+
+		ginkgo.Context("when VM group doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given VM group doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), nonExistingVMGroupID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vmGroup).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("allocate virtual machine in VM group", func() {
+		var (
+			vm          *resources.VirtualMachine
+			vmGroup     *resources.VMGroup
+			vmBlueprint *blueprint.VirtualMachineBlueprint
+		)
+
+		ginkgo.BeforeEach(func() {
+			vmBlueprint = blueprint.CreateAllocateVirtualMachineBlueprint()
+			if vmBlueprint == nil {
+				err = errors.ErrNoVirtualMachineBlueprint
+				return
+			}
+			vmBlueprint.SetName("app-2")
+			vmBlueprint.SetCPU(1)
+			vmBlueprint.SetMemory(512)
+		})
+
+		ginkgo.Context("when role exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupAllocateVirtualMachine
+
+				err = vmBlueprint.SetVMGroup(*resources.CreateVMGroupWithID(usedVMGroupID), "app")
+			})
+
+			ginkgo.It("should add virtual machine to the role", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vm, err = client.VirtualMachineService.Allocate(context.TODO(), vmBlueprint, true)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vm).ShouldNot(gomega.BeNil())
+
+				vmGroup, err = client.VMGroupService.RetrieveInfo(context.TODO(), usedVMGroupID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var roles []*resources.VMGroupRole
+				roles, err = vmGroup.Roles()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(roles[0].VirtualMachines).To(gomega.Equal([]int{280, 281}))
+			})
+		})
+
+		ginkgo.Context("when role doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupAllocateVirtualMachineUnknown
+
+				err = vmBlueprint.SetVMGroup(*resources.CreateVMGroupWithID(usedVMGroupID), "db")
+			})
+
+			ginkgo.It("should return that role doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vm, err = client.VirtualMachineService.Allocate(context.TODO(), vmBlueprint, true)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vm).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group list all", func() {
+		var vmGroups []*resources.VMGroup
+
+		ginkgo.BeforeEach(func() {
+			recName = vmGroupListAllAll
+		})
+
+		ginkgo.It("should return list of all VM groups with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			vmGroups, err = client.VMGroupService.ListAll(context.TODO(), services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(vmGroups).To(gomega.HaveLen(5))
+			gomega.Expect(vmGroups[0].Name()).To(gomega.Equal("database"))
+			gomega.Expect(vmGroups[4].Name()).To(gomega.Equal("cache"))
+		})
+	})
+
+	ginkgo.Describe("VM group list all for user", func() {
+		var vmGroups []*resources.VMGroup
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupListAllForUser
+			})
+
+			ginkgo.It("should return VM groups with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroups, err = client.VMGroupService.ListAllForUser(context.TODO(), *resources.CreateUserWithID(60))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vmGroups).To(gomega.HaveLen(2))
+				gomega.Expect(vmGroups[0].ID()).To(gomega.Equal(existingVMGroupID))
+				gomega.Expect(vmGroups[1].ID()).To(gomega.Equal(2))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroups, err = client.VMGroupService.ListAllForUser(context.TODO(), resources.User{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vmGroups).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("VM group list with pagination", func() {
+		var vmGroups []*resources.VMGroup
+
+		ginkgo.BeforeEach(func() {
+			recName = vmGroupListPagination
+		})
+
+		ginkgo.It("should return VM groups with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			vmGroups, err = client.VMGroupService.List(context.TODO(), 2, 2, services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(vmGroups).To(gomega.HaveLen(2))
+			gomega.Expect(vmGroups[0].ID()).To(gomega.Equal(lockedVMGroupID))
+			gomega.Expect(vmGroups[1].ID()).To(gomega.Equal(usedVMGroupID))
+		})
+	})
+
+	ginkgo.Describe("VM group list for user", func() {
+		var vmGroups []*resources.VMGroup
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = vmGroupListForUser
+			})
+
+			ginkgo.It("should return VM groups with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroups, err = client.VMGroupService.ListForUser(context.TODO(), *resources.CreateUserWithID(0), 2, 2)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vmGroups).To(gomega.HaveLen(1))
+				gomega.Expect(vmGroups[0].ID()).To(gomega.Equal(5))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				vmGroups, err = client.VMGroupService.ListForUser(context.TODO(), resources.User{}, 2, 2)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(vmGroups).Should(gomega.BeNil())
+			})
+		})
+	})
+})
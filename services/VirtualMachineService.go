@@ -2,10 +2,13 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/onego-project/onego/blueprint"
 
 	"github.com/beevik/etree"
+	"github.com/onego-project/onego/errors"
 	"github.com/onego-project/onego/requests"
 	"github.com/onego-project/onego/resources"
 )
@@ -84,12 +87,6 @@ const (
 	CloningFailure
 )
 
-//// Snapshot structure represents VM Snapshot.
-//type Snapshot struct {
-//	Name string
-//	ID   int
-//}
-
 // Allocate allocates a new virtual machine in OpenNebula.
 // Blueprint should contain at least VM name, memory and cpu.
 func (vms *VirtualMachineService) Allocate(ctx context.Context, blueprintInterface blueprint.Interface,
@@ -266,49 +263,49 @@ func (vms *VirtualMachineService) Rename(ctx context.Context, vm resources.Virtu
 	return err
 }
 
-//// CreateSnapshot creates a new virtual machine snapshot.
-//func (vms *VirtualMachineService) CreateSnapshot(ctx context.Context, vm resources.VirtualMachine,
-//	name string) (*Snapshot, error) {
-//	vmID, err := vm.ID()
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	resArr, err := vms.call(ctx, "one.vm.snapshotcreate", vmID, name)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	snapshot := Snapshot{ID: int(resArr[resultIndex].ResultInt()), Name: name}
-//
-//	return &snapshot, nil
-//}
-//
-//// RevertSnapshot reverts a virtual machine to a snapshot.
-//func (vms *VirtualMachineService) RevertSnapshot(ctx context.Context, vm resources.VirtualMachine,
-//	snapshot Snapshot) error {
-//	vmID, err := vm.ID()
-//	if err != nil {
-//		return err
-//	}
-//
-//	_, err = vms.call(ctx, "one.vm.snapshotrevert", vmID, snapshot.ID)
-//
-//	return err
-//}
-//
-//// DeleteSnapshot deletes a virtual machine snapshot.
-//func (vms *VirtualMachineService) DeleteSnapshot(ctx context.Context, vm resources.VirtualMachine,
-//	snapshot Snapshot) error {
-//	vmID, err := vm.ID()
-//	if err != nil {
-//		return err
-//	}
-//
-//	_, err = vms.call(ctx, "one.vm.snapshotdelete", vmID, snapshot.ID)
-//
-//	return err
-//}
+// CreateSnapshot creates a new virtual machine snapshot.
+// Returned snapshot contains only ID and name, the rest of the snapshot
+// attributes can be found in VM info (see resources.VirtualMachine.Snapshots).
+func (vms *VirtualMachineService) CreateSnapshot(ctx context.Context, vm resources.VirtualMachine,
+	name string) (*resources.Snapshot, error) {
+	vmID, err := vm.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := vms.call(ctx, "one.vm.snapshotcreate", vmID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resources.Snapshot{ID: int(resArr[resultIndex].ResultInt()), Name: name}, nil
+}
+
+// RevertSnapshot reverts a virtual machine to a snapshot.
+func (vms *VirtualMachineService) RevertSnapshot(ctx context.Context, vm resources.VirtualMachine,
+	snapshot resources.Snapshot) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vms.call(ctx, "one.vm.snapshotrevert", vmID, snapshot.ID)
+
+	return err
+}
+
+// DeleteSnapshot deletes a virtual machine snapshot.
+func (vms *VirtualMachineService) DeleteSnapshot(ctx context.Context, vm resources.VirtualMachine,
+	snapshot resources.Snapshot) error {
+	vmID, err := vm.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vms.call(ctx, "one.vm.snapshotdelete", vmID, snapshot.ID)
+
+	return err
+}
 
 // Resize changes the capacity of the virtual machine.
 func (vms *VirtualMachineService) Resize(ctx context.Context, vm resources.VirtualMachine,
@@ -377,6 +374,98 @@ func (vms *VirtualMachineService) Recover(ctx context.Context, vm resources.Virt
 	return err
 }
 
+// vmFailureLcmStates contains LCM states the virtual machine can't leave without intervention.
+var vmFailureLcmStates = map[resources.VirtualMachineLcmState]bool{
+	resources.VirtualMachineBootFailure:                  true,
+	resources.VirtualMachineBootMigrateFailure:           true,
+	resources.VirtualMachinePrologMigrateFailure:         true,
+	resources.VirtualMachinePrologFailure:                true,
+	resources.VirtualMachineEpilogFailure:                true,
+	resources.VirtualMachineEpilogStopFailure:            true,
+	resources.VirtualMachineEpilogUndeployFailure:        true,
+	resources.VirtualMachinePrologMigratePoweroffFailure: true,
+	resources.VirtualMachinePrologMigrateSuspendFailure:  true,
+	resources.VirtualMachineBootStoppedFailure:           true,
+	resources.VirtualMachineBootUndeployFailure:          true,
+	resources.VirtualMachinePrologResumeFailure:          true,
+	resources.VirtualMachinePrologUndeployFailure:        true,
+	resources.VirtualMachinePrologMigrateUnknownFailure:  true,
+}
+
+// vmFailureStates contains states the virtual machine can't leave without intervention.
+var vmFailureStates = map[resources.VirtualMachineState]bool{
+	resources.VirtualMachineStateDone:           true,
+	resources.VirtualMachineStateFailed:         true,
+	resources.VirtualMachineStateCloningFailure: true,
+}
+
+// WaitForState waits until the virtual machine reaches given state and returns the virtual machine info.
+// LCM state is checked only when the expected state is VirtualMachineStateActive.
+// Waiting fails when the virtual machine reaches a failure state, DefaultBackoff is used between the checks.
+func (vms *VirtualMachineService) WaitForState(ctx context.Context, vm resources.VirtualMachine,
+	state resources.VirtualMachineState, lcmState resources.VirtualMachineLcmState) (*resources.VirtualMachine,
+	error) {
+	return vms.WaitForStateWithBackoff(ctx, vm, state, lcmState, DefaultBackoff)
+}
+
+// WaitForStateWithBackoff waits until the virtual machine reaches given state with given backoff
+// between the checks. See WaitForState.
+func (vms *VirtualMachineService) WaitForStateWithBackoff(ctx context.Context, vm resources.VirtualMachine,
+	state resources.VirtualMachineState, lcmState resources.VirtualMachineLcmState,
+	backoff Backoff) (*resources.VirtualMachine, error) {
+	vmID, err := vm.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	var oneVM *resources.VirtualMachine
+
+	err = waitFor(ctx, backoff, func() (bool, error) {
+		var checkErr error
+		oneVM, checkErr = vms.RetrieveInfo(ctx, vmID)
+		if checkErr != nil {
+			return false, checkErr
+		}
+
+		return vmStateReached(oneVM, vmID, state, lcmState)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oneVM, nil
+}
+
+func vmStateReached(vm *resources.VirtualMachine, vmID int, state resources.VirtualMachineState,
+	lcmState resources.VirtualMachineLcmState) (bool, error) {
+	currentState, err := vm.State()
+	if err != nil {
+		return false, err
+	}
+
+	currentLcmState, err := vm.LCMState()
+	if err != nil {
+		return false, err
+	}
+
+	if currentState == state && (state != resources.VirtualMachineStateActive || currentLcmState == lcmState) {
+		return true, nil
+	}
+
+	if vmFailureStates[currentState] {
+		return false, &errors.StateError{Resource: "virtual machine", ID: vmID,
+			State: resources.VirtualMachineStateMap[currentState]}
+	}
+
+	if currentState == resources.VirtualMachineStateActive && vmFailureLcmStates[currentLcmState] {
+		return false, &errors.StateError{Resource: "virtual machine", ID: vmID,
+			State: fmt.Sprintf("%s (LCM state %d)", resources.VirtualMachineStateMap[currentState],
+				currentLcmState)}
+	}
+
+	return false, nil
+}
+
 // RetrieveInfo retrieves information for the virtual machine.
 func (vms *VirtualMachineService) RetrieveInfo(ctx context.Context,
 	vmID int) (*resources.VirtualMachine, error) {
@@ -443,3 +532,177 @@ func (vms *VirtualMachineService) ListForUser(ctx context.Context, user resource
 
 	return vms.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize, stateFilter)
 }
+
+// Iterate returns iterator over the vms in the pool which belong to given owner(s) in ownership filter.
+// Pages of IteratorPageSizeDefault vms are retrieved lazily while iterating.
+func (vms *VirtualMachineService) Iterate(ctx context.Context, ownershipFilter OwnershipFilter,
+	stateFilter StateFilter) *VirtualMachineIterator {
+	return vms.IterateWithPageSize(ctx, IteratorPageSizeDefault, ownershipFilter, stateFilter)
+}
+
+// IterateWithPageSize returns iterator over the vms in the pool which belong to given owner(s)
+// in ownership filter. Pages of given size are retrieved lazily while iterating.
+func (vms *VirtualMachineService) IterateWithPageSize(ctx context.Context, pageSize int,
+	ownershipFilter OwnershipFilter, stateFilter StateFilter) *VirtualMachineIterator {
+	it := &VirtualMachineIterator{}
+	it.pager = newPager(ctx, pageSize, func(ctx context.Context, offset, pageSize int) (int, error) {
+		page, err := vms.list(ctx, int(ownershipFilter), offset, -pageSize, stateFilter)
+		if err != nil {
+			return 0, err
+		}
+
+		it.page = page
+		return len(page), nil
+	})
+
+	return it
+}
+
+// Find retrieves the vms in the pool matching given query.
+func (vms *VirtualMachineService) Find(ctx context.Context, query *PoolQuery) ([]*resources.VirtualMachine, error) {
+	if query.err != nil {
+		return nil, query.err
+	}
+
+	virtualMachines := make([]*resources.VirtualMachine, 0)
+
+	it := vms.Iterate(ctx, OwnershipFilter(query.ownership), query.state)
+	for it.Next() {
+		if query.Matches(&it.Value().Resource) {
+			virtualMachines = append(virtualMachines, it.Value())
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return virtualMachines, nil
+}
+
+// Accounting retrieves history records of the vms which belong to given owner(s) in ownership filter and
+// were running in the time range from start to end. Zero start or end means no limit.
+func (vms *VirtualMachineService) Accounting(ctx context.Context, ownershipFilter OwnershipFilter,
+	start, end time.Time) ([]*resources.History, error) {
+	resArr, err := vms.call(ctx, "one.vmpool.accounting", int(ownershipFilter), unixTimeOrNone(start),
+		unixTimeOrNone(end))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("HISTORY_RECORDS/HISTORY")
+
+	records := make([]*resources.History, len(elements))
+	for i, e := range elements {
+		if records[i], err = resources.ParseHistory(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// Showback retrieves monthly costs of the vms which belong to given owner(s) in ownership filter for
+// the months from start to end. Zero start or end means no limit.
+func (vms *VirtualMachineService) Showback(ctx context.Context, ownershipFilter OwnershipFilter,
+	start, end time.Time) ([]*resources.Showback, error) {
+	startMonth, startYear := monthOrNone(start)
+	endMonth, endYear := monthOrNone(end)
+
+	resArr, err := vms.call(ctx, "one.vmpool.showback", int(ownershipFilter), startMonth, startYear, endMonth,
+		endYear)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("SHOWBACK_RECORDS/SHOWBACK")
+
+	records := make([]*resources.Showback, len(elements))
+	for i, e := range elements {
+		if records[i], err = resources.ParseShowback(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// Usage aggregates CPU hours and memory hours of the vms which belong to given owner(s) in ownership filter
+// per user or group in the time range from start to end.
+func (vms *VirtualMachineService) Usage(ctx context.Context, ownershipFilter OwnershipFilter,
+	grouping resources.UsageGrouping, start, end time.Time) ([]*resources.Usage, error) {
+	records, err := vms.Accounting(ctx, ownershipFilter, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.AggregateUsage(records, grouping, start, end)
+}
+
+func unixTimeOrNone(t time.Time) int {
+	if t.IsZero() {
+		return -1
+	}
+
+	return int(t.Unix())
+}
+
+func monthOrNone(t time.Time) (int, int) {
+	if t.IsZero() {
+		return -1, -1
+	}
+
+	return int(t.Month()), t.Year()
+}
+
+func (vms *VirtualMachineService) monitoring(ctx context.Context, methodName string,
+	args ...interface{}) ([]*resources.VirtualMachineMonitoring, error) {
+	resArr, err := vms.call(ctx, methodName, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("MONITORING_DATA/VM")
+
+	samples := make([]*resources.VirtualMachineMonitoring, len(elements))
+	for i, e := range elements {
+		if samples[i], err = resources.ParseVirtualMachineMonitoring(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return samples, nil
+}
+
+// Monitoring retrieves monitoring samples of the virtual machine kept by OpenNebula.
+// Use resources.MonitoringRates to convert the counters to rates.
+func (vms *VirtualMachineService) Monitoring(ctx context.Context,
+	vm resources.VirtualMachine) ([]*resources.VirtualMachineMonitoring, error) {
+	vmID, err := vm.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return vms.monitoring(ctx, "one.vm.monitoring", vmID)
+}
+
+// PoolMonitoring retrieves monitoring samples of the vms which belong to given owner(s) in ownership filter.
+func (vms *VirtualMachineService) PoolMonitoring(ctx context.Context,
+	ownershipFilter OwnershipFilter) ([]*resources.VirtualMachineMonitoring, error) {
+	return vms.monitoring(ctx, "one.vmpool.monitoring", int(ownershipFilter))
+}
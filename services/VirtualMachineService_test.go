@@ -5,6 +5,7 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/onego-project/onego/requests"
 	"github.com/onego-project/onego/services"
@@ -62,14 +63,14 @@ const (
 	virtualMachineRenameEmpty   = "records/virtualMachine/renameEmpty"
 	virtualMachineRenameUnknown = "records/virtualMachine/renameUnknown"
 
-	//virtualMachineCreateSnapshot        = "records/virtualMachine/createSnapshot"
-	//virtualMachineCreateSnapshotWrongID = "records/virtualMachine/createSnapshotWrongID"
-	//
-	//virtualMachineRevertSnapshot        = "records/virtualMachine/revertSnapshot"
-	//virtualMachineRevertSnapshotWrongID = "records/virtualMachine/revertSnapshotWrongID"
-	//
-	//virtualMachineDeleteSnapshot        = "records/virtualMachine/deleteSnapshot"
-	//virtualMachineDeleteSnapshotWrongID = "records/virtualMachine/deleteSnapshotWrongID"
+	virtualMachineCreateSnapshot        = "records/onetest/virtualMachine/createSnapshot"
+	virtualMachineCreateSnapshotWrongID = "records/onetest/virtualMachine/createSnapshotWrongID"
+
+	virtualMachineRevertSnapshot        = "records/onetest/virtualMachine/revertSnapshot"
+	virtualMachineRevertSnapshotWrongID = "records/onetest/virtualMachine/revertSnapshotWrongID"
+
+	virtualMachineDeleteSnapshot        = "records/onetest/virtualMachine/deleteSnapshot"
+	virtualMachineDeleteSnapshotWrongID = "records/onetest/virtualMachine/deleteSnapshotWrongID"
 
 	virtualMachineResize        = "records/virtualMachine/resize"
 	virtualMachineResizeWrongID = "records/virtualMachine/resizeWrongID"
@@ -99,6 +100,15 @@ const (
 
 	virtualMachineListForUser        = "records/virtualMachine/listForUser"
 	virtualMachineListForUserWrongID = "records/virtualMachine/listForUserWrongID"
+
+	virtualMachineAccounting      = "records/onetest/virtualMachine/accounting"
+	virtualMachineAccountingRange = "records/onetest/virtualMachine/accountingRange"
+	virtualMachineShowback        = "records/onetest/virtualMachine/showback"
+	virtualMachineUsage           = "records/onetest/virtualMachine/usage"
+
+	virtualMachineMonitoring        = "records/onetest/virtualMachine/monitoring"
+	virtualMachineMonitoringUnknown = "records/onetest/virtualMachine/monitoringUnknown"
+	virtualMachinePoolMonitoring    = "records/onetest/virtualMachine/poolMonitoring"
 )
 
 var _ = ginkgo.Describe("Virtual Machine Service", func() {
@@ -1217,6 +1227,200 @@ var _ = ginkgo.Describe("Virtual Machine Service", func() {
 		})
 	})
 
+	ginkgo.Describe("virtual machine snapshots", func() {
+		var (
+			virtualMachine    *resources.VirtualMachine
+			oneVirtualMachine *resources.VirtualMachine
+			virtualMachineID  int
+			snapshot          *resources.Snapshot
+			snapshots         []*resources.Snapshot
+		)
+
+		ginkgo.Describe("create snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineCreateSnapshot
+
+					virtualMachineID = 131
+					virtualMachine = resources.CreateVirtualMachineWithID(virtualMachineID)
+					if virtualMachine == nil {
+						err = errors.ErrNoVirtualMachine
+					}
+				})
+
+				ginkgo.It("should create new snapshot of given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					snapshot, err = client.VirtualMachineService.CreateSnapshot(context.TODO(), *virtualMachine,
+						"before-update")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(snapshot).ShouldNot(gomega.BeNil())
+					gomega.Expect(snapshot.ID).To(gomega.Equal(1))
+					gomega.Expect(snapshot.Name).To(gomega.Equal("before-update"))
+
+					// check whether snapshot was really created in OpenNebula
+					oneVirtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), virtualMachineID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneVirtualMachine).ShouldNot(gomega.BeNil())
+
+					snapshots, err = oneVirtualMachine.Snapshots()
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(snapshots).To(gomega.HaveLen(2))
+					gomega.Expect(snapshots[1].ID).To(gomega.Equal(1))
+					gomega.Expect(snapshots[1].Name).To(gomega.Equal("before-update"))
+					gomega.Expect(snapshots[1].HypervisorID).To(gomega.Equal("onesnap-1"))
+					gomega.Expect(snapshots[1].Time).NotTo(gomega.BeNil())
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineCreateSnapshotWrongID
+
+					virtualMachineID = 1000
+					virtualMachine = resources.CreateVirtualMachineWithID(virtualMachineID)
+					if virtualMachine == nil {
+						err = errors.ErrNoVirtualMachine
+					}
+				})
+
+				ginkgo.It("should return that virtual machine with given ID doesn't exist", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					snapshot, err = client.VirtualMachineService.CreateSnapshot(context.TODO(), *virtualMachine,
+						"snap")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+					gomega.Expect(snapshot).Should(gomega.BeNil())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("should return that virtual machine has no ID", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					snapshot, err = client.VirtualMachineService.CreateSnapshot(context.TODO(),
+						resources.VirtualMachine{}, "snap")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+					gomega.Expect(snapshot).Should(gomega.BeNil())
+				})
+			})
+		})
+
+		ginkgo.Describe("revert snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineRevertSnapshot
+
+					virtualMachineID = 131
+					virtualMachine = resources.CreateVirtualMachineWithID(virtualMachineID)
+					if virtualMachine == nil {
+						err = errors.ErrNoVirtualMachine
+					}
+				})
+
+				ginkgo.It("should revert given virtual machine to the snapshot", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualMachineService.RevertSnapshot(context.TODO(), *virtualMachine,
+						resources.Snapshot{ID: 0})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineRevertSnapshotWrongID
+
+					virtualMachineID = 1000
+					virtualMachine = resources.CreateVirtualMachineWithID(virtualMachineID)
+					if virtualMachine == nil {
+						err = errors.ErrNoVirtualMachine
+					}
+				})
+
+				ginkgo.It("should return that virtual machine with given ID doesn't exist", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualMachineService.RevertSnapshot(context.TODO(), *virtualMachine,
+						resources.Snapshot{ID: 0})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("should return that virtual machine has no ID", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualMachineService.RevertSnapshot(context.TODO(), resources.VirtualMachine{},
+						resources.Snapshot{ID: 0})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Describe("delete snapshot", func() {
+			ginkgo.Context("when virtual machine exists", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDeleteSnapshot
+
+					virtualMachineID = 131
+					virtualMachine = resources.CreateVirtualMachineWithID(virtualMachineID)
+					if virtualMachine == nil {
+						err = errors.ErrNoVirtualMachine
+					}
+				})
+
+				ginkgo.It("should delete snapshot of given virtual machine", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualMachineService.DeleteSnapshot(context.TODO(), *virtualMachine,
+						resources.Snapshot{ID: 0})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether snapshot was really deleted in OpenNebula
+					oneVirtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), virtualMachineID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneVirtualMachine).ShouldNot(gomega.BeNil())
+
+					snapshots, err = oneVirtualMachine.Snapshots()
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(snapshots).To(gomega.HaveLen(1))
+					gomega.Expect(snapshots[0].ID).To(gomega.Equal(1))
+				})
+			})
+
+			ginkgo.Context("when virtual machine doesn't exist", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualMachineDeleteSnapshotWrongID
+
+					virtualMachineID = 1000
+					virtualMachine = resources.CreateVirtualMachineWithID(virtualMachineID)
+					if virtualMachine == nil {
+						err = errors.ErrNoVirtualMachine
+					}
+				})
+
+				ginkgo.It("should return that virtual machine with given ID doesn't exist", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualMachineService.DeleteSnapshot(context.TODO(), *virtualMachine,
+						resources.Snapshot{ID: 0})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+
+			ginkgo.Context("when virtual machine is empty", func() {
+				ginkgo.It("should return that virtual machine has no ID", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualMachineService.DeleteSnapshot(context.TODO(), resources.VirtualMachine{},
+						resources.Snapshot{ID: 0})
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+	})
+
 	ginkgo.Describe("Resize Virtual Machine", func() {
 		var (
 			virtualMachine    *resources.VirtualMachine
@@ -1669,6 +1873,7 @@ var _ = ginkgo.Describe("Virtual Machine Service", func() {
 
 				virtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 1000)
 				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
 				gomega.Expect(virtualMachine).Should(gomega.BeNil())
 			})
 		})
@@ -1877,4 +2082,172 @@ var _ = ginkgo.Describe("Virtual Machine Service", func() {
 			})
 		})
 	})
+
+	ginkgo.Describe("accounting", func() {
+		var records []*resources.History
+
+		ginkgo.Context("when time range is not limited", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineAccounting
+			})
+
+			ginkgo.It("should return history records with virtual machines", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				records, err = client.VirtualMachineService.Accounting(context.TODO(), services.OwnershipFilterAll,
+					time.Time{}, time.Time{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(2))
+
+				gomega.Expect(records[0].OID).To(gomega.Equal(250))
+				gomega.Expect(*records[0].ETime).To(gomega.BeTemporally("==", time.Date(2019, 2, 1, 2, 0, 0, 0,
+					time.UTC)))
+				gomega.Expect(records[0].Action).To(gomega.Equal(resources.ActionTerminateHard))
+				gomega.Expect(records[0].VM).To(gomega.Equal(&resources.HistoryVM{ID: 250, Name: "acct-1", UID: 50,
+					GID: 140, UName: "acct-user", GName: "billing", CPU: 2, Memory: 1024}))
+
+				gomega.Expect(records[1].OID).To(gomega.Equal(251))
+				gomega.Expect(records[1].ETime).To(gomega.BeNil())
+				gomega.Expect(records[1].VM.UID).To(gomega.Equal(0))
+			})
+		})
+
+		ginkgo.Context("when time range is limited", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineAccountingRange
+			})
+
+			ginkgo.It("should return only records active in the time range", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				records, err = client.VirtualMachineService.Accounting(context.TODO(), services.OwnershipFilterAll,
+					time.Date(2019, 2, 1, 3, 0, 0, 0, time.UTC), time.Time{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(records).To(gomega.HaveLen(1))
+				gomega.Expect(records[0].OID).To(gomega.Equal(251))
+			})
+		})
+	})
+
+	ginkgo.Describe("showback", func() {
+		var records []*resources.Showback
+
+		ginkgo.BeforeEach(func() {
+			recName = virtualMachineShowback
+		})
+
+		ginkgo.It("should return monthly costs of virtual machines", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			records, err = client.VirtualMachineService.Showback(context.TODO(), services.OwnershipFilterAll,
+				time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(records).To(gomega.HaveLen(4))
+			gomega.Expect(records[0]).To(gomega.Equal(&resources.Showback{VMID: 250, VMName: "acct-1", UID: 50,
+				GID: 140, UName: "acct-user", GName: "billing", Year: 2019, Month: 1, CPUCost: 2, MemoryCost: 2.048,
+				TotalCost: 4.048, Hours: 2}))
+			gomega.Expect(records[3].VMID).To(gomega.Equal(251))
+			gomega.Expect(records[3].Month).To(gomega.Equal(2))
+			gomega.Expect(records[3].Hours).To(gomega.Equal(float64(4)))
+
+			records, err = client.VirtualMachineService.Showback(context.TODO(), services.OwnershipFilterAll,
+				time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(records).To(gomega.HaveLen(2))
+			gomega.Expect(records[0].Month).To(gomega.Equal(2))
+		})
+	})
+
+	ginkgo.Describe("usage", func() {
+		var usage []*resources.Usage
+
+		ginkgo.BeforeEach(func() {
+			recName = virtualMachineUsage
+		})
+
+		ginkgo.It("should aggregate CPU and memory hours per user and group", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			start := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2019, 2, 1, 3, 0, 0, 0, time.UTC)
+
+			usage, err = client.VirtualMachineService.Usage(context.TODO(), services.OwnershipFilterAll,
+				resources.UsageByUser, start, end)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage).To(gomega.Equal([]*resources.Usage{
+				{ID: 0, Name: "oneadmin", CPUHours: 3, MemoryHours: 1536},
+				{ID: 50, Name: "acct-user", CPUHours: 4, MemoryHours: 2048}}))
+
+			usage, err = client.VirtualMachineService.Usage(context.TODO(), services.OwnershipFilterAll,
+				resources.UsageByGroup, start, end)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(usage).To(gomega.HaveLen(2))
+			gomega.Expect(usage[1]).To(gomega.Equal(&resources.Usage{ID: 140, Name: "billing", CPUHours: 4,
+				MemoryHours: 2048}))
+		})
+	})
+
+	ginkgo.Describe("monitoring", func() {
+		var samples []*resources.VirtualMachineMonitoring
+
+		ginkgo.Context("when virtual machine exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineMonitoring
+			})
+
+			ginkgo.It("should return monitoring samples of the virtual machine", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				samples, err = client.VirtualMachineService.Monitoring(context.TODO(),
+					*resources.CreateVirtualMachineWithID(260))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(samples).To(gomega.HaveLen(3))
+				gomega.Expect(samples[0].VMID).To(gomega.Equal(260))
+				gomega.Expect(samples[0].Timestamp).To(gomega.BeTemporally("==",
+					time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)))
+				gomega.Expect(samples[0].CPU).To(gomega.Equal(12.5))
+				gomega.Expect(samples[0].Memory).To(gomega.Equal(524288))
+				gomega.Expect(samples[1].NetRX).To(gomega.Equal(int64(7000)))
+				gomega.Expect(samples[1].DiskReadOperations).To(gomega.Equal(int64(70)))
+
+				rates := resources.MonitoringRates(samples)
+				gomega.Expect(rates).To(gomega.HaveLen(2))
+				gomega.Expect(rates[0].NetRX).To(gomega.Equal(float64(100)))
+				gomega.Expect(rates[0].DiskReadBytes).To(gomega.Equal(float64(1024)))
+				gomega.Expect(rates[1].NetRX).To(gomega.Equal(float64(10)))
+				gomega.Expect(rates[1].DiskWriteOperations).To(gomega.Equal(0.1))
+			})
+		})
+
+		ginkgo.Context("when virtual machine doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineMonitoringUnknown
+			})
+
+			ginkgo.It("should return an error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				samples, err = client.VirtualMachineService.Monitoring(context.TODO(),
+					*resources.CreateVirtualMachineWithID(1000))
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(samples).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when retrieving monitoring of the pool", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachinePoolMonitoring
+			})
+
+			ginkgo.It("should return monitoring samples of all virtual machines", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				samples, err = client.VirtualMachineService.PoolMonitoring(context.TODO(), services.OwnershipFilterAll)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(samples).To(gomega.HaveLen(4))
+				gomega.Expect(samples[3].VMID).To(gomega.Equal(261))
+				gomega.Expect(samples[3].CPU).To(gomega.Equal(float64(3)))
+			})
+		})
+	})
 })
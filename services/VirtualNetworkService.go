@@ -171,3 +171,49 @@ func (vns *VirtualNetworkService) ListForUser(ctx context.Context, user resource
 
 	return vns.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
 }
+
+// Iterate returns iterator over the virtual networks in the pool which belong to given owner(s) in ownership filter.
+// Pages of IteratorPageSizeDefault virtual networks are retrieved lazily while iterating.
+func (vns *VirtualNetworkService) Iterate(ctx context.Context, filter OwnershipFilter) *VirtualNetworkIterator {
+	return vns.IterateWithPageSize(ctx, IteratorPageSizeDefault, filter)
+}
+
+// IterateWithPageSize returns iterator over the virtual networks in the pool which belong to given owner(s)
+// in ownership filter. Pages of given size are retrieved lazily while iterating.
+func (vns *VirtualNetworkService) IterateWithPageSize(ctx context.Context, pageSize int,
+	filter OwnershipFilter) *VirtualNetworkIterator {
+	it := &VirtualNetworkIterator{}
+	it.pager = newPager(ctx, pageSize, func(ctx context.Context, offset, pageSize int) (int, error) {
+		page, err := vns.list(ctx, int(filter), offset, -pageSize)
+		if err != nil {
+			return 0, err
+		}
+
+		it.page = page
+		return len(page), nil
+	})
+
+	return it
+}
+
+// Find retrieves the virtual networks in the pool matching given query.
+func (vns *VirtualNetworkService) Find(ctx context.Context, query *PoolQuery) ([]*resources.VirtualNetwork, error) {
+	if query.err != nil {
+		return nil, query.err
+	}
+
+	virtualNetworks := make([]*resources.VirtualNetwork, 0)
+
+	it := vns.Iterate(ctx, OwnershipFilter(query.ownership))
+	for it.Next() {
+		if query.Matches(&it.Value().Resource) {
+			virtualNetworks = append(virtualNetworks, it.Value())
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return virtualNetworks, nil
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+
+	"github.com/beevik/etree"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+)
+
+// VirtualRouterService structure to manage OpenNebula virtual router.
+type VirtualRouterService struct {
+	Service
+}
+
+// Allocate allocates a new virtual router in OpenNebula.
+func (vrs *VirtualRouterService) Allocate(ctx context.Context,
+	blueprint blueprint.Interface) (*resources.VirtualRouter, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := vrs.call(ctx, "one.vrouter.allocate", blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return vrs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Instantiate creates given number of virtual machines of the virtual router from the template. Name of the
+// virtual machines may contain "%i" which is replaced by the index of the virtual machine.
+func (vrs *VirtualRouterService) Instantiate(ctx context.Context, virtualRouter resources.VirtualRouter, number int,
+	template resources.Template, name string, onHold bool,
+	blueprint blueprint.Interface) (*resources.VirtualRouter, error) {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	templateID, err := template.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := vrs.call(ctx, "one.vrouter.instantiate", virtualRouterID, number, templateID, name, onHold,
+		blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return vrs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// AttachNIC attaches a new network interface to the virtual router and its virtual machines.
+func (vrs *VirtualRouterService) AttachNIC(ctx context.Context, virtualRouter resources.VirtualRouter,
+	nic blueprint.NICBlueprint) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	nicText, err := nic.Render()
+	if err != nil {
+		return err
+	}
+
+	_, err = vrs.call(ctx, "one.vrouter.attachnic", virtualRouterID, "<TEMPLATE>"+nicText+"</TEMPLATE>")
+
+	return err
+}
+
+// DetachNIC detaches a network interface from the virtual router and its virtual machines.
+func (vrs *VirtualRouterService) DetachNIC(ctx context.Context, virtualRouter resources.VirtualRouter,
+	nic resources.VirtualRouterNIC) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vrs.call(ctx, "one.vrouter.detachnic", virtualRouterID, nic.NicID)
+
+	return err
+}
+
+// VirtualMachines retrieves information for all the virtual machines of the virtual router.
+func (vrs *VirtualRouterService) VirtualMachines(ctx context.Context,
+	virtualRouter resources.VirtualRouter) ([]*resources.VirtualMachine, error) {
+	vmIDs, err := virtualRouter.VirtualMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	vmService := VirtualMachineService{Service: vrs.Service}
+
+	virtualMachines := make([]*resources.VirtualMachine, len(vmIDs))
+	for i, vmID := range vmIDs {
+		virtualMachines[i], err = vmService.RetrieveInfo(ctx, vmID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return virtualMachines, nil
+}
+
+// Delete deletes the given virtual router from the pool.
+func (vrs *VirtualRouterService) Delete(ctx context.Context, virtualRouter resources.VirtualRouter) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vrs.call(ctx, "one.vrouter.delete", virtualRouterID)
+
+	return err
+}
+
+// Update merges or replaces the virtual router template contents.
+func (vrs *VirtualRouterService) Update(ctx context.Context, virtualRouter resources.VirtualRouter,
+	blueprint blueprint.Interface, updateType UpdateType) (*resources.VirtualRouter, error) {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := vrs.call(ctx, "one.vrouter.update", virtualRouterID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return vrs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Chmod changes the permission bits of a virtual router.
+func (vrs *VirtualRouterService) Chmod(ctx context.Context, virtualRouter resources.VirtualRouter,
+	request requests.PermissionRequest) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	return vrs.chmod(ctx, "one.vrouter.chmod", virtualRouterID, request)
+}
+
+// Chown changes the ownership of a virtual router.
+func (vrs *VirtualRouterService) Chown(ctx context.Context, virtualRouter resources.VirtualRouter,
+	request requests.OwnershipRequest) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	return vrs.chown(ctx, "one.vrouter.chown", virtualRouterID, request)
+}
+
+// Rename renames a virtual router.
+func (vrs *VirtualRouterService) Rename(ctx context.Context, virtualRouter resources.VirtualRouter, name string) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = vrs.call(ctx, "one.vrouter.rename", virtualRouterID, name)
+
+	return err
+}
+
+// Lock locks a virtual router, actions of given level and the levels above are blocked.
+func (vrs *VirtualRouterService) Lock(ctx context.Context, virtualRouter resources.VirtualRouter,
+	level resources.LockLevel) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	return vrs.lock(ctx, "one.vrouter.lock", virtualRouterID, level)
+}
+
+// Unlock unlocks a virtual router.
+func (vrs *VirtualRouterService) Unlock(ctx context.Context, virtualRouter resources.VirtualRouter) error {
+	virtualRouterID, err := virtualRouter.ID()
+	if err != nil {
+		return err
+	}
+
+	return vrs.unlock(ctx, "one.vrouter.unlock", virtualRouterID)
+}
+
+// RetrieveInfo retrieves information for the virtual router.
+func (vrs *VirtualRouterService) RetrieveInfo(ctx context.Context,
+	virtualRouterID int) (*resources.VirtualRouter, error) {
+	doc, err := vrs.retrieveInfo(ctx, "one.vrouter.info", virtualRouterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateVirtualRouterFromXML(doc.Root()), nil
+}
+
+func (vrs *VirtualRouterService) list(ctx context.Context, filterFlag, pageOffset,
+	pageSize int) ([]*resources.VirtualRouter, error) {
+	resArr, err := vrs.call(ctx, "one.vrouterpool.info", filterFlag, pageOffset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromString(resArr[resultIndex].ResultString()); err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("VROUTER_POOL/VROUTER")
+
+	virtualRouters := make([]*resources.VirtualRouter, len(elements))
+	for i, e := range elements {
+		virtualRouters[i] = resources.CreateVirtualRouterFromXML(e)
+	}
+
+	return virtualRouters, nil
+}
+
+// ListAll retrieves information for all the virtual routers in the pool.
+func (vrs *VirtualRouterService) ListAll(ctx context.Context,
+	filter OwnershipFilter) ([]*resources.VirtualRouter, error) {
+	return vrs.list(ctx, int(filter), pageOffsetDefault, pageSizeDefault)
+}
+
+// ListAllForUser retrieves information for all the virtual routers for the given user in the pool.
+func (vrs *VirtualRouterService) ListAllForUser(ctx context.Context,
+	user resources.User) ([]*resources.VirtualRouter, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return vrs.list(ctx, userID, pageOffsetDefault, pageSizeDefault)
+}
+
+// List retrieves information for a part of the virtual routers in the pool with a given pagination.
+func (vrs *VirtualRouterService) List(ctx context.Context, pageOffset, pageSize int,
+	filter OwnershipFilter) ([]*resources.VirtualRouter, error) {
+	return vrs.list(ctx, int(filter), (pageOffset-1)*pageSize, -pageSize)
+}
+
+// ListForUser retrieves information for a part of the virtual routers for given user in the pool
+// with a given pagination.
+func (vrs *VirtualRouterService) ListForUser(ctx context.Context, user resources.User, pageOffset,
+	pageSize int) ([]*resources.VirtualRouter, error) {
+	userID, err := user.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return vrs.list(ctx, userID, (pageOffset-1)*pageSize, -pageSize)
+}
@@ -0,0 +1,1034 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/onego-project/onego/services"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/requests"
+	"github.com/onego-project/onego/resources"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	virtualRouterAllocate               = "records/onetest/virtualRouter/allocate"
+	virtualRouterAllocateExisting       = "records/onetest/virtualRouter/allocateExisting"
+	virtualRouterAllocateUnknownNetwork = "records/onetest/virtualRouter/allocateUnknownNetwork"
+
+	virtualRouterInstantiate                = "records/onetest/virtualRouter/instantiate"
+	virtualRouterInstantiateUnknownTemplate = "records/onetest/virtualRouter/instantiateUnknownTemplate"
+
+	virtualRouterAttachNIC        = "records/onetest/virtualRouter/attachNIC"
+	virtualRouterAttachNICUnknown = "records/onetest/virtualRouter/attachNICUnknown"
+	virtualRouterAttachNICLocked  = "records/onetest/virtualRouter/attachNICLocked"
+	virtualRouterAttachNICTwice   = "records/onetest/virtualRouter/attachNICTwice"
+
+	virtualRouterDetachNIC           = "records/onetest/virtualRouter/detachNIC"
+	virtualRouterDetachNICUnknownNIC = "records/onetest/virtualRouter/detachNICUnknownNIC"
+
+	virtualRouterDelete        = "records/onetest/virtualRouter/delete"
+	virtualRouterDeleteWrongID = "records/onetest/virtualRouter/deleteWrongID"
+
+	virtualRouterUpdateMerge   = "records/onetest/virtualRouter/updateMerge"
+	virtualRouterUpdateUnknown = "records/onetest/virtualRouter/updateUnknown"
+
+	virtualRouterChmod        = "records/onetest/virtualRouter/chmod"
+	virtualRouterChmodUnknown = "records/onetest/virtualRouter/chmodUnknown"
+
+	virtualRouterChown        = "records/onetest/virtualRouter/chown"
+	virtualRouterChownUnknown = "records/onetest/virtualRouter/chownUnknown"
+
+	virtualRouterRename        = "records/onetest/virtualRouter/rename"
+	virtualRouterRenameEmpty   = "records/onetest/virtualRouter/renameEmpty"
+	virtualRouterRenameUnknown = "records/onetest/virtualRouter/renameUnknown"
+
+	virtualRouterLock        = "records/onetest/virtualRouter/lock"
+	virtualRouterLockUnknown = "records/onetest/virtualRouter/lockUnknown"
+
+	virtualRouterRetrieveInfo        = "records/onetest/virtualRouter/retrieveInfo"
+	virtualRouterRetrieveInfoUnknown = "records/onetest/virtualRouter/retrieveInfoUnknown"
+
+	virtualRouterListAllAll = "records/onetest/virtualRouter/listAllAll"
+
+	virtualRouterListAllForUser = "records/onetest/virtualRouter/listAllForUser"
+
+	virtualRouterListPagination = "records/onetest/virtualRouter/listPagination"
+
+	virtualRouterListForUser = "records/onetest/virtualRouter/listForUser"
+)
+
+var _ = ginkgo.Describe("Virtual Router Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var existingVirtualRouterID = 0
+	var deletedVirtualRouterID = 1
+	var lockedVirtualRouterID = 3
+	var nonExistingVirtualRouterID = 420
+
+	var publicNetworkID = 400
+	var privateNetworkID = 401
+	var routerTemplateID = 410
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate virtual router", func() {
+		var (
+			virtualRouter          *resources.VirtualRouter
+			virtualRouterBlueprint *blueprint.VirtualRouterBlueprint
+			nicBlueprint           *blueprint.NICBlueprint
+		)
+
+		ginkgo.BeforeEach(func() {
+			nicBlueprint = blueprint.CreateNICBlueprint()
+			nicBlueprint.SetFloatingIP(true)
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAllocate
+
+				nicBlueprint.SetNetworkID(publicNetworkID)
+
+				virtualRouterBlueprint = blueprint.CreateAllocateVirtualRouterBlueprint()
+				virtualRouterBlueprint.SetName("border")
+				virtualRouterBlueprint.SetDescription("border router")
+				virtualRouterBlueprint.SetNIC(*nicBlueprint)
+			})
+
+			ginkgo.It("should create new virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouter, err = client.VirtualRouterService.Allocate(context.TODO(), virtualRouterBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualRouter).ShouldNot(gomega.BeNil())
+				gomega.Expect(virtualRouter.ID()).To(gomega.Equal(4))
+				gomega.Expect(virtualRouter.Name()).To(gomega.Equal("border"))
+				gomega.Expect(virtualRouter.Description()).To(gomega.Equal("border router"))
+				gomega.Expect(virtualRouter.VirtualMachines()).To(gomega.HaveLen(0))
+
+				var nics []*resources.VirtualRouterNIC
+				nics, err = virtualRouter.NICs()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(nics).To(gomega.HaveLen(1))
+				gomega.Expect(nics[0].NicID).To(gomega.Equal(0))
+				gomega.Expect(nics[0].Network).To(gomega.Equal("public"))
+				gomega.Expect(nics[0].NetworkID).To(gomega.Equal(publicNetworkID))
+				gomega.Expect(nics[0].FloatingIP).To(gomega.BeTrue())
+				gomega.Expect(nics[0].IP).ShouldNot(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAllocateExisting
+
+				virtualRouterBlueprint = blueprint.CreateAllocateVirtualRouterBlueprint()
+				virtualRouterBlueprint.SetName("gateway")
+			})
+
+			ginkgo.It("should return that virtual router already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouter, err = client.VirtualRouterService.Allocate(context.TODO(), virtualRouterBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(virtualRouter).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual network doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAllocateUnknownNetwork
+
+				nicBlueprint.SetNetworkID(420)
+
+				virtualRouterBlueprint = blueprint.CreateAllocateVirtualRouterBlueprint()
+				virtualRouterBlueprint.SetName("nowhere")
+				virtualRouterBlueprint.SetNIC(*nicBlueprint)
+			})
+
+			ginkgo.It("shouldn't create new virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouter, err = client.VirtualRouterService.Allocate(context.TODO(), virtualRouterBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(virtualRouter).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouter, err = client.VirtualRouterService.Allocate(context.TODO(),
+					&blueprint.VirtualRouterBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(virtualRouter).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("instantiate virtual router", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+		)
+
+		ginkgo.BeforeEach(func() {
+			virtualRouter = resources.CreateVirtualRouterWithID(4)
+			if virtualRouter == nil {
+				err = errors.ErrNoVirtualRouter
+			}
+		})
+
+		ginkgo.Context("when template exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterInstantiate
+			})
+
+			ginkgo.It("should create virtual machines of the virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				oneVirtualRouter, err = client.VirtualRouterService.Instantiate(context.TODO(), *virtualRouter, 2,
+					*resources.CreateTemplateWithID(routerTemplateID), "border-%i", false,
+					blueprint.CreateUpdateTemplateBlueprint())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter).ShouldNot(gomega.BeNil())
+				gomega.Expect(oneVirtualRouter.VirtualMachines()).To(gomega.Equal([]int{303, 304}))
+
+				var virtualMachines []*resources.VirtualMachine
+				virtualMachines, err = client.VirtualRouterService.VirtualMachines(context.TODO(), *oneVirtualRouter)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualMachines).To(gomega.HaveLen(2))
+				gomega.Expect(virtualMachines[0].Name()).To(gomega.Equal("border-0"))
+				gomega.Expect(virtualMachines[1].Name()).To(gomega.Equal("border-1"))
+
+				var nics []*resources.NIC
+				nics, err = virtualMachines[1].NICs()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(nics).To(gomega.HaveLen(1))
+				gomega.Expect(nics[0].NetworkID).To(gomega.Equal(publicNetworkID))
+			})
+		})
+
+		ginkgo.Context("when template doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterInstantiateUnknownTemplate
+			})
+
+			ginkgo.It("should return that template doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				oneVirtualRouter, err = client.VirtualRouterService.Instantiate(context.TODO(), *virtualRouter, 1,
+					*resources.CreateTemplateWithID(420), "", false, blueprint.CreateUpdateTemplateBlueprint())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				oneVirtualRouter, err = client.VirtualRouterService.Instantiate(context.TODO(),
+					resources.VirtualRouter{}, 1, *resources.CreateTemplateWithID(routerTemplateID), "", false,
+					blueprint.CreateUpdateTemplateBlueprint())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router attach NIC", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+			nicBlueprint     *blueprint.NICBlueprint
+		)
+
+		ginkgo.BeforeEach(func() {
+			nicBlueprint = blueprint.CreateNICBlueprint()
+			nicBlueprint.SetNetworkID(privateNetworkID)
+			nicBlueprint.SetVirtualRouterManagement(true)
+		})
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAttachNIC
+
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should attach NIC to virtual router and its virtual machines", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.AttachNIC(context.TODO(), *virtualRouter, *nicBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether NIC was really attached in OpenNebula
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var nics []*resources.VirtualRouterNIC
+				nics, err = oneVirtualRouter.NICs()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(nics).To(gomega.HaveLen(3))
+				gomega.Expect(nics[2].NicID).To(gomega.Equal(2))
+				gomega.Expect(nics[2].Management).To(gomega.BeTrue())
+
+				var virtualMachines []*resources.VirtualMachine
+				virtualMachines, err = client.VirtualRouterService.VirtualMachines(context.TODO(), *oneVirtualRouter)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualMachines).To(gomega.HaveLen(2))
+
+				var vmNICs []*resources.NIC
+				vmNICs, err = virtualMachines[0].NICs()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(vmNICs).To(gomega.HaveLen(3))
+			})
+		})
+
+		ginkgo.Context("when the same NIC blueprint is attached twice", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAttachNICTwice
+
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should attach two NICs without changing the blueprint", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.AttachNIC(context.TODO(), *virtualRouter, *nicBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				err = client.VirtualRouterService.AttachNIC(context.TODO(), *virtualRouter, *nicBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(nicBlueprint.XMLData.FindElement("NIC/NETWORK_ID").Text()).To(
+					gomega.Equal(strconv.Itoa(privateNetworkID)))
+
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var nics []*resources.VirtualRouterNIC
+				nics, err = oneVirtualRouter.NICs()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(nics).To(gomega.HaveLen(3))
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAttachNICUnknown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.AttachNIC(context.TODO(), *virtualRouter, *nicBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual router is locked", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterAttachNICLocked
+
+				virtualRouter = resources.CreateVirtualRouterWithID(lockedVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router is locked", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.AttachNIC(context.TODO(), *virtualRouter, *nicBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.AttachNIC(context.TODO(), resources.VirtualRouter{}, *nicBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router detach NIC", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+		)
+
+		ginkgo.BeforeEach(func() {
+			virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+			if virtualRouter == nil {
+				err = errors.ErrNoVirtualRouter
+			}
+		})
+
+		ginkgo.Context("when NIC exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterDetachNIC
+			})
+
+			ginkgo.It("should detach NIC from virtual router and release its floating IP", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.DetachNIC(context.TODO(), *virtualRouter,
+					resources.VirtualRouterNIC{NicID: 0})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether NIC was really detached in OpenNebula
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var nics []*resources.VirtualRouterNIC
+				nics, err = oneVirtualRouter.NICs()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(nics).To(gomega.HaveLen(2))
+				gomega.Expect(nics[0].NetworkID).To(gomega.Equal(privateNetworkID))
+
+				var virtualNetwork *resources.VirtualNetwork
+				virtualNetwork, err = client.VirtualNetworkService.RetrieveInfo(context.TODO(), publicNetworkID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualNetwork.VirtualRouters()).To(gomega.Equal([]int{4}))
+			})
+		})
+
+		ginkgo.Context("when NIC doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterDetachNICUnknownNIC
+			})
+
+			ginkgo.It("should return that NIC doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.DetachNIC(context.TODO(), *virtualRouter,
+					resources.VirtualRouterNIC{NicID: 7})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete virtual router", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+		)
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterDelete
+
+				virtualRouter = resources.CreateVirtualRouterWithID(deletedVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should delete virtual router and its virtual machines", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Delete(context.TODO(), *virtualRouter)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether virtual router was really deleted in OpenNebula
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					deletedVirtualRouterID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter).Should(gomega.BeNil())
+
+				var virtualMachine *resources.VirtualMachine
+				virtualMachine, err = client.VirtualMachineService.RetrieveInfo(context.TODO(), 302)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualMachine.State()).To(gomega.Equal(resources.VirtualMachineStateDone))
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterDeleteWrongID
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Delete(context.TODO(), *virtualRouter)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Delete(context.TODO(), resources.VirtualRouter{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update virtual router", func() {
+		var (
+			virtualRouter          *resources.VirtualRouter
+			virtualRouterBlueprint *blueprint.VirtualRouterBlueprint
+			retVirtualRouter       *resources.VirtualRouter
+		)
+
+		ginkgo.BeforeEach(func() {
+			virtualRouterBlueprint = blueprint.CreateUpdateVirtualRouterBlueprint()
+			if virtualRouterBlueprint == nil {
+				err = errors.ErrNoVirtualRouterBlueprint
+				return
+			}
+			virtualRouterBlueprint.SetDescription("default gateway")
+		})
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterUpdateMerge
+
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should merge data of given virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVirtualRouter, err = client.VirtualRouterService.Update(context.TODO(), *virtualRouter,
+					virtualRouterBlueprint, services.Merge)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retVirtualRouter).ShouldNot(gomega.BeNil())
+				gomega.Expect(retVirtualRouter.Description()).To(gomega.Equal("default gateway"))
+				gomega.Expect(retVirtualRouter.NICs()).To(gomega.HaveLen(2))
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterUpdateUnknown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVirtualRouter, err = client.VirtualRouterService.Update(context.TODO(), *virtualRouter,
+					virtualRouterBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retVirtualRouter).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retVirtualRouter, err = client.VirtualRouterService.Update(context.TODO(),
+					*resources.CreateVirtualRouterWithID(existingVirtualRouterID), &blueprint.VirtualRouterBlueprint{},
+					services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retVirtualRouter).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router chmod", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+			permRequest      requests.PermissionRequest
+		)
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterChmod
+
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should change permission of given virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.Group,
+					requests.Use).Allow(requests.Other, requests.Use).Build()
+
+				err = client.VirtualRouterService.Chmod(context.TODO(), *virtualRouter, permRequest)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chmod was really changed in OpenNebula
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(), existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter).ShouldNot(gomega.BeNil())
+
+				var perm *resources.Permissions
+				perm, err = oneVirtualRouter.Permissions()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Expect(perm.Group.Use).To(gomega.Equal(true))
+				gomega.Expect(perm.Other.Use).To(gomega.Equal(true))
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterChmodUnknown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				permRequest = requests.CreatePermissionRequestBuilder().Allow(requests.User,
+					requests.Manage).Build()
+
+				err = client.VirtualRouterService.Chmod(context.TODO(), *virtualRouter, permRequest)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Chmod(context.TODO(), resources.VirtualRouter{}, requests.PermissionRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router chown", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+			ownershipReq     requests.OwnershipRequest
+		)
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterChown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should change owner of given virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				userID := 70
+				groupID := 170
+
+				ownershipReq = requests.CreateOwnershipRequestBuilder().User(*resources.CreateUserWithID(userID)).
+					Group(*resources.CreateGroupWithID(groupID)).Build()
+
+				err = client.VirtualRouterService.Chown(context.TODO(), *virtualRouter, ownershipReq)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether chown was really changed in OpenNebula
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(), existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter).ShouldNot(gomega.BeNil())
+
+				gomega.Expect(oneVirtualRouter.User()).To(gomega.Equal(userID))
+				gomega.Expect(oneVirtualRouter.Group()).To(gomega.Equal(groupID))
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterChownUnknown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Chown(context.TODO(), *virtualRouter,
+					requests.CreateOwnershipRequestBuilder().Build())
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Chown(context.TODO(), resources.VirtualRouter{}, requests.OwnershipRequest{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router rename", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+		)
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualRouterRename
+				})
+
+				ginkgo.It("should change name of given virtual router", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualRouterService.Rename(context.TODO(), *virtualRouter, "gw")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(), existingVirtualRouterID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneVirtualRouter).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneVirtualRouter.Name()).To(gomega.Equal("gw"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = virtualRouterRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given virtual router", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.VirtualRouterService.Rename(context.TODO(), *virtualRouter, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterRenameUnknown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Rename(context.TODO(), *virtualRouter, "router")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Rename(context.TODO(), resources.VirtualRouter{}, "router")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router lock", func() {
+		var (
+			virtualRouter    *resources.VirtualRouter
+			oneVirtualRouter *resources.VirtualRouter
+		)
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterLock
+
+				virtualRouter = resources.CreateVirtualRouterWithID(existingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should lock and unlock given virtual router", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Lock(context.TODO(), *virtualRouter, resources.LockUse)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether virtual router was really locked in OpenNebula
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var lock *resources.Lock
+				lock, err = oneVirtualRouter.Lock()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lock).ShouldNot(gomega.BeNil())
+				gomega.Expect(lock.Level).To(gomega.Equal(resources.LockUse))
+
+				err = client.VirtualRouterService.DetachNIC(context.TODO(), *virtualRouter,
+					resources.VirtualRouterNIC{NicID: 1})
+				gomega.Expect(errors.IsLocked(err)).To(gomega.BeTrue())
+
+				err = client.VirtualRouterService.Unlock(context.TODO(), *virtualRouter)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				oneVirtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualRouter.Lock()).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterLockUnknown
+
+				virtualRouter = resources.CreateVirtualRouterWithID(nonExistingVirtualRouterID)
+				if virtualRouter == nil {
+					err = errors.ErrNoVirtualRouter
+				}
+			})
+
+			ginkgo.It("should return that virtual router with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Lock(context.TODO(), *virtualRouter, resources.LockUse)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when virtual router is empty", func() {
+			ginkgo.It("should return that virtual router has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.VirtualRouterService.Unlock(context.TODO(), resources.VirtualRouter{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router retrieve info", func() {
+		var virtualRouter *resources.VirtualRouter
+
+		ginkgo.Context("when virtual router exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterRetrieveInfo
+			})
+
+			ginkgo.It("should return virtual router with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(), existingVirtualRouterID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualRouter).ShouldNot(gomega.BeNil())
+				gomega.Expect(virtualRouter.ID()).To(gomega.Equal(existingVirtualRouterID))
+				gomega.Expect(virtualRouter.Name()).To(gomega.Equal("gw"))
+				gomega.Expect(virtualRouter.VirtualMachines()).To(gomega.Equal([]int{300, 301}))
+			})
+		})
+
+		ginkgo.Context("when virtual router doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given virtual router doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouter, err = client.VirtualRouterService.RetrieveInfo(context.TODO(),
+					nonExistingVirtualRouterID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(virtualRouter).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router list all", func() {
+		var virtualRouters []*resources.VirtualRouter
+
+		ginkgo.BeforeEach(func() {
+			recName = virtualRouterListAllAll
+		})
+
+		ginkgo.It("should return list of all virtual routers with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			virtualRouters, err = client.VirtualRouterService.ListAll(context.TODO(), services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualRouters).To(gomega.HaveLen(4))
+			gomega.Expect(virtualRouters[0].Name()).To(gomega.Equal("gw"))
+			gomega.Expect(virtualRouters[3].Name()).To(gomega.Equal("border"))
+		})
+	})
+
+	ginkgo.Describe("virtual router list all for user", func() {
+		var virtualRouters []*resources.VirtualRouter
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterListAllForUser
+			})
+
+			ginkgo.It("should return virtual routers with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouters, err = client.VirtualRouterService.ListAllForUser(context.TODO(),
+					*resources.CreateUserWithID(70))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualRouters).To(gomega.HaveLen(2))
+				gomega.Expect(virtualRouters[0].ID()).To(gomega.Equal(existingVirtualRouterID))
+				gomega.Expect(virtualRouters[1].ID()).To(gomega.Equal(2))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouters, err = client.VirtualRouterService.ListAllForUser(context.TODO(), resources.User{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(virtualRouters).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("virtual router list with pagination", func() {
+		var virtualRouters []*resources.VirtualRouter
+
+		ginkgo.BeforeEach(func() {
+			recName = virtualRouterListPagination
+		})
+
+		ginkgo.It("should return virtual routers with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			virtualRouters, err = client.VirtualRouterService.List(context.TODO(), 2, 2, services.OwnershipFilterAll)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(virtualRouters).To(gomega.HaveLen(2))
+			gomega.Expect(virtualRouters[0].ID()).To(gomega.Equal(lockedVirtualRouterID))
+			gomega.Expect(virtualRouters[1].ID()).To(gomega.Equal(4))
+		})
+	})
+
+	ginkgo.Describe("virtual router list for user", func() {
+		var virtualRouters []*resources.VirtualRouter
+
+		ginkgo.Context("when user exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualRouterListForUser
+			})
+
+			ginkgo.It("should return virtual routers with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouters, err = client.VirtualRouterService.ListForUser(context.TODO(),
+					*resources.CreateUserWithID(0), 1, 2)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(virtualRouters).To(gomega.HaveLen(2))
+				gomega.Expect(virtualRouters[0].ID()).To(gomega.Equal(lockedVirtualRouterID))
+				gomega.Expect(virtualRouters[1].ID()).To(gomega.Equal(4))
+			})
+		})
+
+		ginkgo.Context("when user is empty", func() {
+			ginkgo.It("should return that user doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				virtualRouters, err = client.VirtualRouterService.ListForUser(context.TODO(), resources.User{}, 2, 2)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(virtualRouters).Should(gomega.BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff structure defines delays between the attempts to check the state of a resource.
+// The delay starts at InitialDelay and is multiplied by Multiplier after each attempt,
+// it never exceeds MaxDelay. Zero or negative delays and Multiplier less than 1 are replaced by the values
+// of DefaultBackoff, delays shorter than a millisecond are prolonged to a millisecond.
+type Backoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+}
+
+// DefaultBackoff is used by WaitForState methods.
+var DefaultBackoff = Backoff{InitialDelay: time.Second, MaxDelay: 30 * time.Second, Multiplier: 2}
+
+// minBackoffDelay is the shortest delay between the attempts.
+const minBackoffDelay = time.Millisecond
+
+// withDefaults returns copy of the backoff with invalid fields taken from DefaultBackoff,
+// so Backoff never ends in a loop without delays.
+func (b Backoff) withDefaults() Backoff {
+	if b.InitialDelay <= 0 {
+		b.InitialDelay = DefaultBackoff.InitialDelay
+	}
+	if b.MaxDelay <= 0 {
+		b.MaxDelay = DefaultBackoff.MaxDelay
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = DefaultBackoff.Multiplier
+	}
+
+	if b.InitialDelay < minBackoffDelay {
+		b.InitialDelay = minBackoffDelay
+	}
+	if b.MaxDelay < minBackoffDelay {
+		b.MaxDelay = minBackoffDelay
+	}
+	return b
+}
+
+func (b Backoff) next(delay time.Duration) time.Duration {
+	delay = time.Duration(float64(delay) * b.Multiplier)
+	if delay > b.MaxDelay {
+		return b.MaxDelay
+	}
+	return delay
+}
+
+// waitFor calls check until it returns true or error. Context cancellation stops the waiting
+// and context error is returned.
+func waitFor(ctx context.Context, backoff Backoff, check func() (bool, error)) error {
+	backoff = backoff.withDefaults()
+	delay := backoff.InitialDelay
+
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+
+		if err = sleep(ctx, delay); err != nil {
+			return err
+		}
+
+		delay = backoff.next(delay)
+	}
+}
+
+// sleep waits for the given delay, context cancellation stops the waiting and context error is returned.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
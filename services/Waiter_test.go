@@ -0,0 +1,417 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	virtualMachineWaitForState              = "records/onetest/virtualMachine/waitForState"
+	virtualMachineWaitForStateBootFailure   = "records/onetest/virtualMachine/waitForStateBootFailure"
+	virtualMachineWaitForStatePrologFailure = "records/onetest/virtualMachine/waitForStatePrologFailure"
+	virtualMachineWaitForStateTimeout       = "records/onetest/virtualMachine/waitForStateTimeout"
+	virtualMachineWaitForStateWrongID       = "records/onetest/virtualMachine/waitForStateWrongID"
+
+	imageWaitForState      = "records/onetest/image/waitForState"
+	imageWaitForStateError = "records/onetest/image/waitForStateError"
+
+	hostWaitForState      = "records/onetest/host/waitForState"
+	hostWaitForStateError = "records/onetest/host/waitForStateError"
+)
+
+// repeatingTransport responds to all the requests with the response to the first request.
+type repeatingTransport struct {
+	calls    int
+	response []byte
+	next     http.RoundTripper
+}
+
+func (rt *repeatingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.calls++
+	if rt.response == nil {
+		resp, err := rt.next.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close() //nolint
+
+		if rt.response, err = ioutil.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK",
+		Body: ioutil.NopCloser(bytes.NewReader(rt.response)), Request: r}, nil
+}
+
+var _ = ginkgo.Describe("Waiter", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var backoff = services.Backoff{InitialDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond, Multiplier: 2}
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("wait for virtual machine state", func() {
+		var virtualMachine *resources.VirtualMachine
+
+		ginkgo.Context("when virtual machine reaches the state", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForState
+
+				virtualMachine = resources.CreateVirtualMachineWithID(150)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should return virtual machine in the state", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(context.TODO(),
+					*virtualMachine, resources.VirtualMachineStateActive, resources.VirtualMachineRunning, backoff)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualMachine).ShouldNot(gomega.BeNil())
+
+				var state resources.VirtualMachineState
+				state, err = oneVirtualMachine.State()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(state).To(gomega.Equal(resources.VirtualMachineStateActive))
+
+				var lcmState resources.VirtualMachineLcmState
+				lcmState, err = oneVirtualMachine.LCMState()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lcmState).To(gomega.Equal(resources.VirtualMachineRunning))
+			})
+		})
+
+		ginkgo.Context("when virtual machine fails to boot", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForStateBootFailure
+
+				virtualMachine = resources.CreateVirtualMachineWithID(151)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should return state error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(context.TODO(),
+					*virtualMachine, resources.VirtualMachineStateActive, resources.VirtualMachineRunning, backoff)
+				gomega.Expect(err).To(gomega.BeAssignableToTypeOf(&errors.StateError{}))
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual machine fails in prolog", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForStatePrologFailure
+
+				virtualMachine = resources.CreateVirtualMachineWithID(152)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should return state error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(context.TODO(),
+					*virtualMachine, resources.VirtualMachineStateActive, resources.VirtualMachineRunning, backoff)
+				gomega.Expect(err).To(gomega.BeAssignableToTypeOf(&errors.StateError{}))
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when context expires before the state is reached", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForStateTimeout
+
+				virtualMachine = resources.CreateVirtualMachineWithID(153)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should return context error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+				defer cancel()
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(ctx, *virtualMachine,
+					resources.VirtualMachineStateActive, resources.VirtualMachineRunning,
+					services.Backoff{InitialDelay: time.Hour, MaxDelay: time.Hour, Multiplier: 1})
+				gomega.Expect(err).To(gomega.Equal(context.DeadlineExceeded))
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when backoff is zero value", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForStateTimeout
+
+				virtualMachine = resources.CreateVirtualMachineWithID(153)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should wait with default delays", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+				defer cancel()
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(ctx, *virtualMachine,
+					resources.VirtualMachineStateActive, resources.VirtualMachineRunning, services.Backoff{})
+				gomega.Expect(err).To(gomega.Equal(context.DeadlineExceeded))
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when backoff multiplier is fractional", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForStateTimeout
+
+				virtualMachine = resources.CreateVirtualMachineWithID(153)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should not shorten the delays", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				repeating := &repeatingTransport{next: rec}
+				client = onego.CreateClient(endpoint, token, &http.Client{Transport: repeating})
+
+				ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+				defer cancel()
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(ctx, *virtualMachine,
+					resources.VirtualMachineStateActive, resources.VirtualMachineRunning,
+					services.Backoff{InitialDelay: 10 * time.Millisecond, MaxDelay: time.Hour, Multiplier: 0.1})
+				gomega.Expect(err).To(gomega.Equal(context.DeadlineExceeded))
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+				gomega.Expect(repeating.calls).To(gomega.BeNumerically("<=", 5))
+			})
+		})
+
+		ginkgo.Context("when virtual machine doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = virtualMachineWaitForStateWrongID
+
+				virtualMachine = resources.CreateVirtualMachineWithID(1000)
+				if virtualMachine == nil {
+					err = errors.ErrNoVirtualMachine
+				}
+			})
+
+			ginkgo.It("should return that virtual machine doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(context.TODO(),
+					*virtualMachine, resources.VirtualMachineStateActive, resources.VirtualMachineRunning, backoff)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when virtual machine is empty", func() {
+			ginkgo.It("should return that virtual machine has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneVirtualMachine *resources.VirtualMachine
+				oneVirtualMachine, err = client.VirtualMachineService.WaitForStateWithBackoff(context.TODO(),
+					resources.VirtualMachine{}, resources.VirtualMachineStateActive, resources.VirtualMachineRunning,
+					backoff)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneVirtualMachine).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("wait for image state", func() {
+		var image *resources.Image
+
+		ginkgo.Context("when image becomes ready", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageWaitForState
+
+				image = resources.CreateImageWithID(30)
+				if image == nil {
+					err = errors.ErrNoImage
+				}
+			})
+
+			ginkgo.It("should return ready image", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneImage *resources.Image
+				oneImage, err = client.ImageService.WaitForStateWithBackoff(context.TODO(), *image,
+					resources.ImageStateReady, backoff)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneImage).ShouldNot(gomega.BeNil())
+
+				var state resources.ImageState
+				state, err = oneImage.State()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(state).To(gomega.Equal(resources.ImageStateReady))
+			})
+		})
+
+		ginkgo.Context("when image ends in error", func() {
+			ginkgo.BeforeEach(func() {
+				recName = imageWaitForStateError
+
+				image = resources.CreateImageWithID(31)
+				if image == nil {
+					err = errors.ErrNoImage
+				}
+			})
+
+			ginkgo.It("should return state error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneImage *resources.Image
+				oneImage, err = client.ImageService.WaitForStateWithBackoff(context.TODO(), *image,
+					resources.ImageStateReady, backoff)
+				gomega.Expect(err).To(gomega.BeAssignableToTypeOf(&errors.StateError{}))
+				gomega.Expect(oneImage).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when image is empty", func() {
+			ginkgo.It("should return that image has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneImage *resources.Image
+				oneImage, err = client.ImageService.WaitForStateWithBackoff(context.TODO(), resources.Image{},
+					resources.ImageStateReady, backoff)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneImage).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("wait for host state", func() {
+		var host *resources.Host
+
+		ginkgo.Context("when host becomes monitored", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hostWaitForState
+
+				host = resources.CreateHostWithID(10)
+				if host == nil {
+					err = errors.ErrNoHost
+				}
+			})
+
+			ginkgo.It("should return monitored host", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneHost *resources.Host
+				oneHost, err = client.HostService.WaitForStateWithBackoff(context.TODO(), *host,
+					resources.HostMonitored, backoff)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(oneHost).ShouldNot(gomega.BeNil())
+
+				var state resources.HostState
+				state, err = oneHost.State()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(state).To(gomega.Equal(resources.HostMonitored))
+			})
+		})
+
+		ginkgo.Context("when host monitoring fails", func() {
+			ginkgo.BeforeEach(func() {
+				recName = hostWaitForStateError
+
+				host = resources.CreateHostWithID(11)
+				if host == nil {
+					err = errors.ErrNoHost
+				}
+			})
+
+			ginkgo.It("should return state error", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneHost *resources.Host
+				oneHost, err = client.HostService.WaitForStateWithBackoff(context.TODO(), *host,
+					resources.HostMonitored, backoff)
+				gomega.Expect(err).To(gomega.BeAssignableToTypeOf(&errors.StateError{}))
+				gomega.Expect(oneHost).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when host is empty", func() {
+			ginkgo.It("should return that host has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				var oneHost *resources.Host
+				oneHost, err = client.HostService.WaitForStateWithBackoff(context.TODO(), resources.Host{},
+					resources.HostMonitored, backoff)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneHost).Should(gomega.BeNil())
+			})
+		})
+	})
+})
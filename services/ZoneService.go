@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/resources"
+)
+
+// ZoneService structure to manage OpenNebula zone.
+type ZoneService struct {
+	Service
+}
+
+// Allocate allocates a new zone in OpenNebula. Zones can be allocated in the master zone of the federation only.
+func (zs *ZoneService) Allocate(ctx context.Context, blueprint blueprint.Interface) (*resources.Zone, error) {
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := zs.call(ctx, "one.zone.allocate", blueprintText)
+	if err != nil {
+		return nil, err
+	}
+
+	return zs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Delete deletes the given zone from the pool.
+func (zs *ZoneService) Delete(ctx context.Context, zone resources.Zone) error {
+	zoneID, err := zone.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = zs.call(ctx, "one.zone.delete", zoneID)
+
+	return err
+}
+
+// Update merges or replaces the zone template contents.
+func (zs *ZoneService) Update(ctx context.Context, zone resources.Zone, blueprint blueprint.Interface,
+	updateType UpdateType) (*resources.Zone, error) {
+	zoneID, err := zone.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintText, err := blueprint.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	resArr, err := zs.call(ctx, "one.zone.update", zoneID, blueprintText, updateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return zs.RetrieveInfo(ctx, int(resArr[resultIndex].ResultInt()))
+}
+
+// Rename renames a zone.
+func (zs *ZoneService) Rename(ctx context.Context, zone resources.Zone, name string) error {
+	zoneID, err := zone.ID()
+	if err != nil {
+		return err
+	}
+
+	_, err = zs.call(ctx, "one.zone.rename", zoneID, name)
+
+	return err
+}
+
+// RetrieveInfo retrieves information for the zone.
+func (zs *ZoneService) RetrieveInfo(ctx context.Context, zoneID int) (*resources.Zone, error) {
+	doc, err := zs.retrieveInfo(ctx, "one.zone.info", zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.CreateZoneFromXML(doc.Root()), nil
+}
+
+// List retrieves information for all of the zones in the federation.
+func (zs *ZoneService) List(ctx context.Context) ([]*resources.Zone, error) {
+	doc, err := zs.list(ctx, "one.zonepool.info")
+	if err != nil {
+		return nil, err
+	}
+
+	elements := doc.FindElements("ZONE_POOL/ZONE")
+
+	zones := make([]*resources.Zone, len(elements))
+	for i, e := range elements {
+		zones[i] = resources.CreateZoneFromXML(e)
+	}
+
+	return zones, nil
+}
@@ -0,0 +1,413 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/onego-project/onego"
+	"github.com/onego-project/onego/blueprint"
+	"github.com/onego-project/onego/errors"
+	"github.com/onego-project/onego/resources"
+	"github.com/onego-project/onego/services"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	zoneAllocate           = "records/onetest/zone/allocate"
+	zoneAllocateExisting   = "records/onetest/zone/allocateExisting"
+	zoneAllocateNoEndpoint = "records/onetest/zone/allocateNoEndpoint"
+
+	zoneDelete        = "records/onetest/zone/delete"
+	zoneDeleteWrongID = "records/onetest/zone/deleteWrongID"
+
+	zoneUpdateMerge   = "records/onetest/zone/updateMerge"
+	zoneUpdateUnknown = "records/onetest/zone/updateUnknown"
+
+	zoneRename        = "records/onetest/zone/rename"
+	zoneRenameEmpty   = "records/onetest/zone/renameEmpty"
+	zoneRenameUnknown = "records/onetest/zone/renameUnknown"
+
+	zoneRetrieveInfo        = "records/onetest/zone/retrieveInfo"
+	zoneRetrieveInfoUnknown = "records/onetest/zone/retrieveInfoUnknown"
+
+	zoneList = "records/onetest/zone/list"
+)
+
+var _ = ginkgo.Describe("Zone Service", func() {
+	var (
+		recName string
+		rec     *recorder.Recorder
+		client  *onego.Client
+		err     error
+	)
+
+	var masterZoneID = 0
+	var existingZoneID = 100
+	var deletedZoneID = 101
+	var nonExistingZoneID = 420
+
+	ginkgo.JustBeforeEach(func() {
+		// Start recorder
+		rec, err = recorder.New(recName)
+		if err != nil {
+			return
+		}
+
+		rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+			var b bytes.Buffer
+			if _, err = b.ReadFrom(r.Body); err != nil {
+				return false
+			}
+			r.Body = ioutil.NopCloser(&b)
+			return cassette.DefaultMatcher(r, i) && (b.String() == "" || b.String() == i.Body)
+		})
+
+		// Create an HTTP client and inject our transport
+		clientHTTP := &http.Client{
+			Transport: rec, // Inject as transport!
+		}
+
+		// create onego client
+		client = onego.CreateClient(endpoint, token, clientHTTP)
+		if client == nil {
+			err = errors.ErrNoClient
+			return
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		rec.Stop()
+	})
+
+	ginkgo.Describe("allocate zone", func() {
+		var (
+			zone          *resources.Zone
+			zoneBlueprint *blueprint.ZoneBlueprint
+		)
+
+		ginkgo.Context("when zone doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneAllocate
+
+				zoneBlueprint = blueprint.CreateAllocateZoneBlueprint()
+				zoneBlueprint.SetName("ostrava")
+				zoneBlueprint.SetEndpoint("http://one-ostrava.example.com:2633/RPC2")
+			})
+
+			ginkgo.It("should create new zone", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				zone, err = client.ZoneService.Allocate(context.TODO(), zoneBlueprint)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(zone).ShouldNot(gomega.BeNil())
+				gomega.Expect(zone.ID()).To(gomega.Equal(102))
+				gomega.Expect(zone.Name()).To(gomega.Equal("ostrava"))
+				gomega.Expect(zone.Endpoint()).To(gomega.Equal("http://one-ostrava.example.com:2633/RPC2"))
+				gomega.Expect(zone.Servers()).To(gomega.HaveLen(0))
+			})
+		})
+
+		ginkgo.Context("when zone exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneAllocateExisting
+
+				zoneBlueprint = blueprint.CreateAllocateZoneBlueprint()
+				zoneBlueprint.SetName("brno")
+				zoneBlueprint.SetEndpoint("http://one-brno.example.com:2633/RPC2")
+			})
+
+			ginkgo.It("should return that zone already exists", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				zone, err = client.ZoneService.Allocate(context.TODO(), zoneBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(zone).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when endpoint is missing", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneAllocateNoEndpoint
+
+				zoneBlueprint = blueprint.CreateAllocateZoneBlueprint()
+				zoneBlueprint.SetName("plzen")
+			})
+
+			ginkgo.It("shouldn't create new zone", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				zone, err = client.ZoneService.Allocate(context.TODO(), zoneBlueprint)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(zone).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("shouldn't create new zone", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				zone, err = client.ZoneService.Allocate(context.TODO(), &blueprint.ZoneBlueprint{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(zone).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("delete zone", func() {
+		var (
+			zone    *resources.Zone
+			oneZone *resources.Zone
+		)
+
+		ginkgo.Context("when zone exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneDelete
+
+				zone = resources.CreateZoneWithID(deletedZoneID)
+				if zone == nil {
+					err = errors.ErrNoZone
+				}
+			})
+
+			ginkgo.It("should delete zone", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ZoneService.Delete(context.TODO(), *zone)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				// check whether zone was really deleted in OpenNebula
+				oneZone, err = client.ZoneService.RetrieveInfo(context.TODO(), deletedZoneID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(oneZone).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when zone doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneDeleteWrongID
+
+				zone = resources.CreateZoneWithID(nonExistingZoneID)
+				if zone == nil {
+					err = errors.ErrNoZone
+				}
+			})
+
+			ginkgo.It("should return that zone with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ZoneService.Delete(context.TODO(), *zone)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when zone is empty", func() {
+			ginkgo.It("should return that zone has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ZoneService.Delete(context.TODO(), resources.Zone{})
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("update zone", func() {
+		var (
+			zone          *resources.Zone
+			zoneBlueprint *blueprint.ZoneBlueprint
+			retZone       *resources.Zone
+		)
+
+		ginkgo.BeforeEach(func() {
+			zoneBlueprint = blueprint.CreateUpdateZoneBlueprint()
+			if zoneBlueprint == nil {
+				err = errors.ErrNoZoneBlueprint
+				return
+			}
+			zoneBlueprint.SetEndpoint("http://one.brno.example.com:2633/RPC2")
+		})
+
+		ginkgo.Context("when zone exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneUpdateMerge
+
+				zone = resources.CreateZoneWithID(existingZoneID)
+				if zone == nil {
+					err = errors.ErrNoZone
+				}
+			})
+
+			ginkgo.It("should merge data of given zone", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retZone, err = client.ZoneService.Update(context.TODO(), *zone, zoneBlueprint, services.Merge)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(retZone).ShouldNot(gomega.BeNil())
+				gomega.Expect(retZone.Endpoint()).To(gomega.Equal("http://one.brno.example.com:2633/RPC2"))
+			})
+		})
+
+		ginkgo.Context("when zone doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneUpdateUnknown
+
+				zone = resources.CreateZoneWithID(nonExistingZoneID)
+				if zone == nil {
+					err = errors.ErrNoZone
+				}
+			})
+
+			ginkgo.It("should return that zone with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retZone, err = client.ZoneService.Update(context.TODO(), *zone, zoneBlueprint, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retZone).Should(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when blueprint is empty", func() {
+			ginkgo.It("should return that blueprint is empty", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				retZone, err = client.ZoneService.Update(context.TODO(), *resources.CreateZoneWithID(existingZoneID),
+					&blueprint.ZoneBlueprint{}, services.Merge)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(retZone).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("zone rename", func() {
+		var (
+			zone    *resources.Zone
+			oneZone *resources.Zone
+		)
+
+		ginkgo.Context("when zone exists", func() {
+			ginkgo.BeforeEach(func() {
+				zone = resources.CreateZoneWithID(existingZoneID)
+				if zone == nil {
+					err = errors.ErrNoZone
+				}
+			})
+
+			ginkgo.When("when new name is not empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = zoneRename
+				})
+
+				ginkgo.It("should change name of given zone", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.ZoneService.Rename(context.TODO(), *zone, "brno-1")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					// check whether name was really changed in OpenNebula
+					oneZone, err = client.ZoneService.RetrieveInfo(context.TODO(), existingZoneID)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(oneZone).ShouldNot(gomega.BeNil())
+					gomega.Expect(oneZone.Name()).To(gomega.Equal("brno-1"))
+				})
+			})
+
+			ginkgo.When("when new name is empty", func() {
+				ginkgo.BeforeEach(func() {
+					recName = zoneRenameEmpty
+				})
+
+				ginkgo.It("should not change name of given zone", func() {
+					gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+					err = client.ZoneService.Rename(context.TODO(), *zone, "")
+					gomega.Expect(err).To(gomega.HaveOccurred())
+				})
+			})
+		})
+
+		ginkgo.Context("when zone doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneRenameUnknown
+
+				zone = resources.CreateZoneWithID(nonExistingZoneID)
+				if zone == nil {
+					err = errors.ErrNoZone
+				}
+			})
+
+			ginkgo.It("should return that zone with given ID doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ZoneService.Rename(context.TODO(), *zone, "zone")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when zone is empty", func() {
+			ginkgo.It("should return that zone has no ID", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				err = client.ZoneService.Rename(context.TODO(), resources.Zone{}, "zone")
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("zone retrieve info", func() {
+		var zone *resources.Zone
+
+		ginkgo.Context("when zone exists", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneRetrieveInfo
+			})
+
+			ginkgo.It("should return zone with full info", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				zone, err = client.ZoneService.RetrieveInfo(context.TODO(), masterZoneID)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(zone).ShouldNot(gomega.BeNil())
+				gomega.Expect(zone.ID()).To(gomega.Equal(masterZoneID))
+				gomega.Expect(zone.Name()).To(gomega.Equal("OpenNebula"))
+				gomega.Expect(zone.Endpoint()).To(gomega.Equal(endpoint))
+			})
+		})
+
+		ginkgo.Context("when zone doesn't exist", func() {
+			ginkgo.BeforeEach(func() {
+				recName = zoneRetrieveInfoUnknown
+			})
+
+			ginkgo.It("should return that given zone doesn't exist", func() {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+				zone, err = client.ZoneService.RetrieveInfo(context.TODO(), nonExistingZoneID)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(zone).Should(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("zone list", func() {
+		var zones []*resources.Zone
+
+		ginkgo.BeforeEach(func() {
+			recName = zoneList
+		})
+
+		ginkgo.It("should return list of all zones with full info", func() {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred()) // no error during BeforeEach
+
+			zones, err = client.ZoneService.List(context.TODO())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(zones).To(gomega.HaveLen(3))
+			gomega.Expect(zones[0].Name()).To(gomega.Equal("OpenNebula"))
+			gomega.Expect(zones[1].Name()).To(gomega.Equal("brno-1"))
+			gomega.Expect(zones[2].Name()).To(gomega.Equal("ostrava"))
+		})
+	})
+})